@@ -0,0 +1,70 @@
+package alerts
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/alerting"
+	"sitecrawler/newgo/internal/services/alerts"
+	"sitecrawler/newgo/models"
+)
+
+type CreateRuleController struct {
+	service alerts.Service
+	logger  *slog.Logger
+}
+
+func NewCreateRuleController(service alerts.Service, logger *slog.Logger) *CreateRuleController {
+	if service == nil {
+		panic("alert rule create service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CreateRuleController{service: service, logger: logger}
+}
+
+func (c *CreateRuleController) Create(ctx *fiber.Ctx) error {
+	var request alertsDto.CreateAlertRuleRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+
+	if err := validateCreateAlertRuleRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.CreateRule(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("alert rule create failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validateCreateAlertRuleRequest(req alertsDto.CreateAlertRuleRequest) error {
+	if req.Data.SearchKeywordURLID == 0 {
+		return errors.New("search_keyword_url_id is required")
+	}
+	mode := req.Data.Mode
+	if mode == "" {
+		mode = alerting.ModeValue
+	}
+	return alerting.ValidateRule(models.AlertRule{
+		Name:         req.Data.Name,
+		AuditCheckID: req.Data.AuditCheckID,
+		Metric:       req.Data.Metric,
+		Mode:         mode,
+		Operator:     req.Data.Operator,
+		WebhookURL:   req.Data.WebhookURL,
+		Emails:       req.Data.Emails,
+	})
+}
@@ -0,0 +1,45 @@
+package alerts
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/services/alerts"
+)
+
+type DeleteRuleController struct {
+	service alerts.Service
+	logger  *slog.Logger
+}
+
+func NewDeleteRuleController(service alerts.Service, logger *slog.Logger) *DeleteRuleController {
+	if service == nil {
+		panic("alert rule delete service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DeleteRuleController{service: service, logger: logger}
+}
+
+func (c *DeleteRuleController) Delete(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.DeleteRule(ctx.Context(), alertsDto.DeleteAlertRuleRequest{ID: id})
+	if err != nil {
+		c.logger.Error("alert rule delete failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -0,0 +1,45 @@
+package alerts
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/services/alerts"
+)
+
+type GetRuleController struct {
+	service alerts.Service
+	logger  *slog.Logger
+}
+
+func NewGetRuleController(service alerts.Service, logger *slog.Logger) *GetRuleController {
+	if service == nil {
+		panic("alert rule get service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &GetRuleController{service: service, logger: logger}
+}
+
+func (c *GetRuleController) Get(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.GetRule(ctx.Context(), alertsDto.GetAlertRuleRequest{ID: id})
+	if err != nil {
+		c.logger.Error("alert rule get failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
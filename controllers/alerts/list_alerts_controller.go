@@ -0,0 +1,63 @@
+package alerts
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/services/alerts"
+)
+
+type ListAlertsController struct {
+	service alerts.Service
+	logger  *slog.Logger
+}
+
+func NewListAlertsController(service alerts.Service, logger *slog.Logger) *ListAlertsController {
+	if service == nil {
+		panic("alert list service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ListAlertsController{service: service, logger: logger}
+}
+
+func (c *ListAlertsController) List(ctx *fiber.Ctx) error {
+	skuID, err := strconv.ParseInt(ctx.Query("search_keyword_url_id"), 10, 64)
+	if err != nil || skuID == 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": errors.New("missing search_keyword_url_id").Error()})
+	}
+	request := alertsDto.ListAlertsRequest{SearchKeywordURLID: skuID}
+
+	if raw := ctx.Query("crawling_session_id"); raw != "" {
+		if request.CrawlingSessionID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid crawling_session_id"})
+		}
+	}
+	if raw := ctx.Query("rule_id"); raw != "" {
+		if request.RuleID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid rule_id"})
+		}
+	}
+	if raw := ctx.Query("limit"); raw != "" {
+		if request.Limit, err = strconv.Atoi(raw); err != nil || request.Limit < 1 {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid limit"})
+		}
+	}
+
+	resp, err := c.service.ListAlerts(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("alert list failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
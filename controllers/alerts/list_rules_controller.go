@@ -0,0 +1,46 @@
+package alerts
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/services/alerts"
+)
+
+type ListRulesController struct {
+	service alerts.Service
+	logger  *slog.Logger
+}
+
+func NewListRulesController(service alerts.Service, logger *slog.Logger) *ListRulesController {
+	if service == nil {
+		panic("alert rule list service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ListRulesController{service: service, logger: logger}
+}
+
+func (c *ListRulesController) List(ctx *fiber.Ctx) error {
+	skuID, err := strconv.ParseInt(ctx.Query("search_keyword_url_id"), 10, 64)
+	if err != nil || skuID == 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": errors.New("missing search_keyword_url_id").Error()})
+	}
+
+	resp, err := c.service.ListRules(ctx.Context(), alertsDto.ListAlertRulesRequest{SearchKeywordURLID: skuID})
+	if err != nil {
+		c.logger.Error("alert rule list failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -0,0 +1,53 @@
+package alerts
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/services/alerts"
+)
+
+type UpdateRuleController struct {
+	service alerts.Service
+	logger  *slog.Logger
+}
+
+func NewUpdateRuleController(service alerts.Service, logger *slog.Logger) *UpdateRuleController {
+	if service == nil {
+		panic("alert rule update service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UpdateRuleController{service: service, logger: logger}
+}
+
+// Update validates the merged rule in the service, since a partial payload
+// cannot be checked on its own.
+func (c *UpdateRuleController) Update(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var request alertsDto.UpdateAlertRuleRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+	request.ID = id
+
+	resp, err := c.service.UpdateRule(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("alert rule update failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
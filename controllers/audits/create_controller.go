@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/scoring"
 	"sitecrawler/newgo/internal/services/audits"
 
 	"github.com/gofiber/fiber/v2"
@@ -59,5 +61,14 @@ func validateCreateAuditCheckRequest(req auditsDto.CreateAuditCheckRequest) erro
 	if strings.TrimSpace(req.Data.Category) == "" {
 		return errors.New("category is required")
 	}
-	return nil
+	if err := scoring.ValidateCategory(req.Data.Category); err != nil {
+		return err
+	}
+	if err := scoring.ValidateSeverity(req.Data.Severity); err != nil {
+		return err
+	}
+	if err := scoring.ValidateWeight(req.Data.Weight); err != nil {
+		return err
+	}
+	return filterconfig.Validate(req.Data.FilterConfig)
 }
@@ -0,0 +1,68 @@
+package audits
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/scoring"
+	"sitecrawler/newgo/internal/services/audits"
+)
+
+type CreateTemplateController struct {
+	service audits.Service
+	logger  *slog.Logger
+}
+
+func NewCreateTemplateController(service audits.Service, logger *slog.Logger) *CreateTemplateController {
+	if service == nil {
+		panic("audit check template create service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CreateTemplateController{service: service, logger: logger}
+}
+
+func (c *CreateTemplateController) Create(ctx *fiber.Ctx) error {
+	var request auditsDto.CreateAuditCheckTemplateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+
+	if err := validateCreateAuditCheckTemplateRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.CreateTemplate(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("audit check template create failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validateCreateAuditCheckTemplateRequest(req auditsDto.CreateAuditCheckTemplateRequest) error {
+	if strings.TrimSpace(req.Data.Name) == "" {
+		return errors.New("name is required")
+	}
+	if err := scoring.ValidateCategory(req.Data.Category); err != nil {
+		return err
+	}
+	if err := scoring.ValidateSeverity(req.Data.Severity); err != nil {
+		return err
+	}
+	if err := scoring.ValidateWeight(req.Data.Weight); err != nil {
+		return err
+	}
+	return filterconfig.Validate(req.Data.FilterConfig)
+}
@@ -0,0 +1,45 @@
+package audits
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/services/audits"
+)
+
+type GetTemplateController struct {
+	service audits.Service
+	logger  *slog.Logger
+}
+
+func NewGetTemplateController(service audits.Service, logger *slog.Logger) *GetTemplateController {
+	if service == nil {
+		panic("audit check template get service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &GetTemplateController{service: service, logger: logger}
+}
+
+func (c *GetTemplateController) Get(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.GetTemplate(ctx.Context(), auditsDto.GetAuditCheckTemplateRequest{ID: id})
+	if err != nil {
+		c.logger.Error("audit check template get failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -0,0 +1,66 @@
+package audits
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/series"
+	"sitecrawler/newgo/internal/services/audits"
+)
+
+const maxHistoryLimit = 100
+
+type HistoryController struct {
+	service audits.Service
+	logger  *slog.Logger
+}
+
+func NewHistoryController(service audits.Service, logger *slog.Logger) *HistoryController {
+	if service == nil {
+		panic("audit check history service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &HistoryController{service: service, logger: logger}
+}
+
+func (c *HistoryController) History(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	from, err := series.ParseBound(ctx.Query("from"), false)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid from"})
+	}
+	to, err := series.ParseBound(ctx.Query("to"), true)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid to"})
+	}
+	limit, err := strconv.Atoi(ctx.Query("limit", "0"))
+	if err != nil || limit < 0 || limit > maxHistoryLimit {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid limit"})
+	}
+	points, err := strconv.Atoi(ctx.Query("points", "0"))
+	if err != nil || points < 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid points"})
+	}
+
+	req := auditsDto.AuditCheckHistoryRequest{ID: id, From: from, To: to, Limit: limit, Points: points}
+	resp, err := c.service.History(ctx.Context(), req)
+	if err != nil {
+		c.logger.Error("audit check history failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
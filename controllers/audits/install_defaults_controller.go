@@ -0,0 +1,47 @@
+package audits
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/services/audits"
+)
+
+type InstallDefaultsController struct {
+	service audits.Service
+	logger  *slog.Logger
+}
+
+func NewInstallDefaultsController(service audits.Service, logger *slog.Logger) *InstallDefaultsController {
+	if service == nil {
+		panic("audit check install defaults service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &InstallDefaultsController{service: service, logger: logger}
+}
+
+// InstallDefaults seeds the built-in check catalogue for a SKU and upgrades
+// previously installed checks the user has not edited.
+func (c *InstallDefaultsController) InstallDefaults(ctx *fiber.Ctx) error {
+	skuID, err := strconv.ParseInt(ctx.Query("search_keyword_url_id"), 10, 64)
+	if err != nil || skuID == 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "missing search_keyword_url_id"})
+	}
+
+	resp, err := c.service.InstallDefaults(ctx.Context(), auditsDto.InstallDefaultsRequest{SearchKeywordURLID: skuID})
+	if err != nil {
+		c.logger.Error("audit check install defaults failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
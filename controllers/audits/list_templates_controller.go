@@ -0,0 +1,38 @@
+package audits
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"sitecrawler/newgo/internal/services/audits"
+)
+
+type ListTemplatesController struct {
+	service audits.Service
+	logger  *slog.Logger
+}
+
+func NewListTemplatesController(service audits.Service, logger *slog.Logger) *ListTemplatesController {
+	if service == nil {
+		panic("audit check template list service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ListTemplatesController{service: service, logger: logger}
+}
+
+func (c *ListTemplatesController) List(ctx *fiber.Ctx) error {
+	resp, err := c.service.ListTemplates(ctx.Context())
+	if err != nil {
+		c.logger.Error("audit check template list failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
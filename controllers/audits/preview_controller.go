@@ -0,0 +1,63 @@
+package audits
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/services/audits"
+)
+
+const maxPreviewLimit = 100
+
+type PreviewController struct {
+	service audits.Service
+	logger  *slog.Logger
+}
+
+func NewPreviewController(service audits.Service, logger *slog.Logger) *PreviewController {
+	if service == nil {
+		panic("audit check preview service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PreviewController{service: service, logger: logger}
+}
+
+func (c *PreviewController) Preview(ctx *fiber.Ctx) error {
+	var request auditsDto.PreviewAuditCheckRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+
+	if err := validatePreviewAuditCheckRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.Preview(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("audit check preview failed", "error", err, "crawling_session_id", request.Data.CrawlingSessionID)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validatePreviewAuditCheckRequest(req auditsDto.PreviewAuditCheckRequest) error {
+	if req.Data.CrawlingSessionID <= 0 {
+		return errors.New("crawling_session_id is required")
+	}
+	if req.Data.Limit < 0 || req.Data.Limit > maxPreviewLimit {
+		return fmt.Errorf("limit must be between 1 and %d", maxPreviewLimit)
+	}
+	return filterconfig.Validate(req.Data.FilterConfig)
+}
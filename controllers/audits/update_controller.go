@@ -8,6 +8,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/scoring"
 	"sitecrawler/newgo/internal/services/audits"
 )
 
@@ -38,6 +40,10 @@ func (c *UpdateController) Update(ctx *fiber.Ctx) error {
 	}
 	request.ID = id
 
+	if err := validateUpdateAuditCheckRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	resp, err := c.service.Update(ctx.Context(), request)
 	if err != nil {
 		c.logger.Error("audit check update failed", "error", err, "id", id)
@@ -49,3 +55,25 @@ func (c *UpdateController) Update(ctx *fiber.Ctx) error {
 	}
 	return ctx.Status(resp.StatusCode).JSON(resp.Body)
 }
+
+func validateUpdateAuditCheckRequest(req auditsDto.UpdateAuditCheckRequest) error {
+	if req.Data.Category != nil {
+		if err := scoring.ValidateCategory(*req.Data.Category); err != nil {
+			return err
+		}
+	}
+	if req.Data.Severity != nil {
+		if err := scoring.ValidateSeverity(*req.Data.Severity); err != nil {
+			return err
+		}
+	}
+	if req.Data.Weight != nil {
+		if err := scoring.ValidateWeight(*req.Data.Weight); err != nil {
+			return err
+		}
+	}
+	if req.Data.FilterConfig != nil {
+		return filterconfig.Validate(*req.Data.FilterConfig)
+	}
+	return nil
+}
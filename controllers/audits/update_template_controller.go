@@ -0,0 +1,84 @@
+package audits
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/scoring"
+	"sitecrawler/newgo/internal/services/audits"
+)
+
+type UpdateTemplateController struct {
+	service audits.Service
+	logger  *slog.Logger
+}
+
+func NewUpdateTemplateController(service audits.Service, logger *slog.Logger) *UpdateTemplateController {
+	if service == nil {
+		panic("audit check template update service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UpdateTemplateController{service: service, logger: logger}
+}
+
+func (c *UpdateTemplateController) Update(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var request auditsDto.UpdateAuditCheckTemplateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+	request.ID = id
+
+	if err := validateUpdateAuditCheckTemplateRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.UpdateTemplate(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("audit check template update failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validateUpdateAuditCheckTemplateRequest(req auditsDto.UpdateAuditCheckTemplateRequest) error {
+	if req.Data.Name != nil && strings.TrimSpace(*req.Data.Name) == "" {
+		return errors.New("name cannot be empty")
+	}
+	if req.Data.Category != nil {
+		if err := scoring.ValidateCategory(*req.Data.Category); err != nil {
+			return err
+		}
+	}
+	if req.Data.Severity != nil {
+		if err := scoring.ValidateSeverity(*req.Data.Severity); err != nil {
+			return err
+		}
+	}
+	if req.Data.Weight != nil {
+		if err := scoring.ValidateWeight(*req.Data.Weight); err != nil {
+			return err
+		}
+	}
+	if req.Data.FilterConfig != nil {
+		return filterconfig.Validate(*req.Data.FilterConfig)
+	}
+	return nil
+}
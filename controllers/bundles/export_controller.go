@@ -0,0 +1,45 @@
+package bundles
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	bundlesDto "sitecrawler/newgo/dto/bundles"
+	"sitecrawler/newgo/internal/services/bundles"
+)
+
+type ExportController struct {
+	service bundles.Service
+	logger  *slog.Logger
+}
+
+func NewExportController(service bundles.Service, logger *slog.Logger) *ExportController {
+	if service == nil {
+		panic("config export service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ExportController{service: service, logger: logger}
+}
+
+func (c *ExportController) Export(ctx *fiber.Ctx) error {
+	skuID, err := strconv.ParseInt(ctx.Params("sku"), 10, 64)
+	if err != nil || skuID == 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid search_keyword_url_id"})
+	}
+
+	resp, err := c.service.Export(ctx.Context(), bundlesDto.ExportConfigRequest{SearchKeywordURLID: skuID})
+	if err != nil {
+		c.logger.Error("config export failed", "error", err, "search_keyword_url_id", skuID)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -0,0 +1,63 @@
+package bundles
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	bundlesDto "sitecrawler/newgo/dto/bundles"
+	"sitecrawler/newgo/internal/services/bundles"
+)
+
+type ImportController struct {
+	service bundles.Service
+	logger  *slog.Logger
+}
+
+func NewImportController(service bundles.Service, logger *slog.Logger) *ImportController {
+	if service == nil {
+		panic("config import service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ImportController{service: service, logger: logger}
+}
+
+// Import applies a bundle to the SKU. The mode defaults to skip; dry_run
+// reports the planned changes without writing.
+func (c *ImportController) Import(ctx *fiber.Ctx) error {
+	skuID, err := strconv.ParseInt(ctx.Params("sku"), 10, 64)
+	if err != nil || skuID == 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid search_keyword_url_id"})
+	}
+
+	var request bundlesDto.ImportConfigRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+	request.SearchKeywordURLID = skuID
+
+	switch request.Mode {
+	case "":
+		request.Mode = bundlesDto.ImportModeSkip
+	case bundlesDto.ImportModeSkip, bundlesDto.ImportModeOverwrite, bundlesDto.ImportModeRename:
+	default:
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("mode must be one of %s, %s, %s",
+			bundlesDto.ImportModeSkip, bundlesDto.ImportModeOverwrite, bundlesDto.ImportModeRename)})
+	}
+
+	resp, err := c.service.Import(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("config import failed", "error", err, "search_keyword_url_id", skuID)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -0,0 +1,85 @@
+package sessions
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/services/sessions"
+)
+
+const maxAuditResultsPageLimit = 100
+
+type AuditResultsController struct {
+	service sessions.Service
+	logger  *slog.Logger
+}
+
+func NewAuditResultsController(service sessions.Service, logger *slog.Logger) *AuditResultsController {
+	if service == nil {
+		panic("crawling session audit results service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AuditResultsController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary List audit results
+// @Description Evaluates every audit check of the session's SKU and returns matched page counts, deltas against a comparison session and a page of matched pages per check
+// @Tags CrawlingSessions
+// @Produce json
+// @Param id path int true "Crawling session ID"
+// @Param comparison_crawling_session_id query int false "Comparison session ID"
+// @Param page query int false "Page of matched pages per check"
+// @Param page_limit query int false "Matched pages per check, at most 100"
+// @Success 200 {object} sessionsDto.AuditResultsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/crawling_sessions/{id}/audit_results [get]
+func (c *AuditResultsController) List(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var comparisonID *int64
+	if rawComparison := ctx.Query("comparison_crawling_session_id"); rawComparison != "" {
+		if comp, err := strconv.ParseInt(rawComparison, 10, 64); err == nil {
+			comparisonID = &comp
+		} else {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid comparison_crawling_session_id"})
+		}
+	}
+
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	pageLimit, err := strconv.Atoi(ctx.Query("page_limit", "0"))
+	if err != nil || pageLimit < 0 || pageLimit > maxAuditResultsPageLimit {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid page_limit"})
+	}
+
+	req := sessionsDto.ListAuditResultsRequest{
+		SessionID:           id,
+		ComparisonSessionID: comparisonID,
+		Page:                page,
+		PageLimit:           pageLimit,
+	}
+
+	resp, err := c.service.ListAuditResults(ctx.Context(), req)
+	if err != nil {
+		c.logger.Error("audit results failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
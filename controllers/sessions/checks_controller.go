@@ -31,15 +31,17 @@ func NewChecksController(service sessions.Service, logger *slog.Logger) *ChecksC
 }
 
 // @Summary List checks with pages
-// @Description Lists audit checks with their associated pages for a session
+// @Description Lists audit checks with their matched pages for a session. With a comparison session, each check also lists its new, resolved and persisting pages.
 // @Tags CrawlingSessions
 // @Produce json
 // @Param id path int true "Crawling session ID"
+// @Param view_id query int false "View whose filters and comparison default apply"
 // @Param comparison_crawling_session_id query int false "Comparison session ID"
 // @Param filters query string false "View filters JSON"
 // @Param page_limit_per_check query int false "Page limit per check"
 // @Success 200 {object} sessionsDto.CrawlingSessionChecksResponse
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 422 {object} map[string]string
 // @Router /api/crawling_sessions/{id}/checks_with_pages [get]
 func (c *ChecksController) List(ctx *fiber.Ctx) error {
@@ -64,10 +66,20 @@ func (c *ChecksController) List(ctx *fiber.Ctx) error {
 		}
 	}
 
+	var viewID int64
+	if rawView := ctx.Query("view_id"); rawView != "" {
+		val, err := strconv.ParseInt(rawView, 10, 64)
+		if err != nil || val <= 0 {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid view_id"})
+		}
+		viewID = val
+	}
+
 	pageLimit, _ := strconv.Atoi(ctx.Query("page_limit_per_check"))
 
 	req := sessionsDto.ListCrawlingSessionChecksRequest{
 		SessionID:           id,
+		ViewID:              viewID,
 		ComparisonSessionID: comparisonID,
 		ViewFilters:         filters,
 		PageLimitPerCheck:   pageLimit,
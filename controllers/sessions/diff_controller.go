@@ -0,0 +1,102 @@
+package sessions
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/services/sessions"
+	"sitecrawler/newgo/models"
+)
+
+const maxDiffPageLimit = 200
+
+type DiffController struct {
+	service sessions.Service
+	logger  *slog.Logger
+}
+
+func NewDiffController(service sessions.Service, logger *slog.Logger) *DiffController {
+	if service == nil {
+		panic("crawling session diff service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DiffController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary Diff pages between sessions
+// @Description Lists pages added, removed or changed since another session of the same SKU, matched by normalized URL
+// @Tags CrawlingSessions
+// @Produce json
+// @Param id path int true "Crawling session ID"
+// @Param against query int false "Session to compare against, defaults to the previous finished session"
+// @Param change query string false "Comma separated change types: added, removed, changed"
+// @Param page query int false "Page number"
+// @Param page_limit query int false "Page size, at most 200"
+// @Success 200 {object} sessionsDto.SessionDiffResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/crawling_sessions/{id}/diff [get]
+func (c *DiffController) Diff(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var against *int64
+	if rawAgainst := ctx.Query("against"); rawAgainst != "" {
+		val, err := strconv.ParseInt(rawAgainst, 10, 64)
+		if err != nil || val <= 0 || val == id {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid against"})
+		}
+		against = &val
+	}
+
+	var changes []string
+	if rawChanges := ctx.Query("change"); rawChanges != "" {
+		for _, change := range strings.Split(rawChanges, ",") {
+			change = strings.TrimSpace(change)
+			switch change {
+			case models.PageChangeAdded, models.PageChangeRemoved, models.PageChangeChanged:
+				changes = append(changes, change)
+			default:
+				return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "change must be one of added, removed, changed"})
+			}
+		}
+	}
+
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	pageLimit, err := strconv.Atoi(ctx.Query("page_limit", "0"))
+	if err != nil || pageLimit < 0 || pageLimit > maxDiffPageLimit {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid page_limit"})
+	}
+
+	req := sessionsDto.SessionDiffRequest{
+		SessionID:        id,
+		AgainstSessionID: against,
+		Changes:          changes,
+		Page:             page,
+		PageLimit:        pageLimit,
+	}
+
+	resp, err := c.service.Diff(ctx.Context(), req)
+	if err != nil {
+		c.logger.Error("crawling session diff failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -0,0 +1,71 @@
+package sessions
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/services/sessions"
+)
+
+type DuplicatesController struct {
+	service sessions.Service
+	logger  *slog.Logger
+}
+
+func NewDuplicatesController(service sessions.Service, logger *slog.Logger) *DuplicatesController {
+	if service == nil {
+		panic("crawling session duplicates service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DuplicatesController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary List duplicate clusters
+// @Description Groups pages of a session sharing a title, meta description or (near-)identical body
+// @Tags CrawlingSessions
+// @Produce json
+// @Param id path int true "Crawling session ID"
+// @Param kind query string true "title, description or content"
+// @Success 200 {object} sessionsDto.DuplicatesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/crawling_sessions/{id}/duplicates [get]
+func (c *DuplicatesController) List(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	kind := ctx.Query("kind")
+	switch kind {
+	case sessionsDto.DuplicateKindTitle, sessionsDto.DuplicateKindDescription, sessionsDto.DuplicateKindContent:
+	default:
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "kind must be one of title, description, content"})
+	}
+
+	req := sessionsDto.ListDuplicatesRequest{
+		SessionID: id,
+		Kind:      kind,
+	}
+
+	resp, err := c.service.ListDuplicates(ctx.Context(), req)
+	if err != nil {
+		c.logger.Error("duplicates list failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
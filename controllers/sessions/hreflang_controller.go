@@ -0,0 +1,57 @@
+package sessions
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/services/sessions"
+)
+
+type HreflangController struct {
+	service sessions.Service
+	logger  *slog.Logger
+}
+
+func NewHreflangController(service sessions.Service, logger *slog.Logger) *HreflangController {
+	if service == nil {
+		panic("crawling session hreflang service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &HreflangController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary List hreflang issues
+// @Description Runs the built-in hreflang checks (return links, codes, target status, canonical targets, x-default) over a session
+// @Tags CrawlingSessions
+// @Produce json
+// @Param id path int true "Crawling session ID"
+// @Success 200 {object} sessionsDto.HreflangResponse
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/crawling_sessions/{id}/hreflang [get]
+func (c *HreflangController) List(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.ListHreflang(ctx.Context(), sessionsDto.ListHreflangRequest{SessionID: id})
+	if err != nil {
+		c.logger.Error("hreflang list failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -35,6 +35,7 @@ func NewPagesController(service sessions.Service, logger *slog.Logger) *PagesCon
 // @Tags CrawlingSessions
 // @Produce json
 // @Param id path int true "Crawling session ID"
+// @Param view_id query int false "View whose filters, sort, page size and columns apply"
 // @Param filters query string false "JSON encoded filters"
 // @Param sort query string false "Sort field"
 // @Param direction query string false "Sort direction"
@@ -42,6 +43,7 @@ func NewPagesController(service sessions.Service, logger *slog.Logger) *PagesCon
 // @Param page_limit query int false "Page size"
 // @Success 200 {object} sessionsDto.CrawlingSessionPagesResponse
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 422 {object} map[string]string
 // @Router /api/crawling_sessions/{id}/pages [get]
 func (c *PagesController) List(ctx *fiber.Ctx) error {
@@ -57,11 +59,21 @@ func (c *PagesController) List(ctx *fiber.Ctx) error {
 		}
 	}
 
+	var viewID int64
+	if rawView := ctx.Query("view_id"); rawView != "" {
+		val, err := strconv.ParseInt(rawView, 10, 64)
+		if err != nil || val <= 0 {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid view_id"})
+		}
+		viewID = val
+	}
+
 	page, _ := strconv.Atoi(ctx.Query("page"))
 	pageLimit, _ := strconv.Atoi(ctx.Query("page_limit"))
 
 	req := sessionsDto.ListCrawlingSessionPagesRequest{
 		SessionID: id,
+		ViewID:    viewID,
 		Filters:   filters,
 		Sort:      ctx.Query("sort"),
 		Direction: ctx.Query("direction"),
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	statsDto "sitecrawler/newgo/dto/stats"
+	"sitecrawler/newgo/internal/series"
+	"sitecrawler/newgo/internal/services/stats"
+)
+
+const maxHistoryLimit = 100
+
+type HistoryController struct {
+	service stats.Service
+	logger  *slog.Logger
+}
+
+func NewHistoryController(service stats.Service, logger *slog.Logger) *HistoryController {
+	if service == nil {
+		panic("stats history service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &HistoryController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary Stats metric history
+// @Description Returns one stats metric for every finished session of a SKU in chronological order
+// @Tags Stats
+// @Produce json
+// @Param search_keyword_url_id query int true "Search keyword URL ID"
+// @Param metric query string true "Dotted metric path, e.g. site_health or performance.response_time_ms.p90"
+// @Param from query string false "Earliest session end (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest session end (RFC 3339 or YYYY-MM-DD)"
+// @Param limit query int false "Most recent sessions to include (default and maximum 100)"
+// @Param points query int false "Downsample to at most this many points"
+// @Success 200 {object} statsDto.MetricHistoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/stats/history [get]
+func (c *HistoryController) History(ctx *fiber.Ctx) error {
+	skuID, err := strconv.ParseInt(ctx.Query("search_keyword_url_id"), 10, 64)
+	if err != nil || skuID == 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid search_keyword_url_id"})
+	}
+	metric := strings.TrimSpace(ctx.Query("metric"))
+	if metric == "" {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "metric is required"})
+	}
+
+	from, err := series.ParseBound(ctx.Query("from"), false)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid from"})
+	}
+	to, err := series.ParseBound(ctx.Query("to"), true)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid to"})
+	}
+	limit, err := strconv.Atoi(ctx.Query("limit", "0"))
+	if err != nil || limit < 0 || limit > maxHistoryLimit {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid limit"})
+	}
+	points, err := strconv.Atoi(ctx.Query("points", "0"))
+	if err != nil || points < 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid points"})
+	}
+
+	req := statsDto.MetricHistoryRequest{SearchKeywordURLID: skuID, Metric: metric, From: from, To: to, Limit: limit, Points: points}
+	resp, err := c.service.History(ctx.Context(), req)
+	if err != nil {
+		c.logger.Error("stats history failed", "error", err, "sku_id", skuID)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -31,15 +31,20 @@ func NewStatsController(service stats.Service, logger *slog.Logger) *StatsContro
 }
 
 // @Summary Fetch stats
-// @Description Retrieves stats for a crawling session with optional filters
+// @Description Retrieves stats for a crawling session with optional filters. Sections the database cannot compute, such as indexability on a pages table without that column, are left out.
 // @Tags Stats
 // @Produce json
 // @Param crawling_session_id query int true "Crawling session ID"
 // @Param filters query string false "JSON filters"
 // @Param prefilters query string false "JSON prefilters"
 // @Param comparison_crawling_session_id query int false "Comparison session ID"
+// @Param view_id query int false "View whose filters and comparison default apply"
+// @Param group_by query string false "Adds page counts, problematic, site health, performance and security per group: directory, subdomain, depth, content_type, status_class or regex"
+// @Param group_segments query int false "Leading directories a directory group keeps (default 1)"
+// @Param group_pattern query string false "Regex whose first capture group, or whole match, keys a page URL for group_by regex"
 // @Success 200 {object} statsDto.StatsResponse
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 422 {object} map[string]string
 // @Router /api/stats [get]
 func (c *StatsController) Fetch(ctx *fiber.Ctx) error {
@@ -75,11 +80,33 @@ func (c *StatsController) Fetch(ctx *fiber.Ctx) error {
 		comparisonID = &val
 	}
 
+	var viewID int64
+	if rawView := ctx.Query("view_id"); rawView != "" {
+		val, err := strconv.ParseInt(rawView, 10, 64)
+		if err != nil || val <= 0 {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid view_id"})
+		}
+		viewID = val
+	}
+
+	var groupSegments int
+	if rawSegments := ctx.Query("group_segments"); rawSegments != "" {
+		val, err := strconv.Atoi(rawSegments)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid group_segments"})
+		}
+		groupSegments = val
+	}
+
 	req := statsDto.StatsRequest{
 		CrawlingSessionID:    sessionID,
 		Filters:              filters,
 		Prefilters:           prefilters,
 		ComparisonCrawlingID: comparisonID,
+		ViewID:               viewID,
+		GroupBy:              ctx.Query("group_by"),
+		GroupSegments:        groupSegments,
+		GroupPattern:         ctx.Query("group_pattern"),
 	}
 
 	resp, err := c.service.Fetch(ctx.Context(), req)
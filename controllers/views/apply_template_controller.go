@@ -0,0 +1,60 @@
+package views
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+type ApplyTemplateController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewApplyTemplateController(service views.Service, logger *slog.Logger) *ApplyTemplateController {
+	if service == nil {
+		panic("view template apply service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ApplyTemplateController{service: service, logger: logger}
+}
+
+func (c *ApplyTemplateController) Apply(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var request viewsDto.ApplyViewTemplateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+	request.ID = id
+
+	if len(request.Data.SearchKeywordURLIDs) == 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "search_keyword_url_ids is required"})
+	}
+	for _, skuID := range request.Data.SearchKeywordURLIDs {
+		if skuID <= 0 {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid search_keyword_url_id"})
+		}
+	}
+
+	resp, err := c.service.ApplyTemplate(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("view template apply failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
@@ -0,0 +1,65 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+type BatchPageCountsController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewBatchPageCountsController(service views.Service, logger *slog.Logger) *BatchPageCountsController {
+	if service == nil {
+		panic("view batch page counts service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &BatchPageCountsController{service: service, logger: logger}
+}
+
+func (c *BatchPageCountsController) PageCounts(ctx *fiber.Ctx) error {
+	var request viewsDto.BatchViewPageCountsRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+
+	if err := validateBatchPageCountsRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.BatchPageCounts(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("view batch page counts failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validateBatchPageCountsRequest(req viewsDto.BatchViewPageCountsRequest) error {
+	if len(req.Data.ViewIDs) == 0 {
+		return errors.New("view_ids is required")
+	}
+	if len(req.Data.ViewIDs) > maxPageCountViews {
+		return fmt.Errorf("at most %d view_ids allowed", maxPageCountViews)
+	}
+	for _, id := range req.Data.ViewIDs {
+		if id <= 0 {
+			return fmt.Errorf("invalid view_id: %d", id)
+		}
+	}
+	return validatePageCountSessions(req.Data.CrawlingSessionIDs)
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/filterconfig"
 	"sitecrawler/newgo/internal/services/views"
+	"sitecrawler/newgo/internal/viewsettings"
 )
 
 type CreateController struct {
@@ -31,6 +33,13 @@ func (c *CreateController) Create(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	if err := viewsettings.Validate(request.Data.Columns, request.Data.Sort, request.Data.Direction, request.Data.PageSize); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := filterconfig.Validate(request.Data.FilterConfig); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	resp, err := c.service.Create(ctx.Context(), request)
 	if err != nil {
 		c.logger.Error("view create failed", "error", err)
@@ -0,0 +1,58 @@
+package views
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+type CreateTemplateController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewCreateTemplateController(service views.Service, logger *slog.Logger) *CreateTemplateController {
+	if service == nil {
+		panic("view template create service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CreateTemplateController{service: service, logger: logger}
+}
+
+func (c *CreateTemplateController) Create(ctx *fiber.Ctx) error {
+	var request viewsDto.CreateViewTemplateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+
+	if err := validateCreateViewTemplateRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.CreateTemplate(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("view template create failed", "error", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validateCreateViewTemplateRequest(req viewsDto.CreateViewTemplateRequest) error {
+	if strings.TrimSpace(req.Data.Name) == "" {
+		return errors.New("name is required")
+	}
+	return filterconfig.Validate(req.Data.FilterConfig)
+}
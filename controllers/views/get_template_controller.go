@@ -0,0 +1,45 @@
+package views
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+type GetTemplateController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewGetTemplateController(service views.Service, logger *slog.Logger) *GetTemplateController {
+	if service == nil {
+		panic("view template get service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &GetTemplateController{service: service, logger: logger}
+}
+
+func (c *GetTemplateController) Get(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.GetTemplate(ctx.Context(), viewsDto.GetViewTemplateRequest{ID: id})
+	if err != nil {
+		c.logger.Error("view template get failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
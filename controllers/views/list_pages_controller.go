@@ -0,0 +1,76 @@
+package views
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/services/views"
+	"sitecrawler/newgo/internal/viewsettings"
+)
+
+type ListPagesController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewListPagesController(service views.Service, logger *slog.Logger) *ListPagesController {
+	if service == nil {
+		panic("view pages service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ListPagesController{service: service, logger: logger}
+}
+
+func (c *ListPagesController) List(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	sessionID, err := strconv.ParseInt(ctx.Query("crawling_session_id"), 10, 64)
+	if err != nil || sessionID <= 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "crawling_session_id is required"})
+	}
+
+	var filters []map[string]any
+	if rawFilters := ctx.Query("filters"); rawFilters != "" {
+		if err := json.Unmarshal([]byte(rawFilters), &filters); err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid filters"})
+		}
+	}
+
+	direction := strings.ToLower(ctx.Query("direction"))
+	if err := viewsettings.ValidateDirection(direction); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	pageLimit, _ := strconv.Atoi(ctx.Query("page_limit"))
+
+	resp, err := c.service.ListPages(ctx.Context(), viewsDto.ListViewPagesRequest{
+		ViewID:    id,
+		SessionID: sessionID,
+		Filters:   filters,
+		Sort:      ctx.Query("sort"),
+		Direction: direction,
+		Page:      page,
+		PageLimit: pageLimit,
+	})
+	if err != nil {
+		c.logger.Error("view pages list failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
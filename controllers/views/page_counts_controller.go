@@ -0,0 +1,94 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+// maxPageCountSessions and maxPageCountViews bound one page counts request.
+const (
+	maxPageCountSessions = 50
+	maxPageCountViews    = 50
+)
+
+type PageCountsController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewPageCountsController(service views.Service, logger *slog.Logger) *PageCountsController {
+	if service == nil {
+		panic("view page counts service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PageCountsController{service: service, logger: logger}
+}
+
+func (c *PageCountsController) PageCounts(ctx *fiber.Ctx) error {
+	viewID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || viewID <= 0 {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid view id"})
+	}
+
+	sessionIDs, err := parseSessionIDs(ctx.Query("crawling_session_ids"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.PageCounts(ctx.Context(), viewsDto.ViewPageCountsRequest{
+		ViewID:     viewID,
+		SessionIDs: sessionIDs,
+	})
+	if err != nil {
+		c.logger.Error("view page counts failed", "error", err, "id", viewID)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+// parseSessionIDs parses a comma separated crawling_session_ids list.
+func parseSessionIDs(raw string) ([]int64, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, errors.New("crawling_session_ids is required")
+	}
+	parts := strings.Split(raw, ",")
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid crawling_session_id: %s", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, validatePageCountSessions(ids)
+}
+
+func validatePageCountSessions(ids []int64) error {
+	if len(ids) == 0 {
+		return errors.New("crawling_session_ids is required")
+	}
+	if len(ids) > maxPageCountSessions {
+		return fmt.Errorf("at most %d crawling_session_ids allowed", maxPageCountSessions)
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("invalid crawling_session_id: %d", id)
+		}
+	}
+	return nil
+}
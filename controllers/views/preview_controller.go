@@ -0,0 +1,63 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+const maxPreviewLimit = 100
+
+type PreviewController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewPreviewController(service views.Service, logger *slog.Logger) *PreviewController {
+	if service == nil {
+		panic("view preview service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PreviewController{service: service, logger: logger}
+}
+
+func (c *PreviewController) Preview(ctx *fiber.Ctx) error {
+	var request viewsDto.PreviewViewRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+
+	if err := validatePreviewViewRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.Preview(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("view preview failed", "error", err, "crawling_session_id", request.Data.CrawlingSessionID)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validatePreviewViewRequest(req viewsDto.PreviewViewRequest) error {
+	if req.Data.CrawlingSessionID <= 0 {
+		return errors.New("crawling_session_id is required")
+	}
+	if req.Data.Limit < 0 || req.Data.Limit > maxPreviewLimit {
+		return fmt.Errorf("limit must be between 1 and %d", maxPreviewLimit)
+	}
+	return filterconfig.Validate(req.Data.FilterConfig)
+}
@@ -0,0 +1,54 @@
+package views
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+type PropagateTemplateController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewPropagateTemplateController(service views.Service, logger *slog.Logger) *PropagateTemplateController {
+	if service == nil {
+		panic("view template propagate service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PropagateTemplateController{service: service, logger: logger}
+}
+
+// Propagate accepts an empty body, which propagates to every SKU.
+func (c *PropagateTemplateController) Propagate(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var request viewsDto.PropagateViewTemplateRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&request); err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+		}
+	}
+	request.ID = id
+
+	resp, err := c.service.PropagateTemplate(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("view template propagate failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
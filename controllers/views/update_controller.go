@@ -8,7 +8,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/filterconfig"
 	"sitecrawler/newgo/internal/services/views"
+	"sitecrawler/newgo/internal/viewsettings"
 )
 
 type UpdateController struct {
@@ -38,6 +40,31 @@ func (c *UpdateController) Update(ctx *fiber.Ctx) error {
 	}
 	request.ID = id
 
+	data := request.Data
+	var columns []string
+	var sort, direction string
+	var pageSize int
+	if data.Columns != nil {
+		columns = *data.Columns
+	}
+	if data.Sort != nil {
+		sort = *data.Sort
+	}
+	if data.Direction != nil {
+		direction = *data.Direction
+	}
+	if data.PageSize != nil {
+		pageSize = *data.PageSize
+	}
+	if err := viewsettings.Validate(columns, sort, direction, pageSize); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if data.FilterConfig != nil {
+		if err := filterconfig.Validate(*data.FilterConfig); err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
 	resp, err := c.service.Update(ctx.Context(), request)
 	if err != nil {
 		c.logger.Error("view update failed", "error", err)
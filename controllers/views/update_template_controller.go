@@ -0,0 +1,68 @@
+package views
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/services/views"
+)
+
+type UpdateTemplateController struct {
+	service views.Service
+	logger  *slog.Logger
+}
+
+func NewUpdateTemplateController(service views.Service, logger *slog.Logger) *UpdateTemplateController {
+	if service == nil {
+		panic("view template update service required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UpdateTemplateController{service: service, logger: logger}
+}
+
+func (c *UpdateTemplateController) Update(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var request viewsDto.UpdateViewTemplateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid json payload"})
+	}
+	request.ID = id
+
+	if err := validateUpdateViewTemplateRequest(request); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := c.service.UpdateTemplate(ctx.Context(), request)
+	if err != nil {
+		c.logger.Error("view template update failed", "error", err, "id", id)
+		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	if resp.Body == nil {
+		return ctx.Status(resp.StatusCode).JSON(fiber.Map{"error": resp.Message})
+	}
+	return ctx.Status(resp.StatusCode).JSON(resp.Body)
+}
+
+func validateUpdateViewTemplateRequest(req viewsDto.UpdateViewTemplateRequest) error {
+	if req.Data.Name != nil && strings.TrimSpace(*req.Data.Name) == "" {
+		return errors.New("name cannot be empty")
+	}
+	if req.Data.FilterConfig != nil {
+		return filterconfig.Validate(*req.Data.FilterConfig)
+	}
+	return nil
+}
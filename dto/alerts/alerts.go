@@ -0,0 +1,78 @@
+package alerts
+
+import "sitecrawler/newgo/models"
+
+type ListAlertRulesRequest struct {
+	SearchKeywordURLID int64 `json:"search_keyword_url_id"`
+}
+
+type AlertRuleResponse struct {
+	Data models.AlertRule `json:"data"`
+}
+
+type AlertRulesResponse struct {
+	Data []models.AlertRule `json:"data"`
+}
+
+type CreateAlertRuleRequest struct {
+	Data CreateAlertRuleData `json:"data"`
+}
+
+type CreateAlertRuleData struct {
+	SearchKeywordURLID int64    `json:"search_keyword_url_id"`
+	Name               string   `json:"name"`
+	AuditCheckID       *int64   `json:"audit_check_id"`
+	Metric             string   `json:"metric"`
+	Mode               string   `json:"mode"`
+	Operator           string   `json:"operator"`
+	Threshold          float64  `json:"threshold"`
+	WebhookURL         string   `json:"webhook_url"`
+	Emails             []string `json:"emails"`
+	Enabled            *bool    `json:"enabled"`
+}
+
+type GetAlertRuleRequest struct {
+	ID int64 `json:"id"`
+}
+
+type UpdateAlertRuleRequest struct {
+	ID   int64               `json:"id"`
+	Data UpdateAlertRuleData `json:"data"`
+}
+
+// UpdateAlertRuleData replaces the fields that are present. Setting
+// audit_check_id clears metric and vice versa.
+type UpdateAlertRuleData struct {
+	Name         *string   `json:"name"`
+	AuditCheckID *int64    `json:"audit_check_id"`
+	Metric       *string   `json:"metric"`
+	Mode         *string   `json:"mode"`
+	Operator     *string   `json:"operator"`
+	Threshold    *float64  `json:"threshold"`
+	WebhookURL   *string   `json:"webhook_url"`
+	Emails       *[]string `json:"emails"`
+	Enabled      *bool     `json:"enabled"`
+}
+
+type DeleteAlertRuleRequest struct {
+	ID int64 `json:"id"`
+}
+
+type DeleteAlertRuleResponse struct {
+	Data DeleteAlertRuleData `json:"data"`
+}
+
+type DeleteAlertRuleData struct {
+	ID int64 `json:"id"`
+}
+
+type ListAlertsRequest struct {
+	SearchKeywordURLID int64 `json:"search_keyword_url_id"`
+	CrawlingSessionID  int64 `json:"crawling_session_id"`
+	RuleID             int64 `json:"rule_id"`
+	Limit              int   `json:"limit"`
+}
+
+type AlertsResponse struct {
+	Data []models.Alert `json:"data"`
+}
@@ -1,6 +1,10 @@
 package audits
 
-import "sitecrawler/newgo/models"
+import (
+	"time"
+
+	"sitecrawler/newgo/models"
+)
 
 type ListAuditChecksRequest struct {
 	SearchKeywordURLID int64 `json:"search_keyword_url_id"`
@@ -22,6 +26,8 @@ type CreateAuditCheckData struct {
 	SearchKeywordURLID int64          `json:"search_keyword_url_id"`
 	Name               string         `json:"name"`
 	Category           string         `json:"category"`
+	Severity           string         `json:"severity"`
+	Weight             float64        `json:"weight"`
 	FilterConfig       map[string]any `json:"filter_config"`
 }
 
@@ -37,6 +43,8 @@ type UpdateAuditCheckRequest struct {
 type UpdateAuditCheckData struct {
 	Name         *string         `json:"name"`
 	Category     *string         `json:"category"`
+	Severity     *string         `json:"severity"`
+	Weight       *float64        `json:"weight"`
 	FilterConfig *map[string]any `json:"filter_config"`
 }
 
@@ -51,3 +59,160 @@ type DeleteAuditCheckResponse struct {
 type DeleteAuditCheckData struct {
 	ID int64 `json:"id"`
 }
+
+type InstallDefaultsRequest struct {
+	SearchKeywordURLID int64 `json:"search_keyword_url_id"`
+}
+
+type InstallDefaultsResponse struct {
+	Data InstallDefaultsData `json:"data"`
+}
+
+// InstallDefaultsData lists catalogue keys by what happened to them.
+// Skipped holds outdated checks that were kept because a user edited them.
+type InstallDefaultsData struct {
+	CatalogVersion int                 `json:"catalog_version"`
+	Installed      []string            `json:"installed"`
+	Upgraded       []string            `json:"upgraded"`
+	Skipped        []string            `json:"skipped"`
+	Unchanged      []string            `json:"unchanged"`
+	Checks         []models.AuditCheck `json:"checks"`
+}
+
+type AuditCheckHistoryRequest struct {
+	ID     int64      `json:"id"`
+	From   *time.Time `json:"from"`
+	To     *time.Time `json:"to"`
+	Limit  int        `json:"limit"`
+	Points int        `json:"points"`
+}
+
+type AuditCheckHistoryResponse struct {
+	Data AuditCheckHistoryData `json:"data"`
+}
+
+type AuditCheckHistoryData struct {
+	CheckID int64                 `json:"check_id"`
+	Name    string                `json:"name"`
+	Points  []models.HistoryPoint `json:"points"`
+}
+
+type PreviewAuditCheckRequest struct {
+	Data PreviewAuditCheckData `json:"data"`
+}
+
+type PreviewAuditCheckData struct {
+	CrawlingSessionID int64          `json:"crawling_session_id"`
+	FilterConfig      map[string]any `json:"filter_config"`
+	Limit             int            `json:"limit"`
+}
+
+type AuditCheckPreviewResponse struct {
+	Data AuditCheckPreviewData `json:"data"`
+}
+
+type AuditCheckPreviewData struct {
+	PageCount int           `json:"page_count"`
+	Pages     []models.Page `json:"pages"`
+}
+
+type AuditCheckTemplateResponse struct {
+	Data models.AuditCheckTemplate `json:"data"`
+}
+
+type AuditCheckTemplatesResponse struct {
+	Data []models.AuditCheckTemplate `json:"data"`
+}
+
+type CreateAuditCheckTemplateRequest struct {
+	Data CreateAuditCheckTemplateData `json:"data"`
+}
+
+type CreateAuditCheckTemplateData struct {
+	Name         string         `json:"name"`
+	Category     string         `json:"category"`
+	Severity     string         `json:"severity"`
+	Weight       float64        `json:"weight"`
+	FilterConfig map[string]any `json:"filter_config"`
+}
+
+type GetAuditCheckTemplateRequest struct {
+	ID int64 `json:"id"`
+}
+
+type UpdateAuditCheckTemplateRequest struct {
+	ID   int64                        `json:"id"`
+	Data UpdateAuditCheckTemplateData `json:"data"`
+}
+
+type UpdateAuditCheckTemplateData struct {
+	Name         *string         `json:"name"`
+	Category     *string         `json:"category"`
+	Severity     *string         `json:"severity"`
+	Weight       *float64        `json:"weight"`
+	FilterConfig *map[string]any `json:"filter_config"`
+}
+
+type DeleteAuditCheckTemplateRequest struct {
+	ID int64 `json:"id"`
+}
+
+type DeleteAuditCheckTemplateResponse struct {
+	Data DeleteAuditCheckTemplateData `json:"data"`
+}
+
+// DeleteAuditCheckTemplateData reports how many applied checks were
+// unlinked; they keep their current definition.
+type DeleteAuditCheckTemplateData struct {
+	ID       int64 `json:"id"`
+	Unlinked int   `json:"unlinked"`
+}
+
+type ApplyAuditCheckTemplateRequest struct {
+	ID   int64                       `json:"id"`
+	Data ApplyAuditCheckTemplateData `json:"data"`
+}
+
+type ApplyAuditCheckTemplateData struct {
+	SearchKeywordURLIDs []int64 `json:"search_keyword_url_ids"`
+}
+
+type ApplyAuditCheckTemplateResponse struct {
+	Data ApplyAuditCheckTemplateResult `json:"data"`
+}
+
+// ApplyAuditCheckTemplateResult lists SKUs by outcome. Existing holds SKUs
+// that already had a copy of the template, which is left as it is.
+type ApplyAuditCheckTemplateResult struct {
+	TemplateID int64               `json:"template_id"`
+	Version    int                 `json:"version"`
+	Applied    []int64             `json:"applied"`
+	Existing   []int64             `json:"existing"`
+	Checks     []models.AuditCheck `json:"checks"`
+}
+
+// PropagateAuditCheckTemplateRequest brings applied copies up to the
+// template's version. An empty SKU list means every SKU.
+type PropagateAuditCheckTemplateRequest struct {
+	ID   int64                           `json:"id"`
+	Data PropagateAuditCheckTemplateData `json:"data"`
+}
+
+type PropagateAuditCheckTemplateData struct {
+	SearchKeywordURLIDs []int64 `json:"search_keyword_url_ids"`
+}
+
+type PropagateAuditCheckTemplateResponse struct {
+	Data PropagateAuditCheckTemplateResult `json:"data"`
+}
+
+// PropagateAuditCheckTemplateResult lists applied check ids by outcome.
+// Skipped holds outdated copies that were kept because a user edited them.
+type PropagateAuditCheckTemplateResult struct {
+	TemplateID int64               `json:"template_id"`
+	Version    int                 `json:"version"`
+	Updated    []int64             `json:"updated"`
+	Skipped    []int64             `json:"skipped"`
+	Unchanged  []int64             `json:"unchanged"`
+	Checks     []models.AuditCheck `json:"checks"`
+}
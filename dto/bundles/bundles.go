@@ -0,0 +1,100 @@
+package bundles
+
+import "time"
+
+// BundleVersion is the bundle format written by export and accepted by import.
+const BundleVersion = 1
+
+// Import conflict modes, applied when a bundle item has the same name as an
+// existing audit check or view of the target SKU.
+const (
+	ImportModeSkip      = "skip"
+	ImportModeOverwrite = "overwrite"
+	ImportModeRename    = "rename"
+)
+
+// Import actions reported per bundle item.
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionRename    = "rename"
+	ImportActionSkip      = "skip"
+	ImportActionUnchanged = "unchanged"
+)
+
+// Bundle kinds.
+const (
+	KindAuditCheck = "audit_check"
+	KindView       = "view"
+)
+
+// Bundle is a portable copy of the audit checks and views of one SKU.
+type Bundle struct {
+	Version            int                `json:"version"`
+	ExportedAt         time.Time          `json:"exported_at"`
+	SearchKeywordURLID int64              `json:"search_keyword_url_id"`
+	AuditChecks        []BundleAuditCheck `json:"audit_checks"`
+	Views              []BundleView       `json:"views"`
+}
+
+type BundleAuditCheck struct {
+	Name           string         `json:"name"`
+	Category       string         `json:"category"`
+	Severity       string         `json:"severity,omitempty"`
+	Weight         float64        `json:"weight,omitempty"`
+	FilterConfig   map[string]any `json:"filter_config"`
+	BuiltinKey     string         `json:"builtin_key,omitempty"`
+	BuiltinVersion int            `json:"builtin_version,omitempty"`
+	Customized     bool           `json:"customized,omitempty"`
+}
+
+type BundleView struct {
+	Name                string         `json:"name"`
+	FilterConfig        map[string]any `json:"filter_config"`
+	Columns             []string       `json:"columns,omitempty"`
+	Sort                string         `json:"sort,omitempty"`
+	Direction           string         `json:"direction,omitempty"`
+	PageSize            int            `json:"page_size,omitempty"`
+	CompareWithPrevious bool           `json:"compare_with_previous,omitempty"`
+}
+
+type ExportConfigRequest struct {
+	SearchKeywordURLID int64 `json:"search_keyword_url_id"`
+}
+
+type ExportConfigResponse struct {
+	Data Bundle `json:"data"`
+}
+
+type ImportConfigRequest struct {
+	SearchKeywordURLID int64  `json:"search_keyword_url_id"`
+	Mode               string `json:"mode"`
+	DryRun             bool   `json:"dry_run"`
+	Bundle             Bundle `json:"bundle"`
+}
+
+type ImportConfigResponse struct {
+	Data ImportConfigData `json:"data"`
+}
+
+// ImportConfigData describes what an import did, or would do on a dry run.
+type ImportConfigData struct {
+	Mode    string         `json:"mode"`
+	DryRun  bool           `json:"dry_run"`
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Skipped int            `json:"skipped"`
+	Changes []ImportChange `json:"changes"`
+}
+
+// ImportChange is the outcome for one bundle item. ID is the existing row
+// for update, skip and unchanged, or the new row after a real create.
+// Fields lists the properties that differ from the existing row.
+type ImportChange struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Action  string   `json:"action"`
+	NewName string   `json:"new_name,omitempty"`
+	ID      int64    `json:"id,omitempty"`
+	Fields  []string `json:"fields,omitempty"`
+}
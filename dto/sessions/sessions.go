@@ -25,6 +25,7 @@ type GetCrawlingSessionResponse = CrawlingSessionResponse
 
 type ListCrawlingSessionPagesRequest struct {
 	SessionID int64            `json:"session_id"`
+	ViewID    int64            `json:"view_id"`
 	Filters   []map[string]any `json:"filters"`
 	Sort      string           `json:"sort"`
 	Direction string           `json:"direction"`
@@ -37,6 +38,7 @@ type CrawlingSessionPagesResponse struct {
 }
 
 type CrawlingSessionPagesData struct {
+	Columns    []string      `json:"columns,omitempty"`
 	Pages      []models.Page `json:"pages"`
 	PagesTotal int           `json:"pages_total"`
 }
@@ -44,6 +46,7 @@ type CrawlingSessionPagesData struct {
 type ListCrawlingSessionChecksRequest struct {
 	SessionID           int64            `json:"session_id"`
 	ComparisonSessionID *int64           `json:"comparison_session_id"`
+	ViewID              int64            `json:"view_id"`
 	ViewFilters         []map[string]any `json:"view_filters"`
 	PageLimitPerCheck   int              `json:"page_limit_per_check"`
 }
@@ -55,3 +58,88 @@ type CrawlingSessionChecksResponse struct {
 type CrawlingSessionChecksData struct {
 	Checks []models.CheckWithPages `json:"checks"`
 }
+
+// Duplicate kinds accepted by the duplicates endpoint.
+const (
+	DuplicateKindTitle       = "title"
+	DuplicateKindDescription = "description"
+	DuplicateKindContent     = "content"
+)
+
+type ListDuplicatesRequest struct {
+	SessionID int64  `json:"session_id"`
+	Kind      string `json:"kind"`
+}
+
+type DuplicatesResponse struct {
+	Data DuplicatesData `json:"data"`
+}
+
+type DuplicatesData struct {
+	Kind     string                    `json:"kind"`
+	Clusters []models.DuplicateCluster `json:"clusters"`
+}
+
+type ListHreflangRequest struct {
+	SessionID int64 `json:"session_id"`
+}
+
+type HreflangResponse struct {
+	Data HreflangData `json:"data"`
+}
+
+type HreflangData struct {
+	Checks []models.HreflangCheck `json:"checks"`
+}
+
+type SessionDiffRequest struct {
+	SessionID        int64    `json:"session_id"`
+	AgainstSessionID *int64   `json:"against_session_id"`
+	Changes          []string `json:"changes"`
+	Page             int      `json:"page"`
+	PageLimit        int      `json:"page_limit"`
+}
+
+type SessionDiffResponse struct {
+	Data SessionDiffData `json:"data"`
+}
+
+// SessionDiffData holds one page of each requested change list. Lists that
+// were not requested are omitted.
+type SessionDiffData struct {
+	CrawlingSessionID int64           `json:"crawling_session_id"`
+	AgainstSessionID  int64           `json:"against_session_id"`
+	Added             *PageChangeList `json:"added,omitempty"`
+	Removed           *PageChangeList `json:"removed,omitempty"`
+	Changed           *PageChangeList `json:"changed,omitempty"`
+}
+
+type PageChangeList struct {
+	Total int                 `json:"total"`
+	Pages []models.PageChange `json:"pages"`
+}
+
+type ListAuditResultsRequest struct {
+	SessionID           int64  `json:"session_id"`
+	ComparisonSessionID *int64 `json:"comparison_session_id"`
+	Page                int    `json:"page"`
+	PageLimit           int    `json:"page_limit"`
+}
+
+// Sources of the counts returned by the audit results endpoint.
+const (
+	AuditResultsSourceSnapshot = "snapshot"
+	AuditResultsSourceLive     = "live"
+)
+
+type AuditResultsResponse struct {
+	Data AuditResultsData `json:"data"`
+}
+
+type AuditResultsData struct {
+	Page                int                       `json:"page"`
+	PageLimit           int                       `json:"page_limit"`
+	ComparisonSessionID *int64                    `json:"comparison_session_id,omitempty"`
+	Source              string                    `json:"source"`
+	Checks              []models.AuditCheckResult `json:"checks"`
+}
@@ -1,6 +1,10 @@
 package stats
 
-import "sitecrawler/newgo/models"
+import (
+	"time"
+
+	"sitecrawler/newgo/models"
+)
 
 type PageDetailsRequest struct {
 	PageID             int64 `json:"page_id"`
@@ -20,11 +24,33 @@ type PageDetailsData struct {
 
 type StatsRequest struct {
 	CrawlingSessionID    int64            `json:"crawling_session_id"`
+	ViewID               int64            `json:"view_id"`
 	Filters              []map[string]any `json:"filters"`
 	Prefilters           []map[string]any `json:"prefilters"`
 	ComparisonCrawlingID *int64           `json:"comparison_crawling_session_id"`
+	GroupBy              string           `json:"group_by"`
+	GroupSegments        int              `json:"group_segments"`
+	GroupPattern         string           `json:"group_pattern"`
 }
 
 type StatsResponse struct {
 	Data map[string]any `json:"data"`
 }
+
+type MetricHistoryRequest struct {
+	SearchKeywordURLID int64      `json:"search_keyword_url_id"`
+	Metric             string     `json:"metric"`
+	From               *time.Time `json:"from"`
+	To                 *time.Time `json:"to"`
+	Limit              int        `json:"limit"`
+	Points             int        `json:"points"`
+}
+
+type MetricHistoryResponse struct {
+	Data MetricHistoryData `json:"data"`
+}
+
+type MetricHistoryData struct {
+	Metric string                `json:"metric"`
+	Points []models.HistoryPoint `json:"points"`
+}
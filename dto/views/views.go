@@ -19,9 +19,14 @@ type CreateViewRequest struct {
 }
 
 type CreateViewData struct {
-	SearchKeywordURLID int64          `json:"search_keyword_url_id"`
-	Name               string         `json:"name"`
-	FilterConfig       map[string]any `json:"filter_config"`
+	SearchKeywordURLID  int64          `json:"search_keyword_url_id"`
+	Name                string         `json:"name"`
+	FilterConfig        map[string]any `json:"filter_config"`
+	Columns             []string       `json:"columns"`
+	Sort                string         `json:"sort"`
+	Direction           string         `json:"direction"`
+	PageSize            int            `json:"page_size"`
+	CompareWithPrevious bool           `json:"compare_with_previous"`
 }
 
 type GetViewRequest struct {
@@ -34,8 +39,13 @@ type UpdateViewRequest struct {
 }
 
 type UpdateViewData struct {
-	Name         *string         `json:"name"`
-	FilterConfig *map[string]any `json:"filter_config"`
+	Name                *string         `json:"name"`
+	FilterConfig        *map[string]any `json:"filter_config"`
+	Columns             *[]string       `json:"columns"`
+	Sort                *string         `json:"sort"`
+	Direction           *string         `json:"direction"`
+	PageSize            *int            `json:"page_size"`
+	CompareWithPrevious *bool           `json:"compare_with_previous"`
 }
 
 type DeleteViewRequest struct {
@@ -62,3 +72,172 @@ type ViewPageCountResponse struct {
 type ViewPageCountData struct {
 	PageCount int `json:"page_count"`
 }
+
+type ViewPageCountsRequest struct {
+	ViewID     int64   `json:"view_id"`
+	SessionIDs []int64 `json:"crawling_session_ids"`
+}
+
+type ViewPageCountsResponse struct {
+	Data ViewPageCounts `json:"data"`
+}
+
+type BatchViewPageCountsRequest struct {
+	Data BatchViewPageCountsData `json:"data"`
+}
+
+type BatchViewPageCountsData struct {
+	ViewIDs            []int64 `json:"view_ids"`
+	CrawlingSessionIDs []int64 `json:"crawling_session_ids"`
+}
+
+type BatchViewPageCountsResponse struct {
+	Data []ViewPageCounts `json:"data"`
+}
+
+// ViewPageCounts holds a view's page count in each requested session, in
+// the order the sessions were requested.
+type ViewPageCounts struct {
+	ViewID int64              `json:"view_id"`
+	Counts []SessionPageCount `json:"counts"`
+}
+
+type SessionPageCount struct {
+	CrawlingSessionID int64 `json:"crawling_session_id"`
+	PageCount         int   `json:"page_count"`
+}
+
+type ListViewPagesRequest struct {
+	ViewID    int64            `json:"view_id"`
+	SessionID int64            `json:"crawling_session_id"`
+	Filters   []map[string]any `json:"filters"`
+	Sort      string           `json:"sort"`
+	Direction string           `json:"direction"`
+	Page      int              `json:"page"`
+	PageLimit int              `json:"page_limit"`
+}
+
+type ViewPagesResponse struct {
+	Data ViewPagesData `json:"data"`
+}
+
+type ViewPagesData struct {
+	ViewID     int64         `json:"view_id"`
+	Columns    []string      `json:"columns"`
+	Pages      []models.Page `json:"pages"`
+	PagesTotal int           `json:"pages_total"`
+}
+
+type PreviewViewRequest struct {
+	Data PreviewViewData `json:"data"`
+}
+
+type PreviewViewData struct {
+	CrawlingSessionID int64          `json:"crawling_session_id"`
+	FilterConfig      map[string]any `json:"filter_config"`
+	Limit             int            `json:"limit"`
+}
+
+type ViewPreviewResponse struct {
+	Data ViewPreviewData `json:"data"`
+}
+
+type ViewPreviewData struct {
+	PageCount int           `json:"page_count"`
+	Pages     []models.Page `json:"pages"`
+}
+
+type ViewTemplateResponse struct {
+	Data models.ViewTemplate `json:"data"`
+}
+
+type ViewTemplatesResponse struct {
+	Data []models.ViewTemplate `json:"data"`
+}
+
+type CreateViewTemplateRequest struct {
+	Data CreateViewTemplateData `json:"data"`
+}
+
+type CreateViewTemplateData struct {
+	Name         string         `json:"name"`
+	FilterConfig map[string]any `json:"filter_config"`
+}
+
+type GetViewTemplateRequest struct {
+	ID int64 `json:"id"`
+}
+
+type UpdateViewTemplateRequest struct {
+	ID   int64                  `json:"id"`
+	Data UpdateViewTemplateData `json:"data"`
+}
+
+type UpdateViewTemplateData struct {
+	Name         *string         `json:"name"`
+	FilterConfig *map[string]any `json:"filter_config"`
+}
+
+type DeleteViewTemplateRequest struct {
+	ID int64 `json:"id"`
+}
+
+type DeleteViewTemplateResponse struct {
+	Data DeleteViewTemplateData `json:"data"`
+}
+
+// DeleteViewTemplateData reports how many applied views were unlinked; they
+// keep their current definition.
+type DeleteViewTemplateData struct {
+	ID       int64 `json:"id"`
+	Unlinked int   `json:"unlinked"`
+}
+
+type ApplyViewTemplateRequest struct {
+	ID   int64                 `json:"id"`
+	Data ApplyViewTemplateData `json:"data"`
+}
+
+type ApplyViewTemplateData struct {
+	SearchKeywordURLIDs []int64 `json:"search_keyword_url_ids"`
+}
+
+type ApplyViewTemplateResponse struct {
+	Data ApplyViewTemplateResult `json:"data"`
+}
+
+// ApplyViewTemplateResult lists SKUs by outcome. Existing holds SKUs that
+// already had a copy of the template, which is left as it is.
+type ApplyViewTemplateResult struct {
+	TemplateID int64         `json:"template_id"`
+	Version    int           `json:"version"`
+	Applied    []int64       `json:"applied"`
+	Existing   []int64       `json:"existing"`
+	Views      []models.View `json:"views"`
+}
+
+// PropagateViewTemplateRequest brings applied copies up to the template's
+// version. An empty SKU list means every SKU.
+type PropagateViewTemplateRequest struct {
+	ID   int64                     `json:"id"`
+	Data PropagateViewTemplateData `json:"data"`
+}
+
+type PropagateViewTemplateData struct {
+	SearchKeywordURLIDs []int64 `json:"search_keyword_url_ids"`
+}
+
+type PropagateViewTemplateResponse struct {
+	Data PropagateViewTemplateResult `json:"data"`
+}
+
+// PropagateViewTemplateResult lists applied view ids by outcome.
+// Skipped holds outdated copies that were kept because a user edited them.
+type PropagateViewTemplateResult struct {
+	TemplateID int64         `json:"template_id"`
+	Version    int           `json:"version"`
+	Updated    []int64       `json:"updated"`
+	Skipped    []int64       `json:"skipped"`
+	Unchanged  []int64       `json:"unchanged"`
+	Views      []models.View `json:"views"`
+}
@@ -0,0 +1,239 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/smtp"
+	"strings"
+	"syscall"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+// Notifier delivers a triggered alert through one channel. Applies reports
+// whether the rule has a destination for the channel at all.
+type Notifier interface {
+	Channel() string
+	Applies(rule models.AlertRule) bool
+	Notify(ctx context.Context, rule models.AlertRule, alert models.Alert) error
+}
+
+// Dispatch sends alert through every notifier that applies to rule and
+// returns one delivery per attempt. A failing notifier does not stop the
+// others.
+func Dispatch(ctx context.Context, notifiers []Notifier, rule models.AlertRule, alert models.Alert) []models.AlertDelivery {
+	deliveries := []models.AlertDelivery{}
+	for _, n := range notifiers {
+		if !n.Applies(rule) {
+			continue
+		}
+		d := models.AlertDelivery{Channel: n.Channel()}
+		if err := n.Notify(ctx, rule, alert); err != nil {
+			d.Error = err.Error()
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
+
+// WebhookPayload is the JSON body posted to a rule's webhook_url.
+type WebhookPayload struct {
+	Rule  models.AlertRule `json:"rule"`
+	Alert models.Alert     `json:"alert"`
+}
+
+// WebhookNotifier posts alerts as JSON to the rule's webhook_url.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier posts with client. Webhook URLs come from API users, so
+// a nil client gets one that refuses to connect to non-public addresses;
+// the check runs on every dial, which covers redirects and DNS rebinding.
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	if client == nil {
+		dialer := &net.Dialer{Timeout: 5 * time.Second, Control: rejectNonPublicAddr}
+		client = &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+		}
+	}
+	return &WebhookNotifier{client: client}
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice but
+// not covered by netip.Addr.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// rejectNonPublicAddr is a net.Dialer Control hook refusing loopback,
+// private, link-local, multicast and unspecified addresses.
+func rejectNonPublicAddr(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("webhook address %s is not public", ip)
+	}
+	return nil
+}
+
+func (n *WebhookNotifier) Channel() string {
+	return "webhook"
+}
+
+func (n *WebhookNotifier) Applies(rule models.AlertRule) bool {
+	return rule.WebhookURL != ""
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, rule models.AlertRule, alert models.Alert) error {
+	body, err := json.Marshal(WebhookPayload{Rule: rule, Alert: alert})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rule.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPConfig configures the SMTP server used for email alerts. Username may
+// be empty for servers that accept unauthenticated mail.
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// SMTP conversations run inside MarkDone, so an unreachable or stalled
+// server must not hold it up for longer than this.
+const (
+	smtpDialTimeout = 5 * time.Second
+	smtpTimeout     = 15 * time.Second
+)
+
+// SMTPNotifier emails alerts to the rule's addresses.
+type SMTPNotifier struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+func (n *SMTPNotifier) Channel() string {
+	return "email"
+}
+
+func (n *SMTPNotifier) Applies(rule models.AlertRule) bool {
+	return len(rule.Emails) > 0
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, rule models.AlertRule, alert models.Alert) error {
+	host, _, err := net.SplitHostPort(n.cfg.Addr)
+	if err != nil {
+		return err
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(rule.Emails, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "[alert] "+rule.Name))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\nCrawling session: %d\r\n", alert.Message, alert.CrawlingSessionID)
+
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp has no context support; bound the whole conversation with a
+	// deadline and close the connection if ctx ends first.
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := n.send(conn, host, rule.Emails, msg.String()); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// send runs the conversation smtp.SendMail would over conn: STARTTLS when
+// offered, AUTH when configured, then the message itself.
+func (n *SMTPNotifier) send(conn net.Conn, host string, to []string, msg string) error {
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.cfg.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.cfg.From); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
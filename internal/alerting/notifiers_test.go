@@ -0,0 +1,205 @@
+package alerting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var got WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(srv.Client())
+	rule := models.AlertRule{ID: 4, Name: "Errors", WebhookURL: srv.URL + "/hook"}
+	alert := models.Alert{RuleID: 4, CrawlingSessionID: 9, Value: 60}
+	if err := n.Notify(context.Background(), rule, alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Rule.ID != 4 || got.Alert.CrawlingSessionID != 9 || got.Alert.Value != 60 {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+
+	rule.WebhookURL = srv.URL + "/fail"
+	if err := n.Notify(context.Background(), rule, alert); err == nil {
+		t.Fatalf("expected error on non-2xx response")
+	}
+}
+
+func TestWebhookNotifierRefusesNonPublicAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(nil)
+	rule := models.AlertRule{Name: "Errors", WebhookURL: srv.URL + "/hook"}
+	if err := n.Notify(context.Background(), rule, models.Alert{}); err == nil || !strings.Contains(err.Error(), "not public") {
+		t.Fatalf("expected the loopback webhook to be refused, got %v", err)
+	}
+
+	for addr, public := range map[string]bool{
+		"93.184.216.34:443":     true,
+		"[2606:4700::1111]:443": true,
+		"127.0.0.1:80":          false,
+		"10.1.2.3:80":           false,
+		"172.16.0.1:80":         false,
+		"192.168.1.1:80":        false,
+		"169.254.169.254:80":    false,
+		"100.64.0.1:80":         false,
+		"0.0.0.0:80":            false,
+		"[::1]:80":              false,
+		"[fe80::1]:80":          false,
+		"[fd00::1]:80":          false,
+		"[::ffff:127.0.0.1]:80": false,
+	} {
+		if err := rejectNonPublicAddr("tcp", addr, nil); (err == nil) != public {
+			t.Errorf("%s: expected public=%v got error %v", addr, public, err)
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, messages := startSMTPStub(t)
+
+	n := NewSMTPNotifier(SMTPConfig{Addr: addr, From: "alerts@example.com"})
+	rule := models.AlertRule{Name: "Errors", Emails: []string{"ops@example.com", "seo@example.com"}}
+	alert := models.Alert{CrawlingSessionID: 9, Message: "Errors: matched pages is 60 (> 50)"}
+	if err := n.Notify(context.Background(), rule, alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := <-messages
+	if msg.from != "alerts@example.com" || len(msg.to) != 2 || msg.to[1] != "seo@example.com" {
+		t.Fatalf("unexpected envelope %+v", msg)
+	}
+	if !strings.Contains(msg.data, "Subject: [alert] Errors") || !strings.Contains(msg.data, "matched pages is 60") {
+		t.Fatalf("unexpected message %q", msg.data)
+	}
+}
+
+func TestSMTPNotifierGivesUpOnStalledServer(t *testing.T) {
+	// The server accepts the connection but never sends its greeting.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		<-done
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	n := NewSMTPNotifier(SMTPConfig{Addr: ln.Addr().String(), From: "alerts@example.com"})
+	start := time.Now()
+	err = n.Notify(ctx, models.AlertRule{Emails: []string{"ops@example.com"}}, models.Alert{})
+	if err == nil {
+		t.Fatal("expected an error from a server that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected Notify to give up with ctx, took %s", elapsed)
+	}
+}
+
+func TestDispatchSkipsChannelsWithoutDestination(t *testing.T) {
+	notifiers := []Notifier{NewWebhookNotifier(nil), NewSMTPNotifier(SMTPConfig{Addr: "127.0.0.1:1", From: "a@example.com"})}
+	rule := models.AlertRule{Emails: []string{"ops@example.com"}}
+
+	deliveries := Dispatch(context.Background(), notifiers, rule, models.Alert{})
+	if len(deliveries) != 1 || deliveries[0].Channel != "email" || deliveries[0].Error == "" {
+		t.Fatalf("expected one failed email delivery, got %+v", deliveries)
+	}
+}
+
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPStub accepts a single SMTP session on a local port and reports the
+// message it received.
+func startSMTPStub(t *testing.T) (string, <-chan smtpMessage) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	out := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+		var msg smtpMessage
+		reply("220 stub ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			switch upper := strings.ToUpper(cmd); {
+			case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+				reply("250 stub")
+			case strings.HasPrefix(upper, "MAIL FROM:"):
+				msg.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+				reply("250 ok")
+			case strings.HasPrefix(upper, "RCPT TO:"):
+				msg.to = append(msg.to, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+				reply("250 ok")
+			case upper == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				msg.data = data.String()
+				reply("250 queued")
+			case upper == "QUIT":
+				reply("221 bye")
+				out <- msg
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), out
+}
@@ -0,0 +1,126 @@
+// Package alerting evaluates alert rules and delivers triggered alerts.
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"sitecrawler/newgo/models"
+)
+
+// Rule modes.
+const (
+	ModeValue  = "value"
+	ModeChange = "change"
+)
+
+// Operators compare the observed value (or change) with the threshold.
+const (
+	OperatorGT  = "gt"
+	OperatorGTE = "gte"
+	OperatorLT  = "lt"
+	OperatorLTE = "lte"
+	OperatorEQ  = "eq"
+	OperatorNEQ = "neq"
+)
+
+var operatorSymbols = map[string]string{
+	OperatorGT:  ">",
+	OperatorGTE: ">=",
+	OperatorLT:  "<",
+	OperatorLTE: "<=",
+	OperatorEQ:  "=",
+	OperatorNEQ: "!=",
+}
+
+// ValidateRule checks the fields a rule needs to be evaluated. Mode defaults
+// to ModeValue when empty.
+func ValidateRule(rule models.AlertRule) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return errors.New("name is required")
+	}
+	// The name goes into the email subject header.
+	if strings.ContainsAny(rule.Name, "\r\n") {
+		return errors.New("name must not contain line breaks")
+	}
+	hasCheck := rule.AuditCheckID != nil && *rule.AuditCheckID != 0
+	hasMetric := strings.TrimSpace(rule.Metric) != ""
+	if hasCheck == hasMetric {
+		return errors.New("exactly one of audit_check_id and metric is required")
+	}
+	if rule.Mode != "" && rule.Mode != ModeValue && rule.Mode != ModeChange {
+		return fmt.Errorf("invalid mode %q: must be %s or %s", rule.Mode, ModeValue, ModeChange)
+	}
+	if _, ok := operatorSymbols[rule.Operator]; !ok {
+		return fmt.Errorf("invalid operator %q", rule.Operator)
+	}
+	if rule.WebhookURL != "" {
+		u, err := url.Parse(rule.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook_url %q", rule.WebhookURL)
+		}
+	}
+	if _, err := NormalizeEmails(rule.Emails); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NormalizeEmails parses every address and returns the bare addresses, so
+// "Ops <ops@example.com>" is stored and mailed as "ops@example.com".
+func NormalizeEmails(emails []string) ([]string, error) {
+	if emails == nil {
+		return nil, nil
+	}
+	out := make([]string, len(emails))
+	for i, raw := range emails {
+		addr, err := mail.ParseAddress(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email %q", raw)
+		}
+		out[i] = addr.Address
+	}
+	return out, nil
+}
+
+// Evaluate reports whether rule fires for value, given the previous
+// session's value when there is one. Change rules never fire without a
+// previous value. observed is the number compared with the threshold.
+func Evaluate(rule models.AlertRule, value float64, previous *float64) (fired bool, observed float64) {
+	observed = value
+	if rule.Mode == ModeChange {
+		if previous == nil {
+			return false, 0
+		}
+		observed = value - *previous
+	}
+
+	switch rule.Operator {
+	case OperatorGT:
+		return observed > rule.Threshold, observed
+	case OperatorGTE:
+		return observed >= rule.Threshold, observed
+	case OperatorLT:
+		return observed < rule.Threshold, observed
+	case OperatorLTE:
+		return observed <= rule.Threshold, observed
+	case OperatorEQ:
+		return observed == rule.Threshold, observed
+	case OperatorNEQ:
+		return observed != rule.Threshold, observed
+	default:
+		return false, observed
+	}
+}
+
+// Describe renders a one-line summary of a triggered rule.
+func Describe(rule models.AlertRule, subject string, observed float64) string {
+	what := subject
+	if rule.Mode == ModeChange {
+		what = "change in " + subject
+	}
+	return fmt.Sprintf("%s: %s is %g (%s %g)", rule.Name, what, observed, operatorSymbols[rule.Operator], rule.Threshold)
+}
@@ -0,0 +1,70 @@
+package alerting
+
+import (
+	"testing"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestValidateRule(t *testing.T) {
+	checkID := int64(3)
+	valid := models.AlertRule{Name: "Errors", AuditCheckID: &checkID, Operator: OperatorGT, Threshold: 50}
+	if err := ValidateRule(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]func(r *models.AlertRule){
+		"missing name":      func(r *models.AlertRule) { r.Name = " " },
+		"name with newline": func(r *models.AlertRule) { r.Name = "Errors\r\nBcc: evil@example.com" },
+		"no target":         func(r *models.AlertRule) { r.AuditCheckID = nil },
+		"both targets":      func(r *models.AlertRule) { r.Metric = "site_health" },
+		"bad mode":          func(r *models.AlertRule) { r.Mode = "delta" },
+		"bad operator":      func(r *models.AlertRule) { r.Operator = "above" },
+		"bad webhook":       func(r *models.AlertRule) { r.WebhookURL = "ftp://example.com" },
+		"bad email address": func(r *models.AlertRule) { r.Emails = []string{"not-an-email"} },
+	}
+	for name, mutate := range cases {
+		rule := valid
+		mutate(&rule)
+		if err := ValidateRule(rule); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestNormalizeEmails(t *testing.T) {
+	got, err := NormalizeEmails([]string{"Ops Team <ops@example.com>", "seo@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "ops@example.com" || got[1] != "seo@example.com" {
+		t.Fatalf("unexpected addresses %v", got)
+	}
+	if _, err := NormalizeEmails([]string{"not-an-email"}); err == nil {
+		t.Fatalf("expected an error for an invalid address")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	prev := 80.0
+	cases := []struct {
+		name     string
+		rule     models.AlertRule
+		value    float64
+		previous *float64
+		fired    bool
+		observed float64
+	}{
+		{name: "value above", rule: models.AlertRule{Operator: OperatorGT, Threshold: 50}, value: 51, fired: true, observed: 51},
+		{name: "value at threshold", rule: models.AlertRule{Operator: OperatorGT, Threshold: 50}, value: 50, observed: 50},
+		{name: "drop by ten", rule: models.AlertRule{Mode: ModeChange, Operator: OperatorLTE, Threshold: -10}, value: 70, previous: &prev, fired: true, observed: -10},
+		{name: "small drop", rule: models.AlertRule{Mode: ModeChange, Operator: OperatorLTE, Threshold: -10}, value: 75, previous: &prev, observed: -5},
+		{name: "change without previous", rule: models.AlertRule{Mode: ModeChange, Operator: OperatorLTE, Threshold: -10}, value: 0},
+	}
+	for _, tc := range cases {
+		fired, observed := Evaluate(tc.rule, tc.value, tc.previous)
+		if fired != tc.fired || observed != tc.observed {
+			t.Fatalf("%s: expected %v/%v got %v/%v", tc.name, tc.fired, tc.observed, fired, observed)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// NearDuplicateDistance is the largest SimHash Hamming distance at which two
+// page bodies are considered near-identical.
+const NearDuplicateDistance = 3
+
+// NormalizeText lower-cases s, drops punctuation and collapses whitespace so
+// that cosmetic differences do not defeat duplicate detection.
+func NormalizeText(s string) string {
+	return strings.Join(words(s), " ")
+}
+
+// ContentHash returns the hex SHA-256 of the normalized text.
+func ContentHash(text string) string {
+	sum := sha256.Sum256([]byte(NormalizeText(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// SimHash returns a 64-bit Charikar fingerprint of text built from word
+// 3-shingles. Similar texts produce fingerprints with a small Hamming distance.
+func SimHash(text string) uint64 {
+	tokens := words(text)
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	if len(tokens) < 3 {
+		add(strings.Join(tokens, " "))
+	}
+	for i := 0; i+3 <= len(tokens); i++ {
+		add(strings.Join(tokens[i:i+3], " "))
+	}
+
+	var out uint64
+	for i, w := range weights {
+		if w > 0 {
+			out |= 1 << uint(i)
+		}
+	}
+	return out
+}
+
+// HammingDistance counts the differing bits between two fingerprints.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// ClusterSimHashes groups fingerprints whose Hamming distance is at most
+// maxDistance, transitively. It returns clusters of two or more indexes into
+// hashes. Zero fingerprints (no content) are never clustered.
+//
+// Candidates are found by splitting fingerprints into maxDistance+1 bands:
+// by the pigeonhole principle two fingerprints within maxDistance bits agree
+// exactly on at least one band, so only pages sharing a band are compared.
+func ClusterSimHashes(hashes []uint64, maxDistance int) [][]int {
+	parent := make([]int, len(hashes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			if ra < rb {
+				parent[rb] = ra
+			} else {
+				parent[ra] = rb
+			}
+		}
+	}
+
+	bands := maxDistance + 1
+	if bands > 64 {
+		bands = 64
+	}
+	width := 64 / bands
+	for band := 0; band < bands; band++ {
+		shift := uint(band * width)
+		mask := uint64(1)<<uint(width) - 1
+		if band == bands-1 {
+			mask = ^uint64(0) >> shift
+		}
+		buckets := map[uint64][]int{}
+		for i, h := range hashes {
+			if h == 0 {
+				continue
+			}
+			key := (h >> shift) & mask
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, members := range buckets {
+			for i := 0; i < len(members); i++ {
+				for j := i + 1; j < len(members); j++ {
+					a, b := members[i], members[j]
+					if find(a) != find(b) && HammingDistance(hashes[a], hashes[b]) <= maxDistance {
+						union(a, b)
+					}
+				}
+			}
+		}
+	}
+
+	groups := map[int][]int{}
+	var roots []int
+	for i, h := range hashes {
+		if h == 0 {
+			continue
+		}
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	var out [][]int
+	for _, root := range roots {
+		if len(groups[root]) > 1 {
+			out = append(out, groups[root])
+		}
+	}
+	return out
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
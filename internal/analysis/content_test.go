@@ -0,0 +1,47 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContentHashIgnoresCosmeticDifferences(t *testing.T) {
+	a := ContentHash("Hello,   World!\nWelcome to our shop.")
+	b := ContentHash("hello world welcome TO our shop")
+	if a != b {
+		t.Fatalf("expected equal hashes, got %s and %s", a, b)
+	}
+	if a == ContentHash("hello world welcome to your shop") {
+		t.Fatalf("expected different hashes for different text")
+	}
+}
+
+func TestSimHashNearDuplicates(t *testing.T) {
+	base := strings.Repeat("the quick brown fox jumps over the lazy dog while the farmer sleeps ", 20)
+	near := base + " today"
+	other := strings.Repeat("completely unrelated article about databases indexes and query planners ", 20)
+
+	if d := HammingDistance(SimHash(base), SimHash(near)); d > NearDuplicateDistance {
+		t.Fatalf("expected near duplicates, distance %d", d)
+	}
+	if d := HammingDistance(SimHash(base), SimHash(other)); d <= NearDuplicateDistance {
+		t.Fatalf("expected distinct texts, distance %d", d)
+	}
+}
+
+func TestClusterSimHashes(t *testing.T) {
+	hashes := []uint64{
+		0xFFFF0000FFFF0000,
+		0xFFFF0000FFFF0001, // 1 bit from [0]
+		0x0000FFFF0000FFFF,
+		0,                  // no content, never clustered
+		0xFFFF0000FFFF0003, // 1 bit from [1], 2 from [0]
+		0x0000FFFF0000FFFF, // identical to [2]
+	}
+	got := ClusterSimHashes(hashes, NearDuplicateDistance)
+	want := [][]int{{0, 1, 4}, {2, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v got %v", want, got)
+	}
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"sitecrawler/newgo/models"
+)
+
+// DiffPages compares the pages of a session with those of an earlier one,
+// matching them by normalized URL. It returns the pages only found in
+// current, those only found in previous, and those whose response code,
+// title, canonical, indexability or content hash differ. Indexability and
+// content hash are compared only when recorded in both sessions. When a
+// session holds several pages with the same normalized URL, the first one
+// is used.
+func DiffPages(current, previous []models.PageSnapshot) (added, removed, changed []models.PageChange) {
+	before := make(map[string]models.PageSnapshot, len(previous))
+	for _, p := range previous {
+		key := diffKey(p.URL)
+		if _, ok := before[key]; !ok {
+			before[key] = p
+		}
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, p := range current {
+		key := diffKey(p.URL)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		old, ok := before[key]
+		if !ok {
+			added = append(added, models.PageChange{Change: models.PageChangeAdded, URL: p.URL, PageID: p.ID})
+			continue
+		}
+		if fields := DiffFields(old, p); len(fields) > 0 {
+			changed = append(changed, models.PageChange{
+				Change:         models.PageChangeChanged,
+				URL:            p.URL,
+				PageID:         p.ID,
+				PreviousPageID: old.ID,
+				Fields:         fields,
+			})
+		}
+	}
+
+	for _, p := range previous {
+		key := diffKey(p.URL)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		removed = append(removed, models.PageChange{Change: models.PageChangeRemoved, URL: p.URL, PreviousPageID: p.ID})
+	}
+	return added, removed, changed
+}
+
+func diffKey(rawURL string) string {
+	if u, err := NormalizeURL("", rawURL); err == nil {
+		return u
+	}
+	return rawURL
+}
+
+// DiffFields lists the fields DiffPages compares that differ between two
+// snapshots of a page.
+func DiffFields(before, after models.PageSnapshot) []models.PageFieldChange {
+	var fields []models.PageFieldChange
+	if before.ResponseCode != after.ResponseCode {
+		fields = append(fields, models.PageFieldChange{Field: "response_code", Before: before.ResponseCode, After: after.ResponseCode})
+	}
+	if before.Title != after.Title {
+		fields = append(fields, models.PageFieldChange{Field: "title", Before: before.Title, After: after.Title})
+	}
+	if before.Canonical != after.Canonical && (before.Canonical == "" || after.Canonical == "" || !SameURL(before.Canonical, after.Canonical)) {
+		fields = append(fields, models.PageFieldChange{Field: "canonical", Before: before.Canonical, After: after.Canonical})
+	}
+	if before.Indexability != "" && after.Indexability != "" && before.Indexability != after.Indexability {
+		fields = append(fields, models.PageFieldChange{Field: "indexability", Before: before.Indexability, After: after.Indexability})
+	}
+	if before.ContentHash != "" && after.ContentHash != "" && before.ContentHash != after.ContentHash {
+		fields = append(fields, models.PageFieldChange{Field: "content_hash", Before: before.ContentHash, After: after.ContentHash})
+	}
+	return fields
+}
@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestDiffPages(t *testing.T) {
+	previous := []models.PageSnapshot{
+		{ID: 1, URL: "https://example.com/", ResponseCode: 200, Title: "Home", ContentHash: "a", Indexability: "indexable"},
+		{ID: 2, URL: "https://example.com/gone", ResponseCode: 200},
+		{ID: 3, URL: "https://Example.com:443/about#team", ResponseCode: 200, Title: "About", Canonical: "https://example.com/about"},
+		{ID: 4, URL: "https://example.com/blog", ResponseCode: 200, Title: "Blog", ContentHash: "b", Indexability: "indexable"},
+	}
+	current := []models.PageSnapshot{
+		{ID: 11, URL: "https://example.com", ResponseCode: 200, Title: "Home", ContentHash: "a", Indexability: "indexable"},
+		{ID: 12, URL: "https://example.com/new", ResponseCode: 200},
+		{ID: 13, URL: "https://example.com/about", ResponseCode: 200, Title: "About", Canonical: "https://EXAMPLE.com/about"},
+		{ID: 14, URL: "https://example.com/blog", ResponseCode: 404, Title: "Not found", ContentHash: "c", Indexability: "non_indexable"},
+		{ID: 15, URL: "https://example.com/blog#comments", ResponseCode: 200},
+	}
+
+	added, removed, changed := DiffPages(current, previous)
+
+	wantAdded := []models.PageChange{{Change: models.PageChangeAdded, URL: "https://example.com/new", PageID: 12}}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Fatalf("unexpected added %+v", added)
+	}
+	wantRemoved := []models.PageChange{{Change: models.PageChangeRemoved, URL: "https://example.com/gone", PreviousPageID: 2}}
+	if !reflect.DeepEqual(removed, wantRemoved) {
+		t.Fatalf("unexpected removed %+v", removed)
+	}
+	wantChanged := []models.PageChange{{
+		Change:         models.PageChangeChanged,
+		URL:            "https://example.com/blog",
+		PageID:         14,
+		PreviousPageID: 4,
+		Fields: []models.PageFieldChange{
+			{Field: "response_code", Before: 200, After: 404},
+			{Field: "title", Before: "Blog", After: "Not found"},
+			{Field: "indexability", Before: "indexable", After: "non_indexable"},
+			{Field: "content_hash", Before: "b", After: "c"},
+		},
+	}}
+	if !reflect.DeepEqual(changed, wantChanged) {
+		t.Fatalf("unexpected changed %+v", changed)
+	}
+}
+
+func TestDiffPagesSkipsUnrecordedSignals(t *testing.T) {
+	previous := []models.PageSnapshot{{ID: 1, URL: "https://example.com/", ContentHash: "a", Indexability: "indexable"}}
+	current := []models.PageSnapshot{{ID: 2, URL: "https://example.com/"}}
+
+	added, removed, changed := DiffPages(current, previous)
+	if len(added) != 0 || len(removed) != 0 || len(changed) != 0 {
+		t.Fatalf("expected no differences, got %+v %+v %+v", added, removed, changed)
+	}
+}
@@ -0,0 +1,304 @@
+package analysis
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"sitecrawler/newgo/models"
+)
+
+// Hreflang issue types.
+const (
+	HreflangInvalidCode        = "invalid_code"
+	HreflangMissingReturnLink  = "missing_return_link"
+	HreflangNon200Target       = "non_200_target"
+	HreflangNonCanonicalTarget = "non_canonical_target"
+	HreflangMissingXDefault    = "missing_x_default"
+)
+
+// HreflangChecks are the built-in hreflang audit checks, in display order.
+var HreflangChecks = []struct {
+	Key  string
+	Type string
+	Name string
+}{
+	{Key: "hreflang_missing_return_link", Type: HreflangMissingReturnLink, Name: "Hreflang missing return link"},
+	{Key: "hreflang_invalid_code", Type: HreflangInvalidCode, Name: "Hreflang invalid language or region code"},
+	{Key: "hreflang_non_200_target", Type: HreflangNon200Target, Name: "Hreflang target is not 200"},
+	{Key: "hreflang_non_canonical_target", Type: HreflangNonCanonicalTarget, Name: "Hreflang target is not canonical"},
+	{Key: "hreflang_missing_x_default", Type: HreflangMissingXDefault, Name: "Hreflang missing x-default"},
+}
+
+// ParseHreflangHTML extracts <link rel="alternate" hreflang="..."> tags from
+// the document head. Hrefs are resolved against pageURL.
+func ParseHreflangHTML(pageURL string, r io.Reader) ([]models.HreflangLink, error) {
+	var out []models.HreflangLink
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return out, nil
+			}
+			return out, z.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "body":
+				// hreflang annotations are only honoured in the head.
+				return out, nil
+			case "link":
+				if !hasAttr {
+					continue
+				}
+				attrs := tokenAttrs(z)
+				if !hasToken(attrs["rel"], "alternate") || attrs["hreflang"] == "" || attrs["href"] == "" {
+					continue
+				}
+				if link, ok := newHreflangLink(pageURL, attrs["hreflang"], attrs["href"], models.HreflangSourceHTML); ok {
+					out = append(out, link)
+				}
+			}
+		}
+	}
+}
+
+// ParseHreflangHeader extracts hreflang alternates from HTTP Link header
+// values such as `<https://example.com/de>; rel="alternate"; hreflang="de"`.
+func ParseHreflangHeader(pageURL string, values []string) []models.HreflangLink {
+	var out []models.HreflangLink
+	for _, value := range values {
+		for _, entry := range splitLinkHeader(value) {
+			start := strings.IndexByte(entry, '<')
+			end := strings.IndexByte(entry, '>')
+			if start < 0 || end < start {
+				continue
+			}
+			href := entry[start+1 : end]
+			params := map[string]string{}
+			for _, param := range strings.Split(entry[end+1:], ";") {
+				key, val, ok := strings.Cut(param, "=")
+				if !ok {
+					continue
+				}
+				params[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(val), `"`)
+			}
+			if !hasToken(params["rel"], "alternate") || params["hreflang"] == "" {
+				continue
+			}
+			if link, ok := newHreflangLink(pageURL, params["hreflang"], href, models.HreflangSourceHeader); ok {
+				out = append(out, link)
+			}
+		}
+	}
+	return out
+}
+
+type sitemapURLSet struct {
+	URLs []struct {
+		Loc   string `xml:"loc"`
+		Links []struct {
+			Rel      string `xml:"rel,attr"`
+			Hreflang string `xml:"hreflang,attr"`
+			Href     string `xml:"href,attr"`
+		} `xml:"http://www.w3.org/1999/xhtml link"`
+	} `xml:"url"`
+}
+
+// ParseHreflangSitemap extracts xhtml:link alternates from a sitemap and
+// returns them keyed by the normalized <loc> they belong to.
+func ParseHreflangSitemap(r io.Reader) (map[string][]models.HreflangLink, error) {
+	var set sitemapURLSet
+	if err := xml.NewDecoder(r).Decode(&set); err != nil {
+		return nil, err
+	}
+	out := map[string][]models.HreflangLink{}
+	for _, u := range set.URLs {
+		loc, err := NormalizeURL("", u.Loc)
+		if err != nil {
+			continue
+		}
+		for _, l := range u.Links {
+			if !hasToken(l.Rel, "alternate") || l.Hreflang == "" {
+				continue
+			}
+			if link, ok := newHreflangLink(loc, l.Hreflang, l.Href, models.HreflangSourceSitemap); ok {
+				out[loc] = append(out[loc], link)
+			}
+		}
+	}
+	return out, nil
+}
+
+// HreflangPage is a crawled page together with the hreflang links declared
+// for it from any source.
+type HreflangPage struct {
+	ID         int64
+	URL        string
+	StatusCode int
+	Canonical  string
+	Links      []models.HreflangLink
+}
+
+// AuditHreflang cross-checks the hreflang annotations of a session. Targets
+// that were not crawled are skipped because their state is unknown.
+func AuditHreflang(pages []HreflangPage) []models.HreflangIssue {
+	byURL := make(map[string]*HreflangPage, len(pages))
+	for i := range pages {
+		if u, err := NormalizeURL("", pages[i].URL); err == nil {
+			byURL[u] = &pages[i]
+		}
+	}
+
+	var issues []models.HreflangIssue
+	for _, page := range pages {
+		if len(page.Links) == 0 {
+			continue
+		}
+		self, err := NormalizeURL("", page.URL)
+		if err != nil {
+			continue
+		}
+		issue := func(kind, lang, target string) {
+			issues = append(issues, models.HreflangIssue{PageID: page.ID, URL: page.URL, Type: kind, Hreflang: lang, Target: target})
+		}
+
+		seen := map[string]bool{}
+		hasXDefault := false
+		for _, link := range page.Links {
+			key := strings.ToLower(link.Hreflang) + " " + link.Href
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if strings.EqualFold(link.Hreflang, "x-default") {
+				hasXDefault = true
+			}
+			if !ValidHreflangCode(link.Hreflang) {
+				issue(HreflangInvalidCode, link.Hreflang, link.Href)
+			}
+
+			target, err := NormalizeURL(page.URL, link.Href)
+			if err != nil || target == self {
+				continue
+			}
+			tp, ok := byURL[target]
+			if !ok {
+				continue
+			}
+			if tp.StatusCode != 200 {
+				issue(HreflangNon200Target, link.Hreflang, link.Href)
+				continue
+			}
+			if tp.Canonical != "" {
+				if canonical, err := NormalizeURL(tp.URL, tp.Canonical); err == nil && canonical != target {
+					issue(HreflangNonCanonicalTarget, link.Hreflang, link.Href)
+					continue
+				}
+			}
+			if !linksBackTo(tp, self) {
+				issue(HreflangMissingReturnLink, link.Hreflang, link.Href)
+			}
+		}
+		if !hasXDefault {
+			issue(HreflangMissingXDefault, "", "")
+		}
+	}
+	return issues
+}
+
+// HreflangIssueTypes returns the distinct issue types of issues, sorted.
+func HreflangIssueTypes(issues []models.HreflangIssue) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, is := range issues {
+		if seen[is.Type] {
+			continue
+		}
+		seen[is.Type] = true
+		out = append(out, is.Type)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// GroupHreflangIssues buckets issues into the built-in checks. Every check
+// is returned, including those without issues.
+func GroupHreflangIssues(issues []models.HreflangIssue) []models.HreflangCheck {
+	out := make([]models.HreflangCheck, 0, len(HreflangChecks))
+	for _, c := range HreflangChecks {
+		check := models.HreflangCheck{Key: c.Key, Name: c.Name, Issues: []models.HreflangIssue{}}
+		for _, is := range issues {
+			if is.Type == c.Type {
+				check.Issues = append(check.Issues, is)
+			}
+		}
+		check.Count = len(check.Issues)
+		out = append(out, check)
+	}
+	return out
+}
+
+func linksBackTo(page *HreflangPage, target string) bool {
+	for _, link := range page.Links {
+		if u, err := NormalizeURL(page.URL, link.Href); err == nil && u == target {
+			return true
+		}
+	}
+	return false
+}
+
+func newHreflangLink(base, lang, href, source string) (models.HreflangLink, bool) {
+	u, err := NormalizeURL(base, href)
+	if err != nil {
+		return models.HreflangLink{}, false
+	}
+	return models.HreflangLink{Hreflang: strings.TrimSpace(lang), Href: u, Source: source}, true
+}
+
+func tokenAttrs(z *html.Tokenizer) map[string]string {
+	attrs := map[string]string{}
+	for {
+		key, val, more := z.TagAttr()
+		attrs[strings.ToLower(string(key))] = string(val)
+		if !more {
+			return attrs
+		}
+	}
+}
+
+func hasToken(list, token string) bool {
+	for _, f := range strings.Fields(strings.ToLower(list)) {
+		if f == token {
+			return true
+		}
+	}
+	return false
+}
+
+// splitLinkHeader splits a Link header on commas that are outside of <...>
+// and quoted strings.
+func splitLinkHeader(value string) []string {
+	var out []string
+	inURL, inQuote := false, false
+	start := 0
+	for i, r := range value {
+		switch {
+		case r == '<' && !inQuote:
+			inURL = true
+		case r == '>' && !inQuote:
+			inURL = false
+		case r == '"' && !inURL:
+			inQuote = !inQuote
+		case r == ',' && !inURL && !inQuote:
+			out = append(out, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, value[start:])
+}
@@ -0,0 +1,67 @@
+package analysis
+
+import "strings"
+
+// iso639_1 lists the two-letter ISO 639-1 language codes accepted by search
+// engines in hreflang annotations.
+var iso639_1 = setOf(`aa ab ae af ak am an ar as av ay az ba be bg bh bi bm bn bo br bs ca ce ch co cr cs cu cv cy
+da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha he hi ho hr ht hu hy hz
+ia id ie ig ii ik io is it iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky la lb lg li ln lo
+lt lu lv mg mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny oc oj om or os pa pi pl ps
+pt qu rm rn ro ru rw sa sc sd se sg si sk sl sm sn so sq sr ss st su sv sw ta te tg th ti tk tl tn
+to tr ts tt tw ty ug uk ur uz ve vi vo wa wo xh yi yo za zh zu`)
+
+// iso3166_1 lists the ISO 3166-1 alpha-2 region codes. Common mistakes such
+// as "UK" (instead of GB) and "EU" are deliberately absent.
+var iso3166_1 = setOf(`AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ
+BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC
+EE EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI
+LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE
+NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC
+SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW
+TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`)
+
+// iso15924 lists the script subtags commonly used in hreflang values.
+var iso15924 = setOf(`Arab Cyrl Deva Grek Hans Hant Hebr Jpan Kore Latn Thai`)
+
+func setOf(list string) map[string]struct{} {
+	out := map[string]struct{}{}
+	for _, v := range strings.Fields(list) {
+		out[v] = struct{}{}
+	}
+	return out
+}
+
+// ValidHreflangCode reports whether code is "x-default" or an ISO 639-1
+// language optionally followed by an ISO 15924 script and/or an ISO 3166-1
+// region, e.g. "en", "en-GB", "zh-Hant-TW". Matching is case-insensitive.
+func ValidHreflangCode(code string) bool {
+	if strings.EqualFold(code, "x-default") {
+		return true
+	}
+	parts := strings.Split(code, "-")
+	if len(parts) > 3 {
+		return false
+	}
+	if _, ok := iso639_1[strings.ToLower(parts[0])]; !ok {
+		return false
+	}
+	rest := parts[1:]
+	if len(rest) > 0 && len(rest[0]) == 4 {
+		script := strings.ToUpper(rest[0][:1]) + strings.ToLower(rest[0][1:])
+		if _, ok := iso15924[script]; !ok {
+			return false
+		}
+		rest = rest[1:]
+	}
+	switch len(rest) {
+	case 0:
+		return true
+	case 1:
+		_, ok := iso3166_1[strings.ToUpper(rest[0])]
+		return ok
+	default:
+		return false
+	}
+}
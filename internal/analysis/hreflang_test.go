@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestValidHreflangCode(t *testing.T) {
+	valid := []string{"en", "en-GB", "EN-us", "x-default", "zh-Hant", "zh-Hant-TW", "pt-br"}
+	invalid := []string{"", "en-UK", "english", "de-EU", "xx", "en-GB-extra", "zh-Abcd"}
+	for _, code := range valid {
+		if !ValidHreflangCode(code) {
+			t.Errorf("expected %q to be valid", code)
+		}
+	}
+	for _, code := range invalid {
+		if ValidHreflangCode(code) {
+			t.Errorf("expected %q to be invalid", code)
+		}
+	}
+}
+
+func TestParseHreflangSources(t *testing.T) {
+	doc := `<html><head>
+		<link rel="alternate" hreflang="de" href="/de/">
+		<link rel="canonical" href="/en/">
+		<link rel="alternate" hreflang="x-default" href="https://example.com/"/>
+	</head><body><link rel="alternate" hreflang="fr" href="/fr/"></body></html>`
+	links, err := ParseHreflangHTML("https://example.com/en/", strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(links) != 2 || links[0].Href != "https://example.com/de/" || links[1].Hreflang != "x-default" {
+		t.Fatalf("unexpected html links: %+v", links)
+	}
+
+	header := ParseHreflangHeader("https://example.com/doc.pdf", []string{
+		`<https://example.com/de/doc.pdf>; rel="alternate"; hreflang="de", <https://example.com/a,b>; rel="preload"`,
+	})
+	if len(header) != 1 || header[0].Hreflang != "de" || header[0].Source != models.HreflangSourceHeader {
+		t.Fatalf("unexpected header links: %+v", header)
+	}
+
+	sitemap := `<?xml version="1.0"?>
+	<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">
+	  <url>
+	    <loc>https://example.com/en/</loc>
+	    <xhtml:link rel="alternate" hreflang="de" href="https://example.com/de/"/>
+	    <xhtml:link rel="alternate" hreflang="en" href="https://example.com/en/"/>
+	  </url>
+	</urlset>`
+	byLoc, err := ParseHreflangSitemap(strings.NewReader(sitemap))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := byLoc["https://example.com/en/"]; len(got) != 2 {
+		t.Fatalf("unexpected sitemap links: %+v", byLoc)
+	}
+}
+
+func TestAuditHreflang(t *testing.T) {
+	link := func(lang, href string) models.HreflangLink {
+		return models.HreflangLink{Hreflang: lang, Href: href, Source: models.HreflangSourceHTML}
+	}
+	pages := []HreflangPage{
+		{ID: 1, URL: "https://example.com/en/", StatusCode: 200, Links: []models.HreflangLink{
+			link("en", "https://example.com/en/"),
+			link("de", "https://example.com/de/"),
+			link("fr", "https://example.com/fr/"),
+			link("es", "https://example.com/es/"),
+			link("en-UK", "https://example.com/uk/"),
+			link("x-default", "https://example.com/en/"),
+		}},
+		// Links back to /en/ but has no x-default.
+		{ID: 2, URL: "https://example.com/de/", StatusCode: 200, Links: []models.HreflangLink{
+			link("de", "https://example.com/de/"),
+			link("en", "https://example.com/en/"),
+		}},
+		// No return link.
+		{ID: 3, URL: "https://example.com/fr/", StatusCode: 200, Links: []models.HreflangLink{
+			link("fr", "https://example.com/fr/"),
+			link("x-default", "https://example.com/fr/"),
+		}},
+		{ID: 4, URL: "https://example.com/es/", StatusCode: 404},
+		{ID: 5, URL: "https://example.com/uk/", StatusCode: 200, Canonical: "https://example.com/en/"},
+	}
+
+	var got []string
+	for _, is := range AuditHreflang(pages) {
+		got = append(got, is.Type+" "+is.URL+" "+is.Hreflang)
+	}
+	sort.Strings(got)
+	want := []string{
+		"invalid_code https://example.com/en/ en-UK",
+		"missing_return_link https://example.com/en/ fr",
+		"missing_x_default https://example.com/de/ ",
+		"non_200_target https://example.com/en/ es",
+		"non_canonical_target https://example.com/en/ en-UK",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected issues:\n%s", strings.Join(got, "\n"))
+	}
+
+	checks := GroupHreflangIssues(AuditHreflang(pages))
+	if len(checks) != len(HreflangChecks) || checks[0].Key != "hreflang_missing_return_link" || checks[0].Count != 1 {
+		t.Fatalf("unexpected checks: %+v", checks)
+	}
+
+	var enIssues []models.HreflangIssue
+	for _, is := range AuditHreflang(pages) {
+		if is.PageID == 1 {
+			enIssues = append(enIssues, is)
+		}
+	}
+	wantTypes := []string{HreflangInvalidCode, HreflangMissingReturnLink, HreflangNon200Target, HreflangNonCanonicalTarget}
+	if got := HreflangIssueTypes(enIssues); !reflect.DeepEqual(got, wantTypes) {
+		t.Fatalf("expected issue types %v got %v", wantTypes, got)
+	}
+}
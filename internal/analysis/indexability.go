@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"strings"
+
+	"sitecrawler/newgo/models"
+)
+
+// Indexability reasons stored in pages.indexability_reason.
+const (
+	ReasonIndexable          = "indexable"
+	ReasonBlockedByRobotsTxt = "blocked_by_robots_txt"
+	ReasonNoResponse         = "no_response"
+	ReasonRedirect           = "redirect"
+	ReasonClientError        = "client_error"
+	ReasonServerError        = "server_error"
+	ReasonNon200             = "non_200_status"
+	ReasonNoindexHeader      = "noindex_header"
+	ReasonNoindexMeta        = "noindex_meta"
+	ReasonCanonicalized      = "canonicalized"
+	ReasonCanonicalNon200    = "canonical_non_200"
+	ReasonCanonicalChain     = "canonical_chain"
+)
+
+// IndexabilitySignals collects what the crawler learned about a page that
+// decides whether search engines may index it.
+type IndexabilitySignals struct {
+	URL        string
+	StatusCode int
+	// RobotsMeta is the content of <meta name="robots"> (and googlebot) tags.
+	RobotsMeta []string
+	// XRobotsTag holds every X-Robots-Tag response header value.
+	XRobotsTag         []string
+	BlockedByRobotsTxt bool
+	// Canonical is the rel=canonical href, possibly relative to URL.
+	Canonical string
+	// CanonicalTarget describes the page the canonical points to, when it
+	// was crawled. It is only consulted when Canonical points elsewhere.
+	CanonicalTarget *CanonicalTarget
+}
+
+// CanonicalTarget is the crawled state of a canonical URL.
+type CanonicalTarget struct {
+	StatusCode int
+	Canonical  string
+}
+
+// EvaluateIndexability combines the signals into a single status and the
+// reason that decided it. Signals are checked in the order a search engine
+// would encounter them: robots.txt, HTTP status, headers, markup, canonical.
+func EvaluateIndexability(s IndexabilitySignals) models.PageIndexability {
+	nonIndexable := func(reason string) models.PageIndexability {
+		return models.PageIndexability{Status: models.IndexabilityNonIndexable, Reason: reason}
+	}
+
+	if s.BlockedByRobotsTxt {
+		return nonIndexable(ReasonBlockedByRobotsTxt)
+	}
+	if s.StatusCode != 200 {
+		return nonIndexable(statusReason(s.StatusCode))
+	}
+	if hasNoindex(s.XRobotsTag) {
+		return nonIndexable(ReasonNoindexHeader)
+	}
+	if hasNoindex(s.RobotsMeta) {
+		return nonIndexable(ReasonNoindexMeta)
+	}
+
+	if s.Canonical != "" {
+		canonical, err := NormalizeURL(s.URL, s.Canonical)
+		if err == nil && !SameURL(canonical, s.URL) {
+			if t := s.CanonicalTarget; t != nil {
+				if t.StatusCode != 200 {
+					return nonIndexable(ReasonCanonicalNon200)
+				}
+				if t.Canonical != "" {
+					next, err := NormalizeURL(canonical, t.Canonical)
+					if err == nil && !SameURL(next, canonical) {
+						return nonIndexable(ReasonCanonicalChain)
+					}
+				}
+			}
+			return nonIndexable(ReasonCanonicalized)
+		}
+	}
+
+	return models.PageIndexability{Status: models.IndexabilityIndexable, Reason: ReasonIndexable}
+}
+
+func statusReason(code int) string {
+	switch {
+	case code == 0:
+		return ReasonNoResponse
+	case code >= 300 && code < 400:
+		return ReasonRedirect
+	case code >= 400 && code < 500:
+		return ReasonClientError
+	case code >= 500:
+		return ReasonServerError
+	default:
+		return ReasonNon200
+	}
+}
+
+// hasNoindex reports whether any robots directive list contains noindex or
+// none. Directives scoped to a user agent ("bingbot: noindex") only count
+// when they target every crawler or Googlebot.
+func hasNoindex(values []string) bool {
+	for _, value := range values {
+		value = strings.ToLower(value)
+		if agent, rest, ok := strings.Cut(value, ":"); ok && !strings.Contains(agent, ",") {
+			agent = strings.TrimSpace(agent)
+			if agent != "googlebot" && agent != "robots" && agent != "*" {
+				continue
+			}
+			value = rest
+		}
+		for _, directive := range strings.Split(value, ",") {
+			switch strings.TrimSpace(directive) {
+			case "noindex", "none":
+				return true
+			}
+		}
+	}
+	return false
+}
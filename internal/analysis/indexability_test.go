@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestEvaluateIndexability(t *testing.T) {
+	tests := []struct {
+		name       string
+		signals    IndexabilitySignals
+		wantStatus string
+		wantReason string
+	}{
+		{
+			name:       "plain 200 page",
+			signals:    IndexabilitySignals{URL: "https://example.com/a", StatusCode: 200},
+			wantStatus: models.IndexabilityIndexable,
+			wantReason: ReasonIndexable,
+		},
+		{
+			name:       "robots.txt wins over everything",
+			signals:    IndexabilitySignals{URL: "https://example.com/a", StatusCode: 404, BlockedByRobotsTxt: true},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonBlockedByRobotsTxt,
+		},
+		{
+			name:       "redirect",
+			signals:    IndexabilitySignals{URL: "https://example.com/a", StatusCode: 301},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonRedirect,
+		},
+		{
+			name:       "server error",
+			signals:    IndexabilitySignals{URL: "https://example.com/a", StatusCode: 503},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonServerError,
+		},
+		{
+			name:       "noindex header",
+			signals:    IndexabilitySignals{URL: "https://example.com/a", StatusCode: 200, XRobotsTag: []string{"noarchive, noindex"}},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonNoindexHeader,
+		},
+		{
+			name:       "noindex header for another bot is ignored",
+			signals:    IndexabilitySignals{URL: "https://example.com/a", StatusCode: 200, XRobotsTag: []string{"bingbot: noindex"}},
+			wantStatus: models.IndexabilityIndexable,
+			wantReason: ReasonIndexable,
+		},
+		{
+			name:       "robots meta none",
+			signals:    IndexabilitySignals{URL: "https://example.com/a", StatusCode: 200, RobotsMeta: []string{"NONE"}},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonNoindexMeta,
+		},
+		{
+			name:       "self canonical",
+			signals:    IndexabilitySignals{URL: "https://Example.com:443/a", StatusCode: 200, Canonical: "/a#top"},
+			wantStatus: models.IndexabilityIndexable,
+			wantReason: ReasonIndexable,
+		},
+		{
+			name:       "canonical elsewhere",
+			signals:    IndexabilitySignals{URL: "https://example.com/a?x=1", StatusCode: 200, Canonical: "https://example.com/a"},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonCanonicalized,
+		},
+		{
+			name: "canonical to non-200",
+			signals: IndexabilitySignals{
+				URL: "https://example.com/a", StatusCode: 200, Canonical: "/b",
+				CanonicalTarget: &CanonicalTarget{StatusCode: 404},
+			},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonCanonicalNon200,
+		},
+		{
+			name: "canonical chain",
+			signals: IndexabilitySignals{
+				URL: "https://example.com/a", StatusCode: 200, Canonical: "/b",
+				CanonicalTarget: &CanonicalTarget{StatusCode: 200, Canonical: "/c"},
+			},
+			wantStatus: models.IndexabilityNonIndexable,
+			wantReason: ReasonCanonicalChain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluateIndexability(tt.signals)
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Fatalf("expected %s/%s got %s/%s", tt.wantStatus, tt.wantReason, got.Status, got.Reason)
+			}
+		})
+	}
+}
+
+func TestRobotsTxtAllowed(t *testing.T) {
+	robots, err := ParseRobotsTxt(strings.NewReader(`
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+
+User-agent: Googlebot
+User-agent: bingbot
+Disallow: /nogoogle
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		agent string
+		path  string
+		want  bool
+	}{
+		{"sitecrawler", "/", true},
+		{"sitecrawler", "/private/x", false},
+		{"sitecrawler", "/private/public/x", true},
+		{"sitecrawler", "/docs/file.pdf", false},
+		{"sitecrawler", "/docs/file.pdf?x=1", true},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1)", "/private/x", true},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1)", "/nogoogle/page", false},
+	}
+	for _, tt := range tests {
+		if got := robots.Allowed(tt.agent, tt.path); got != tt.want {
+			t.Errorf("Allowed(%q, %q) = %v want %v", tt.agent, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+// PerformanceRecorder collects the phase timings of a single request through
+// an httptrace.ClientTrace. Dialing may race several connection attempts
+// (Happy Eyeballs) whose hooks run concurrently, so every phase keeps its
+// first event only and the successful connect.
+type PerformanceRecorder struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func NewPerformanceRecorder() *PerformanceRecorder {
+	return &PerformanceRecorder{}
+}
+
+// WithTrace marks the start of the request and returns ctx carrying the
+// recorder's trace. Build the request with the returned context.
+func (p *PerformanceRecorder) WithTrace(ctx context.Context) context.Context {
+	p.mu.Lock()
+	p.start = time.Now()
+	p.mu.Unlock()
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:     func(httptrace.DNSStartInfo) { p.mark(&p.dnsStart) },
+		DNSDone:      func(httptrace.DNSDoneInfo) { p.mark(&p.dnsDone) },
+		ConnectStart: func(string, string) { p.mark(&p.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				p.mark(&p.connectDone)
+			}
+		},
+		TLSHandshakeStart:    func() { p.mark(&p.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { p.mark(&p.tlsDone) },
+		GotFirstResponseByte: func() { p.mark(&p.firstByte) },
+	})
+}
+
+// mark records the current time in t unless an earlier event already did.
+func (p *PerformanceRecorder) mark(t *time.Time) {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t.IsZero() {
+		*t = now
+	}
+}
+
+// Finish reads and closes the response body and returns the recorded metrics
+// together with the decoded body. To measure the transfer size, send the
+// request with an explicit Accept-Encoding so the transport leaves the body
+// compressed; gzip and deflate are decoded here, other encodings are
+// returned as received.
+func (p *PerformanceRecorder) Finish(resp *http.Response) (models.PagePerformance, []byte, error) {
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return models.PagePerformance{}, nil, err
+	}
+	end := time.Now()
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	body, err := decodeBody(encoding, raw)
+	if err != nil {
+		return models.PagePerformance{}, nil, err
+	}
+
+	p.mu.Lock()
+	perf := models.PagePerformance{
+		DNSMs:            elapsedMs(p.dnsStart, p.dnsDone),
+		ConnectMs:        elapsedMs(p.connectStart, p.connectDone),
+		TLSMs:            elapsedMs(p.tlsStart, p.tlsDone),
+		TTFBMs:           elapsedMs(p.start, p.firstByte),
+		ResponseTimeMs:   elapsedMs(p.start, end),
+		TransferSize:     int64(len(raw)),
+		UncompressedSize: int64(len(body)),
+		ContentEncoding:  encoding,
+	}
+	p.mu.Unlock()
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/html" {
+		perf.ResourceCount = CountSubresources(bytes.NewReader(body))
+	}
+	return perf, body, nil
+}
+
+// CountSubresources returns the number of distinct subresources (scripts,
+// stylesheets, images, frames, media) an HTML document references.
+func CountSubresources(r io.Reader) int {
+	seen := map[string]bool{}
+	_ = eachSubresource(r, func(raw string) { seen[raw] = true })
+	return len(seen)
+}
+
+func decodeBody(encoding string, raw []byte) ([]byte, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case "deflate":
+		zr := flate.NewReader(bytes.NewReader(raw))
+		defer zr.Close()
+		return io.ReadAll(zr)
+	default:
+		return raw, nil
+	}
+}
+
+func elapsedMs(from, to time.Time) int64 {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from).Milliseconds()
+}
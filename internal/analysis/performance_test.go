@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPerformanceRecorder(t *testing.T) {
+	page := `<html><head><link rel="stylesheet" href="/a.css"><script src="/app.js"></script></head>
+		<body>` + strings.Repeat("<p>lorem ipsum dolor sit amet</p>", 200) + `
+		<img src="/logo.png"><img src="/logo.png"><a href="/next">next</a></body></html>`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(page))
+		_ = zw.Close()
+	}))
+	defer srv.Close()
+
+	rec := NewPerformanceRecorder()
+	req, err := http.NewRequestWithContext(rec.WithTrace(context.Background()), http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	perf, body, err := rec.Finish(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != page {
+		t.Fatalf("expected decoded body")
+	}
+	if perf.ContentEncoding != "gzip" || perf.UncompressedSize != int64(len(page)) {
+		t.Fatalf("unexpected sizes %+v", perf)
+	}
+	if perf.TransferSize <= 0 || perf.TransferSize >= perf.UncompressedSize {
+		t.Fatalf("expected compressed transfer size, got %+v", perf)
+	}
+	if perf.ResourceCount != 3 {
+		t.Fatalf("expected 3 resources got %d", perf.ResourceCount)
+	}
+	if perf.ResponseTimeMs < perf.TTFBMs || perf.DNSMs != 0 {
+		t.Fatalf("unexpected timings %+v", perf)
+	}
+}
+
+func TestPerformanceRecorderKeepsSuccessfulConnect(t *testing.T) {
+	rec := NewPerformanceRecorder()
+	trace := httptrace.ContextClientTrace(rec.WithTrace(context.Background()))
+
+	// Two dials racing as with Happy Eyeballs; the first one fails.
+	var wg sync.WaitGroup
+	for _, dialErr := range []error{errors.New("connection refused"), nil} {
+		wg.Add(1)
+		go func(dialErr error) {
+			defer wg.Done()
+			trace.ConnectStart("tcp", "127.0.0.1:80")
+			trace.ConnectDone("tcp", "127.0.0.1:80", dialErr)
+		}(dialErr)
+	}
+	wg.Wait()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.connectStart.IsZero() || rec.connectDone.IsZero() || rec.connectDone.Before(rec.connectStart) {
+		t.Fatalf("unexpected connect timings %v %v", rec.connectStart, rec.connectDone)
+	}
+}
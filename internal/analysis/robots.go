@@ -0,0 +1,152 @@
+package analysis
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// RobotsTxt is a parsed robots.txt file. Only the directives that affect
+// crawling permission (User-agent, Allow, Disallow) are retained.
+type RobotsTxt struct {
+	groups []robotsGroup
+}
+
+type robotsGroup struct {
+	agents []string
+	rules  []robotsRule
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// ParseRobotsTxt parses robots.txt content. Unknown directives and malformed
+// lines are ignored, mirroring how search engines treat them.
+func ParseRobotsTxt(r io.Reader) (*RobotsTxt, error) {
+	out := &RobotsTxt{}
+	var current *robotsGroup
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || !lastWasAgent {
+				out.groups = append(out.groups, robotsGroup{})
+				current = &out.groups[len(out.groups)-1]
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+		case "allow", "disallow":
+			lastWasAgent = false
+			if current == nil {
+				continue
+			}
+			// An empty Disallow permits everything and carries no rule.
+			if value == "" {
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+		default:
+			lastWasAgent = false
+		}
+	}
+	return out, scanner.Err()
+}
+
+// Allowed reports whether userAgent may fetch path. The most specific
+// user-agent group applies and, within it, the longest matching rule wins;
+// Allow wins ties.
+func (r *RobotsTxt) Allowed(userAgent, path string) bool {
+	if r == nil {
+		return true
+	}
+	if path == "" {
+		path = "/"
+	}
+	group := r.groupFor(strings.ToLower(userAgent))
+	if group == nil {
+		return true
+	}
+
+	allowed := true
+	best := -1
+	for _, rule := range group.rules {
+		if !robotsPatternMatch(rule.pattern, path) {
+			continue
+		}
+		n := len(rule.pattern)
+		if n > best || (n == best && rule.allow) {
+			best = n
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+func (r *RobotsTxt) groupFor(userAgent string) *robotsGroup {
+	var wildcard *robotsGroup
+	var match *robotsGroup
+	matchLen := 0
+	for i := range r.groups {
+		g := &r.groups[i]
+		for _, agent := range g.agents {
+			if agent == "*" {
+				if wildcard == nil {
+					wildcard = g
+				}
+				continue
+			}
+			if strings.Contains(userAgent, agent) && len(agent) > matchLen {
+				match = g
+				matchLen = len(agent)
+			}
+		}
+	}
+	if match != nil {
+		return match
+	}
+	return wildcard
+}
+
+// robotsPatternMatch implements the robots.txt path matching rules: patterns
+// are prefixes, '*' matches any sequence and a trailing '$' anchors the end.
+func robotsPatternMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	if anchored && len(parts) == 1 {
+		return rest == ""
+	}
+	return true
+}
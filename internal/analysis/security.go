@@ -0,0 +1,184 @@
+package analysis
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"sitecrawler/newgo/models"
+)
+
+// Security issues stored in pages.security_issues.
+const (
+	SecurityMissingHSTS                = "missing_hsts"
+	SecurityWeakHSTS                   = "weak_hsts"
+	SecurityMissingCSP                 = "missing_csp"
+	SecurityMissingXFrameOptions       = "missing_x_frame_options"
+	SecurityMissingXContentTypeOptions = "missing_x_content_type_options"
+	SecurityMissingReferrerPolicy      = "missing_referrer_policy"
+	SecurityCookieMissingSecure        = "cookie_missing_secure"
+	SecurityCookieMissingHTTPOnly      = "cookie_missing_httponly"
+	SecurityCookieMissingSameSite      = "cookie_missing_samesite"
+	SecurityMixedContent               = "mixed_content"
+)
+
+// MinHSTSMaxAge is the shortest Strict-Transport-Security max-age (180 days)
+// not reported as weak.
+const MinHSTSMaxAge = 15552000
+
+// subresourceAttrs maps the elements that load subresources to the attribute
+// holding the resource URL. <a href> is navigation, not a subresource.
+var subresourceAttrs = map[string]string{
+	"img":    "src",
+	"script": "src",
+	"iframe": "src",
+	"audio":  "src",
+	"video":  "src",
+	"source": "src",
+	"track":  "src",
+	"embed":  "src",
+	"object": "data",
+	"link":   "href",
+}
+
+// AuditSecurity checks the response headers of a page and, for https pages,
+// the document for resources loaded over plain http. body may be nil for
+// non-HTML responses. Issues are sorted and unique.
+func AuditSecurity(pageURL string, header http.Header, body io.Reader) (models.PageSecurity, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return models.PageSecurity{}, err
+	}
+	https := strings.EqualFold(u.Scheme, "https")
+
+	issues := map[string]bool{}
+	if https {
+		if hsts := header.Get("Strict-Transport-Security"); hsts == "" {
+			issues[SecurityMissingHSTS] = true
+		} else if hstsMaxAge(hsts) < MinHSTSMaxAge {
+			issues[SecurityWeakHSTS] = true
+		}
+	}
+
+	csp := header.Get("Content-Security-Policy")
+	if csp == "" {
+		issues[SecurityMissingCSP] = true
+	}
+	// frame-ancestors supersedes X-Frame-Options in browsers that support it.
+	if header.Get("X-Frame-Options") == "" && !strings.Contains(strings.ToLower(csp), "frame-ancestors") {
+		issues[SecurityMissingXFrameOptions] = true
+	}
+	if !strings.EqualFold(strings.TrimSpace(header.Get("X-Content-Type-Options")), "nosniff") {
+		issues[SecurityMissingXContentTypeOptions] = true
+	}
+	if header.Get("Referrer-Policy") == "" {
+		issues[SecurityMissingReferrerPolicy] = true
+	}
+
+	for _, c := range (&http.Response{Header: header}).Cookies() {
+		if https && !c.Secure {
+			issues[SecurityCookieMissingSecure] = true
+		}
+		if !c.HttpOnly {
+			issues[SecurityCookieMissingHTTPOnly] = true
+		}
+		// The zero value means the attribute was absent.
+		if c.SameSite == 0 || c.SameSite == http.SameSiteDefaultMode {
+			issues[SecurityCookieMissingSameSite] = true
+		}
+	}
+
+	out := models.PageSecurity{Issues: []string{}, MixedContent: []string{}}
+	if https && body != nil {
+		mixed, err := findMixedContent(u, body)
+		if err != nil {
+			return models.PageSecurity{}, err
+		}
+		if len(mixed) > 0 {
+			issues[SecurityMixedContent] = true
+			out.MixedContent = mixed
+		}
+	}
+
+	for issue := range issues {
+		out.Issues = append(out.Issues, issue)
+	}
+	sort.Strings(out.Issues)
+	return out, nil
+}
+
+func hstsMaxAge(value string) int {
+	for _, directive := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "max-age") {
+			continue
+		}
+		n, err := strconv.Atoi(strings.Trim(strings.TrimSpace(val), `"`))
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+	return 0
+}
+
+// findMixedContent returns the distinct http:// subresource URLs referenced
+// by the document, resolved against base, in document order.
+func findMixedContent(base *url.URL, r io.Reader) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	err := eachSubresource(r, func(raw string) {
+		ref, err := base.Parse(raw)
+		if err != nil || !strings.EqualFold(ref.Scheme, "http") {
+			return
+		}
+		if s := ref.String(); !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	})
+	return out, err
+}
+
+// eachSubresource calls fn with the raw URL of every subresource the
+// document references.
+func eachSubresource(r io.Reader, fn func(raw string)) error {
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return nil
+			}
+			return z.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			key, ok := subresourceAttrs[string(name)]
+			if !ok || !hasAttr {
+				continue
+			}
+			attrs := tokenAttrs(z)
+			// Only stylesheets, icons and preloads are fetched by <link>.
+			if string(name) == "link" && !isSubresourceLink(attrs["rel"]) {
+				continue
+			}
+			if raw := strings.TrimSpace(attrs[key]); raw != "" {
+				fn(raw)
+			}
+		}
+	}
+}
+
+func isSubresourceLink(rel string) bool {
+	for _, token := range []string{"stylesheet", "icon", "preload", "modulepreload"} {
+		if hasToken(rel, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package analysis
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAuditSecurityHeaders(t *testing.T) {
+	secure := http.Header{}
+	secure.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+	secure.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	secure.Set("X-Content-Type-Options", "nosniff")
+	secure.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+	secure.Add("Set-Cookie", "sid=1; Secure; HttpOnly; SameSite=Lax")
+
+	weak := http.Header{}
+	weak.Set("Strict-Transport-Security", "max-age=300")
+	weak.Set("X-Frame-Options", "DENY")
+	weak.Add("Set-Cookie", "sid=1; HttpOnly")
+
+	tests := []struct {
+		name   string
+		url    string
+		header http.Header
+		want   []string
+	}{
+		{name: "all headers set", url: "https://example.com/", header: secure, want: []string{}},
+		{
+			name:   "weak https",
+			url:    "https://example.com/",
+			header: weak,
+			want: []string{
+				SecurityCookieMissingSameSite, SecurityCookieMissingSecure, SecurityMissingCSP,
+				SecurityMissingReferrerPolicy, SecurityMissingXContentTypeOptions, SecurityWeakHSTS,
+			},
+		},
+		{
+			name:   "plain http skips transport checks",
+			url:    "http://example.com/",
+			header: weak,
+			want:   []string{SecurityCookieMissingSameSite, SecurityMissingCSP, SecurityMissingReferrerPolicy, SecurityMissingXContentTypeOptions},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AuditSecurity(tt.url, tt.header, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Issues, tt.want) {
+				t.Fatalf("expected %v got %v", tt.want, got.Issues)
+			}
+		})
+	}
+}
+
+func TestAuditSecurityMixedContent(t *testing.T) {
+	doc := `<html><head>
+		<link rel="stylesheet" href="http://cdn.example.com/a.css">
+		<link rel="canonical" href="http://example.com/">
+		<script src="//example.com/app.js"></script>
+	</head><body>
+		<a href="http://example.com/page">link</a>
+		<img src="http://example.com/logo.png"><img src="http://example.com/logo.png">
+		<img src="/relative.png">
+	</body></html>`
+
+	got, err := AuditSecurity("https://example.com/", secureHeaders(), strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got.Issues, []string{SecurityMixedContent}) {
+		t.Fatalf("unexpected issues %v", got.Issues)
+	}
+	want := []string{"http://cdn.example.com/a.css", "http://example.com/logo.png"}
+	if !reflect.DeepEqual(got.MixedContent, want) {
+		t.Fatalf("expected %v got %v", want, got.MixedContent)
+	}
+}
+
+func secureHeaders() http.Header {
+	h := http.Header{}
+	h.Set("Strict-Transport-Security", "max-age=31536000")
+	h.Set("Content-Security-Policy", "default-src 'self'")
+	h.Set("X-Frame-Options", "SAMEORIGIN")
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Referrer-Policy", "no-referrer")
+	return h
+}
@@ -0,0 +1,226 @@
+package analysis
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"sitecrawler/newgo/models"
+)
+
+// RequiredStructuredDataProperties lists the properties each validated
+// schema.org type must carry. Every entry is a set of alternatives of which
+// at least one has to be present.
+var RequiredStructuredDataProperties = map[string][][]string{
+	"Product":        {{"name"}, {"offers", "review", "aggregateRating"}},
+	"Article":        {{"headline"}, {"author"}, {"datePublished"}},
+	"BreadcrumbList": {{"itemListElement"}},
+	"FAQPage":        {{"mainEntity"}},
+}
+
+// ExtractStructuredData returns the top-level JSON-LD and Microdata items of
+// an HTML document, each validated against RequiredStructuredDataProperties.
+// Malformed JSON-LD blocks are reported as items with Error set.
+func ExtractStructuredData(r io.Reader) ([]models.StructuredDataItem, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.StructuredDataItem
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if n.DataAtom == atom.Script && strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") {
+				items = append(items, parseJSONLD(textContent(n))...)
+			}
+			if hasAttr(n, "itemscope") && !hasAttr(n, "itemprop") {
+				items = append(items, microdataItems(n)...)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	for i := range items {
+		items[i].Missing = missingProperties(items[i])
+	}
+	return items, nil
+}
+
+// StructuredDataTypes returns the distinct schema.org types of items, sorted.
+func StructuredDataTypes(items []models.StructuredDataItem) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, it := range items {
+		if it.Type == "" || seen[it.Type] {
+			continue
+		}
+		seen[it.Type] = true
+		out = append(out, it.Type)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// StructuredDataErrorCount counts the items that failed to parse or miss a
+// required property.
+func StructuredDataErrorCount(items []models.StructuredDataItem) int {
+	n := 0
+	for _, it := range items {
+		if it.Error != "" || len(it.Missing) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func parseJSONLD(raw string) []models.StructuredDataItem {
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return []models.StructuredDataItem{{Format: models.StructuredDataJSONLD, Error: "invalid JSON-LD: " + err.Error()}}
+	}
+
+	var out []models.StructuredDataItem
+	var visit func(v any)
+	visit = func(v any) {
+		switch t := v.(type) {
+		case []any:
+			for _, e := range t {
+				visit(e)
+			}
+		case map[string]any:
+			if graph, ok := t["@graph"]; ok {
+				visit(graph)
+			}
+			var props []string
+			for key := range t {
+				if !strings.HasPrefix(key, "@") {
+					props = append(props, key)
+				}
+			}
+			sort.Strings(props)
+			for _, typ := range jsonLDTypes(t["@type"]) {
+				out = append(out, models.StructuredDataItem{Format: models.StructuredDataJSONLD, Type: typ, Properties: props})
+			}
+		}
+	}
+	visit(v)
+	return out
+}
+
+func jsonLDTypes(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{schemaType(t)}
+	case []any:
+		var out []string
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				out = append(out, schemaType(s))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// microdataItems builds one item per itemtype of an itemscope element. Only
+// properties of the element itself are collected, not those of nested items.
+func microdataItems(n *html.Node) []models.StructuredDataItem {
+	seen := map[string]bool{}
+	var props []string
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			for _, p := range strings.Fields(attr(c, "itemprop")) {
+				if !seen[p] {
+					seen[p] = true
+					props = append(props, p)
+				}
+			}
+			if !hasAttr(c, "itemscope") {
+				collect(c)
+			}
+		}
+	}
+	collect(n)
+	sort.Strings(props)
+
+	var out []models.StructuredDataItem
+	for _, t := range strings.Fields(attr(n, "itemtype")) {
+		out = append(out, models.StructuredDataItem{Format: models.StructuredDataMicrodata, Type: schemaType(t), Properties: props})
+	}
+	return out
+}
+
+// schemaType reduces "https://schema.org/Product" or "schema:Product" to
+// "Product".
+func schemaType(t string) string {
+	t = strings.TrimSpace(t)
+	if i := strings.LastIndexAny(t, "/#:"); i >= 0 {
+		t = t[i+1:]
+	}
+	return t
+}
+
+func missingProperties(item models.StructuredDataItem) []string {
+	if item.Error != "" {
+		return nil
+	}
+	present := map[string]bool{}
+	for _, p := range item.Properties {
+		present[p] = true
+	}
+	var missing []string
+	for _, alternatives := range RequiredStructuredDataProperties[item.Type] {
+		found := false
+		for _, p := range alternatives {
+			if present[p] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, strings.Join(alternatives, "|"))
+		}
+	}
+	return missing
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestExtractStructuredData(t *testing.T) {
+	doc := `<html><head>
+		<script type="application/ld+json">
+		{"@context":"https://schema.org","@graph":[
+			{"@type":"Article","headline":"Hello","author":{"@type":"Person","name":"Ann"}},
+			{"@type":"BreadcrumbList","itemListElement":[]}
+		]}
+		</script>
+		<script type="application/ld+json">{not json}</script>
+	</head><body>
+		<div itemscope itemtype="https://schema.org/Product">
+			<span itemprop="name">Widget</span>
+			<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+				<span itemprop="price">10</span>
+			</div>
+		</div>
+		<div itemscope itemtype="http://schema.org/FAQPage"><h1>FAQ</h1></div>
+	</body></html>`
+
+	items, err := ExtractStructuredData(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []models.StructuredDataItem{
+		{Format: models.StructuredDataJSONLD, Type: "Article", Properties: []string{"author", "headline"}, Missing: []string{"datePublished"}},
+		{Format: models.StructuredDataJSONLD, Type: "BreadcrumbList", Properties: []string{"itemListElement"}},
+		{Format: models.StructuredDataMicrodata, Type: "Product", Properties: []string{"name", "offers"}},
+		{Format: models.StructuredDataMicrodata, Type: "FAQPage", Missing: []string{"mainEntity"}},
+	}
+	if len(items) != 5 || items[2].Error == "" {
+		t.Fatalf("expected 4 items and a JSON-LD error, got %+v", items)
+	}
+	got := append(items[:2:2], items[3:]...)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v got %+v", want, got)
+	}
+
+	if types := StructuredDataTypes(items); !reflect.DeepEqual(types, []string{"Article", "BreadcrumbList", "FAQPage", "Product"}) {
+		t.Fatalf("unexpected types %v", types)
+	}
+	if n := StructuredDataErrorCount(items); n != 3 {
+		t.Fatalf("expected 3 errors got %d", n)
+	}
+}
@@ -0,0 +1,47 @@
+package analysis
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NormalizeURL resolves ref against base and returns a canonical string form
+// suitable for comparing URLs: lower-cased scheme and host, default ports and
+// fragments removed, and an empty path replaced by "/".
+func NormalizeURL(base, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	if base != "" {
+		b, err := url.Parse(strings.TrimSpace(base))
+		if err != nil {
+			return "", err
+		}
+		u = b.ResolveReference(u)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && strings.HasSuffix(u.Host, ":80")) ||
+		(u.Scheme == "https" && strings.HasSuffix(u.Host, ":443")) {
+		u.Host = u.Host[:strings.LastIndex(u.Host, ":")]
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), nil
+}
+
+// SameURL reports whether a and b point to the same resource once normalized.
+func SameURL(a, b string) bool {
+	na, errA := NormalizeURL("", a)
+	nb, errB := NormalizeURL("", b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return na == nb
+}
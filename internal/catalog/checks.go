@@ -0,0 +1,73 @@
+// Package catalog holds the built-in audit checks that can be installed for
+// a search keyword URL.
+package catalog
+
+import "sitecrawler/newgo/internal/analysis"
+
+// Check is one entry of the built-in audit check catalogue. Version is bumped
+// whenever the definition changes so installed copies can be upgraded.
+type Check struct {
+	Key          string
+	Version      int
+	Name         string
+	Category     string
+	FilterConfig map[string]any
+}
+
+// Version is the catalogue revision; it equals the highest check version.
+const Version = 2
+
+// Checks returns a fresh copy of the catalogue so callers may keep or modify
+// the filter configs.
+func Checks() []Check {
+	return []Check{
+		{Key: "missing_title", Version: 1, Name: "Missing title", Category: "error",
+			FilterConfig: filterGroup(cond("title_length", "eq", 0), cond("response_code", "eq", 200))},
+		{Key: "title_too_long", Version: 1, Name: "Title too long", Category: "warning",
+			FilterConfig: filterGroup(cond("title_length", "gt", 60))},
+		{Key: "missing_meta_description", Version: 1, Name: "Missing meta description", Category: "warning",
+			FilterConfig: filterGroup(cond("meta_description_length", "eq", 0), cond("response_code", "eq", 200))},
+		{Key: "missing_h1", Version: 1, Name: "Missing H1", Category: "warning",
+			FilterConfig: filterGroup(cond("h1_length", "eq", 0), cond("response_code", "eq", 200))},
+		{Key: "client_error_pages", Version: 1, Name: "4xx pages", Category: "error",
+			FilterConfig: filterGroup(cond("response_code", "gte", 400), cond("response_code", "lt", 500))},
+		{Key: "server_error_pages", Version: 1, Name: "5xx pages", Category: "error",
+			FilterConfig: filterGroup(cond("response_code", "gte", 500))},
+		{Key: "redirect_chains", Version: 1, Name: "Redirect chains", Category: "warning",
+			FilterConfig: filterGroup(cond("redirect_chain_length", "gt", 1))},
+		{Key: "thin_content", Version: 1, Name: "Thin content", Category: "notice",
+			FilterConfig: filterGroup(cond("word_count", "lt", 200), cond("response_code", "eq", 200))},
+		// The start page has no inbound links by definition.
+		{Key: "orphan_pages", Version: 1, Name: "Orphan pages", Category: "notice",
+			FilterConfig: filterGroup(cond("inlinks_count", "eq", 0), cond("depth", "gt", 0))},
+		// Hreflang issues come from the session-wide audit stored on each page.
+		{Key: "hreflang_missing_return_link", Version: 2, Name: "Hreflang missing return link", Category: "error",
+			FilterConfig: filterGroup(cond("hreflang_issues", "contains", analysis.HreflangMissingReturnLink))},
+		{Key: "hreflang_invalid_code", Version: 2, Name: "Hreflang invalid language or region code", Category: "error",
+			FilterConfig: filterGroup(cond("hreflang_issues", "contains", analysis.HreflangInvalidCode))},
+		{Key: "hreflang_non_200_target", Version: 2, Name: "Hreflang target is not 200", Category: "warning",
+			FilterConfig: filterGroup(cond("hreflang_issues", "contains", analysis.HreflangNon200Target))},
+		{Key: "hreflang_non_canonical_target", Version: 2, Name: "Hreflang target is not canonical", Category: "warning",
+			FilterConfig: filterGroup(cond("hreflang_issues", "contains", analysis.HreflangNonCanonicalTarget))},
+		{Key: "hreflang_missing_x_default", Version: 2, Name: "Hreflang missing x-default", Category: "notice",
+			FilterConfig: filterGroup(cond("hreflang_issues", "contains", analysis.HreflangMissingXDefault))},
+	}
+}
+
+// Lookup returns the catalogue entry for key.
+func Lookup(key string) (Check, bool) {
+	for _, c := range Checks() {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Check{}, false
+}
+
+func filterGroup(filters ...any) map[string]any {
+	return map[string]any{"filter_groups": []any{map[string]any{"filters": filters}}}
+}
+
+func cond(name, operator string, value any) any {
+	return map[string]any{"name": name, "operator": operator, "value": value}
+}
@@ -0,0 +1,131 @@
+// Package filterconfig validates the filter_config documents stored on audit
+// checks and views before they reach a repository.
+//
+// A filter_config holds a "filter_groups" list. Each group is either an
+// equality map ({"response_code": 200}) or a "filters" list of
+// {"name", "operator", "value"} conditions. Groups are ORed and the
+// conditions of a group ANDed. A config without filter_groups is valid and
+// matches nothing.
+package filterconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var columnRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Operators lists the condition operators understood by the page repositories.
+var Operators = []string{"eq", "neq", "gt", "gte", "lt", "lte", "isnull", "notnull", "contains", "in"}
+
+// ValidColumn reports whether name can be used as a page column in filters,
+// sorts and column lists.
+func ValidColumn(name string) bool {
+	return columnRE.MatchString(name)
+}
+
+// Validate reports the first problem found in cfg, or nil when every
+// repository can evaluate it.
+func Validate(cfg map[string]any) error {
+	raw, ok := cfg["filter_groups"]
+	if !ok || raw == nil {
+		return nil
+	}
+	groups, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("filter_groups must be a list")
+	}
+	for i, item := range groups {
+		group, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("filter_groups[%d] must be an object", i)
+		}
+		if err := validateGroup(group); err != nil {
+			return fmt.Errorf("filter_groups[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateGroup(group map[string]any) error {
+	raw, ok := group["filters"]
+	if !ok {
+		for key, value := range group {
+			if !columnRE.MatchString(key) {
+				return fmt.Errorf("invalid filter column: %s", key)
+			}
+			if !isScalar(value) {
+				return fmt.Errorf("%s: value must be a scalar", key)
+			}
+		}
+		return nil
+	}
+
+	filters, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("filters must be a list")
+	}
+	for i, item := range filters {
+		cond, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("filters[%d] must be an object", i)
+		}
+		if err := validateCondition(cond); err != nil {
+			return fmt.Errorf("filters[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateCondition(cond map[string]any) error {
+	name, _ := cond["name"].(string)
+	if !columnRE.MatchString(name) {
+		return fmt.Errorf("invalid filter column: %q", name)
+	}
+
+	op := "eq"
+	if raw, ok := cond["operator"]; ok && raw != nil {
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("operator must be a string")
+		}
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			op = s
+		}
+	}
+
+	value := cond["value"]
+	switch op {
+	case "eq", "neq", "isnull", "notnull":
+		if !isScalar(value) {
+			return fmt.Errorf("%s: value must be a scalar", name)
+		}
+	case "gt", "gte", "lt", "lte", "contains":
+		if value == nil || !isScalar(value) {
+			return fmt.Errorf("%s: operator %s needs a scalar value", name, op)
+		}
+	case "in":
+		list, ok := value.([]any)
+		if !ok || len(list) == 0 {
+			return fmt.Errorf("%s: operator in needs a non-empty list", name)
+		}
+		for _, v := range list {
+			if v == nil || !isScalar(v) {
+				return fmt.Errorf("%s: operator in needs scalar values", name)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported operator: %s", op)
+	}
+	return nil
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case nil, string, bool, float64, float32, int, int64, int32:
+		return true
+	default:
+		return false
+	}
+}
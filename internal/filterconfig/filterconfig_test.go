@@ -0,0 +1,47 @@
+package filterconfig
+
+import (
+	"testing"
+
+	"sitecrawler/newgo/internal/catalog"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  map[string]any
+		ok   bool
+	}{
+		{"no groups", map[string]any{"k": "v"}, true},
+		{"equality group", map[string]any{"filter_groups": []any{map[string]any{"response_code": 200.0, "canonical": nil}}}, true},
+		{"filters group", map[string]any{"filter_groups": []any{map[string]any{"filters": []any{
+			map[string]any{"name": "response_code", "operator": "gte", "value": 400.0},
+			map[string]any{"name": "url", "operator": "in", "value": []any{"a", "b"}},
+			map[string]any{"name": "title", "operator": "isnull"},
+			map[string]any{"name": "depth", "value": 1.0},
+		}}}}, true},
+		{"groups not a list", map[string]any{"filter_groups": "x"}, false},
+		{"group not an object", map[string]any{"filter_groups": []any{1.0}}, false},
+		{"bad equality column", map[string]any{"filter_groups": []any{map[string]any{"a b": 1.0}}}, false},
+		{"filters not a list", map[string]any{"filter_groups": []any{map[string]any{"filters": "x"}}}, false},
+		{"bad column", map[string]any{"filter_groups": []any{map[string]any{"filters": []any{map[string]any{"name": "1; DROP", "value": 1.0}}}}}, false},
+		{"bad operator", map[string]any{"filter_groups": []any{map[string]any{"filters": []any{map[string]any{"name": "depth", "operator": "like", "value": 1.0}}}}}, false},
+		{"gt without value", map[string]any{"filter_groups": []any{map[string]any{"filters": []any{map[string]any{"name": "depth", "operator": "gt"}}}}}, false},
+		{"empty in", map[string]any{"filter_groups": []any{map[string]any{"filters": []any{map[string]any{"name": "depth", "operator": "in", "value": []any{}}}}}}, false},
+		{"object value", map[string]any{"filter_groups": []any{map[string]any{"filters": []any{map[string]any{"name": "depth", "value": map[string]any{}}}}}}, false},
+	}
+	for _, tc := range cases {
+		err := Validate(tc.cfg)
+		if (err == nil) != tc.ok {
+			t.Fatalf("%s: expected ok=%v got %v", tc.name, tc.ok, err)
+		}
+	}
+}
+
+func TestValidateCatalogue(t *testing.T) {
+	for _, c := range catalog.Checks() {
+		if err := Validate(c.FilterConfig); err != nil {
+			t.Fatalf("catalogue check %s: %v", c.Key, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package postcrawl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"sitecrawler/newgo/internal/alerting"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/series"
+	"sitecrawler/newgo/models"
+)
+
+// AlertsStage evaluates the SKU's enabled alert rules against the finished
+// session, records an alert for every rule that fires and dispatches it
+// through the notifiers. It reads audit check counts from the audit results
+// snapshot, so it should run after AuditResultsStage.
+type AlertsStage struct {
+	ruleRepo    repository.AlertRuleRepository
+	alertRepo   repository.AlertRepository
+	sessionRepo repository.CrawlingSessionRepository
+	statsRepo   repository.StatsRepository
+	resultRepo  repository.AuditResultRepository
+	notifiers   []alerting.Notifier
+	logger      *slog.Logger
+}
+
+func NewAlertsStage(
+	ruleRepo repository.AlertRuleRepository,
+	alertRepo repository.AlertRepository,
+	sessionRepo repository.CrawlingSessionRepository,
+	statsRepo repository.StatsRepository,
+	resultRepo repository.AuditResultRepository,
+	logger *slog.Logger,
+	notifiers ...alerting.Notifier,
+) *AlertsStage {
+	if ruleRepo == nil {
+		panic("alert rule repository required")
+	}
+	if alertRepo == nil {
+		panic("alert repository required")
+	}
+	if sessionRepo == nil {
+		panic("crawling session repository required")
+	}
+	if statsRepo == nil {
+		panic("stats repository required")
+	}
+	if resultRepo == nil {
+		panic("audit result repository required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AlertsStage{
+		ruleRepo:    ruleRepo,
+		alertRepo:   alertRepo,
+		sessionRepo: sessionRepo,
+		statsRepo:   statsRepo,
+		resultRepo:  resultRepo,
+		notifiers:   notifiers,
+		logger:      logger,
+	}
+}
+
+func (s *AlertsStage) Name() string {
+	return "alerts"
+}
+
+func (s *AlertsStage) Run(ctx context.Context, session models.CrawlingSession) error {
+	rules, err := s.ruleRepo.ListBySKU(ctx, session.SearchKeywordURLID)
+	if err != nil {
+		return err
+	}
+
+	current := newObservations(s, session.ID)
+	var before *observations
+	previousLoaded := false
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		value, ok, err := current.value(ctx, rule)
+		if err != nil {
+			s.logger.Error("alert rule evaluation failed", "rule_id", rule.ID, "error", err, "session_id", session.ID)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		var prevValue *float64
+		if rule.Mode == alerting.ModeChange {
+			if !previousLoaded {
+				previous, err := repository.PreviousDoneSession(ctx, s.sessionRepo, session)
+				if err != nil {
+					return err
+				}
+				if previous != nil {
+					before = newObservations(s, previous.ID)
+				}
+				previousLoaded = true
+			}
+			if before != nil {
+				if v, ok, err := before.value(ctx, rule); err == nil && ok {
+					prevValue = &v
+				}
+			}
+		}
+
+		fired, observed := alerting.Evaluate(rule, value, prevValue)
+		if !fired {
+			continue
+		}
+
+		alert := models.Alert{
+			RuleID:             rule.ID,
+			SearchKeywordURLID: session.SearchKeywordURLID,
+			CrawlingSessionID:  session.ID,
+			RuleName:           rule.Name,
+			Value:              value,
+			PreviousValue:      prevValue,
+			Threshold:          rule.Threshold,
+			Message:            alerting.Describe(rule, ruleSubject(rule), observed),
+		}
+		alert.Deliveries = alerting.Dispatch(ctx, s.notifiers, rule, alert)
+		if err := s.alertRepo.Create(ctx, &alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ruleSubject(rule models.AlertRule) string {
+	if rule.AuditCheckID != nil {
+		return fmt.Sprintf("matched pages of audit check %d", *rule.AuditCheckID)
+	}
+	return rule.Metric
+}
+
+// observations lazily loads the stats and audit results of one session so
+// that several rules share a single fetch of each.
+type observations struct {
+	stage     *AlertsStage
+	sessionID int64
+	stats     map[string]any
+	checks    map[int64]models.AuditResult
+}
+
+func newObservations(stage *AlertsStage, sessionID int64) *observations {
+	return &observations{stage: stage, sessionID: sessionID}
+}
+
+// value returns the rule's value in the session, and false when the session
+// has no such metric or no result for the check.
+func (o *observations) value(ctx context.Context, rule models.AlertRule) (float64, bool, error) {
+	if rule.AuditCheckID != nil {
+		if o.checks == nil {
+			results, err := o.stage.resultRepo.ListBySession(ctx, o.sessionID)
+			if err != nil {
+				return 0, false, err
+			}
+			o.checks = make(map[int64]models.AuditResult, len(results))
+			for _, res := range results {
+				o.checks[res.AuditCheckID] = res
+			}
+		}
+		res, ok := o.checks[*rule.AuditCheckID]
+		if !ok || res.Error != "" {
+			return 0, false, nil
+		}
+		return float64(res.MatchedPages), true, nil
+	}
+
+	if o.stats == nil {
+		stats, err := o.stage.statsRepo.Fetch(ctx, repository.StatsQueryParams{SessionID: o.sessionID})
+		if err != nil {
+			return 0, false, err
+		}
+		o.stats = stats
+	}
+	v, ok := series.MetricValue(o.stats, rule.Metric)
+	return v, ok, nil
+}
@@ -0,0 +1,80 @@
+package postcrawl
+
+import (
+	"context"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// AuditResultsStage evaluates every audit check of the session's SKU and
+// stores the counts and sample page IDs as the session's audit results.
+type AuditResultsStage struct {
+	auditRepo  repository.AuditCheckRepository
+	matchRepo  repository.PageMatchRepository
+	resultRepo repository.AuditResultRepository
+}
+
+func NewAuditResultsStage(
+	auditRepo repository.AuditCheckRepository,
+	matchRepo repository.PageMatchRepository,
+	resultRepo repository.AuditResultRepository,
+) *AuditResultsStage {
+	if auditRepo == nil {
+		panic("audit check repository required")
+	}
+	if matchRepo == nil {
+		panic("page match repository required")
+	}
+	if resultRepo == nil {
+		panic("audit result repository required")
+	}
+	return &AuditResultsStage{auditRepo: auditRepo, matchRepo: matchRepo, resultRepo: resultRepo}
+}
+
+func (s *AuditResultsStage) Name() string {
+	return "audit_results"
+}
+
+func (s *AuditResultsStage) Run(ctx context.Context, session models.CrawlingSession) error {
+	checks, err := s.auditRepo.ListBySKU(ctx, session.SearchKeywordURLID)
+	if err != nil {
+		return err
+	}
+
+	configs := make([]map[string]any, len(checks))
+	for i, check := range checks {
+		configs[i] = check.FilterConfig
+	}
+	counts, errs := repository.CountMatchesEach(ctx, s.matchRepo, session.ID, configs)
+
+	results := make([]models.AuditResult, len(checks))
+	for i, check := range checks {
+		results[i] = models.AuditResult{
+			CrawlingSessionID: session.ID,
+			AuditCheckID:      check.ID,
+			Name:              check.Name,
+			Category:          check.Category,
+			Severity:          check.Severity,
+			FilterConfig:      check.FilterConfig,
+			SamplePageIDs:     []int64{},
+		}
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+			continue
+		}
+		results[i].MatchedPages = counts[i]
+		if counts[i] == 0 {
+			continue
+		}
+		pages, _, err := s.matchRepo.ListMatches(ctx, session.ID, check.FilterConfig, 1, repository.AuditResultSampleSize)
+		if err != nil {
+			return err
+		}
+		for _, p := range pages {
+			results[i].SamplePageIDs = append(results[i].SamplePageIDs, p.ID)
+		}
+	}
+
+	return s.resultRepo.Save(ctx, session.ID, results)
+}
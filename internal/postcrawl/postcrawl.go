@@ -0,0 +1,58 @@
+// Package postcrawl runs the stages that follow a finished crawl, such as
+// snapshotting audit results.
+package postcrawl
+
+import (
+	"context"
+	"log/slog"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// Stage is one step run after a session is marked done.
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, session models.CrawlingSession) error
+}
+
+// SessionRepository wraps a CrawlingSessionRepository and runs the
+// configured stages, in order, after every successful MarkDone. A failing
+// stage is logged and does not stop the following ones or fail MarkDone:
+// the session is done either way.
+type SessionRepository struct {
+	repository.CrawlingSessionRepository
+	stages []Stage
+	logger *slog.Logger
+}
+
+func NewSessionRepository(repo repository.CrawlingSessionRepository, logger *slog.Logger, stages ...Stage) *SessionRepository {
+	if repo == nil {
+		panic("crawling session repository required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SessionRepository{CrawlingSessionRepository: repo, stages: stages, logger: logger}
+}
+
+func (r *SessionRepository) MarkDone(ctx context.Context, id int64, reason string) error {
+	if err := r.CrawlingSessionRepository.MarkDone(ctx, id, reason); err != nil {
+		return err
+	}
+	if len(r.stages) == 0 {
+		return nil
+	}
+
+	session, err := r.CrawlingSessionRepository.GetByID(ctx, id)
+	if err != nil {
+		r.logger.Error("post-crawl session lookup failed", "error", err, "id", id)
+		return nil
+	}
+	for _, stage := range r.stages {
+		if err := stage.Run(ctx, *session); err != nil {
+			r.logger.Error("post-crawl stage failed", "stage", stage.Name(), "error", err, "id", id)
+		}
+	}
+	return nil
+}
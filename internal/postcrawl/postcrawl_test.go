@@ -0,0 +1,46 @@
+package postcrawl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+type recordingStage struct {
+	name string
+	err  error
+	runs *[]string
+}
+
+func (s recordingStage) Name() string { return s.name }
+
+func (s recordingStage) Run(ctx context.Context, session models.CrawlingSession) error {
+	*s.runs = append(*s.runs, s.name)
+	return s.err
+}
+
+func TestSessionRepositoryRunsStagesAfterMarkDone(t *testing.T) {
+	ctx := context.Background()
+	inner := repository.NewInMemoryCrawlingSessionRepository()
+	_ = inner.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 1, Status: "processing"})
+
+	var runs []string
+	repo := NewSessionRepository(inner, nil,
+		recordingStage{name: "first", err: errors.New("boom"), runs: &runs},
+		recordingStage{name: "second", runs: &runs},
+	)
+	if err := repo.MarkDone(ctx, 1, "finished"); err != nil {
+		t.Fatalf("failing stage must not fail MarkDone: %v", err)
+	}
+	if len(runs) != 2 || runs[0] != "first" || runs[1] != "second" {
+		t.Fatalf("unexpected stage runs %v", runs)
+	}
+
+	session, _ := inner.GetByID(ctx, 1)
+	if session.Status != "done" {
+		t.Fatalf("expected done status got %s", session.Status)
+	}
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+var ErrAlertRuleNotFound = errors.New("alert rule not found")
+
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *models.AlertRule) error
+	Update(ctx context.Context, rule *models.AlertRule) error
+	Delete(ctx context.Context, id int64) error
+	Get(ctx context.Context, id int64) (*models.AlertRule, error)
+	ListBySKU(ctx context.Context, skuID int64) ([]models.AlertRule, error)
+}
+
+// AlertListParams narrows /api/alerts; zero values do not filter.
+type AlertListParams struct {
+	SearchKeywordURLID int64
+	CrawlingSessionID  int64
+	RuleID             int64
+	Limit              int
+}
+
+type AlertRepository interface {
+	Create(ctx context.Context, alert *models.Alert) error
+	// List returns the newest alerts first.
+	List(ctx context.Context, params AlertListParams) ([]models.Alert, error)
+}
+
+type InMemoryAlertRuleRepository struct {
+	mu    sync.Mutex
+	seq   int64
+	items map[int64]*models.AlertRule
+}
+
+func NewInMemoryAlertRuleRepository() *InMemoryAlertRuleRepository {
+	return &InMemoryAlertRuleRepository{items: map[int64]*models.AlertRule{}}
+}
+
+func (r *InMemoryAlertRuleRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	now := time.Now().UTC()
+	rule.ID = r.seq
+	rule.CreatedAt, rule.UpdatedAt = now, now
+	c := *rule
+	r.items[rule.ID] = &c
+	return nil
+}
+
+func (r *InMemoryAlertRuleRepository) Update(ctx context.Context, rule *models.AlertRule) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[rule.ID]; !ok {
+		return ErrAlertRuleNotFound
+	}
+	rule.UpdatedAt = time.Now().UTC()
+	c := *rule
+	r.items[rule.ID] = &c
+	return nil
+}
+
+func (r *InMemoryAlertRuleRepository) Delete(ctx context.Context, id int64) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[id]; !ok {
+		return ErrAlertRuleNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func (r *InMemoryAlertRuleRepository) Get(ctx context.Context, id int64) (*models.AlertRule, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.items[id]; ok {
+		c := *v
+		return &c, nil
+	}
+	return nil, ErrAlertRuleNotFound
+}
+
+func (r *InMemoryAlertRuleRepository) ListBySKU(ctx context.Context, skuID int64) ([]models.AlertRule, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.AlertRule{}
+	for _, v := range r.items {
+		if v.SearchKeywordURLID == skuID {
+			out = append(out, *v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+type InMemoryAlertRepository struct {
+	mu    sync.Mutex
+	seq   int64
+	items []models.Alert
+}
+
+func NewInMemoryAlertRepository() *InMemoryAlertRepository {
+	return &InMemoryAlertRepository{}
+}
+
+func (r *InMemoryAlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	alert.ID = r.seq
+	alert.CreatedAt = time.Now().UTC()
+	r.items = append(r.items, *alert)
+	return nil
+}
+
+func (r *InMemoryAlertRepository) List(ctx context.Context, params AlertListParams) ([]models.Alert, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.Alert{}
+	for i := len(r.items) - 1; i >= 0; i-- {
+		a := r.items[i]
+		if (params.SearchKeywordURLID != 0 && a.SearchKeywordURLID != params.SearchKeywordURLID) ||
+			(params.CrawlingSessionID != 0 && a.CrawlingSessionID != params.CrawlingSessionID) ||
+			(params.RuleID != 0 && a.RuleID != params.RuleID) {
+			continue
+		}
+		out = append(out, a)
+		if params.Limit > 0 && len(out) == params.Limit {
+			break
+		}
+	}
+	return out, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 
 	"sitecrawler/newgo/models"
@@ -17,6 +18,8 @@ type AuditCheckRepository interface {
 	Get(ctx context.Context, id int64) (*models.AuditCheck, error)
 	ListBySKU(ctx context.Context, skuID int64) ([]models.AuditCheck, error)
 	ListByIDsAndSKUs(ctx context.Context, ids, skuIDs []int64) ([]models.AuditCheck, error)
+	// ListByTemplate returns the checks applied from a template, across SKUs.
+	ListByTemplate(ctx context.Context, templateID int64) ([]models.AuditCheck, error)
 }
 
 type InMemoryAuditCheckRepository struct {
@@ -75,14 +78,44 @@ func (r *InMemoryAuditCheckRepository) ListBySKU(ctx context.Context, skuID int6
 			out = append(out, *cloneAuditCheck(v))
 		}
 	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
 	return out, nil
 }
 
 func (r *InMemoryAuditCheckRepository) ListByIDsAndSKUs(ctx context.Context, ids, skuIDs []int64) ([]models.AuditCheck, error) {
 	_ = ctx
-	_ = ids
-	_ = skuIDs
-	return []models.AuditCheck{}, nil
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wantID := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		wantID[id] = true
+	}
+	wantSKU := make(map[int64]bool, len(skuIDs))
+	for _, id := range skuIDs {
+		wantSKU[id] = true
+	}
+	out := []models.AuditCheck{}
+	for _, v := range r.items {
+		if wantID[v.ID] && wantSKU[v.SearchKeywordURLID] {
+			out = append(out, *cloneAuditCheck(v))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *InMemoryAuditCheckRepository) ListByTemplate(ctx context.Context, templateID int64) ([]models.AuditCheck, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.AuditCheck{}
+	for _, v := range r.items {
+		if v.TemplateID != nil && *v.TemplateID == templateID {
+			out = append(out, *cloneAuditCheck(v))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
 }
 
 func cloneAuditCheck(ac *models.AuditCheck) *models.AuditCheck {
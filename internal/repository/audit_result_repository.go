@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+// AuditResultSampleSize is the number of matched page IDs stored per check.
+const AuditResultSampleSize = 20
+
+// AuditResultRepository stores the audit results snapshot taken when a
+// session finishes.
+type AuditResultRepository interface {
+	// Save replaces the snapshot of a session.
+	Save(ctx context.Context, sessionID int64, results []models.AuditResult) error
+	// ListBySession returns the snapshot ordered by check id; it is empty
+	// when the session has not been evaluated.
+	ListBySession(ctx context.Context, sessionID int64) ([]models.AuditResult, error)
+	// ListByCheck returns the check's result in each of sessionIDs whose
+	// snapshot includes it, keyed by session.
+	ListByCheck(ctx context.Context, checkID int64, sessionIDs []int64) (map[int64]models.AuditResult, error)
+	// ListEvaluatedSessions reports which of sessionIDs have a snapshot.
+	ListEvaluatedSessions(ctx context.Context, sessionIDs []int64) (map[int64]bool, error)
+}
+
+type InMemoryAuditResultRepository struct {
+	mu    sync.Mutex
+	items map[int64][]models.AuditResult
+}
+
+func NewInMemoryAuditResultRepository() *InMemoryAuditResultRepository {
+	return &InMemoryAuditResultRepository{items: map[int64][]models.AuditResult{}}
+}
+
+func (r *InMemoryAuditResultRepository) Save(ctx context.Context, sessionID int64, results []models.AuditResult) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	saved := append([]models.AuditResult(nil), results...)
+	for i := range saved {
+		saved[i].CrawlingSessionID = sessionID
+		saved[i].CreatedAt = now
+	}
+	sort.SliceStable(saved, func(i, j int) bool { return saved[i].AuditCheckID < saved[j].AuditCheckID })
+	r.items[sessionID] = saved
+	return nil
+}
+
+func (r *InMemoryAuditResultRepository) ListBySession(ctx context.Context, sessionID int64) ([]models.AuditResult, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.AuditResult(nil), r.items[sessionID]...), nil
+}
+
+func (r *InMemoryAuditResultRepository) ListByCheck(ctx context.Context, checkID int64, sessionIDs []int64) (map[int64]models.AuditResult, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[int64]models.AuditResult)
+	for _, id := range sessionIDs {
+		for _, res := range r.items[id] {
+			if res.AuditCheckID == checkID {
+				out[id] = res
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *InMemoryAuditResultRepository) ListEvaluatedSessions(ctx context.Context, sessionIDs []int64) (map[int64]bool, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[int64]bool)
+	for _, id := range sessionIDs {
+		if len(r.items[id]) > 0 {
+			out[id] = true
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,206 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+const alertRuleColumns = `id, search_keyword_url_id, name, audit_check_id, metric, mode, operator, threshold,
+	webhook_url, emails, enabled, created_at, updated_at`
+
+type AlertRuleRepo struct {
+	db  *sql.DB
+	ids IDGenerator
+}
+
+func NewAlertRuleRepo(db *sql.DB, ids IDGenerator) *AlertRuleRepo {
+	if ids == nil {
+		panic("id generator required")
+	}
+	return &AlertRuleRepo{db: db, ids: ids}
+}
+
+func (r *AlertRuleRepo) Create(ctx context.Context, rule *models.AlertRule) error {
+	now := time.Now().UTC()
+	rule.CreatedAt, rule.UpdatedAt = now, now
+
+	emailsJSON, err := json.Marshal(rule.Emails)
+	if err != nil {
+		return fmt.Errorf("failed to marshal emails: %w", err)
+	}
+
+	q := `INSERT INTO alert_rules (id, search_keyword_url_id, name, audit_check_id, metric, mode, operator, threshold, webhook_url, emails, enabled, created_at, updated_at)
+	      VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.ids.NextID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to generate alert rule ID: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, q, id, rule.SearchKeywordURLID, rule.Name, rule.AuditCheckID, rule.Metric, rule.Mode, rule.Operator,
+		rule.Threshold, rule.WebhookURL, string(emailsJSON), rule.Enabled, rule.CreatedAt, rule.UpdatedAt); err != nil {
+		return err
+	}
+
+	rule.ID = id
+	return nil
+}
+
+func (r *AlertRuleRepo) Update(ctx context.Context, rule *models.AlertRule) error {
+	emailsJSON, err := json.Marshal(rule.Emails)
+	if err != nil {
+		return fmt.Errorf("failed to marshal emails: %w", err)
+	}
+	rule.UpdatedAt = time.Now().UTC()
+	q := `ALTER TABLE alert_rules UPDATE name = ?, audit_check_id = ?, metric = ?, mode = ?, operator = ?, threshold = ?,
+	      webhook_url = ?, emails = ?, enabled = ?, updated_at = ? WHERE id = ?`
+	_, err = r.db.ExecContext(ctx, q, rule.Name, rule.AuditCheckID, rule.Metric, rule.Mode, rule.Operator, rule.Threshold,
+		rule.WebhookURL, string(emailsJSON), rule.Enabled, rule.UpdatedAt, rule.ID)
+	return err
+}
+
+func (r *AlertRuleRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `ALTER TABLE alert_rules DELETE WHERE id = ?`, id)
+	return err
+}
+
+func (r *AlertRuleRepo) Get(ctx context.Context, id int64) (*models.AlertRule, error) {
+	rule, err := scanAlertRule(r.db.QueryRowContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrAlertRuleNotFound
+	}
+	return rule, err
+}
+
+func (r *AlertRuleRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE search_keyword_url_id = ? ORDER BY id ASC`, skuID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.AlertRule{}
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *rule)
+	}
+	return out, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAlertRule(row rowScanner) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	var checkID sql.NullInt64
+	var emailsJSON string
+	if err := row.Scan(&rule.ID, &rule.SearchKeywordURLID, &rule.Name, &checkID, &rule.Metric, &rule.Mode, &rule.Operator,
+		&rule.Threshold, &rule.WebhookURL, &emailsJSON, &rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if checkID.Valid {
+		rule.AuditCheckID = &checkID.Int64
+	}
+	if emailsJSON != "" {
+		if err := json.Unmarshal([]byte(emailsJSON), &rule.Emails); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal emails: %w", err)
+		}
+	}
+	return &rule, nil
+}
+
+type AlertRepo struct {
+	db  *sql.DB
+	ids IDGenerator
+}
+
+func NewAlertRepo(db *sql.DB, ids IDGenerator) *AlertRepo {
+	if ids == nil {
+		panic("id generator required")
+	}
+	return &AlertRepo{db: db, ids: ids}
+}
+
+func (r *AlertRepo) Create(ctx context.Context, alert *models.Alert) error {
+	alert.CreatedAt = time.Now().UTC()
+
+	deliveriesJSON, err := json.Marshal(alert.Deliveries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deliveries: %w", err)
+	}
+
+	q := `INSERT INTO alerts (id, rule_id, search_keyword_url_id, crawling_session_id, rule_name, value, previous_value, threshold, message, deliveries, created_at)
+	      VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.ids.NextID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to generate alert ID: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, q, id, alert.RuleID, alert.SearchKeywordURLID, alert.CrawlingSessionID, alert.RuleName,
+		alert.Value, alert.PreviousValue, alert.Threshold, alert.Message, string(deliveriesJSON), alert.CreatedAt); err != nil {
+		return err
+	}
+
+	alert.ID = id
+	return nil
+}
+
+func (r *AlertRepo) List(ctx context.Context, params repository.AlertListParams) ([]models.Alert, error) {
+	var where []string
+	var args []any
+	add := func(col string, v int64) {
+		if v != 0 {
+			where = append(where, col+" = ?")
+			args = append(args, v)
+		}
+	}
+	add("search_keyword_url_id", params.SearchKeywordURLID)
+	add("crawling_session_id", params.CrawlingSessionID)
+	add("rule_id", params.RuleID)
+
+	q := `SELECT id, rule_id, search_keyword_url_id, crawling_session_id, rule_name, value, previous_value, threshold, message, deliveries, created_at FROM alerts`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY created_at DESC, id DESC"
+	if params.Limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, params.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Alert{}
+	for rows.Next() {
+		var a models.Alert
+		var prev sql.NullFloat64
+		var deliveriesJSON string
+		if err := rows.Scan(&a.ID, &a.RuleID, &a.SearchKeywordURLID, &a.CrawlingSessionID, &a.RuleName, &a.Value, &prev,
+			&a.Threshold, &a.Message, &deliveriesJSON, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		if prev.Valid {
+			a.PreviousValue = &prev.Float64
+		}
+		if deliveriesJSON != "" {
+			if err := json.Unmarshal([]byte(deliveriesJSON), &a.Deliveries); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal deliveries: %w", err)
+			}
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -4,18 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"sitecrawler/newgo/internal/repository"
 	"sitecrawler/newgo/models"
 )
 
+const auditCheckColumns = `id, search_keyword_url_id, name, category, severity, weight, filter_config, builtin_key, builtin_version, customized,
+	template_id, template_version, created_at, updated_at`
+
 type AuditRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	ids IDGenerator
 }
 
-func NewAuditRepo(db *sql.DB) *AuditRepo {
-	return &AuditRepo{db: db}
+func NewAuditRepo(db *sql.DB, ids IDGenerator) *AuditRepo {
+	if ids == nil {
+		panic("id generator required")
+	}
+	return &AuditRepo{db: db, ids: ids}
 }
 
 func (r *AuditRepo) Create(ctx context.Context, ac *models.AuditCheck) error {
@@ -27,21 +37,19 @@ func (r *AuditRepo) Create(ctx context.Context, ac *models.AuditCheck) error {
 		return fmt.Errorf("failed to marshal filter config: %w", err)
 	}
 
-	q := `INSERT INTO audit_checks (search_keyword_url_id, name, category, filter_config, created_at, updated_at)
-	      VALUES (?, ?, ?, ?, ?, ?)`
+	q := `INSERT INTO audit_checks (id, search_keyword_url_id, name, category, severity, weight, filter_config, builtin_key, builtin_version, customized,
+	      template_id, template_version, created_at, updated_at)
+	      VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, q, ac.SearchKeywordURLID, ac.Name, ac.Category, string(filterJSON), ac.CreatedAt, ac.UpdatedAt)
+	id, err := r.ids.NextID(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to generate audit check ID: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, q, id, ac.SearchKeywordURLID, ac.Name, ac.Category, ac.Severity, ac.Weight, string(filterJSON),
+		ac.BuiltinKey, ac.BuiltinVersion, ac.Customized, ac.TemplateID, ac.TemplateVersion, ac.CreatedAt, ac.UpdatedAt); err != nil {
 		return err
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		err = r.db.QueryRowContext(ctx, "SELECT max(id) + 1 FROM audit_checks").Scan(&id)
-		if err != nil {
-			return fmt.Errorf("failed to get audit check ID: %w", err)
-		}
-	}
 	ac.ID = id
 	return nil
 }
@@ -52,8 +60,10 @@ func (r *AuditRepo) Update(ctx context.Context, ac *models.AuditCheck) error {
 		return fmt.Errorf("failed to marshal filter config: %w", err)
 	}
 
-	q := `ALTER TABLE audit_checks UPDATE name = ?, category = ?, filter_config = ?, updated_at = ? WHERE id = ?`
-	_, err = r.db.ExecContext(ctx, q, ac.Name, ac.Category, string(filterJSON), time.Now().UTC(), ac.ID)
+	q := `ALTER TABLE audit_checks UPDATE name = ?, category = ?, severity = ?, weight = ?, filter_config = ?, builtin_version = ?, customized = ?,
+	      template_id = ?, template_version = ?, updated_at = ? WHERE id = ?`
+	_, err = r.db.ExecContext(ctx, q, ac.Name, ac.Category, ac.Severity, ac.Weight, string(filterJSON), ac.BuiltinVersion, ac.Customized,
+		ac.TemplateID, ac.TemplateVersion, time.Now().UTC(), ac.ID)
 	return err
 }
 
@@ -64,33 +74,24 @@ func (r *AuditRepo) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *AuditRepo) Get(ctx context.Context, id int64) (*models.AuditCheck, error) {
-	q := `SELECT id, search_keyword_url_id, name, category, filter_config, created_at, updated_at 
-	      FROM audit_checks WHERE id = ?`
-
-	var ac models.AuditCheck
-	var filterJSON string
-
-	err := r.db.QueryRowContext(ctx, q, id).Scan(
-		&ac.ID, &ac.SearchKeywordURLID, &ac.Name, &ac.Category, &filterJSON, &ac.CreatedAt, &ac.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	if filterJSON != "" {
-		if err := json.Unmarshal([]byte(filterJSON), &ac.FilterConfig); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
-		}
+	q := `SELECT ` + auditCheckColumns + ` FROM audit_checks WHERE id = ?`
+	ac, err := scanAuditCheck(r.db.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrAuditCheckNotFound
 	}
-
-	return &ac, nil
+	return ac, err
 }
 
 func (r *AuditRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.AuditCheck, error) {
-	q := `SELECT id, search_keyword_url_id, name, category, filter_config, created_at, updated_at 
-	      FROM audit_checks WHERE search_keyword_url_id = ? ORDER BY created_at ASC`
+	return r.list(ctx, `SELECT `+auditCheckColumns+` FROM audit_checks WHERE search_keyword_url_id = ? ORDER BY id ASC`, skuID)
+}
+
+func (r *AuditRepo) ListByTemplate(ctx context.Context, templateID int64) ([]models.AuditCheck, error) {
+	return r.list(ctx, `SELECT `+auditCheckColumns+` FROM audit_checks WHERE template_id = ? ORDER BY id ASC`, templateID)
+}
 
-	rows, err := r.db.QueryContext(ctx, q, skuID)
+func (r *AuditRepo) list(ctx context.Context, q string, args ...any) ([]models.AuditCheck, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,29 +99,52 @@ func (r *AuditRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.AuditC
 
 	var checks []models.AuditCheck
 	for rows.Next() {
-		var ac models.AuditCheck
-		var filterJSON string
-
-		err := rows.Scan(&ac.ID, &ac.SearchKeywordURLID, &ac.Name, &ac.Category, &filterJSON, &ac.CreatedAt, &ac.UpdatedAt)
+		ac, err := scanAuditCheck(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		if filterJSON != "" {
-			if err := json.Unmarshal([]byte(filterJSON), &ac.FilterConfig); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
-			}
-		}
-
-		checks = append(checks, ac)
+		checks = append(checks, *ac)
 	}
 
 	return checks, rows.Err()
 }
 
 func (r *AuditRepo) ListByIDsAndSKUs(ctx context.Context, ids, skuIDs []int64) ([]models.AuditCheck, error) {
-	_ = ctx
-	_ = ids
-	_ = skuIDs
-	return nil, fmt.Errorf("ListByIDsAndSKUs not yet implemented for ClickHouse")
+	if len(ids) == 0 || len(skuIDs) == 0 {
+		return nil, nil
+	}
+	args := make([]any, 0, len(ids)+len(skuIDs))
+	for _, v := range ids {
+		args = append(args, v)
+	}
+	for _, v := range skuIDs {
+		args = append(args, v)
+	}
+	q := fmt.Sprintf(`SELECT `+auditCheckColumns+` FROM audit_checks WHERE id IN (%s) AND search_keyword_url_id IN (%s) ORDER BY id ASC`,
+		strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","), strings.TrimSuffix(strings.Repeat("?,", len(skuIDs)), ","))
+	return r.list(ctx, q, args...)
+}
+
+// scanAuditCheck reads a row selected with auditCheckColumns; filter_config
+// is stored as a JSON string.
+func scanAuditCheck(row rowScanner) (*models.AuditCheck, error) {
+	var ac models.AuditCheck
+	var filterJSON string
+	var templateID sql.NullInt64
+
+	err := row.Scan(&ac.ID, &ac.SearchKeywordURLID, &ac.Name, &ac.Category, &ac.Severity, &ac.Weight, &filterJSON,
+		&ac.BuiltinKey, &ac.BuiltinVersion, &ac.Customized, &templateID, &ac.TemplateVersion, &ac.CreatedAt, &ac.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if templateID.Valid {
+		ac.TemplateID = &templateID.Int64
+	}
+
+	if filterJSON != "" {
+		if err := json.Unmarshal([]byte(filterJSON), &ac.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	return &ac, nil
 }
@@ -0,0 +1,146 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+type AuditResultRepo struct {
+	db *sql.DB
+}
+
+func NewAuditResultRepo(db *sql.DB) *AuditResultRepo {
+	return &AuditResultRepo{db: db}
+}
+
+// Save replaces the session's snapshot. The delete is a mutation, so it
+// waits for mutations_sync=2 before the new rows go in as one INSERT;
+// otherwise a reader right after Save could see old and new rows together.
+func (r *AuditResultRepo) Save(ctx context.Context, sessionID int64, results []models.AuditResult) error {
+	q := `ALTER TABLE audit_results DELETE WHERE crawling_session_id = ? SETTINGS mutations_sync = 2`
+	if _, err := r.db.ExecContext(ctx, q, sessionID); err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	rows := make([]string, 0, len(results))
+	args := make([]any, 0, len(results)*10)
+	for _, res := range results {
+		filterJSON, err := json.Marshal(res.FilterConfig)
+		if err != nil {
+			return fmt.Errorf("failed to marshal filter config: %w", err)
+		}
+		samplesJSON, err := json.Marshal(res.SamplePageIDs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sample page ids: %w", err)
+		}
+		rows = append(rows, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, sessionID, res.AuditCheckID, res.Name, res.Category, res.Severity, string(filterJSON),
+			res.MatchedPages, string(samplesJSON), res.Error, now)
+	}
+	q = `INSERT INTO audit_results (crawling_session_id, audit_check_id, name, category, severity, filter_config,
+		matched_pages, sample_page_ids, error, created_at) VALUES ` + strings.Join(rows, ", ")
+	_, err := r.db.ExecContext(ctx, q, args...)
+	return err
+}
+
+const auditResultColumns = `crawling_session_id, audit_check_id, name, category, severity, filter_config,
+	matched_pages, sample_page_ids, error, created_at`
+
+func (r *AuditResultRepo) ListBySession(ctx context.Context, sessionID int64) ([]models.AuditResult, error) {
+	q := `SELECT ` + auditResultColumns + ` FROM audit_results WHERE crawling_session_id = ? ORDER BY audit_check_id ASC`
+	rows, err := r.db.QueryContext(ctx, q, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAuditResults(rows)
+}
+
+func (r *AuditResultRepo) ListByCheck(ctx context.Context, checkID int64, sessionIDs []int64) (map[int64]models.AuditResult, error) {
+	out := make(map[int64]models.AuditResult)
+	if len(sessionIDs) == 0 {
+		return out, nil
+	}
+	ph := make([]string, len(sessionIDs))
+	args := []any{checkID}
+	for i, id := range sessionIDs {
+		ph[i] = "?"
+		args = append(args, id)
+	}
+	q := fmt.Sprintf(`SELECT `+auditResultColumns+` FROM audit_results
+		WHERE audit_check_id = ? AND crawling_session_id IN (%s)`, strings.Join(ph, ","))
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results, err := scanAuditResults(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		out[res.CrawlingSessionID] = res
+	}
+	return out, nil
+}
+
+func (r *AuditResultRepo) ListEvaluatedSessions(ctx context.Context, sessionIDs []int64) (map[int64]bool, error) {
+	out := make(map[int64]bool)
+	if len(sessionIDs) == 0 {
+		return out, nil
+	}
+	ph := make([]string, len(sessionIDs))
+	args := make([]any, len(sessionIDs))
+	for i, id := range sessionIDs {
+		ph[i] = "?"
+		args[i] = id
+	}
+	q := fmt.Sprintf(`SELECT DISTINCT crawling_session_id FROM audit_results WHERE crawling_session_id IN (%s)`, strings.Join(ph, ","))
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+func scanAuditResults(rows *sql.Rows) ([]models.AuditResult, error) {
+	var out []models.AuditResult
+	for rows.Next() {
+		var res models.AuditResult
+		var filterJSON, samplesJSON string
+		if err := rows.Scan(&res.CrawlingSessionID, &res.AuditCheckID, &res.Name, &res.Category, &res.Severity, &filterJSON,
+			&res.MatchedPages, &samplesJSON, &res.Error, &res.CreatedAt); err != nil {
+			return nil, err
+		}
+		if filterJSON != "" {
+			if err := json.Unmarshal([]byte(filterJSON), &res.FilterConfig); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+			}
+		}
+		if samplesJSON != "" {
+			if err := json.Unmarshal([]byte(samplesJSON), &res.SamplePageIDs); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal sample page ids: %w", err)
+			}
+		}
+		out = append(out, res)
+	}
+	return out, rows.Err()
+}
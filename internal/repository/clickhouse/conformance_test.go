@@ -0,0 +1,144 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"sitecrawler/newgo/internal/repository/repotest"
+
+	_ "github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// openTestDB connects to the database in CLICKHOUSE_TEST_DSN, skipping the
+// test when it is unset, and creates the tables of testdata/schema.sql that
+// are missing. Updates and deletes are ALTER TABLE mutations, so the DSN
+// should set mutations_sync=1 for the suites to see them.
+func openTestDB(t *testing.T) *sql.DB {
+	db := repotest.OpenDB(t, "CLICKHOUSE_TEST_DSN", "CLICKHOUSE_TEST_DRIVER", "clickhouse")
+	repotest.ApplySchema(t, db, "testdata/schema.sql")
+	return db
+}
+
+// testIDs is shared by every repository under test so their ids never
+// collide; the node keeps concurrent test runs apart.
+var testIDs = func() *SnowflakeGenerator {
+	g, err := NewSnowflakeGenerator(int64(os.Getpid() % (MaxSnowflakeNode + 1)))
+	if err != nil {
+		panic(err)
+	}
+	return g
+}()
+
+func TestCrawlingSessionRepoConformance(t *testing.T) {
+	repotest.RunCrawlingSessionConformance(t, NewCrawlingSessionRepo(openTestDB(t), testIDs))
+}
+
+func TestAuditRepoConformance(t *testing.T) {
+	repotest.RunAuditCheckConformance(t, NewAuditRepo(openTestDB(t), testIDs))
+}
+
+func TestAuditResultRepoConformance(t *testing.T) {
+	repotest.RunAuditResultConformance(t, NewAuditResultRepo(openTestDB(t)))
+}
+
+func TestViewRepoConformance(t *testing.T) {
+	repotest.RunViewConformance(t, NewViewRepo(openTestDB(t), testIDs))
+}
+
+func TestAlertRepoConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunAlertRuleConformance(t, NewAlertRuleRepo(db, testIDs))
+	repotest.RunAlertConformance(t, NewAlertRepo(db, testIDs))
+}
+
+func TestTemplateRepoConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunAuditCheckTemplateConformance(t, NewAuditCheckTemplateRepo(db, testIDs))
+	repotest.RunViewTemplateConformance(t, NewViewTemplateRepo(db, testIDs))
+}
+
+func TestStatsRepoConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunStatsConformance(t, repotest.StatsHarness{
+		Repo: NewStatsRepo(db),
+		Seed: func(t *testing.T, f repotest.StatsFixture) []int64 { return seedStatsFixture(t, db, f) },
+	})
+}
+
+func TestCheckPagesConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunCheckPagesConformance(t, repotest.CheckPagesHarness{
+		Repo: NewCrawlingSessionCheckRepo(db),
+		Seed: func(t *testing.T, f repotest.StatsFixture) []int64 { return seedStatsFixture(t, db, f) },
+	})
+}
+
+func TestSessionDiffConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunSessionDiffConformance(t, repotest.SessionDiffHarness{
+		Repo: NewPageSnapshotRepo(db),
+		Seed: func(t *testing.T, f repotest.StatsFixture) []int64 { return seedStatsFixture(t, db, f) },
+	})
+}
+
+// seedStatsFixture stores the fixture, taking session and page ids from
+// testIDs as the repositories do.
+func seedStatsFixture(t *testing.T, db *sql.DB, f repotest.StatsFixture) []int64 {
+	t.Helper()
+	ctx := context.Background()
+	t.Cleanup(func() {
+		db.ExecContext(ctx, `ALTER TABLE page_links DELETE WHERE source_page_id IN (SELECT id FROM pages
+			WHERE crawling_session_id IN (SELECT id FROM crawling_sessions WHERE search_keyword_url_id = ?))`, f.SKU)
+		db.ExecContext(ctx, `ALTER TABLE pages DELETE WHERE crawling_session_id IN (SELECT id FROM crawling_sessions WHERE search_keyword_url_id = ?)`, f.SKU)
+		db.ExecContext(ctx, `ALTER TABLE crawling_sessions DELETE WHERE search_keyword_url_id = ?`, f.SKU)
+		db.ExecContext(ctx, `ALTER TABLE audit_checks DELETE WHERE search_keyword_url_id = ?`, f.SKU)
+	})
+
+	checks := NewAuditRepo(db, testIDs)
+	for _, c := range f.Checks {
+		c.SearchKeywordURLID = f.SKU
+		if err := checks.Create(ctx, &c); err != nil {
+			t.Fatalf("seed audit check: %v", err)
+		}
+	}
+
+	nextID := func() int64 {
+		id, err := testIDs.NextID(ctx)
+		if err != nil {
+			t.Fatalf("generate id: %v", err)
+		}
+		return id
+	}
+	ids := make([]int64, len(f.Sessions))
+	for i, pages := range f.Sessions {
+		ids[i] = nextID()
+		now := time.Now().UTC()
+		if _, err := db.ExecContext(ctx, `INSERT INTO crawling_sessions (id, search_keyword_url_id, url, status, created_at, updated_at)
+			VALUES (?, ?, '', 'finished', ?, ?)`, ids[i], f.SKU, now, now); err != nil {
+			t.Fatalf("seed crawling session: %v", err)
+		}
+		pageIDs := make([]int64, len(pages))
+		for j, p := range pages {
+			pageIDs[j] = nextID()
+			if _, err := db.ExecContext(ctx, `INSERT INTO pages (id, crawling_session_id, url, response_code, redirect_code, depth,
+				indexability, indexability_reason, title, meta_description, canonical, content_hash, og_title, og_description, content_type, response_time_ms)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				pageIDs[j], ids[i], p.URL, p.ResponseCode, p.RedirectCode, p.Depth, p.Indexability, p.IndexabilityReason,
+				p.Title, p.MetaDescription, p.Canonical, p.ContentHash, p.OGTitle, p.OGDescription, p.ContentType, p.ResponseTimeMs); err != nil {
+				t.Fatalf("seed page: %v", err)
+			}
+		}
+		for j, p := range pages {
+			for _, target := range p.LinksTo {
+				if _, err := db.ExecContext(ctx, `INSERT INTO page_links (source_page_id, target_page_id) VALUES (?, ?)`,
+					pageIDs[j], pageIDs[target]); err != nil {
+					t.Fatalf("seed page link: %v", err)
+				}
+			}
+		}
+	}
+	return ids
+}
@@ -10,15 +10,20 @@ import (
 	"time"
 
 	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/scoring"
 	"sitecrawler/newgo/models"
 )
 
 type CrawlingSessionRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	ids IDGenerator
 }
 
-func NewCrawlingSessionRepo(db *sql.DB) *CrawlingSessionRepo {
-	return &CrawlingSessionRepo{db: db}
+func NewCrawlingSessionRepo(db *sql.DB, ids IDGenerator) *CrawlingSessionRepo {
+	if ids == nil {
+		panic("id generator required")
+	}
+	return &CrawlingSessionRepo{db: db, ids: ids}
 }
 
 func (r *CrawlingSessionRepo) Create(ctx context.Context, cs *models.CrawlingSession) error {
@@ -40,28 +45,21 @@ func (r *CrawlingSessionRepo) Create(ctx context.Context, cs *models.CrawlingSes
 
 	// ClickHouse INSERT
 	q := `INSERT INTO crawling_sessions (
-		search_keyword_url_id, url, status, queue, version, options, 
+		id, search_keyword_url_id, url, status, queue, version, options, 
 		created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, q,
-		cs.SearchKeywordURLID, cs.URL, cs.Status, cs.Queue, cs.Version,
-		string(optJSON), cs.CreatedAt, cs.UpdatedAt,
-	)
+	id, err := r.ids.NextID(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, q,
+		id, cs.SearchKeywordURLID, cs.URL, cs.Status, cs.Queue, cs.Version,
+		string(optJSON), cs.CreatedAt, cs.UpdatedAt,
+	); err != nil {
 		return err
 	}
 
-	// ClickHouse doesn't auto-generate IDs like Postgres SERIAL
-	// We need to either use a separate sequence or generate IDs client-side
-	id, err := result.LastInsertId()
-	if err != nil {
-		// Fallback: query max ID + 1 (not ideal for concurrency)
-		err = r.db.QueryRowContext(ctx, "SELECT max(id) + 1 FROM crawling_sessions").Scan(&id)
-		if err != nil {
-			return fmt.Errorf("failed to get session ID: %w", err)
-		}
-	}
 	cs.ID = id
 	return nil
 }
@@ -96,7 +94,7 @@ func (r *CrawlingSessionRepo) GetByID(ctx context.Context, id int64) (*models.Cr
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("crawling session not found")
+			return nil, repository.ErrCrawlingSessionNotFound
 		}
 		return nil, err
 	}
@@ -199,6 +197,45 @@ func (r *CrawlingSessionRepo) MarkDone(ctx context.Context, id int64, reason str
 	return err
 }
 
+func (r *CrawlingSessionRepo) ListDoneBySKU(ctx context.Context, skuID int64, from, to *time.Time) ([]models.CrawlingSession, error) {
+	q := `SELECT id, search_keyword_url_id, url, status, queue, version, started_at, ended_at, created_at, updated_at
+	      FROM crawling_sessions WHERE search_keyword_url_id = ? AND status = 'done' AND ended_at > 0`
+	args := []any{skuID}
+	if from != nil {
+		q += " AND ended_at >= ?"
+		args = append(args, from.Unix())
+	}
+	if to != nil {
+		q += " AND ended_at <= ?"
+		args = append(args, to.Unix())
+	}
+	q += " ORDER BY ended_at ASC, id ASC"
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.CrawlingSession
+	for rows.Next() {
+		var cs models.CrawlingSession
+		var startedAt, endedAt int64
+		if err := rows.Scan(&cs.ID, &cs.SearchKeywordURLID, &cs.URL, &cs.Status, &cs.Queue, &cs.Version,
+			&startedAt, &endedAt, &cs.CreatedAt, &cs.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if startedAt > 0 {
+			t := time.Unix(startedAt, 0)
+			cs.StartedAt = &t
+		}
+		t := time.Unix(endedAt, 0)
+		cs.EndedAt = &t
+		out = append(out, cs)
+	}
+	return out, rows.Err()
+}
+
 func (r *CrawlingSessionRepo) UpdateSiteInfo(ctx context.Context, id int64, info repository.SiteInfo) error {
 	// Convert slices to JSON arrays for ClickHouse
 	ipsJSON, _ := json.Marshal(info.IPs)
@@ -237,29 +274,33 @@ func NewCrawlingSessionPageRepo(db *sql.DB) *CrawlingSessionPageRepo {
 }
 
 func (r *CrawlingSessionPageRepo) List(ctx context.Context, params repository.PageListParams) ([]models.Page, int, error) {
-	whereClause := "crawling_session_id = ?"
-	args := []any{params.SessionID}
-
-	for _, filter := range params.Filters {
-		for key, value := range filter {
-			whereClause += fmt.Sprintf(" AND %s = ?", key)
-			args = append(args, value)
-		}
+	scope := pageScope{params.SessionID}
+	whereClause, args, err := appendFilterMaps("crawling_session_id = ?", []any{params.SessionID}, params.Filters, scope)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM pages WHERE %s", whereClause)
-	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	whereClause, args, err = appendFilterConfig(whereClause, args, params.FilterConfig, scope)
+	if err != nil {
 		return nil, 0, err
 	}
 
 	orderClause := "id ASC"
 	if params.Sort != "" {
+		col, ok := allowedPageColumn(params.Sort, scope)
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid sort column: %s", params.Sort)
+		}
 		direction := "ASC"
 		if strings.ToUpper(params.Direction) == "DESC" {
 			direction = "DESC"
 		}
-		orderClause = fmt.Sprintf("%s %s", params.Sort, direction)
+		orderClause = fmt.Sprintf("%s %s", col, direction)
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM pages WHERE %s", whereClause)
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
 	}
 
 	limit := params.PageLimit
@@ -271,7 +312,9 @@ func (r *CrawlingSessionPageRepo) List(ctx context.Context, params repository.Pa
 		offset = (params.Page - 1) * limit
 	}
 
-	query := fmt.Sprintf(`SELECT id, crawling_session_id, url, response_code FROM pages WHERE %s ORDER BY %s LIMIT ? OFFSET ?`, whereClause, orderClause)
+	query := fmt.Sprintf(`SELECT id, crawling_session_id, url, response_code, indexability, indexability_reason,
+		response_time_ms, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_size, uncompressed_size, content_encoding, resource_count
+		FROM pages WHERE %s ORDER BY %s LIMIT ? OFFSET ?`, whereClause, orderClause)
 	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -283,9 +326,16 @@ func (r *CrawlingSessionPageRepo) List(ctx context.Context, params repository.Pa
 	var pages []models.Page
 	for rows.Next() {
 		var p models.Page
-		if err := rows.Scan(&p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode); err != nil {
+		var perf models.PagePerformance
+		if err := rows.Scan(&p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode, &p.Indexability, &p.IndexabilityReason,
+			&perf.ResponseTimeMs, &perf.DNSMs, &perf.ConnectMs, &perf.TLSMs, &perf.TTFBMs,
+			&perf.TransferSize, &perf.UncompressedSize, &perf.ContentEncoding, &perf.ResourceCount); err != nil {
 			return nil, 0, err
 		}
+		// Pages fetched before timings were recorded have no performance data.
+		if perf.ResponseTimeMs > 0 {
+			p.Performance = &perf
+		}
 		pages = append(pages, p)
 	}
 	return pages, total, rows.Err()
@@ -299,66 +349,169 @@ func NewCrawlingSessionCheckRepo(db *sql.DB) *CrawlingSessionCheckRepo {
 	return &CrawlingSessionCheckRepo{db: db}
 }
 
+// ChecksWithPages evaluates every audit check of the session's SKU against
+// the session's pages, restricted by the view filters. With a comparison
+// session, each check's matches in both sessions are joined by URL into new,
+// resolved and persisting pages. A check whose filter_config cannot be
+// evaluated is returned with its error.
 func (r *CrawlingSessionCheckRepo) ChecksWithPages(ctx context.Context, params repository.ChecksWithPagesParams) ([]models.CheckWithPages, error) {
-	whereClause := "p.crawling_session_id = ?"
-	args := []any{params.SessionID}
-
-	for _, filter := range params.ViewFilters {
-		for key, value := range filter {
-			whereClause += fmt.Sprintf(" AND p.%s = ?", key)
-			args = append(args, value)
-		}
+	// The view scope is shared by both sides of the comparison.
+	sessions := checkPagesScope(params)
+	scope, scopeArgs, err := appendFilterMaps("1", nil, params.ViewFilters, sessions)
+	if err != nil {
+		return nil, err
 	}
-
-	if params.ComparisonSessionID != nil {
-		whereClause += " OR p.crawling_session_id = ?"
-		args = append(args, *params.ComparisonSessionID)
+	scope, scopeArgs, err = appendFilterConfig(scope, scopeArgs, params.FilterConfig, sessions)
+	if err != nil {
+		return nil, err
 	}
 
-	query := fmt.Sprintf(`SELECT c.id, c.name, p.id, p.crawling_session_id, p.url, p.response_code
-		FROM audit_checks c LEFT JOIN pages p ON p.crawling_session_id = ?
-		WHERE %s ORDER BY c.id ASC, p.id ASC`, whereClause)
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, filter_config FROM audit_checks
+		WHERE search_keyword_url_id = (SELECT search_keyword_url_id FROM crawling_sessions WHERE id = ?)
+		ORDER BY id ASC`, params.SessionID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	checksMap := make(map[int64]*models.CheckWithPages)
-	var orderedIDs []int64
-
+	type checkRow struct {
+		check models.CheckWithPages
+		cfg   map[string]any
+	}
+	var checks []checkRow
 	for rows.Next() {
-		var checkID int64
-		var checkName string
-		var pageID, pageCrawlingSessionID sql.NullInt64
-		var pageURL sql.NullString
-		var pageResponseCode sql.NullInt64
-
-		if err := rows.Scan(&checkID, &checkName, &pageID, &pageCrawlingSessionID, &pageURL, &pageResponseCode); err != nil {
+		var c checkRow
+		var raw string
+		if err := rows.Scan(&c.check.ID, &c.check.Name, &raw); err != nil {
+			rows.Close()
 			return nil, err
 		}
-
-		check, exists := checksMap[checkID]
-		if !exists {
-			check = &models.CheckWithPages{ID: checkID, Name: checkName, Pages: []models.Page{}}
-			checksMap[checkID] = check
-			orderedIDs = append(orderedIDs, checkID)
+		if raw != "" {
+			if err := json.Unmarshal([]byte(raw), &c.cfg); err != nil {
+				c.check.Error = "invalid filter_config: " + err.Error()
+			}
 		}
+		checks = append(checks, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		if pageID.Valid && len(check.Pages) < params.PageLimitPerCheck {
-			check.Pages = append(check.Pages, models.Page{
-				ID: pageID.Int64, CrawlingSessionID: pageCrawlingSessionID.Int64,
-				URL: pageURL.String, ResponseCode: int(pageResponseCode.Int64),
-			})
+	result := make([]models.CheckWithPages, 0, len(checks))
+	for _, c := range checks {
+		check := c.check
+		check.Pages = []models.Page{}
+		if params.ComparisonSessionID != nil {
+			check.Comparison = repository.NewCheckPageDiff(*params.ComparisonSessionID)
 		}
+		if check.Error == "" {
+			if err := r.fillCheckPages(ctx, &check, c.cfg, scope, scopeArgs, params); err != nil {
+				check.Error = err.Error()
+			}
+		}
+		result = append(result, check)
 	}
+	return result, nil
+}
 
-	var result []models.CheckWithPages
-	for _, id := range orderedIDs {
-		result = append(result, *checksMap[id])
+// checkPagesScope is the session and, when comparing, the comparison
+// session whose pages ChecksWithPages reads.
+func checkPagesScope(params repository.ChecksWithPagesParams) pageScope {
+	if params.ComparisonSessionID != nil {
+		return pageScope{params.SessionID, *params.ComparisonSessionID}
+	}
+	return pageScope{params.SessionID}
+}
+
+func (r *CrawlingSessionCheckRepo) fillCheckPages(ctx context.Context, check *models.CheckWithPages, cfg map[string]any,
+	scope string, scopeArgs []any, params repository.ChecksWithPagesParams) error {
+	clause, clauseArgs, err := buildFilterConfigClause(cfg, checkPagesScope(params))
+	if err != nil {
+		return err
+	}
+	if clause == "" {
+		return nil
+	}
+	matches := fmt.Sprintf("SELECT id, url, response_code FROM pages WHERE crawling_session_id = ? AND %s AND (%s)", scope, clause)
+	matchArgs := func(sessionID int64) []any {
+		return append(append([]any{sessionID}, scopeArgs...), clauseArgs...)
+	}
+
+	if check.Comparison == nil {
+		q := matches + " ORDER BY id ASC LIMIT ?"
+		rows, err := r.db.QueryContext(ctx, q, append(matchArgs(params.SessionID), params.PageLimitPerCheck)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			p := models.Page{CrawlingSessionID: params.SessionID}
+			if err := rows.Scan(&p.ID, &p.URL, &p.ResponseCode); err != nil {
+				return err
+			}
+			check.Pages = append(check.Pages, p)
+		}
+		return rows.Err()
+	}
+
+	// Pages pair up by normalized URL, keeping the first page of each URL in
+	// a session, as the session diff does. Unmatched sides of the outer join
+	// hold default values, so a zero id marks a page missing from that
+	// session.
+	comparisonID := *params.ComparisonSessionID
+	keyed := fmt.Sprintf("SELECT id, url, response_code, %s AS url_key FROM (%s) ORDER BY id ASC LIMIT 1 BY url_key",
+		normalizedURLExpr("url"), matches)
+	q := fmt.Sprintf(`SELECT status, id, session_id, url, response_code, total FROM (
+			SELECT status, id, session_id, url, response_code,
+				row_number() OVER (PARTITION BY status ORDER BY id) AS rn,
+				count() OVER (PARTITION BY status) AS total
+			FROM (
+				SELECT multiIf(prev.id = 0, '%[2]s', cur.id = 0, '%[3]s', '%[4]s') AS status,
+					if(cur.id = 0, prev.id, cur.id) AS id,
+					if(cur.id = 0, toInt64(?), toInt64(?)) AS session_id,
+					if(cur.id = 0, prev.url, cur.url) AS url,
+					if(cur.id = 0, prev.response_code, cur.response_code) AS response_code
+				FROM (%[1]s) AS cur FULL OUTER JOIN (%[1]s) AS prev ON cur.url_key = prev.url_key
+			)
+		) WHERE rn <= ? ORDER BY status, id`,
+		keyed, models.CheckPageNew, models.CheckPageResolved, models.CheckPagePersisting)
+	args := []any{comparisonID, params.SessionID}
+	args = append(args, matchArgs(params.SessionID)...)
+	args = append(args, matchArgs(comparisonID)...)
+	args = append(args, params.PageLimitPerCheck)
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var p models.Page
+		var total int
+		if err := rows.Scan(&status, &p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode, &total); err != nil {
+			return err
+		}
+		repository.AddCheckDiffPage(check.Comparison, status, p, total)
+	}
+	if err := rows.Err(); err != nil {
+		return err
 	}
-	return result, rows.Err()
+	check.Pages = repository.CurrentCheckPages(check.Comparison, params.PageLimitPerCheck)
+	return nil
+}
+
+// normalizedURLExpr normalizes the URL in col the way analysis.NormalizeURL
+// does: scheme and host lowercased, default ports and the fragment dropped,
+// and an empty path read as "/". Values that are not absolute URLs are kept
+// as they are. The patterns spell "?" as \x3F so the driver does not read
+// it as a placeholder.
+func normalizedURLExpr(col string) string {
+	return fmt.Sprintf(`if(extract(trimBoth(%[1]s), '^[A-Za-z][A-Za-z0-9+.-]*://[^/\\x3F#]*') = '', %[1]s, concat(
+		replaceRegexpOne(replaceRegexpOne(lower(extract(trimBoth(%[1]s), '^[A-Za-z][A-Za-z0-9+.-]*://[^/\\x3F#]*')),
+			'^(http://[^/]*):80$', '\\1'), '^(https://[^/]*):443$', '\\1'),
+		if(extract(trimBoth(%[1]s), '^[A-Za-z][A-Za-z0-9+.-]*://[^/\\x3F#]*([^\\x3F#]*)') = '', '/',
+			extract(trimBoth(%[1]s), '^[A-Za-z][A-Za-z0-9+.-]*://[^/\\x3F#]*([^\\x3F#]*)')),
+		extract(trimBoth(%[1]s), '^[^\\x3F#]*(\\x3F[^#]*)')))`, col)
 }
 
 type PageDetailsRepo struct {
@@ -456,58 +609,335 @@ func NewStatsRepo(db *sql.DB) *StatsRepo {
 }
 
 func (r *StatsRepo) Fetch(ctx context.Context, params repository.StatsQueryParams) (map[string]any, error) {
-	whereClause := "crawling_session_id = ?"
-	args := []any{params.SessionID}
+	whereClause, args, err := buildStatsWhere(params.SessionID, params)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, filter := range params.Prefilters {
-		for key, value := range filter {
-			whereClause += fmt.Sprintf(" AND %s = ?", key)
-			args = append(args, value)
+	query := fmt.Sprintf(`SELECT %s FROM pages WHERE %s`, statsCountColumns, whereClause)
+	var counts repository.StatsCounts
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(counts.Dest()...); err != nil {
+		return nil, err
+	}
+	result := counts.Map()
+
+	// The sections below read columns and tables a database created before
+	// them lacks; one that cannot be computed is left out instead of failing
+	// the request.
+	if indexability, err := r.fetchIndexability(ctx, whereClause, args); err == nil {
+		result["indexability"] = indexability
+	}
+	if structuredData, err := r.fetchStructuredDataCoverage(ctx, whereClause, args, counts.Total); err == nil {
+		result["structured_data"] = structuredData
+	}
+	if security, err := r.fetchSecuritySummary(ctx, whereClause, args, params.SessionID); err == nil {
+		result["security"] = security
+	}
+	if performance, err := r.fetchPerformanceSummary(ctx, whereClause, args); err == nil {
+		result["performance"] = performance
+	}
+	if depths, err := r.fetchDepthHistogram(ctx, whereClause, args); err == nil {
+		result["depth_histogram"] = depths
+	}
+
+	checkResults, problematicCount, err := r.fetchCheckResults(ctx, whereClause, args, params.SessionID)
+	if err == nil {
+		result["problematic"] = problematicCount
+		health, formula := scoring.SiteHealth(counts.Total, checkResults)
+		result["site_health"] = health
+		result["site_health_formula"] = formula
+	}
+
+	if params.GroupBy != nil {
+		if groups, err := r.fetchGroups(ctx, whereClause, args, *params.GroupBy, params.SessionID); err == nil {
+			result["group_by"] = params.GroupBy.Field
+			result["groups"] = groups
 		}
 	}
 
-	for _, filter := range params.Filters {
-		for key, value := range filter {
-			whereClause += fmt.Sprintf(" AND %s = ?", key)
-			args = append(args, value)
+	if params.ComparisonSessionID != nil {
+		cWhere, cArgs, err := buildStatsWhere(*params.ComparisonSessionID, params)
+		if err == nil {
+			cQuery := fmt.Sprintf(`SELECT %s FROM pages WHERE %s`, statsCountColumns, cWhere)
+			var comparison repository.StatsCounts
+			if err := r.db.QueryRowContext(ctx, cQuery, cArgs...).Scan(comparison.Dest()...); err == nil {
+				result["comparison"] = comparison.Map()
+				result["changes"] = counts.Changes(comparison)
+			}
 		}
 	}
 
-	query := fmt.Sprintf(`SELECT COUNT(*) as total_pages,
-		countIf(response_code >= 200 AND response_code < 300) as success_pages,
-		countIf(response_code >= 300 AND response_code < 400) as redirect_pages,
-		countIf(response_code >= 400 AND response_code < 500) as client_error_pages,
-		countIf(response_code >= 500) as server_error_pages
+	return result, nil
+}
+
+// fetchIndexability counts the indexable and non-indexable filtered pages,
+// the latter also per reason.
+func (r *StatsRepo) fetchIndexability(ctx context.Context, whereClause string, args []any) (map[string]any, error) {
+	query := fmt.Sprintf(`SELECT %s FROM pages WHERE %s`, indexabilityColumns, whereClause)
+	var indexable, nonIndexable int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&indexable, &nonIndexable); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`SELECT indexability_reason, count() FROM pages
+		WHERE %s AND indexability = 'non_indexable' GROUP BY indexability_reason`, whereClause)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reasons := map[string]int{}
+	for rows.Next() {
+		var reason string
+		var count int
+		if err := rows.Scan(&reason, &count); err != nil {
+			return nil, err
+		}
+		reasons[reason] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return map[string]any{"indexable": indexable, "non_indexable": nonIndexable, "reasons": reasons}, nil
+}
+
+// fetchStructuredDataCoverage reports how many of the filtered pages carry
+// structured data, overall and per schema.org type, and how many have errors.
+func (r *StatsRepo) fetchStructuredDataCoverage(ctx context.Context, whereClause string, args []any, total int) (map[string]any, error) {
+	query := fmt.Sprintf(`SELECT countIf(structured_data_types != '') as with_data,
+		countIf(structured_data_errors > 0) as with_errors
 		FROM pages WHERE %s`, whereClause)
+	var withData, withErrors int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&withData, &withErrors); err != nil {
+		return nil, err
+	}
 
-	var totalPages, successPages, redirectPages, clientErrorPages, serverErrorPages int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&totalPages, &successPages, &redirectPages, &clientErrorPages, &serverErrorPages)
+	query = fmt.Sprintf(`SELECT type, uniqExact(page_id),
+		uniqExactIf(page_id, missing_properties != '' OR error != '')
+		FROM page_structured_data WHERE type != '' AND page_id IN (SELECT id FROM pages WHERE %s)
+		GROUP BY type`, whereClause)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	result := map[string]any{
-		"total_pages": totalPages, "success_pages": successPages, "redirect_pages": redirectPages,
-		"client_error_pages": clientErrorPages, "server_error_pages": serverErrorPages,
+	types := map[string]any{}
+	for rows.Next() {
+		var typ string
+		var pages, errored int
+		if err := rows.Scan(&typ, &pages, &errored); err != nil {
+			return nil, err
+		}
+		coverage := 0
+		if total > 0 {
+			coverage = pages * 100 / total
+		}
+		types[typ] = map[string]any{"pages": pages, "pages_with_errors": errored, "coverage": coverage}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	if params.ComparisonSessionID != nil {
-		compQuery := `SELECT COUNT(*) as total_pages,
-			countIf(response_code >= 200 AND response_code < 300) as success_pages,
-			countIf(response_code >= 300 AND response_code < 400) as redirect_pages,
-			countIf(response_code >= 400 AND response_code < 500) as client_error_pages,
-			countIf(response_code >= 500) as server_error_pages
-			FROM pages WHERE crawling_session_id = ?`
-
-		var compTotal, compSuccess, compRedirect, compClientError, compServerError int
-		err := r.db.QueryRowContext(ctx, compQuery, *params.ComparisonSessionID).Scan(&compTotal, &compSuccess, &compRedirect, &compClientError, &compServerError)
-		if err == nil {
-			result["comparison"] = map[string]any{
-				"total_pages": compTotal, "success_pages": compSuccess, "redirect_pages": compRedirect,
-				"client_error_pages": compClientError, "server_error_pages": compServerError,
-			}
+	return map[string]any{"pages": withData, "pages_with_errors": withErrors, "types": types}, nil
+}
+
+// fetchSecuritySummary counts the filtered pages per security issue. The
+// session-level certificate check is reported next to the per-page findings.
+func (r *StatsRepo) fetchSecuritySummary(ctx context.Context, whereClause string, args []any, sessionID int64) (map[string]any, error) {
+	query := fmt.Sprintf(`SELECT countIf(security_issues != '') as with_issues,
+		countIf(mixed_content_count > 0) as mixed_content
+		FROM pages WHERE %s`, whereClause)
+	var withIssues, mixedContent int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&withIssues, &mixedContent); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`SELECT issue, count() FROM pages
+		ARRAY JOIN splitByChar(',', security_issues) AS issue
+		WHERE %s AND issue != '' GROUP BY issue`, whereClause)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issues := map[string]int{}
+	for rows.Next() {
+		var issue string
+		var count int
+		if err := rows.Scan(&issue, &count); err != nil {
+			return nil, err
 		}
+		issues[issue] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	out := map[string]any{"pages_with_issues": withIssues, "mixed_content_pages": mixedContent, "issues": issues}
+	var sslValid bool
+	if err := r.db.QueryRowContext(ctx, `SELECT ssl_valid FROM crawling_sessions WHERE id = ?`, sessionID).Scan(&sslValid); err == nil {
+		out["ssl_valid"] = sslValid
+	}
+	return out, nil
+}
+
+// fetchCheckResults counts the filtered pages matched by every audit check of
+// the session's SKU in a single scan, together with the pages matched by any
+// problematic check.
+func (r *StatsRepo) fetchCheckResults(ctx context.Context, whereClause string, args []any, sessionID int64) ([]scoring.CheckResult, int, error) {
+	results, selects, selectArgs, err := r.checkCountSelects(ctx, sessionID)
+	if err != nil {
+		return nil, 0, err
+	}
+	counts := make([]int, len(selects))
+	dest := make([]any, len(selects))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	// The select list precedes the WHERE clause in the query.
+	query := fmt.Sprintf("SELECT %s FROM pages WHERE %s", strings.Join(selects, ", "), whereClause)
+	if err := r.db.QueryRowContext(ctx, query, append(selectArgs, args...)...).Scan(dest...); err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		results[i].MatchedPages = counts[i]
+	}
+	return results, counts[len(counts)-1], nil
+}
+
+// checkCountSelects returns the audit checks of the session's SKU with one
+// count per check, followed by the count of pages matched by any problematic
+// check, and their args. Checks whose filter_config does not build are left
+// out.
+func (r *StatsRepo) checkCountSelects(ctx context.Context, sessionID int64) ([]scoring.CheckResult, []string, []any, error) {
+	var skuID int64
+	if err := r.db.QueryRowContext(ctx, `SELECT search_keyword_url_id FROM crawling_sessions WHERE id = ?`, sessionID).Scan(&skuID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, category, ifNull(severity, ''), ifNull(weight, 0), filter_config
+		FROM audit_checks WHERE search_keyword_url_id = ? ORDER BY id ASC`, skuID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	var results []scoring.CheckResult
+	var selects []string
+	var selectArgs []any
+	var problematicGroups []any
+	for rows.Next() {
+		var c scoring.CheckResult
+		var raw string
+		if err := rows.Scan(&c.ID, &c.Name, &c.Category, &c.Severity, &c.Weight, &raw); err != nil {
+			return nil, nil, nil, err
+		}
+		var cfg map[string]any
+		if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+			continue
+		}
+		clause, clauseArgs, err := buildFilterConfigClause(cfg, pageScope{sessionID})
+		if err != nil || clause == "" {
+			continue
+		}
+		selects = append(selects, fmt.Sprintf("countIf(%s)", clause))
+		selectArgs = append(selectArgs, clauseArgs...)
+		results = append(results, c)
+		if c.Category == scoring.CategoryProblematic {
+			problematicGroups = append(problematicGroups, cfg["filter_groups"].([]any)...)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// A page is problematic when any group of any problematic check matches
+	// it, which is the union of their groups evaluated as a single config.
+	probClause, probArgs, err := buildFilterConfigClause(map[string]any{"filter_groups": problematicGroups}, pageScope{sessionID})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if probClause == "" {
+		probClause = "0"
+	}
+	selects = append(selects, fmt.Sprintf("countIf(%s)", probClause))
+	return results, selects, append(selectArgs, probArgs...), nil
+}
+
+// fetchDepthHistogram counts the filtered pages at every crawl depth, in
+// depth order, split by response status class.
+func (r *StatsRepo) fetchDepthHistogram(ctx context.Context, whereClause string, args []any) ([]models.DepthLevel, error) {
+	query := fmt.Sprintf(`SELECT depth, count(),
+		countIf(response_code >= 200 AND response_code < 300),
+		countIf(response_code >= 300 AND response_code < 400),
+		countIf(response_code >= 400 AND response_code < 500),
+		countIf(response_code >= 500 AND response_code < 600)
+		FROM pages WHERE %s AND depth IS NOT NULL GROUP BY depth ORDER BY depth`, whereClause)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []models.DepthLevel{}
+	for rows.Next() {
+		var level models.DepthLevel
+		var s2xx, s3xx, s4xx, s5xx int
+		if err := rows.Scan(&level.Depth, &level.Pages, &s2xx, &s3xx, &s4xx, &s5xx); err != nil {
+			return nil, err
+		}
+		level.StatusClasses = map[string]int{"2xx": s2xx, "3xx": s3xx, "4xx": s4xx, "5xx": s5xx}
+		levels = append(levels, level)
+	}
+	return levels, rows.Err()
+}
+
+// timedPages keeps the pages with a recorded response time. Pages that were
+// not timed hold 0.
+const timedPages = "response_time_ms > 0"
+
+// fetchPerformanceSummary reports response time percentiles, average page
+// weight and the number of slow pages. Pages without recorded timings are
+// left out.
+func (r *StatsRepo) fetchPerformanceSummary(ctx context.Context, whereClause string, args []any) (map[string]any, error) {
+	query := fmt.Sprintf(`SELECT %s FROM pages WHERE %s AND %s`, performanceColumns, whereClause, timedPages)
+	queryArgs := append([]any{repository.SlowPageThresholdMs}, args...)
+
+	var p performanceRow
+	if err := r.db.QueryRowContext(ctx, query, queryArgs...).Scan(p.dest()...); err != nil {
+		return nil, err
+	}
+	return p.summary(), nil
+}
+
+// performanceColumns selects the aggregates of a performanceRow, reading
+// the slow page threshold from its only placeholder. Aggregates over no rows
+// yield nan, which JSON cannot encode.
+const performanceColumns = `ifNotFinite(quantileExactInclusive(0.5)(response_time_ms), 0),
+		ifNotFinite(quantileExactInclusive(0.9)(response_time_ms), 0),
+		ifNotFinite(quantileExactInclusive(0.99)(response_time_ms), 0),
+		ifNotFinite(avg(transfer_size), 0), ifNotFinite(avg(uncompressed_size), 0),
+		countIf(response_time_ms > ?)`
+
+// performanceRow holds the aggregates selected by performanceColumns.
+type performanceRow struct {
+	p50, p90, p99, avgTransfer, avgUncompressed float64
+	slow                                        int
+}
+
+func (p *performanceRow) dest() []any {
+	return []any{&p.p50, &p.p90, &p.p99, &p.avgTransfer, &p.avgUncompressed, &p.slow}
+}
+
+func (p performanceRow) summary() map[string]any {
+	return map[string]any{
+		"response_time_ms":       map[string]float64{"p50": p.p50, "p90": p.p90, "p99": p.p99},
+		"avg_page_weight":        int64(p.avgTransfer),
+		"avg_uncompressed_size":  int64(p.avgUncompressed),
+		"slow_pages":             p.slow,
+		"slow_page_threshold_ms": repository.SlowPageThresholdMs,
+	}
 }
@@ -0,0 +1,271 @@
+package clickhouse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sitecrawler/newgo/internal/repository"
+)
+
+var pageIdentRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// pageScope lists the sessions a query reads pages of. Virtual columns such
+// as duplicate_title_count aggregate only these sessions, so their cost
+// follows the crawls involved rather than the whole table.
+type pageScope []int64
+
+// sessions renders the scope for an IN list. The ids are int64 values, so
+// they are written inline rather than bound, which keeps the expressions
+// free of placeholders wherever they land in a query.
+func (s pageScope) sessions() string {
+	ids := make([]string, len(s))
+	for i, id := range s {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// buildFilterConfigClause turns an audit check or view filter_config into a
+// WHERE fragment with ? placeholders: filter groups are ORed, the filters of
+// a group ANDed. A config without filter groups yields an empty clause.
+func buildFilterConfigClause(cfg map[string]any, scope pageScope) (string, []any, error) {
+	raw, ok := cfg["filter_groups"]
+	if !ok || raw == nil {
+		return "", nil, nil
+	}
+	groups, ok := raw.([]any)
+	if !ok {
+		return "", nil, errors.New("filter_groups must be a list")
+	}
+
+	var orParts []string
+	var args []any
+	for _, item := range groups {
+		g, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		var clause string
+		var groupArgs []any
+		var err error
+		if _, ok := g["filters"]; ok {
+			clause, groupArgs, err = buildGroupClause(g, scope)
+		} else {
+			clause, groupArgs, err = buildEqualityClause(g, scope)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if clause == "" {
+			continue
+		}
+		args = append(args, groupArgs...)
+		orParts = append(orParts, "("+clause+")")
+	}
+	return strings.Join(orParts, " OR "), args, nil
+}
+
+// appendFilterConfig ANDs cfg onto where. A nil cfg leaves where unchanged;
+// a config without filter groups matches nothing.
+func appendFilterConfig(where string, args []any, cfg map[string]any, scope pageScope) (string, []any, error) {
+	if cfg == nil {
+		return where, args, nil
+	}
+	clause, clauseArgs, err := buildFilterConfigClause(cfg, scope)
+	if err != nil {
+		return "", nil, err
+	}
+	if clause == "" {
+		clause = "0"
+	}
+	return where + " AND (" + clause + ")", append(args, clauseArgs...), nil
+}
+
+// buildStatsWhere restricts the pages of sessionID to the stats request's
+// prefilters, filters and view filter config.
+func buildStatsWhere(sessionID int64, params repository.StatsQueryParams) (string, []any, error) {
+	scope := pageScope{sessionID}
+	where, args, err := appendFilterMaps("crawling_session_id = ?", []any{sessionID}, params.Prefilters, scope)
+	if err != nil {
+		return "", nil, err
+	}
+	where, args, err = appendFilterMaps(where, args, params.Filters, scope)
+	if err != nil {
+		return "", nil, err
+	}
+	return appendFilterConfig(where, args, params.FilterConfig, scope)
+}
+
+// appendFilterMaps ANDs request filters onto where: equality maps such as
+// {"response_code":200} and filter groups such as {"filters":[...]}.
+func appendFilterMaps(where string, args []any, filters []map[string]any, scope pageScope) (string, []any, error) {
+	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
+		var clause string
+		var clauseArgs []any
+		var err error
+		if _, ok := filter["filters"]; ok {
+			clause, clauseArgs, err = buildGroupClause(filter, scope)
+		} else {
+			clause, clauseArgs, err = buildEqualityClause(filter, scope)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			where += " AND (" + clause + ")"
+			args = append(args, clauseArgs...)
+		}
+	}
+	return where, args, nil
+}
+
+func buildEqualityClause(m map[string]any, scope pageScope) (string, []any, error) {
+	var parts []string
+	var args []any
+	for key, value := range m {
+		col, ok := allowedPageColumn(key, scope)
+		if !ok {
+			return "", nil, fmt.Errorf("invalid filter column: %s", key)
+		}
+		if value == nil {
+			parts = append(parts, col+" IS NULL")
+			continue
+		}
+		parts = append(parts, col+" = ?")
+		args = append(args, value)
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+func buildGroupClause(group map[string]any, scope pageScope) (string, []any, error) {
+	rawSlice, ok := group["filters"].([]any)
+	if !ok {
+		return "", nil, errors.New("invalid filters format")
+	}
+
+	var parts []string
+	var args []any
+	for _, item := range rawSlice {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		op, _ := m["operator"].(string)
+		op = strings.ToLower(strings.TrimSpace(op))
+		if op == "" {
+			op = "eq"
+		}
+
+		col, ok := allowedPageColumn(name, scope)
+		if !ok {
+			return "", nil, fmt.Errorf("invalid filter column: %s", name)
+		}
+		clause, clauseArgs, err := buildCondition(col, op, m["value"])
+		if err != nil {
+			return "", nil, err
+		}
+		if clause == "" {
+			continue
+		}
+		parts = append(parts, clause)
+		args = append(args, clauseArgs...)
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+func buildCondition(col, op string, value any) (string, []any, error) {
+	switch op {
+	case "eq":
+		if value == nil {
+			return col + " IS NULL", nil, nil
+		}
+		return col + " = ?", []any{value}, nil
+	case "neq":
+		if value == nil {
+			return col + " IS NOT NULL", nil, nil
+		}
+		return col + " != ?", []any{value}, nil
+	case "gt":
+		return col + " > ?", []any{value}, nil
+	case "gte":
+		return col + " >= ?", []any{value}, nil
+	case "lt":
+		return col + " < ?", []any{value}, nil
+	case "lte":
+		return col + " <= ?", []any{value}, nil
+	case "isnull":
+		return col + " IS NULL", nil, nil
+	case "notnull":
+		return col + " IS NOT NULL", nil, nil
+	case "contains":
+		return "positionCaseInsensitive(" + col + ", ?) > 0", []any{value}, nil
+	case "in":
+		vals, ok := value.([]any)
+		if !ok || len(vals) == 0 {
+			return "", nil, nil
+		}
+		ph := strings.TrimSuffix(strings.Repeat("?,", len(vals)), ",")
+		return fmt.Sprintf("%s IN (%s)", col, ph), vals, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported operator: %s", op)
+	}
+}
+
+// virtualPageColumn returns the expression of a filterable name computed per
+// row rather than read from a pages column.
+func virtualPageColumn(name string, scope pageScope) (string, bool) {
+	switch name {
+	case "duplicate_title_count":
+		return duplicateCountExpr("title", scope), true
+	case "duplicate_description_count":
+		return duplicateCountExpr("meta_description", scope), true
+	case "duplicate_content_count":
+		return duplicateCountExpr("content_hash", scope), true
+	case "title_length":
+		return "lengthUTF8(title)", true
+	case "meta_description_length":
+		return "lengthUTF8(meta_description)", true
+	case "h1_length":
+		return "lengthUTF8(h1)", true
+	case "inlinks_count":
+		return inlinksCountExpr(scope), true
+	}
+	return "", false
+}
+
+// inlinksCountExpr counts the links pointing at the page, looked up in a
+// map of link counts per target for the same reason as duplicateCountExpr.
+// Only links to pages of the scope's sessions are counted.
+func inlinksCountExpr(scope pageScope) string {
+	return fmt.Sprintf(`(SELECT mapFromArrays(groupArray(target), groupArray(n))
+	FROM (SELECT toInt64(target_page_id) AS target, count() AS n FROM page_links
+		WHERE target_page_id IN (SELECT id FROM pages WHERE crawling_session_id IN (%s)) GROUP BY target))[toInt64(pages.id)]`, scope.sessions())
+}
+
+// duplicateCountExpr counts pages of the same session sharing col, row
+// included; empty values never count. ClickHouse cannot correlate a
+// subquery with the outer row, so the counts of the scope's sessions and
+// values are built once into a map the row is looked up in.
+func duplicateCountExpr(col string, scope pageScope) string {
+	return fmt.Sprintf(`if(ifNull(%[1]s, '') = '', 0, (SELECT mapFromArrays(groupArray(k), groupArray(n))
+		FROM (SELECT concat(toString(crawling_session_id), ':', ifNull(%[1]s, '')) AS k, count() AS n
+			FROM pages WHERE crawling_session_id IN (%[2]s) AND ifNull(%[1]s, '') != '' GROUP BY k))[concat(toString(crawling_session_id), ':', ifNull(%[1]s, ''))])`,
+		col, scope.sessions())
+}
+
+func allowedPageColumn(name string, scope pageScope) (string, bool) {
+	if expr, ok := virtualPageColumn(name, scope); ok {
+		return expr, true
+	}
+	if !pageIdentRE.MatchString(name) {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,77 @@
+package clickhouse
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+)
+
+func TestBuildStatsWhere(t *testing.T) {
+	where, args, err := buildStatsWhere(7, repository.StatsQueryParams{
+		Prefilters: []map[string]any{{"depth": 1}},
+		Filters: []map[string]any{
+			{"filters": []any{map[string]any{"name": "response_code", "operator": "gte", "value": 400}}},
+		},
+		FilterConfig: map[string]any{"filter_groups": []any{map[string]any{"indexability": "indexable"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "crawling_session_id = ? AND (depth = ?) AND (response_code >= ?) AND ((indexability = ?))"
+	if where != want {
+		t.Fatalf("expected where %q got %q", want, where)
+	}
+	if !reflect.DeepEqual(args, []any{int64(7), 1, 400, "indexable"}) {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+}
+
+func TestBuildStatsWhereRejectsUnknownColumns(t *testing.T) {
+	for _, filters := range [][]map[string]any{
+		{{"depth = 1 OR 1": 1}},
+		{{"filters": []any{map[string]any{"name": "depth;", "operator": "eq", "value": 1}}}},
+	} {
+		if _, _, err := buildStatsWhere(1, repository.StatsQueryParams{Filters: filters}); err == nil {
+			t.Fatalf("expected an error for filters %v", filters)
+		}
+	}
+}
+
+func TestCrawlingSessionPageRepoListRejectsUnsafeInput(t *testing.T) {
+	// Both are rejected before any query runs, so no database is needed.
+	repo := NewCrawlingSessionPageRepo(nil)
+	cases := map[string]repository.PageListParams{
+		"sort":        {SessionID: 1, Sort: "url; DROP TABLE pages --"},
+		"filter key":  {SessionID: 1, Filters: []map[string]any{{"1 = 1 OR depth": 1}}},
+		"filter name": {SessionID: 1, Filters: []map[string]any{{"filters": []any{map[string]any{"name": "depth)", "operator": "eq", "value": 1}}}}},
+	}
+	for name, params := range cases {
+		if _, _, err := repo.List(context.Background(), params); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+	if _, _, err := repo.List(context.Background(), cases["sort"]); err == nil || !strings.Contains(err.Error(), "invalid sort column") {
+		t.Fatalf("expected an invalid sort column error got %v", err)
+	}
+}
+
+func TestNormalizedURLExprHasNoPlaceholder(t *testing.T) {
+	if expr := normalizedURLExpr("url"); strings.Contains(expr, "?") {
+		t.Fatalf("expected no ? in %s", expr)
+	}
+}
+
+func TestVirtualColumnsReadOnlyScopedSessions(t *testing.T) {
+	for _, name := range []string{"duplicate_title_count", "duplicate_description_count", "duplicate_content_count", "inlinks_count"} {
+		expr, ok := allowedPageColumn(name, pageScope{3, 5})
+		if !ok {
+			t.Fatalf("%s: expected a virtual column", name)
+		}
+		if !strings.Contains(expr, "crawling_session_id IN (3, 5)") || strings.Contains(expr, "?") {
+			t.Fatalf("%s: expected the sessions inline and no placeholder in %s", name, expr)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IDGenerator hands out the ids of new rows. ClickHouse has no SERIAL
+// columns, so every Create asks it for the id before inserting; ids must be
+// unique across all processes writing to the same database.
+type IDGenerator interface {
+	NextID(ctx context.Context) (int64, error)
+}
+
+const (
+	snowflakeTimeBits     = 32
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 11
+	// MaxSnowflakeNode is the largest node id a SnowflakeGenerator accepts.
+	MaxSnowflakeNode      = 1<<snowflakeNodeBits - 1
+	snowflakeSequenceMask = 1<<snowflakeSequenceBits - 1
+)
+
+// snowflakeEpoch is the start of the generator's clock in seconds; 32 bits
+// of seconds last about 136 years.
+var snowflakeEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeGenerator builds ids from the seconds since snowflakeEpoch, a
+// node id and a per-second sequence. Ids never carry a second the clock has
+// not reached, so they are unique as long as no two processes writing to
+// the database use the same node id at the same time. That includes
+// restarts: a process taking over a node id must not start before the
+// previous holder has stopped and the clock has moved past the last second
+// it used. Ids stay below 2^53 so that JSON clients decoding them as
+// doubles read them exactly.
+type SnowflakeGenerator struct {
+	mu    sync.Mutex
+	node  int64
+	last  int64
+	seq   int64
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewSnowflakeGenerator(node int64) (*SnowflakeGenerator, error) {
+	if node < 0 || node > MaxSnowflakeNode {
+		return nil, fmt.Errorf("snowflake node must be between 0 and %d", MaxSnowflakeNode)
+	}
+	return &SnowflakeGenerator{node: node, now: time.Now, sleep: sleepContext}, nil
+}
+
+// NextID returns an id greater than every id the generator returned before.
+// When the clock goes backwards it keeps counting in the last second used;
+// once that second's sequence runs out it waits for the clock to reach the
+// next second, or for ctx to end.
+func (g *SnowflakeGenerator) NextID(ctx context.Context) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for {
+		now := g.now()
+		sec := int64(now.Sub(snowflakeEpoch) / time.Second)
+		switch {
+		case sec > g.last:
+			if sec >= 1<<snowflakeTimeBits {
+				return 0, fmt.Errorf("snowflake clock exhausted")
+			}
+			g.last, g.seq = sec, 0
+		case g.seq < snowflakeSequenceMask:
+			g.seq++
+		default:
+			next := snowflakeEpoch.Add(time.Duration(g.last+1) * time.Second)
+			if err := g.sleep(ctx, next.Sub(now)); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		return g.last<<(snowflakeNodeBits+snowflakeSequenceBits) | g.node<<snowflakeSequenceBits | g.seq, nil
+	}
+}
+
+// sleepContext waits for d to pass or ctx to end, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SequenceGenerator takes ids from a Postgres sequence, for deployments
+// that already run Postgres next to ClickHouse.
+type SequenceGenerator struct {
+	db       *sql.DB
+	sequence string
+}
+
+// NewSequenceGenerator returns a generator drawing from the named sequence
+// of the Postgres database db.
+func NewSequenceGenerator(db *sql.DB, sequence string) *SequenceGenerator {
+	return &SequenceGenerator{db: db, sequence: sequence}
+}
+
+func (g *SequenceGenerator) NextID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := g.db.QueryRowContext(ctx, `SELECT nextval($1::regclass)`, g.sequence).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to read sequence %s: %w", g.sequence, err)
+	}
+	return id, nil
+}
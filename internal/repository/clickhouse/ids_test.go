@@ -0,0 +1,188 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeGeneratorConcurrentIDsAreUnique(t *testing.T) {
+	g, err := NewSnowflakeGenerator(7)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+
+	// More ids than one second's sequence holds, so some wait for the clock.
+	const workers, perWorker = 8, 300
+	ids := make(chan int64, workers*perWorker)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				id, err := g.NextID(context.Background())
+				if err != nil {
+					t.Errorf("next id: %v", err)
+					return
+				}
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int64]bool, workers*perWorker)
+	for id := range ids {
+		if id <= 0 {
+			t.Fatalf("expected a positive id got %d", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+		if node := id >> snowflakeSequenceBits & MaxSnowflakeNode; node != 7 {
+			t.Fatalf("expected node 7 in id %d got %d", id, node)
+		}
+	}
+}
+
+// fakeClock stands in for the generator's clock; sleeping moves it forward.
+type fakeClock struct {
+	now    time.Time
+	slept  time.Duration
+	sleeps int
+}
+
+func (c *fakeClock) install(g *SnowflakeGenerator) {
+	g.now = func() time.Time { return c.now }
+	g.sleep = func(ctx context.Context, d time.Duration) error {
+		c.now = c.now.Add(d)
+		c.slept += d
+		c.sleeps++
+		return nil
+	}
+}
+
+func TestSnowflakeGeneratorStaysMonotonic(t *testing.T) {
+	g, err := NewSnowflakeGenerator(1)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+	clock := &fakeClock{now: snowflakeEpoch.Add(time.Hour)}
+	clock.install(g)
+
+	var last int64
+	next := func() int64 {
+		t.Helper()
+		id, err := g.NextID(context.Background())
+		if err != nil {
+			t.Fatalf("next id: %v", err)
+		}
+		if id <= last {
+			t.Fatalf("expected id above %d got %d", last, id)
+		}
+		last = id
+		return id
+	}
+
+	// Exhaust one second's sequence, then move the clock backwards.
+	for i := 0; i <= snowflakeSequenceMask+1; i++ {
+		next()
+	}
+	clock.now = clock.now.Add(-time.Second)
+	next()
+}
+
+func TestSnowflakeGeneratorWaitsForTheNextSecond(t *testing.T) {
+	g, err := NewSnowflakeGenerator(1)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+	clock := &fakeClock{now: snowflakeEpoch.Add(time.Hour + 300*time.Millisecond)}
+	clock.install(g)
+
+	second := func(id int64) int64 { return id >> (snowflakeNodeBits + snowflakeSequenceBits) }
+	for i := 0; i <= snowflakeSequenceMask; i++ {
+		if _, err := g.NextID(context.Background()); err != nil {
+			t.Fatalf("next id: %v", err)
+		}
+	}
+	if clock.sleeps != 0 {
+		t.Fatalf("expected no wait within the sequence got %d", clock.sleeps)
+	}
+
+	id, err := g.NextID(context.Background())
+	if err != nil {
+		t.Fatalf("next id: %v", err)
+	}
+	if clock.sleeps != 1 || clock.slept != 700*time.Millisecond {
+		t.Fatalf("expected one wait of 700ms got %d waits of %s", clock.sleeps, clock.slept)
+	}
+	if want := int64(clock.now.Sub(snowflakeEpoch) / time.Second); second(id) != want {
+		t.Fatalf("expected an id of second %d got %d", want, second(id))
+	}
+}
+
+func TestSnowflakeGeneratorStopsWaitingWhenContextEnds(t *testing.T) {
+	g, err := NewSnowflakeGenerator(1)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+	// The clock is stuck, so only the context ends the wait.
+	g.now = func() time.Time { return snowflakeEpoch.Add(time.Hour) }
+	for i := 0; i <= snowflakeSequenceMask; i++ {
+		if _, err := g.NextID(context.Background()); err != nil {
+			t.Fatalf("next id: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := g.NextID(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline got %v", err)
+	}
+}
+
+func TestNewSnowflakeGeneratorRejectsInvalidNodes(t *testing.T) {
+	for _, node := range []int64{-1, MaxSnowflakeNode + 1} {
+		if _, err := NewSnowflakeGenerator(node); err == nil {
+			t.Fatalf("expected node %d to be rejected", node)
+		}
+	}
+}
+
+func TestSnowflakeIDsStayBelow2To53(t *testing.T) {
+	g, err := NewSnowflakeGenerator(MaxSnowflakeNode)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+	// The last second the clock can represent, with its sequence used up.
+	clock := &fakeClock{now: snowflakeEpoch.Add((1<<snowflakeTimeBits - 1) * time.Second)}
+	clock.install(g)
+	for i := 0; i <= snowflakeSequenceMask; i++ {
+		id, err := g.NextID(context.Background())
+		if err != nil {
+			t.Fatalf("next id: %v", err)
+		}
+		if id >= 1<<53 {
+			t.Fatalf("expected an id below 2^53 got %d", id)
+		}
+	}
+	if _, err := g.NextID(context.Background()); err == nil {
+		t.Fatalf("expected an error once the clock is exhausted")
+	}
+
+	g.now, g.sleep = time.Now, sleepContext
+	g.last, g.seq = 0, 0
+	id, err := g.NextID(context.Background())
+	if err != nil {
+		t.Fatalf("next id: %v", err)
+	}
+	if id >= 1<<53 {
+		t.Fatalf("expected an id below 2^53 got %d", id)
+	}
+}
@@ -0,0 +1,212 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"sitecrawler/newgo/internal/analysis"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+type PageAnalysisRepo struct {
+	db *sql.DB
+}
+
+func NewPageAnalysisRepo(db *sql.DB) *PageAnalysisRepo {
+	return &PageAnalysisRepo{db: db}
+}
+
+func (r *PageAnalysisRepo) SaveIndexability(ctx context.Context, pageID int64, result models.PageIndexability) error {
+	q := `ALTER TABLE pages UPDATE indexability = ?, indexability_reason = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, q, result.Status, result.Reason, pageID)
+	return err
+}
+
+func (r *PageAnalysisRepo) SaveContentFingerprint(ctx context.Context, pageID int64, fp models.PageContentFingerprint) error {
+	q := `ALTER TABLE pages UPDATE content_hash = ?, simhash = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, q, fp.ContentHash, fp.SimHash, pageID)
+	return err
+}
+
+func (r *PageAnalysisRepo) SaveHreflangLinks(ctx context.Context, pageID int64, links []models.HreflangLink) error {
+	if _, err := r.db.ExecContext(ctx, `ALTER TABLE page_hreflangs DELETE WHERE page_id = ?`, pageID); err != nil {
+		return err
+	}
+	for _, l := range links {
+		q := `INSERT INTO page_hreflangs (page_id, hreflang, href, source) VALUES (?, ?, ?, ?)`
+		if _, err := r.db.ExecContext(ctx, q, pageID, l.Hreflang, l.Href, l.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveHreflangIssues stores the issue types the session-wide hreflang audit
+// found for the page, so the built-in hreflang checks can filter on them.
+func (r *PageAnalysisRepo) SaveHreflangIssues(ctx context.Context, pageID int64, issues []models.HreflangIssue) error {
+	q := `ALTER TABLE pages UPDATE hreflang_issues = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, q, strings.Join(analysis.HreflangIssueTypes(issues), ","), pageID)
+	return err
+}
+
+// SaveStructuredData stores the item breakdown in page_structured_data and
+// denormalizes the types and error count onto pages for filtering.
+func (r *PageAnalysisRepo) SaveStructuredData(ctx context.Context, pageID int64, items []models.StructuredDataItem) error {
+	q := `ALTER TABLE pages UPDATE structured_data_types = ?, structured_data_errors = ? WHERE id = ?`
+	types := strings.Join(analysis.StructuredDataTypes(items), ",")
+	if _, err := r.db.ExecContext(ctx, q, types, analysis.StructuredDataErrorCount(items), pageID); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `ALTER TABLE page_structured_data DELETE WHERE page_id = ?`, pageID); err != nil {
+		return err
+	}
+	for _, it := range items {
+		q := `INSERT INTO page_structured_data (page_id, format, type, missing_properties, error) VALUES (?, ?, ?, ?, ?)`
+		if _, err := r.db.ExecContext(ctx, q, pageID, it.Format, it.Type, strings.Join(it.Missing, ","), it.Error); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveSecurity stores the issue list on pages and replaces the page's
+// mixed-content resources.
+func (r *PageAnalysisRepo) SaveSecurity(ctx context.Context, pageID int64, result models.PageSecurity) error {
+	q := `ALTER TABLE pages UPDATE security_issues = ?, mixed_content_count = ? WHERE id = ?`
+	if _, err := r.db.ExecContext(ctx, q, strings.Join(result.Issues, ","), len(result.MixedContent), pageID); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `ALTER TABLE page_mixed_content DELETE WHERE page_id = ?`, pageID); err != nil {
+		return err
+	}
+	for _, u := range result.MixedContent {
+		if _, err := r.db.ExecContext(ctx, `INSERT INTO page_mixed_content (page_id, url) VALUES (?, ?)`, pageID, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PageAnalysisRepo) SavePerformance(ctx context.Context, pageID int64, perf models.PagePerformance) error {
+	q := `ALTER TABLE pages UPDATE dns_ms = ?, connect_ms = ?, tls_ms = ?, ttfb_ms = ?, response_time_ms = ?,
+		transfer_size = ?, uncompressed_size = ?, content_encoding = ?, resource_count = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, q, perf.DNSMs, perf.ConnectMs, perf.TLSMs, perf.TTFBMs, perf.ResponseTimeMs,
+		perf.TransferSize, perf.UncompressedSize, perf.ContentEncoding, perf.ResourceCount, pageID)
+	return err
+}
+
+type PageSnapshotRepo struct {
+	db *sql.DB
+}
+
+func NewPageSnapshotRepo(db *sql.DB) *PageSnapshotRepo {
+	return &PageSnapshotRepo{db: db}
+}
+
+func (r *PageSnapshotRepo) ListSnapshots(ctx context.Context, sessionID int64) ([]models.PageSnapshot, error) {
+	q := `SELECT id, url, response_code, title, meta_description, content_hash, simhash, canonical, indexability
+	      FROM pages WHERE crawling_session_id = ? ORDER BY id ASC`
+	rows, err := r.db.QueryContext(ctx, q, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.PageSnapshot
+	for rows.Next() {
+		var s models.PageSnapshot
+		if err := rows.Scan(&s.ID, &s.URL, &s.ResponseCode, &s.Title, &s.MetaDescription, &s.ContentHash, &s.SimHash, &s.Canonical, &s.Indexability); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *PageSnapshotRepo) ListHreflangLinks(ctx context.Context, sessionID int64) (map[int64][]models.HreflangLink, error) {
+	q := `SELECT h.page_id, h.hreflang, h.href, h.source
+	      FROM page_hreflangs AS h INNER JOIN pages AS p ON p.id = h.page_id
+	      WHERE p.crawling_session_id = ? ORDER BY h.page_id ASC, h.hreflang ASC`
+	rows, err := r.db.QueryContext(ctx, q, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64][]models.HreflangLink{}
+	for rows.Next() {
+		var pageID int64
+		var l models.HreflangLink
+		if err := rows.Scan(&pageID, &l.Hreflang, &l.Href, &l.Source); err != nil {
+			return nil, err
+		}
+		out[pageID] = append(out[pageID], l)
+	}
+	return out, rows.Err()
+}
+
+// DiffSessions pairs the pages of both sessions by normalized URL, keeping
+// the first page of each URL, and compares them as analysis.DiffPages does.
+// Unmatched sides of the outer join hold default values, so a zero id marks
+// a page missing from that session. Every change list is numbered in page
+// id order and only the requested page of it is returned.
+func (r *PageSnapshotRepo) DiffSessions(ctx context.Context, params repository.SessionDiffParams) (map[string]*models.PageChangeSet, error) {
+	diff := repository.NewSessionDiff(params)
+	if len(diff) == 0 {
+		return diff, nil
+	}
+	ph := make([]string, 0, len(diff))
+	changeArgs := make([]any, 0, len(diff))
+	for change := range diff {
+		ph = append(ph, "?")
+		changeArgs = append(changeArgs, change)
+	}
+
+	keyed := fmt.Sprintf(`SELECT id, url, response_code, title, canonical, indexability, content_hash, %s AS url_key
+		FROM pages WHERE crawling_session_id = ? ORDER BY id ASC LIMIT 1 BY url_key`, normalizedURLExpr("url"))
+	q := fmt.Sprintf(`SELECT change_type, rn, total, cur_id, cur_url, cur_response_code, cur_title, cur_canonical, cur_indexability,
+			cur_content_hash, prev_id, prev_url, prev_response_code, prev_title, prev_canonical, prev_indexability, prev_content_hash
+		FROM (
+			SELECT *, row_number() OVER (PARTITION BY change_type ORDER BY if(cur_id = 0, prev_id, cur_id)) AS rn,
+				count() OVER (PARTITION BY change_type) AS total
+			FROM (
+				SELECT multiIf(prev.id = 0, '%[2]s', cur.id = 0, '%[3]s', '%[4]s') AS change_type,
+					cur.id AS cur_id, cur.url AS cur_url, cur.response_code AS cur_response_code, cur.title AS cur_title,
+					cur.canonical AS cur_canonical, cur.indexability AS cur_indexability, cur.content_hash AS cur_content_hash,
+					prev.id AS prev_id, prev.url AS prev_url, prev.response_code AS prev_response_code, prev.title AS prev_title,
+					prev.canonical AS prev_canonical, prev.indexability AS prev_indexability, prev.content_hash AS prev_content_hash
+				FROM (%[1]s) AS cur FULL OUTER JOIN (%[1]s) AS prev ON cur.url_key = prev.url_key
+				WHERE cur.id = 0 OR prev.id = 0
+					OR cur.response_code != prev.response_code OR cur.title != prev.title
+					OR (cur.canonical != prev.canonical AND (cur.canonical = '' OR prev.canonical = '' OR %[5]s != %[6]s))
+					OR (cur.indexability != '' AND prev.indexability != '' AND cur.indexability != prev.indexability)
+					OR (cur.content_hash != '' AND prev.content_hash != '' AND cur.content_hash != prev.content_hash)
+			) WHERE change_type IN (%[7]s)
+		) WHERE rn = 1 OR (rn > ? AND rn <= ?) ORDER BY change_type, rn`,
+		keyed, models.PageChangeAdded, models.PageChangeRemoved, models.PageChangeChanged,
+		normalizedURLExpr("cur.canonical"), normalizedURLExpr("prev.canonical"), strings.Join(ph, ", "))
+	args := []any{params.SessionID, params.AgainstSessionID}
+	args = append(args, changeArgs...)
+	args = append(args, params.Offset(), params.Offset()+params.PageLimit)
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var change string
+		var row, total int
+		var cur, prev models.PageSnapshot
+		if err := rows.Scan(&change, &row, &total,
+			&cur.ID, &cur.URL, &cur.ResponseCode, &cur.Title, &cur.Canonical, &cur.Indexability, &cur.ContentHash,
+			&prev.ID, &prev.URL, &prev.ResponseCode, &prev.Title, &prev.Canonical, &prev.Indexability, &prev.ContentHash); err != nil {
+			return nil, err
+		}
+		repository.AddSessionDiffRow(diff, change, row, total, params.Offset(), cur, prev)
+	}
+	return diff, rows.Err()
+}
@@ -0,0 +1,262 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/scoring"
+)
+
+// statsCountColumns selects the page counts of repository.StatsCounts.
+const statsCountColumns = `count() AS total,
+		countIf(ifNull(og_title, '') = '' OR ifNull(og_description, '') = '') AS warning,
+		countIf(response_code >= 400 AND response_code <= 599) AS error,
+		countIf(response_code >= 200 AND response_code <= 299 AND ifNull(redirect_code, '') = '') AS ok,
+		countIf(redirect_code IN ('301','302','307','308')) AS redirection,
+		countIf(depth = 1) AS level1,
+		countIf(depth = 2) AS level2,
+		countIf(depth = 3) AS level3,
+		countIf(depth = 4) AS level4,
+		countIf(response_code >= 200 AND response_code < 300) AS success_pages,
+		countIf(response_code >= 300 AND response_code < 400) AS redirect_pages,
+		countIf(response_code >= 400 AND response_code < 500) AS client_error_pages,
+		countIf(response_code >= 500) AS server_error_pages`
+
+// indexabilityColumns selects the indexable and non-indexable page counts.
+const indexabilityColumns = `countIf(indexability = 'indexable') AS indexable,
+		countIf(indexability = 'non_indexable') AS non_indexable`
+
+// statsGroupExpr returns the SQL expression keying a page by group, with its
+// args. Pages without a key fall into "".
+func statsGroupExpr(g repository.StatsGroupBy) (string, []any, error) {
+	switch g.Field {
+	case repository.StatsGroupDirectory:
+		dir := fmt.Sprintf(`extract(url, '^[^:]+://[^/?#]+(/(?:[^/?#]+/){0,%d})')`, g.Segments)
+		return fmt.Sprintf(`if(%[1]s = '', '/', %[1]s)`, dir), nil, nil
+	case repository.StatsGroupSubdomain:
+		return `lower(extract(url, '^[^:]+://([^/?#:]+)'))`, nil, nil
+	case repository.StatsGroupDepth:
+		return `toString(depth)`, nil, nil
+	case repository.StatsGroupContentType:
+		return `lower(trim(splitByChar(';', content_type)[1]))`, nil, nil
+	case repository.StatsGroupStatusClass:
+		return `if(response_code >= 100, concat(toString(intDiv(response_code, 100)), 'xx'), '')`, nil, nil
+	case repository.StatsGroupRegex:
+		return `extract(url, ?)`, []any{g.Pattern}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported group_by: %s", g.Field)
+	}
+}
+
+// fetchGroups returns the metrics of the largest groups of the filtered
+// pages, at most repository.MaxStatsGroups: the page counts, the security
+// and performance summaries, and the problematic count and site health from
+// the session's audit checks. Indexability reasons, structured data, the
+// depth histogram and the certificate check are only reported for the
+// whole selection. Like the sections of the whole selection, a group metric
+// the database cannot compute is left out of every group.
+func (r *StatsRepo) fetchGroups(ctx context.Context, whereClause string, args []any, g repository.StatsGroupBy, sessionID int64) ([]map[string]any, error) {
+	expr, exprArgs, err := statsGroupExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	// The group expression precedes the WHERE clause in the query.
+	queryArgs := append(append(exprArgs, args...), repository.MaxStatsGroups)
+	query := fmt.Sprintf(`SELECT %s AS group_key, %s FROM pages WHERE %s
+		GROUP BY group_key ORDER BY total DESC, group_key ASC LIMIT ?`, expr, statsCountColumns, whereClause)
+	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []map[string]any{}
+	byKey := map[string]map[string]any{}
+	for rows.Next() {
+		var key string
+		var counts repository.StatsCounts
+		if err := rows.Scan(append([]any{&key}, counts.Dest()...)...); err != nil {
+			return nil, err
+		}
+		group := counts.Map()
+		group["key"] = key
+		groups = append(groups, group)
+		byKey[key] = group
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_ = r.fillGroupIndexability(ctx, whereClause, args, g, byKey)
+	_ = r.fillGroupSecurity(ctx, whereClause, args, g, byKey)
+	_ = r.fillGroupPerformance(ctx, whereClause, args, g, byKey)
+	_ = r.fillGroupCheckResults(ctx, whereClause, args, g, sessionID, byKey)
+	return groups, nil
+}
+
+// fillGroupIndexability sets the indexable and non-indexable page counts of
+// every group.
+func (r *StatsRepo) fillGroupIndexability(ctx context.Context, whereClause string, args []any, g repository.StatsGroupBy, byKey map[string]map[string]any) error {
+	expr, exprArgs, err := statsGroupExpr(g)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`SELECT %s AS group_key, %s FROM pages WHERE %s GROUP BY group_key`, expr, indexabilityColumns, whereClause)
+	rows, err := r.db.QueryContext(ctx, query, append(exprArgs, args...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	indexability := map[string]map[string]any{}
+	for rows.Next() {
+		var key string
+		var indexable, nonIndexable int
+		if err := rows.Scan(&key, &indexable, &nonIndexable); err != nil {
+			return err
+		}
+		indexability[key] = map[string]any{"indexable": indexable, "non_indexable": nonIndexable}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for key, group := range byKey {
+		group["indexability"] = indexability[key]
+	}
+	return nil
+}
+
+// fillGroupSecurity sets the security summary of every group: the pages with
+// issues or mixed content and the pages per security issue.
+func (r *StatsRepo) fillGroupSecurity(ctx context.Context, whereClause string, args []any, g repository.StatsGroupBy, byKey map[string]map[string]any) error {
+	expr, exprArgs, err := statsGroupExpr(g)
+	if err != nil {
+		return err
+	}
+	queryArgs := append(exprArgs, args...)
+	query := fmt.Sprintf(`SELECT %s AS group_key,
+		countIf(security_issues != '') AS with_issues,
+		countIf(mixed_content_count > 0) AS mixed_content
+		FROM pages WHERE %s GROUP BY group_key`, expr, whereClause)
+	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	security := map[string]map[string]any{}
+	for rows.Next() {
+		var key string
+		var withIssues, mixedContent int
+		if err := rows.Scan(&key, &withIssues, &mixedContent); err != nil {
+			return err
+		}
+		security[key] = map[string]any{"pages_with_issues": withIssues, "mixed_content_pages": mixedContent, "issues": map[string]int{}}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`SELECT %s AS group_key, issue, count() FROM pages
+		ARRAY JOIN splitByChar(',', security_issues) AS issue
+		WHERE %s AND issue != '' GROUP BY group_key, issue`, expr, whereClause)
+	issueRows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer issueRows.Close()
+	for issueRows.Next() {
+		var key, issue string
+		var count int
+		if err := issueRows.Scan(&key, &issue, &count); err != nil {
+			return err
+		}
+		if s, ok := security[key]; ok {
+			s["issues"].(map[string]int)[issue] = count
+		}
+	}
+	if err := issueRows.Err(); err != nil {
+		return err
+	}
+	for key, group := range byKey {
+		group["security"] = security[key]
+	}
+	return nil
+}
+
+// fillGroupPerformance sets the performance summary of every group, empty
+// for groups without recorded timings.
+func (r *StatsRepo) fillGroupPerformance(ctx context.Context, whereClause string, args []any, g repository.StatsGroupBy, byKey map[string]map[string]any) error {
+	expr, exprArgs, err := statsGroupExpr(g)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`SELECT %s AS group_key, %s FROM pages WHERE %s AND %s GROUP BY group_key`,
+		expr, performanceColumns, whereClause, timedPages)
+	queryArgs := append(append(exprArgs, repository.SlowPageThresholdMs), args...)
+	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	performance := map[string]performanceRow{}
+	for rows.Next() {
+		var key string
+		var p performanceRow
+		if err := rows.Scan(append([]any{&key}, p.dest()...)...); err != nil {
+			return err
+		}
+		performance[key] = p
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for key, group := range byKey {
+		group["performance"] = performance[key].summary()
+	}
+	return nil
+}
+
+// fillGroupCheckResults sets the problematic count and site health of every
+// group from the session's audit checks, evaluated in a single scan.
+func (r *StatsRepo) fillGroupCheckResults(ctx context.Context, whereClause string, args []any, g repository.StatsGroupBy, sessionID int64, byKey map[string]map[string]any) error {
+	results, selects, selectArgs, err := r.checkCountSelects(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	expr, exprArgs, err := statsGroupExpr(g)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("SELECT %s AS group_key, %s FROM pages WHERE %s GROUP BY group_key", expr, strings.Join(selects, ", "), whereClause)
+	queryArgs := append(append(exprArgs, selectArgs...), args...)
+	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		counts := make([]int, len(selects))
+		dest := []any{&key}
+		for i := range counts {
+			dest = append(dest, &counts[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		group, ok := byKey[key]
+		if !ok {
+			continue
+		}
+		groupResults := append([]scoring.CheckResult{}, results...)
+		for i := range groupResults {
+			groupResults[i].MatchedPages = counts[i]
+		}
+		health, formula := scoring.SiteHealth(group["total"].(int), groupResults)
+		group["problematic"] = counts[len(counts)-1]
+		group["site_health"] = health
+		group["site_health_formula"] = formula
+	}
+	return rows.Err()
+}
@@ -0,0 +1,201 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+const auditCheckTemplateColumns = `id, name, category, severity, weight, filter_config, version, created_at, updated_at`
+
+type AuditCheckTemplateRepo struct {
+	db  *sql.DB
+	ids IDGenerator
+}
+
+func NewAuditCheckTemplateRepo(db *sql.DB, ids IDGenerator) *AuditCheckTemplateRepo {
+	if ids == nil {
+		panic("id generator required")
+	}
+	return &AuditCheckTemplateRepo{db: db, ids: ids}
+}
+
+func (r *AuditCheckTemplateRepo) Create(ctx context.Context, t *models.AuditCheckTemplate) error {
+	now := time.Now().UTC()
+	t.CreatedAt, t.UpdatedAt = now, now
+
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+
+	q := `INSERT INTO audit_check_templates (id, name, category, severity, weight, filter_config, version, created_at, updated_at)
+	      VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.ids.NextID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to generate audit check template ID: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, q, id, t.Name, t.Category, t.Severity, t.Weight, string(filterJSON), t.Version, t.CreatedAt, t.UpdatedAt); err != nil {
+		return err
+	}
+
+	t.ID = id
+	return nil
+}
+
+func (r *AuditCheckTemplateRepo) Update(ctx context.Context, t *models.AuditCheckTemplate) error {
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	t.UpdatedAt = time.Now().UTC()
+
+	q := `ALTER TABLE audit_check_templates UPDATE name = ?, category = ?, severity = ?, weight = ?, filter_config = ?, version = ?, updated_at = ?
+	      WHERE id = ?`
+	_, err = r.db.ExecContext(ctx, q, t.Name, t.Category, t.Severity, t.Weight, string(filterJSON), t.Version, t.UpdatedAt, t.ID)
+	return err
+}
+
+func (r *AuditCheckTemplateRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `ALTER TABLE audit_check_templates DELETE WHERE id = ?`, id)
+	return err
+}
+
+func (r *AuditCheckTemplateRepo) Get(ctx context.Context, id int64) (*models.AuditCheckTemplate, error) {
+	t, err := scanAuditCheckTemplate(r.db.QueryRowContext(ctx, `SELECT `+auditCheckTemplateColumns+` FROM audit_check_templates WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrTemplateNotFound
+	}
+	return t, err
+}
+
+func (r *AuditCheckTemplateRepo) List(ctx context.Context) ([]models.AuditCheckTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+auditCheckTemplateColumns+` FROM audit_check_templates ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.AuditCheckTemplate{}
+	for rows.Next() {
+		t, err := scanAuditCheckTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+func scanAuditCheckTemplate(row rowScanner) (*models.AuditCheckTemplate, error) {
+	var t models.AuditCheckTemplate
+	var filterJSON string
+	if err := row.Scan(&t.ID, &t.Name, &t.Category, &t.Severity, &t.Weight, &filterJSON, &t.Version, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if filterJSON != "" {
+		if err := json.Unmarshal([]byte(filterJSON), &t.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	return &t, nil
+}
+
+const viewTemplateColumns = `id, name, filter_config, version, created_at, updated_at`
+
+type ViewTemplateRepo struct {
+	db  *sql.DB
+	ids IDGenerator
+}
+
+func NewViewTemplateRepo(db *sql.DB, ids IDGenerator) *ViewTemplateRepo {
+	if ids == nil {
+		panic("id generator required")
+	}
+	return &ViewTemplateRepo{db: db, ids: ids}
+}
+
+func (r *ViewTemplateRepo) Create(ctx context.Context, t *models.ViewTemplate) error {
+	now := time.Now().UTC()
+	t.CreatedAt, t.UpdatedAt = now, now
+
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+
+	q := `INSERT INTO view_templates (id, name, filter_config, version, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`
+	id, err := r.ids.NextID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to generate view template ID: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, q, id, t.Name, string(filterJSON), t.Version, t.CreatedAt, t.UpdatedAt); err != nil {
+		return err
+	}
+
+	t.ID = id
+	return nil
+}
+
+func (r *ViewTemplateRepo) Update(ctx context.Context, t *models.ViewTemplate) error {
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	t.UpdatedAt = time.Now().UTC()
+
+	q := `ALTER TABLE view_templates UPDATE name = ?, filter_config = ?, version = ?, updated_at = ? WHERE id = ?`
+	_, err = r.db.ExecContext(ctx, q, t.Name, string(filterJSON), t.Version, t.UpdatedAt, t.ID)
+	return err
+}
+
+func (r *ViewTemplateRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `ALTER TABLE view_templates DELETE WHERE id = ?`, id)
+	return err
+}
+
+func (r *ViewTemplateRepo) Get(ctx context.Context, id int64) (*models.ViewTemplate, error) {
+	t, err := scanViewTemplate(r.db.QueryRowContext(ctx, `SELECT `+viewTemplateColumns+` FROM view_templates WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrTemplateNotFound
+	}
+	return t, err
+}
+
+func (r *ViewTemplateRepo) List(ctx context.Context) ([]models.ViewTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+viewTemplateColumns+` FROM view_templates ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.ViewTemplate{}
+	for rows.Next() {
+		t, err := scanViewTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+func scanViewTemplate(row rowScanner) (*models.ViewTemplate, error) {
+	var t models.ViewTemplate
+	var filterJSON string
+	if err := row.Scan(&t.ID, &t.Name, &filterJSON, &t.Version, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if filterJSON != "" {
+		if err := json.Unmarshal([]byte(filterJSON), &t.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	return &t, nil
+}
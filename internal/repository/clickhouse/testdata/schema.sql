@@ -0,0 +1,200 @@
+-- Tables the conformance suites expect, as the repositories in this package
+-- read and write them. Ids come from the snowflake generator; JSON values
+-- and string lists are stored as String, and started_at, ended_at and
+-- ssl_valid_until as unix seconds with 0 for unset. Every statement is
+-- idempotent so the suites can apply the file to a database that already
+-- holds the schema.
+
+CREATE TABLE IF NOT EXISTS crawling_sessions (
+    id                    Int64,
+    search_keyword_url_id Int64,
+    url                   String,
+    status                String,
+    queue                 Int32,
+    version               Int32,
+    options               String,
+    started_at            Int64 DEFAULT 0,
+    ended_at              Int64 DEFAULT 0,
+    end_reason            Nullable(String),
+    error                 Nullable(String),
+    ips                   String DEFAULT '[]',
+    dns_servers           String DEFAULT '[]',
+    aliases               String DEFAULT '[]',
+    location              String DEFAULT '',
+    sitemap               Bool DEFAULT false,
+    robots                Bool DEFAULT false,
+    ssl_valid             Bool DEFAULT false,
+    ssl_valid_until       Int64 DEFAULT 0,
+    pages_count           Int64 DEFAULT 0,
+    internal_urls_count   Int64 DEFAULT 0,
+    ignored_urls_count    Int64 DEFAULT 0,
+    external_urls_count   Int64 DEFAULT 0,
+    created_at            DateTime64(3, 'UTC'),
+    updated_at            DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY id;
+
+-- redirect_code is empty for pages that were not redirected.
+CREATE TABLE IF NOT EXISTS pages (
+    id                     Int64,
+    crawling_session_id    Int64,
+    url                    String,
+    response_code          Int32 DEFAULT 0,
+    redirect_code          String DEFAULT '',
+    redirect_chain_length  Int32 DEFAULT 0,
+    depth                  Int32 DEFAULT 0,
+    indexability           String DEFAULT '',
+    indexability_reason    String DEFAULT '',
+    title                  String DEFAULT '',
+    meta_description       String DEFAULT '',
+    h1                     String DEFAULT '',
+    canonical              String DEFAULT '',
+    og_title               String DEFAULT '',
+    og_description         String DEFAULT '',
+    content_type           String DEFAULT '',
+    word_count             Int32 DEFAULT 0,
+    content_hash           String DEFAULT '',
+    simhash                UInt64 DEFAULT 0,
+    hreflang_issues        String DEFAULT '',
+    structured_data_types  String DEFAULT '',
+    structured_data_errors Int32 DEFAULT 0,
+    security_issues        String DEFAULT '',
+    mixed_content_count    Int32 DEFAULT 0,
+    response_time_ms       Int64 DEFAULT 0,
+    dns_ms                 Int64 DEFAULT 0,
+    connect_ms             Int64 DEFAULT 0,
+    tls_ms                 Int64 DEFAULT 0,
+    ttfb_ms                Int64 DEFAULT 0,
+    transfer_size          Int64 DEFAULT 0,
+    uncompressed_size      Int64 DEFAULT 0,
+    content_encoding       String DEFAULT '',
+    resource_count         Int32 DEFAULT 0
+) ENGINE = MergeTree ORDER BY (crawling_session_id, id);
+
+CREATE TABLE IF NOT EXISTS page_links (
+    source_page_id Int64,
+    target_page_id Int64
+) ENGINE = MergeTree ORDER BY (source_page_id, target_page_id);
+
+CREATE TABLE IF NOT EXISTS page_images (
+    id      Int64,
+    page_id Int64,
+    url     String
+) ENGINE = MergeTree ORDER BY (page_id, id);
+
+CREATE TABLE IF NOT EXISTS page_hreflangs (
+    page_id  Int64,
+    hreflang String,
+    href     String,
+    source   String DEFAULT ''
+) ENGINE = MergeTree ORDER BY page_id;
+
+CREATE TABLE IF NOT EXISTS page_structured_data (
+    page_id            Int64,
+    format             String,
+    type               String DEFAULT '',
+    missing_properties String DEFAULT '',
+    error              String DEFAULT ''
+) ENGINE = MergeTree ORDER BY page_id;
+
+CREATE TABLE IF NOT EXISTS page_mixed_content (
+    page_id Int64,
+    url     String
+) ENGINE = MergeTree ORDER BY page_id;
+
+CREATE TABLE IF NOT EXISTS audit_checks (
+    id                    Int64,
+    search_keyword_url_id Int64,
+    name                  String,
+    category              String,
+    severity              String DEFAULT '',
+    weight                Float64 DEFAULT 0,
+    filter_config         String DEFAULT '',
+    builtin_key           String DEFAULT '',
+    builtin_version       Int32 DEFAULT 0,
+    customized            Bool DEFAULT false,
+    template_id           Nullable(Int64),
+    template_version      Int32 DEFAULT 0,
+    created_at            DateTime64(3, 'UTC'),
+    updated_at            DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY id;
+
+CREATE TABLE IF NOT EXISTS audit_results (
+    crawling_session_id Int64,
+    audit_check_id      Int64,
+    name                String,
+    category            String,
+    severity            String DEFAULT '',
+    filter_config       String DEFAULT '',
+    matched_pages       Int64 DEFAULT 0,
+    sample_page_ids     String DEFAULT '[]',
+    error               String DEFAULT '',
+    created_at          DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY (crawling_session_id, audit_check_id);
+
+CREATE TABLE IF NOT EXISTS audit_check_templates (
+    id            Int64,
+    name          String,
+    category      String,
+    severity      String DEFAULT '',
+    weight        Float64 DEFAULT 0,
+    filter_config String DEFAULT '',
+    version       Int32 DEFAULT 1,
+    created_at    DateTime64(3, 'UTC'),
+    updated_at    DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY id;
+
+CREATE TABLE IF NOT EXISTS views (
+    id                    Int64,
+    search_keyword_url_id Int64,
+    name                  String,
+    filter_config         String DEFAULT '',
+    columns               String DEFAULT '',
+    sort_field            String DEFAULT '',
+    sort_direction        String DEFAULT '',
+    page_size             Int32 DEFAULT 0,
+    compare_with_previous Bool DEFAULT false,
+    template_id           Nullable(Int64),
+    template_version      Int32 DEFAULT 0,
+    customized            Bool DEFAULT false,
+    created_at            DateTime64(3, 'UTC'),
+    updated_at            DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY id;
+
+CREATE TABLE IF NOT EXISTS view_templates (
+    id            Int64,
+    name          String,
+    filter_config String DEFAULT '',
+    version       Int32 DEFAULT 1,
+    created_at    DateTime64(3, 'UTC'),
+    updated_at    DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY id;
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id                    Int64,
+    search_keyword_url_id Int64,
+    name                  String,
+    audit_check_id        Nullable(Int64),
+    metric                String DEFAULT '',
+    mode                  String,
+    operator              String,
+    threshold             Float64,
+    webhook_url           String DEFAULT '',
+    emails                String DEFAULT '',
+    enabled               Bool DEFAULT true,
+    created_at            DateTime64(3, 'UTC'),
+    updated_at            DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY id;
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id                    Int64,
+    rule_id               Int64,
+    search_keyword_url_id Int64,
+    crawling_session_id   Int64,
+    rule_name             String,
+    value                 Float64,
+    previous_value        Nullable(Float64),
+    threshold             Float64,
+    message               String,
+    deliveries            String DEFAULT '',
+    created_at            DateTime64(3, 'UTC')
+) ENGINE = MergeTree ORDER BY id;
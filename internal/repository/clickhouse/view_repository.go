@@ -4,56 +4,65 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"sitecrawler/newgo/internal/repository"
 	"sitecrawler/newgo/models"
 )
 
+const viewColumns = `id, search_keyword_url_id, name, filter_config, columns, sort_field, sort_direction, page_size, compare_with_previous,
+	template_id, template_version, customized, created_at, updated_at`
+
 type ViewRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	ids IDGenerator
 }
 
-func NewViewRepo(db *sql.DB) *ViewRepo {
-	return &ViewRepo{db: db}
+func NewViewRepo(db *sql.DB, ids IDGenerator) *ViewRepo {
+	if ids == nil {
+		panic("id generator required")
+	}
+	return &ViewRepo{db: db, ids: ids}
 }
 
 func (r *ViewRepo) Create(ctx context.Context, v *models.View) error {
 	now := time.Now().UTC()
 	v.CreatedAt, v.UpdatedAt = now, now
 
-	filterJSON, err := json.Marshal(v.FilterConfig)
+	filterJSON, columnsJSON, err := marshalView(v)
 	if err != nil {
-		return fmt.Errorf("failed to marshal filter config: %w", err)
+		return err
 	}
 
-	q := `INSERT INTO views (search_keyword_url_id, name, filter_config, created_at, updated_at)
-	      VALUES (?, ?, ?, ?, ?)`
+	q := `INSERT INTO views (id, search_keyword_url_id, name, filter_config, columns, sort_field, sort_direction, page_size, compare_with_previous,
+	      template_id, template_version, customized, created_at, updated_at)
+	      VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, q, v.SearchKeywordURLID, v.Name, string(filterJSON), v.CreatedAt, v.UpdatedAt)
+	id, err := r.ids.NextID(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to generate view ID: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, q, id, v.SearchKeywordURLID, v.Name, filterJSON, columnsJSON, v.SortField, v.SortDirection, v.PageSize,
+		v.CompareWithPrevious, v.TemplateID, v.TemplateVersion, v.Customized, v.CreatedAt, v.UpdatedAt); err != nil {
 		return err
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		err = r.db.QueryRowContext(ctx, "SELECT max(id) + 1 FROM views").Scan(&id)
-		if err != nil {
-			return fmt.Errorf("failed to get view ID: %w", err)
-		}
-	}
 	v.ID = id
 	return nil
 }
 
 func (r *ViewRepo) Update(ctx context.Context, v *models.View) error {
-	filterJSON, err := json.Marshal(v.FilterConfig)
+	filterJSON, columnsJSON, err := marshalView(v)
 	if err != nil {
-		return fmt.Errorf("failed to marshal filter config: %w", err)
+		return err
 	}
 
-	q := `ALTER TABLE views UPDATE name = ?, filter_config = ?, updated_at = ? WHERE id = ?`
-	_, err = r.db.ExecContext(ctx, q, v.Name, string(filterJSON), time.Now().UTC(), v.ID)
+	q := `ALTER TABLE views UPDATE name = ?, filter_config = ?, columns = ?, sort_field = ?, sort_direction = ?, page_size = ?,
+	      compare_with_previous = ?, template_id = ?, template_version = ?, customized = ?, updated_at = ? WHERE id = ?`
+	_, err = r.db.ExecContext(ctx, q, v.Name, filterJSON, columnsJSON, v.SortField, v.SortDirection, v.PageSize, v.CompareWithPrevious,
+		v.TemplateID, v.TemplateVersion, v.Customized, time.Now().UTC(), v.ID)
 	return err
 }
 
@@ -64,33 +73,23 @@ func (r *ViewRepo) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *ViewRepo) Get(ctx context.Context, id int64) (*models.View, error) {
-	q := `SELECT id, search_keyword_url_id, name, filter_config, created_at, updated_at 
-	      FROM views WHERE id = ?`
-
-	var v models.View
-	var filterJSON string
-
-	err := r.db.QueryRowContext(ctx, q, id).Scan(
-		&v.ID, &v.SearchKeywordURLID, &v.Name, &filterJSON, &v.CreatedAt, &v.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
+	v, err := scanView(r.db.QueryRowContext(ctx, `SELECT `+viewColumns+` FROM views WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrViewNotFound
 	}
-
-	if filterJSON != "" {
-		if err := json.Unmarshal([]byte(filterJSON), &v.FilterConfig); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
-		}
-	}
-
-	return &v, nil
+	return v, err
 }
 
 func (r *ViewRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.View, error) {
-	q := `SELECT id, search_keyword_url_id, name, filter_config, created_at, updated_at 
-	      FROM views WHERE search_keyword_url_id = ? ORDER BY created_at ASC`
+	return r.list(ctx, `SELECT `+viewColumns+` FROM views WHERE search_keyword_url_id = ? ORDER BY id ASC`, skuID)
+}
+
+func (r *ViewRepo) ListByTemplate(ctx context.Context, templateID int64) ([]models.View, error) {
+	return r.list(ctx, `SELECT `+viewColumns+` FROM views WHERE template_id = ? ORDER BY id ASC`, templateID)
+}
 
-	rows, err := r.db.QueryContext(ctx, q, skuID)
+func (r *ViewRepo) list(ctx context.Context, q string, args ...any) ([]models.View, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,22 +97,53 @@ func (r *ViewRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.View, e
 
 	var views []models.View
 	for rows.Next() {
-		var v models.View
-		var filterJSON string
-
-		err := rows.Scan(&v.ID, &v.SearchKeywordURLID, &v.Name, &filterJSON, &v.CreatedAt, &v.UpdatedAt)
+		v, err := scanView(rows)
 		if err != nil {
 			return nil, err
 		}
+		views = append(views, *v)
+	}
 
-		if filterJSON != "" {
-			if err := json.Unmarshal([]byte(filterJSON), &v.FilterConfig); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
-			}
-		}
+	return views, rows.Err()
+}
 
-		views = append(views, v)
+// marshalView encodes the JSON string columns of a view.
+func marshalView(v *models.View) (string, string, error) {
+	filterJSON, err := json.Marshal(v.FilterConfig)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal filter config: %w", err)
 	}
+	columnsJSON, err := json.Marshal(v.Columns)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal columns: %w", err)
+	}
+	return string(filterJSON), string(columnsJSON), nil
+}
 
-	return views, rows.Err()
+// scanView reads a row selected with viewColumns.
+func scanView(row rowScanner) (*models.View, error) {
+	var v models.View
+	var filterJSON, columnsJSON string
+	var templateID sql.NullInt64
+
+	err := row.Scan(&v.ID, &v.SearchKeywordURLID, &v.Name, &filterJSON, &columnsJSON, &v.SortField, &v.SortDirection, &v.PageSize,
+		&v.CompareWithPrevious, &templateID, &v.TemplateVersion, &v.Customized, &v.CreatedAt, &v.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if templateID.Valid {
+		v.TemplateID = &templateID.Int64
+	}
+
+	if filterJSON != "" {
+		if err := json.Unmarshal([]byte(filterJSON), &v.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	if columnsJSON != "" {
+		if err := json.Unmarshal([]byte(columnsJSON), &v.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal columns: %w", err)
+		}
+	}
+	return &v, nil
 }
@@ -0,0 +1,75 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/repository/repotest"
+	"sitecrawler/newgo/models"
+)
+
+func TestInMemoryCrawlingSessionConformance(t *testing.T) {
+	repotest.RunCrawlingSessionConformance(t, repository.NewInMemoryCrawlingSessionRepository())
+}
+
+func TestInMemoryAuditCheckConformance(t *testing.T) {
+	repotest.RunAuditCheckConformance(t, repository.NewInMemoryAuditCheckRepository())
+}
+
+func TestInMemoryAuditResultConformance(t *testing.T) {
+	repotest.RunAuditResultConformance(t, repository.NewInMemoryAuditResultRepository())
+}
+
+func TestInMemoryViewConformance(t *testing.T) {
+	repotest.RunViewConformance(t, repository.NewInMemoryViewRepository())
+}
+
+func TestInMemoryAlertRuleConformance(t *testing.T) {
+	repotest.RunAlertRuleConformance(t, repository.NewInMemoryAlertRuleRepository())
+}
+
+func TestInMemoryAlertConformance(t *testing.T) {
+	repotest.RunAlertConformance(t, repository.NewInMemoryAlertRepository())
+}
+
+func TestInMemoryTemplateConformance(t *testing.T) {
+	repotest.RunAuditCheckTemplateConformance(t, repository.NewInMemoryAuditCheckTemplateRepository())
+	repotest.RunViewTemplateConformance(t, repository.NewInMemoryViewTemplateRepository())
+}
+
+func TestSnapshotSessionDiffConformance(t *testing.T) {
+	snapshots := fixtureSnapshots{}
+	repotest.RunSessionDiffConformance(t, repotest.SessionDiffHarness{
+		Repo: repository.NewSnapshotSessionDiffRepository(snapshots),
+		Seed: snapshots.seed,
+	})
+}
+
+// fixtureSnapshots serves the pages of seeded fixtures as snapshots,
+// numbering sessions and pages in fixture order.
+type fixtureSnapshots map[int64][]models.PageSnapshot
+
+func (s fixtureSnapshots) seed(t *testing.T, f repotest.StatsFixture) []int64 {
+	ids := make([]int64, len(f.Sessions))
+	var pageID int64
+	for i, pages := range f.Sessions {
+		ids[i] = int64(len(s) + 1)
+		snapshots := make([]models.PageSnapshot, len(pages))
+		for j, p := range pages {
+			pageID++
+			snapshots[j] = models.PageSnapshot{ID: pageID, URL: p.URL, ResponseCode: p.ResponseCode, Title: p.Title,
+				MetaDescription: p.MetaDescription, ContentHash: p.ContentHash, Canonical: p.Canonical, Indexability: p.Indexability}
+		}
+		s[ids[i]] = snapshots
+	}
+	return ids
+}
+
+func (s fixtureSnapshots) ListSnapshots(ctx context.Context, sessionID int64) ([]models.PageSnapshot, error) {
+	return s[sessionID], nil
+}
+
+func (s fixtureSnapshots) ListHreflangLinks(ctx context.Context, sessionID int64) (map[int64][]models.HreflangLink, error) {
+	return nil, nil
+}
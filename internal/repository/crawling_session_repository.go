@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -21,6 +22,9 @@ type CrawlingSessionRepository interface {
 	ClaimPending(ctx context.Context, queueID, limit int) ([]models.CrawlingSession, error)
 	ClaimStalled(ctx context.Context, queueID int, excludeIDs []int64, limit int) ([]models.CrawlingSession, error)
 	MarkDone(ctx context.Context, id int64, reason string) error
+	// ListDoneBySKU returns the finished sessions of a SKU ordered by
+	// ended_at, optionally limited to those that ended within [from, to].
+	ListDoneBySKU(ctx context.Context, skuID int64, from, to *time.Time) ([]models.CrawlingSession, error)
 	UpdateSiteInfo(ctx context.Context, id int64, info SiteInfo) error
 	UpdateProgress(ctx context.Context, id int64, d ProgressDelta) error
 }
@@ -47,18 +51,70 @@ type ProgressDelta struct {
 
 type PageListParams struct {
 	SessionID int64
-	Filters   []map[string]any
-	Sort      string
-	Direction string
-	Page      int
-	PageLimit int
+	// FilterConfig, when set, restricts the listing to the pages matched by
+	// a view or audit check filter_config, evaluated as PageMatchRepository
+	// does. Filters are ANDed on top of it.
+	FilterConfig map[string]any
+	Filters      []map[string]any
+	Sort         string
+	Direction    string
+	Page         int
+	PageLimit    int
 }
 
+// ChecksWithPagesParams selects the audit checks of the session's SKU and
+// their matched pages. FilterConfig and ViewFilters restrict the pages in
+// both sessions alike.
 type ChecksWithPagesParams struct {
 	SessionID           int64
 	ComparisonSessionID *int64
-	ViewFilters         []map[string]any
-	PageLimitPerCheck   int
+	// FilterConfig, when set, restricts the pages to a view's matches.
+	FilterConfig      map[string]any
+	ViewFilters       []map[string]any
+	PageLimitPerCheck int
+}
+
+// NewCheckPageDiff returns an empty diff against the comparison session.
+func NewCheckPageDiff(comparisonSessionID int64) *models.CheckPageDiff {
+	return &models.CheckPageDiff{
+		ComparisonSessionID: comparisonSessionID,
+		New:                 models.CheckPageSet{Pages: []models.Page{}},
+		Resolved:            models.CheckPageSet{Pages: []models.Page{}},
+		Persisting:          models.CheckPageSet{Pages: []models.Page{}},
+	}
+}
+
+// AddCheckDiffPage records one row of a check diff query: a page with its
+// status and the total number of pages with that status.
+func AddCheckDiffPage(diff *models.CheckPageDiff, status string, page models.Page, total int) {
+	var set *models.CheckPageSet
+	switch status {
+	case models.CheckPageNew:
+		set = &diff.New
+	case models.CheckPageResolved:
+		set = &diff.Resolved
+	case models.CheckPagePersisting:
+		set = &diff.Persisting
+	default:
+		return
+	}
+	set.Total = total
+	set.Pages = append(set.Pages, page)
+}
+
+// CurrentCheckPages returns the first limit pages of the current session
+// matched by the check, merging the new and persisting pages by id.
+func CurrentCheckPages(diff *models.CheckPageDiff, limit int) []models.Page {
+	pages := make([]models.Page, 0, limit)
+	a, b := diff.New.Pages, diff.Persisting.Pages
+	for len(pages) < limit && (len(a) > 0 || len(b) > 0) {
+		if len(b) == 0 || (len(a) > 0 && a[0].ID < b[0].ID) {
+			pages, a = append(pages, a[0]), a[1:]
+		} else {
+			pages, b = append(pages, b[0]), b[1:]
+		}
+	}
+	return pages
 }
 
 type CrawlingSessionPageRepository interface {
@@ -79,14 +135,97 @@ type PageDetailsRepository interface {
 type StatsQueryParams struct {
 	SessionID           int64
 	ComparisonSessionID *int64
-	Filters             []map[string]any
-	Prefilters          []map[string]any
+	// FilterConfig, when set, restricts the stats to a view's matches.
+	FilterConfig map[string]any
+	Filters      []map[string]any
+	Prefilters   []map[string]any
+	// GroupBy, when set, adds the metrics of each group of pages.
+	GroupBy *StatsGroupBy
+}
+
+// Stats group_by fields.
+const (
+	StatsGroupDirectory   = "directory"
+	StatsGroupSubdomain   = "subdomain"
+	StatsGroupDepth       = "depth"
+	StatsGroupContentType = "content_type"
+	StatsGroupStatusClass = "status_class"
+	StatsGroupRegex       = "regex"
+)
+
+// StatsGroupBy keys each page of /api/stats by a group. Segments is the
+// number of leading directories a directory group keeps; Pattern is the
+// regex whose first capture group, or whole match, keys a page by its URL.
+type StatsGroupBy struct {
+	Field    string
+	Segments int
+	Pattern  string
+}
+
+// MaxStatsGroups caps the groups /api/stats returns, largest first.
+const MaxStatsGroups = 100
+
+// StatsCounts are the page counts /api/stats reports for a session, its
+// comparison session and each group of pages. Backends select them in the
+// order of Dest.
+type StatsCounts struct {
+	Total, Warning, Error, OK, Redirection      int
+	Level1, Level2, Level3, Level4              int
+	Success, Redirect, ClientError, ServerError int
+}
+
+// Dest returns the scan destinations of one row of counts.
+func (c *StatsCounts) Dest() []any {
+	return []any{
+		&c.Total, &c.Warning, &c.Error, &c.OK, &c.Redirection,
+		&c.Level1, &c.Level2, &c.Level3, &c.Level4,
+		&c.Success, &c.Redirect, &c.ClientError, &c.ServerError,
+	}
+}
+
+// Map returns the counts under their /api/stats keys. level1 to level4
+// predate the depth histogram and are kept for existing clients.
+func (c StatsCounts) Map() map[string]any {
+	return map[string]any{
+		"total": c.Total, "warning": c.Warning, "error": c.Error, "ok": c.OK, "redirection": c.Redirection,
+		"level1": c.Level1, "level2": c.Level2, "level3": c.Level3, "level4": c.Level4,
+		"total_pages": c.Total, "success_pages": c.Success, "redirect_pages": c.Redirect,
+		"client_error_pages": c.ClientError, "server_error_pages": c.ServerError,
+	}
 }
 
+// Changes returns the change of the headline counts since previous.
+func (c StatsCounts) Changes(previous StatsCounts) map[string]int {
+	return map[string]int{
+		"total": c.Total - previous.Total, "warning": c.Warning - previous.Warning, "error": c.Error - previous.Error,
+		"ok": c.OK - previous.OK, "redirection": c.Redirection - previous.Redirection,
+	}
+}
+
+// SlowPageThresholdMs is the response time above which /api/stats counts a
+// page as slow.
+const SlowPageThresholdMs = 1000
+
 type StatsRepository interface {
 	Fetch(ctx context.Context, params StatsQueryParams) (map[string]any, error)
 }
 
+// PreviousDoneSession returns the finished session of the same SKU that ended
+// last before session, or nil when there is none. A session that has not
+// ended yet is compared with the SKU's latest finished session.
+func PreviousDoneSession(ctx context.Context, repo CrawlingSessionRepository, session models.CrawlingSession) (*models.CrawlingSession, error) {
+	done, err := repo.ListDoneBySKU(ctx, session.SearchKeywordURLID, nil, session.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(done) - 1; i >= 0; i-- {
+		if done[i].ID != session.ID {
+			return &done[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // InMemoryCrawlingSessionRepository is a temporary implementation that stores sessions
 // in memory so the new architecture can be exercised without database dependencies.
 type InMemoryCrawlingSessionRepository struct {
@@ -138,6 +277,9 @@ func (r *InMemoryCrawlingSessionRepository) Create(ctx context.Context, session
 	r.nextID++
 	session.CreatedAt = now
 	session.UpdatedAt = now
+	if session.Status == "" {
+		session.Status = "pending"
+	}
 	// mark SKU as no longer active once persisted
 	delete(r.activeSKU, session.SearchKeywordURLID)
 	r.items[session.ID] = session
@@ -211,6 +353,29 @@ func (r *InMemoryCrawlingSessionRepository) MarkDone(ctx context.Context, id int
 	return nil
 }
 
+func (r *InMemoryCrawlingSessionRepository) ListDoneBySKU(ctx context.Context, skuID int64, from, to *time.Time) ([]models.CrawlingSession, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.CrawlingSession
+	for _, s := range r.items {
+		if s.SearchKeywordURLID != skuID || s.Status != "done" || s.EndedAt == nil {
+			continue
+		}
+		if (from != nil && s.EndedAt.Before(*from)) || (to != nil && s.EndedAt.After(*to)) {
+			continue
+		}
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].EndedAt.Equal(*out[j].EndedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].EndedAt.Before(*out[j].EndedAt)
+	})
+	return out, nil
+}
+
 func (r *InMemoryCrawlingSessionRepository) UpdateSiteInfo(ctx context.Context, id int64, info SiteInfo) error {
 	_ = ctx
 	r.mu.Lock()
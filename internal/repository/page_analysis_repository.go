@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"sitecrawler/newgo/internal/analysis"
+	"sitecrawler/newgo/models"
+)
+
+// PageAnalysisRepository persists the per-page analysis results produced by
+// the crawler so they can be filtered on like any other page column.
+type PageAnalysisRepository interface {
+	SaveIndexability(ctx context.Context, pageID int64, result models.PageIndexability) error
+	SaveContentFingerprint(ctx context.Context, pageID int64, fp models.PageContentFingerprint) error
+	SaveHreflangLinks(ctx context.Context, pageID int64, links []models.HreflangLink) error
+	SaveHreflangIssues(ctx context.Context, pageID int64, issues []models.HreflangIssue) error
+	SaveStructuredData(ctx context.Context, pageID int64, items []models.StructuredDataItem) error
+	SaveSecurity(ctx context.Context, pageID int64, result models.PageSecurity) error
+	SavePerformance(ctx context.Context, pageID int64, perf models.PagePerformance) error
+}
+
+// PageSnapshotRepository loads every page of a session for analyses that need
+// to compare pages with each other.
+type PageSnapshotRepository interface {
+	ListSnapshots(ctx context.Context, sessionID int64) ([]models.PageSnapshot, error)
+	ListHreflangLinks(ctx context.Context, sessionID int64) (map[int64][]models.HreflangLink, error)
+}
+
+// SessionDiffParams selects one page of each requested change list between
+// two sessions. Changes holds models.PageChangeAdded, PageChangeRemoved and
+// PageChangeChanged; empty means all of them.
+type SessionDiffParams struct {
+	SessionID        int64
+	AgainstSessionID int64
+	Changes          []string
+	Page             int
+	PageLimit        int
+}
+
+// Offset is the number of pages of each list before the requested page.
+func (p SessionDiffParams) Offset() int {
+	return max(p.Page-1, 0) * p.PageLimit
+}
+
+// SessionDiffRepository compares the pages of two sessions the way
+// analysis.DiffPages does and returns one page of each requested change
+// list, keyed by change, with the list's total.
+type SessionDiffRepository interface {
+	DiffSessions(ctx context.Context, params SessionDiffParams) (map[string]*models.PageChangeSet, error)
+}
+
+// NewSessionDiff returns an empty change set for every change params asks
+// for.
+func NewSessionDiff(params SessionDiffParams) map[string]*models.PageChangeSet {
+	diff := map[string]*models.PageChangeSet{}
+	for _, change := range []string{models.PageChangeAdded, models.PageChangeRemoved, models.PageChangeChanged} {
+		if len(params.Changes) == 0 || slices.Contains(params.Changes, change) {
+			diff[change] = &models.PageChangeSet{Pages: []models.PageChange{}}
+		}
+	}
+	return diff
+}
+
+// AddSessionDiffRow records one row of a session diff query: the pages
+// paired under a change, the row's 1-based position in its change list and
+// the list's total. Queries return the first row of every list even when the
+// requested page lies past it, so rows up to offset only set the total.
+func AddSessionDiffRow(diff map[string]*models.PageChangeSet, change string, row, total, offset int, current, previous models.PageSnapshot) {
+	set, ok := diff[change]
+	if !ok {
+		return
+	}
+	set.Total = total
+	if row <= offset {
+		return
+	}
+	switch change {
+	case models.PageChangeAdded:
+		set.Pages = append(set.Pages, models.PageChange{Change: change, URL: current.URL, PageID: current.ID})
+	case models.PageChangeRemoved:
+		set.Pages = append(set.Pages, models.PageChange{Change: change, URL: previous.URL, PreviousPageID: previous.ID})
+	default:
+		set.Pages = append(set.Pages, models.PageChange{
+			Change:         change,
+			URL:            current.URL,
+			PageID:         current.ID,
+			PreviousPageID: previous.ID,
+			Fields:         analysis.DiffFields(previous, current),
+		})
+	}
+}
+
+// SnapshotSessionDiffRepository diffs sessions in memory from their page
+// snapshots, for stores that cannot compare pages themselves.
+type SnapshotSessionDiffRepository struct {
+	snapshots PageSnapshotRepository
+}
+
+func NewSnapshotSessionDiffRepository(snapshots PageSnapshotRepository) *SnapshotSessionDiffRepository {
+	return &SnapshotSessionDiffRepository{snapshots: snapshots}
+}
+
+func (r *SnapshotSessionDiffRepository) DiffSessions(ctx context.Context, params SessionDiffParams) (map[string]*models.PageChangeSet, error) {
+	current, err := r.snapshots.ListSnapshots(ctx, params.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := r.snapshots.ListSnapshots(ctx, params.AgainstSessionID)
+	if err != nil {
+		return nil, err
+	}
+	added, removed, changed := analysis.DiffPages(current, previous)
+	lists := map[string][]models.PageChange{
+		models.PageChangeAdded:   added,
+		models.PageChangeRemoved: removed,
+		models.PageChangeChanged: changed,
+	}
+
+	diff := NewSessionDiff(params)
+	for change, set := range diff {
+		all := lists[change]
+		start := min(params.Offset(), len(all))
+		end := min(start+params.PageLimit, len(all))
+		set.Total = len(all)
+		set.Pages = append(set.Pages, all[start:end]...)
+	}
+	return diff, nil
+}
+
+type NoopPageAnalysisRepository struct{}
+
+func NewNoopPageAnalysisRepository() *NoopPageAnalysisRepository {
+	return &NoopPageAnalysisRepository{}
+}
+
+func (r *NoopPageAnalysisRepository) SaveIndexability(ctx context.Context, pageID int64, result models.PageIndexability) error {
+	_ = ctx
+	_ = pageID
+	_ = result
+	return nil
+}
+
+func (r *NoopPageAnalysisRepository) SaveContentFingerprint(ctx context.Context, pageID int64, fp models.PageContentFingerprint) error {
+	_ = ctx
+	_ = pageID
+	_ = fp
+	return nil
+}
+
+func (r *NoopPageAnalysisRepository) SaveHreflangLinks(ctx context.Context, pageID int64, links []models.HreflangLink) error {
+	_ = ctx
+	_ = pageID
+	_ = links
+	return nil
+}
+
+func (r *NoopPageAnalysisRepository) SaveHreflangIssues(ctx context.Context, pageID int64, issues []models.HreflangIssue) error {
+	_ = ctx
+	_ = pageID
+	_ = issues
+	return nil
+}
+
+func (r *NoopPageAnalysisRepository) SaveStructuredData(ctx context.Context, pageID int64, items []models.StructuredDataItem) error {
+	_ = ctx
+	_ = pageID
+	_ = items
+	return nil
+}
+
+func (r *NoopPageAnalysisRepository) SaveSecurity(ctx context.Context, pageID int64, result models.PageSecurity) error {
+	_ = ctx
+	_ = pageID
+	_ = result
+	return nil
+}
+
+func (r *NoopPageAnalysisRepository) SavePerformance(ctx context.Context, pageID int64, perf models.PagePerformance) error {
+	_ = ctx
+	_ = pageID
+	_ = perf
+	return nil
+}
+
+type NoopPageSnapshotRepository struct{}
+
+func NewNoopPageSnapshotRepository() *NoopPageSnapshotRepository {
+	return &NoopPageSnapshotRepository{}
+}
+
+func (r *NoopPageSnapshotRepository) ListSnapshots(ctx context.Context, sessionID int64) ([]models.PageSnapshot, error) {
+	_ = ctx
+	_ = sessionID
+	return nil, nil
+}
+
+func (r *NoopPageSnapshotRepository) ListHreflangLinks(ctx context.Context, sessionID int64) (map[int64][]models.HreflangLink, error) {
+	_ = ctx
+	_ = sessionID
+	return nil, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"sitecrawler/newgo/models"
+)
+
+// PageMatchRepository evaluates audit check and view filter_configs against
+// the pages of a session. Filter groups of a config are ORed and the filters
+// inside a group ANDed; a config without filter groups matches nothing.
+type PageMatchRepository interface {
+	// CountMatches returns the number of pages matched by each config, in
+	// the order given.
+	CountMatches(ctx context.Context, sessionID int64, configs []map[string]any) ([]int, error)
+	// CountMatchesBySession counts the pages matched by each config in every
+	// given session with a single grouped query. Every session is present in
+	// the result, with zero counts when it has no pages.
+	CountMatchesBySession(ctx context.Context, sessionIDs []int64, configs []map[string]any) (map[int64][]int, error)
+	// ListMatches returns one page of matched pages ordered by id and the
+	// total number of matches.
+	ListMatches(ctx context.Context, sessionID int64, config map[string]any, page, limit int) ([]models.Page, int, error)
+}
+
+type NoopPageMatchRepository struct{}
+
+func NewNoopPageMatchRepository() *NoopPageMatchRepository {
+	return &NoopPageMatchRepository{}
+}
+
+func (r *NoopPageMatchRepository) CountMatches(ctx context.Context, sessionID int64, configs []map[string]any) ([]int, error) {
+	_ = ctx
+	_ = sessionID
+	return make([]int, len(configs)), nil
+}
+
+func (r *NoopPageMatchRepository) CountMatchesBySession(ctx context.Context, sessionIDs []int64, configs []map[string]any) (map[int64][]int, error) {
+	_ = ctx
+	return ZeroCountsBySession(sessionIDs, len(configs)), nil
+}
+
+func (r *NoopPageMatchRepository) ListMatches(ctx context.Context, sessionID int64, config map[string]any, page, limit int) ([]models.Page, int, error) {
+	_ = ctx
+	_ = sessionID
+	_ = config
+	_ = page
+	_ = limit
+	return []models.Page{}, 0, nil
+}
+
+// ZeroCountsBySession returns a CountMatchesBySession result with n zero
+// counts for every session.
+func ZeroCountsBySession(sessionIDs []int64, n int) map[int64][]int {
+	out := make(map[int64][]int, len(sessionIDs))
+	for _, id := range sessionIDs {
+		out[id] = make([]int, n)
+	}
+	return out
+}
+
+// CountMatchesEach counts all configs in one call and, when that fails, falls
+// back to one call per config so a single broken filter_config does not hide
+// the counts of the others. errs[i] is set for each config that failed.
+func CountMatchesEach(ctx context.Context, repo PageMatchRepository, sessionID int64, configs []map[string]any) ([]int, []error) {
+	errs := make([]error, len(configs))
+	counts, err := repo.CountMatches(ctx, sessionID, configs)
+	if err == nil {
+		return counts, errs
+	}
+
+	counts = make([]int, len(configs))
+	for i, cfg := range configs {
+		single, err := repo.CountMatches(ctx, sessionID, []map[string]any{cfg})
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		counts[i] = single[0]
+	}
+	return counts, errs
+}
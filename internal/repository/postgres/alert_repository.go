@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+const alertRuleColumns = `id, search_keyword_url_id, name, audit_check_id, COALESCE(metric, ''), mode, operator, threshold,
+	COALESCE(webhook_url, ''), emails, enabled, created_at, updated_at`
+
+type AlertRuleRepo struct {
+	db *sql.DB
+}
+
+func NewAlertRuleRepo(db *sql.DB) *AlertRuleRepo {
+	return &AlertRuleRepo{db: db}
+}
+
+func (r *AlertRuleRepo) Create(ctx context.Context, rule *models.AlertRule) error {
+	emailsJSON, err := json.Marshal(rule.Emails)
+	if err != nil {
+		return fmt.Errorf("failed to marshal emails: %w", err)
+	}
+	q := `INSERT INTO alert_rules (search_keyword_url_id, name, audit_check_id, metric, mode, operator, threshold, webhook_url, emails, enabled, created_at, updated_at)
+		VALUES ($1,$2,$3,NULLIF($4, ''),$5,$6,$7,NULLIF($8, ''),$9,$10,NOW(),NOW()) RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, q, rule.SearchKeywordURLID, rule.Name, rule.AuditCheckID, rule.Metric, rule.Mode, rule.Operator,
+		rule.Threshold, rule.WebhookURL, emailsJSON, rule.Enabled).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *AlertRuleRepo) Update(ctx context.Context, rule *models.AlertRule) error {
+	emailsJSON, err := json.Marshal(rule.Emails)
+	if err != nil {
+		return fmt.Errorf("failed to marshal emails: %w", err)
+	}
+	q := `UPDATE alert_rules SET name=$2, audit_check_id=$3, metric=NULLIF($4, ''), mode=$5, operator=$6, threshold=$7,
+		webhook_url=NULLIF($8, ''), emails=$9, enabled=$10, updated_at=NOW() WHERE id=$1 RETURNING updated_at`
+	err = r.db.QueryRowContext(ctx, q, rule.ID, rule.Name, rule.AuditCheckID, rule.Metric, rule.Mode, rule.Operator,
+		rule.Threshold, rule.WebhookURL, emailsJSON, rule.Enabled).Scan(&rule.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrAlertRuleNotFound
+	}
+	return err
+}
+
+func (r *AlertRuleRepo) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+func (r *AlertRuleRepo) Get(ctx context.Context, id int64) (*models.AlertRule, error) {
+	rule, err := scanAlertRule(r.db.QueryRowContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE id=$1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrAlertRuleNotFound
+	}
+	return rule, err
+}
+
+func (r *AlertRuleRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE search_keyword_url_id=$1 ORDER BY id ASC`, skuID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.AlertRule{}
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *rule)
+	}
+	return out, rows.Err()
+}
+
+func scanAlertRule(row rowScanner) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	var checkID sql.NullInt64
+	var emailsJSON []byte
+	if err := row.Scan(&rule.ID, &rule.SearchKeywordURLID, &rule.Name, &checkID, &rule.Metric, &rule.Mode, &rule.Operator,
+		&rule.Threshold, &rule.WebhookURL, &emailsJSON, &rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if checkID.Valid {
+		rule.AuditCheckID = &checkID.Int64
+	}
+	if len(emailsJSON) > 0 {
+		if err := json.Unmarshal(emailsJSON, &rule.Emails); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal emails: %w", err)
+		}
+	}
+	return &rule, nil
+}
+
+type AlertRepo struct {
+	db *sql.DB
+}
+
+func NewAlertRepo(db *sql.DB) *AlertRepo {
+	return &AlertRepo{db: db}
+}
+
+func (r *AlertRepo) Create(ctx context.Context, alert *models.Alert) error {
+	deliveriesJSON, err := json.Marshal(alert.Deliveries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deliveries: %w", err)
+	}
+	q := `INSERT INTO alerts (rule_id, search_keyword_url_id, crawling_session_id, rule_name, value, previous_value, threshold, message, deliveries, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NOW()) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, q, alert.RuleID, alert.SearchKeywordURLID, alert.CrawlingSessionID, alert.RuleName,
+		alert.Value, alert.PreviousValue, alert.Threshold, alert.Message, deliveriesJSON).Scan(&alert.ID, &alert.CreatedAt)
+}
+
+func (r *AlertRepo) List(ctx context.Context, params repository.AlertListParams) ([]models.Alert, error) {
+	var where []string
+	var args []any
+	add := func(col string, v int64) {
+		if v != 0 {
+			args = append(args, v)
+			where = append(where, fmt.Sprintf("%s=$%d", col, len(args)))
+		}
+	}
+	add("search_keyword_url_id", params.SearchKeywordURLID)
+	add("crawling_session_id", params.CrawlingSessionID)
+	add("rule_id", params.RuleID)
+
+	q := `SELECT id, rule_id, search_keyword_url_id, crawling_session_id, rule_name, value, previous_value, threshold, message, deliveries, created_at FROM alerts`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY created_at DESC, id DESC"
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		q += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.Alert{}
+	for rows.Next() {
+		var a models.Alert
+		var prev sql.NullFloat64
+		var deliveriesJSON []byte
+		if err := rows.Scan(&a.ID, &a.RuleID, &a.SearchKeywordURLID, &a.CrawlingSessionID, &a.RuleName, &a.Value, &prev,
+			&a.Threshold, &a.Message, &deliveriesJSON, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		if prev.Valid {
+			a.PreviousValue = &prev.Float64
+		}
+		if len(deliveriesJSON) > 0 {
+			if err := json.Unmarshal(deliveriesJSON, &a.Deliveries); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal deliveries: %w", err)
+			}
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
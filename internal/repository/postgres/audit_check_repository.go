@@ -3,12 +3,19 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
+	"sitecrawler/newgo/internal/repository"
 	"sitecrawler/newgo/models"
 )
 
+const auditCheckColumns = `id, search_keyword_url_id, name, category, COALESCE(severity, ''), COALESCE(weight, 0), filter_config,
+	COALESCE(builtin_key, ''), COALESCE(builtin_version, 0), COALESCE(customized, false), template_id, COALESCE(template_version, 0),
+	created_at, updated_at`
+
 type AuditRepo struct {
 	db *sql.DB
 }
@@ -18,14 +25,26 @@ func NewAuditRepo(db *sql.DB) *AuditRepo {
 }
 
 func (r *AuditRepo) Create(ctx context.Context, ac *models.AuditCheck) error {
-	q := `INSERT INTO audit_checks (search_keyword_url_id, name, category, filter_config, created_at, updated_at)
-          VALUES ($1,$2,$3,$4,NOW(),NOW()) RETURNING id, created_at, updated_at`
-	return r.db.QueryRowContext(ctx, q, ac.SearchKeywordURLID, ac.Name, ac.Category, ac.FilterConfig).Scan(&ac.ID, &ac.CreatedAt, &ac.UpdatedAt)
+	filterJSON, err := json.Marshal(ac.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	q := `INSERT INTO audit_checks (search_keyword_url_id, name, category, severity, weight, filter_config, builtin_key, builtin_version, customized,
+          template_id, template_version, created_at, updated_at)
+          VALUES ($1,$2,$3,$4,$5,$6,NULLIF($7, ''),$8,$9,$10,$11,NOW(),NOW()) RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, q, ac.SearchKeywordURLID, ac.Name, ac.Category, ac.Severity, ac.Weight, filterJSON,
+		ac.BuiltinKey, ac.BuiltinVersion, ac.Customized, ac.TemplateID, ac.TemplateVersion).Scan(&ac.ID, &ac.CreatedAt, &ac.UpdatedAt)
 }
 
 func (r *AuditRepo) Update(ctx context.Context, ac *models.AuditCheck) error {
-	q := `UPDATE audit_checks SET name=$2, category=$3, filter_config=$4, updated_at=NOW() WHERE id=$1`
-	_, err := r.db.ExecContext(ctx, q, ac.ID, ac.Name, ac.Category, ac.FilterConfig)
+	filterJSON, err := json.Marshal(ac.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	q := `UPDATE audit_checks SET name=$2, category=$3, severity=$4, weight=$5, filter_config=$6, builtin_version=$7, customized=$8,
+          template_id=$9, template_version=$10, updated_at=NOW() WHERE id=$1`
+	_, err = r.db.ExecContext(ctx, q, ac.ID, ac.Name, ac.Category, ac.Severity, ac.Weight, filterJSON, ac.BuiltinVersion, ac.Customized,
+		ac.TemplateID, ac.TemplateVersion)
 	return err
 }
 
@@ -35,27 +54,35 @@ func (r *AuditRepo) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *AuditRepo) Get(ctx context.Context, id int64) (*models.AuditCheck, error) {
-	var ac models.AuditCheck
-	q := `SELECT id, search_keyword_url_id, name, category, filter_config, created_at, updated_at FROM audit_checks WHERE id=$1`
-	if err := r.db.QueryRowContext(ctx, q, id).Scan(&ac.ID, &ac.SearchKeywordURLID, &ac.Name, &ac.Category, &ac.FilterConfig, &ac.CreatedAt, &ac.UpdatedAt); err != nil {
-		return nil, err
+	q := `SELECT ` + auditCheckColumns + ` FROM audit_checks WHERE id=$1`
+	ac, err := scanAuditCheck(r.db.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrAuditCheckNotFound
 	}
-	return &ac, nil
+	return ac, err
 }
 
 func (r *AuditRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.AuditCheck, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, search_keyword_url_id, name, category, filter_config, created_at, updated_at FROM audit_checks WHERE search_keyword_url_id=$1 ORDER BY id ASC`, skuID)
+	return r.list(ctx, `SELECT `+auditCheckColumns+` FROM audit_checks WHERE search_keyword_url_id=$1 ORDER BY id ASC`, skuID)
+}
+
+func (r *AuditRepo) ListByTemplate(ctx context.Context, templateID int64) ([]models.AuditCheck, error) {
+	return r.list(ctx, `SELECT `+auditCheckColumns+` FROM audit_checks WHERE template_id=$1 ORDER BY id ASC`, templateID)
+}
+
+func (r *AuditRepo) list(ctx context.Context, q string, args ...any) ([]models.AuditCheck, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []models.AuditCheck
 	for rows.Next() {
-		var ac models.AuditCheck
-		if err := rows.Scan(&ac.ID, &ac.SearchKeywordURLID, &ac.Name, &ac.Category, &ac.FilterConfig, &ac.CreatedAt, &ac.UpdatedAt); err != nil {
+		ac, err := scanAuditCheck(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, ac)
+		out = append(out, *ac)
 	}
 	return out, rows.Err()
 }
@@ -76,19 +103,31 @@ func (r *AuditRepo) ListByIDsAndSKUs(ctx context.Context, ids, skuIDs []int64) (
 		skuPH[i] = fmt.Sprintf("$%d", base+i+1)
 		args = append(args, v)
 	}
-	q := fmt.Sprintf(`SELECT id, search_keyword_url_id, name, category, filter_config, created_at, updated_at FROM audit_checks WHERE id IN (%s) AND search_keyword_url_id IN (%s) ORDER BY id ASC`, strings.Join(idph, ","), strings.Join(skuPH, ","))
-	rows, err := r.db.QueryContext(ctx, q, args...)
-	if err != nil {
+	q := fmt.Sprintf(`SELECT `+auditCheckColumns+` FROM audit_checks WHERE id IN (%s) AND search_keyword_url_id IN (%s) ORDER BY id ASC`, strings.Join(idph, ","), strings.Join(skuPH, ","))
+	return r.list(ctx, q, args...)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAuditCheck reads a row selected with auditCheckColumns. filter_config
+// is JSONB, which the driver returns as raw bytes.
+func scanAuditCheck(row rowScanner) (*models.AuditCheck, error) {
+	var ac models.AuditCheck
+	var filterJSON []byte
+	var templateID sql.NullInt64
+	if err := row.Scan(&ac.ID, &ac.SearchKeywordURLID, &ac.Name, &ac.Category, &ac.Severity, &ac.Weight, &filterJSON,
+		&ac.BuiltinKey, &ac.BuiltinVersion, &ac.Customized, &templateID, &ac.TemplateVersion, &ac.CreatedAt, &ac.UpdatedAt); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	var out []models.AuditCheck
-	for rows.Next() {
-		var ac models.AuditCheck
-		if err := rows.Scan(&ac.ID, &ac.SearchKeywordURLID, &ac.Name, &ac.Category, &ac.FilterConfig, &ac.CreatedAt, &ac.UpdatedAt); err != nil {
-			return nil, err
+	if templateID.Valid {
+		ac.TemplateID = &templateID.Int64
+	}
+	if len(filterJSON) > 0 {
+		if err := json.Unmarshal(filterJSON, &ac.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
 		}
-		out = append(out, ac)
 	}
-	return out, rows.Err()
+	return &ac, nil
 }
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sitecrawler/newgo/models"
+)
+
+type AuditResultRepo struct {
+	db *sql.DB
+}
+
+func NewAuditResultRepo(db *sql.DB) *AuditResultRepo {
+	return &AuditResultRepo{db: db}
+}
+
+func (r *AuditResultRepo) Save(ctx context.Context, sessionID int64, results []models.AuditResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM audit_results WHERE crawling_session_id=$1`, sessionID); err != nil {
+		return err
+	}
+	for _, res := range results {
+		filterJSON, err := json.Marshal(res.FilterConfig)
+		if err != nil {
+			return fmt.Errorf("failed to marshal filter config: %w", err)
+		}
+		samplesJSON, err := json.Marshal(res.SamplePageIDs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sample page ids: %w", err)
+		}
+		q := `INSERT INTO audit_results (crawling_session_id, audit_check_id, name, category, severity, filter_config,
+			matched_pages, sample_page_ids, error, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NOW())`
+		if _, err := tx.ExecContext(ctx, q, sessionID, res.AuditCheckID, res.Name, res.Category, res.Severity, filterJSON,
+			res.MatchedPages, samplesJSON, res.Error); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+const auditResultColumns = `crawling_session_id, audit_check_id, name, category, COALESCE(severity, ''), filter_config,
+	matched_pages, sample_page_ids, COALESCE(error, ''), created_at`
+
+func (r *AuditResultRepo) ListBySession(ctx context.Context, sessionID int64) ([]models.AuditResult, error) {
+	q := `SELECT ` + auditResultColumns + ` FROM audit_results WHERE crawling_session_id=$1 ORDER BY audit_check_id ASC`
+	rows, err := r.db.QueryContext(ctx, q, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAuditResults(rows)
+}
+
+func (r *AuditResultRepo) ListByCheck(ctx context.Context, checkID int64, sessionIDs []int64) (map[int64]models.AuditResult, error) {
+	out := make(map[int64]models.AuditResult)
+	if len(sessionIDs) == 0 {
+		return out, nil
+	}
+	ph, args := sessionPlaceholders(sessionIDs, checkID)
+	q := fmt.Sprintf(`SELECT `+auditResultColumns+` FROM audit_results
+		WHERE audit_check_id=$1 AND crawling_session_id IN (%s)`, ph)
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results, err := scanAuditResults(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		out[res.CrawlingSessionID] = res
+	}
+	return out, nil
+}
+
+func (r *AuditResultRepo) ListEvaluatedSessions(ctx context.Context, sessionIDs []int64) (map[int64]bool, error) {
+	out := make(map[int64]bool)
+	if len(sessionIDs) == 0 {
+		return out, nil
+	}
+	ph, args := sessionPlaceholders(sessionIDs)
+	q := fmt.Sprintf(`SELECT DISTINCT crawling_session_id FROM audit_results WHERE crawling_session_id IN (%s)`, ph)
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// sessionPlaceholders numbers a placeholder per session id after the
+// leading args and returns them with the full argument list.
+func sessionPlaceholders(sessionIDs []int64, leading ...any) (string, []any) {
+	ph := make([]string, len(sessionIDs))
+	args := append([]any(nil), leading...)
+	for i, id := range sessionIDs {
+		ph[i] = fmt.Sprintf("$%d", len(leading)+i+1)
+		args = append(args, id)
+	}
+	return strings.Join(ph, ","), args
+}
+
+func scanAuditResults(rows *sql.Rows) ([]models.AuditResult, error) {
+	var out []models.AuditResult
+	for rows.Next() {
+		var res models.AuditResult
+		var filterJSON, samplesJSON []byte
+		if err := rows.Scan(&res.CrawlingSessionID, &res.AuditCheckID, &res.Name, &res.Category, &res.Severity, &filterJSON,
+			&res.MatchedPages, &samplesJSON, &res.Error, &res.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := unmarshalAuditResultJSON(filterJSON, samplesJSON, &res); err != nil {
+			return nil, err
+		}
+		out = append(out, res)
+	}
+	return out, rows.Err()
+}
+
+func unmarshalAuditResultJSON(filterJSON, samplesJSON []byte, res *models.AuditResult) error {
+	if len(filterJSON) > 0 {
+		if err := json.Unmarshal(filterJSON, &res.FilterConfig); err != nil {
+			return fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	if len(samplesJSON) > 0 {
+		if err := json.Unmarshal(samplesJSON, &res.SamplePageIDs); err != nil {
+			return fmt.Errorf("failed to unmarshal sample page ids: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository/repotest"
+	"sitecrawler/newgo/models"
+
+	_ "github.com/lib/pq"
+)
+
+// openTestDB connects to the database in POSTGRES_TEST_DSN, skipping the
+// test when it is unset, and creates the tables of testdata/schema.sql that
+// are missing.
+func openTestDB(t *testing.T) *sql.DB {
+	db := repotest.OpenDB(t, "POSTGRES_TEST_DSN", "POSTGRES_TEST_DRIVER", "postgres")
+	repotest.ApplySchema(t, db, "testdata/schema.sql")
+	return db
+}
+
+func TestCrawlingSessionRepoConformance(t *testing.T) {
+	repotest.RunCrawlingSessionConformance(t, NewCrawlingSessionRepo(openTestDB(t)))
+}
+
+func TestAuditRepoConformance(t *testing.T) {
+	repotest.RunAuditCheckConformance(t, NewAuditRepo(openTestDB(t)))
+}
+
+func TestAuditResultRepoConformance(t *testing.T) {
+	repotest.RunAuditResultConformance(t, NewAuditResultRepo(openTestDB(t)))
+}
+
+func TestViewRepoConformance(t *testing.T) {
+	repotest.RunViewConformance(t, NewViewRepo(openTestDB(t)))
+}
+
+func TestAlertRepoConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunAlertRuleConformance(t, NewAlertRuleRepo(db))
+	repotest.RunAlertConformance(t, NewAlertRepo(db))
+}
+
+func TestTemplateRepoConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunAuditCheckTemplateConformance(t, NewAuditCheckTemplateRepo(db))
+	repotest.RunViewTemplateConformance(t, NewViewTemplateRepo(db))
+}
+
+func TestStatsRepoConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunStatsConformance(t, repotest.StatsHarness{
+		Repo: NewStatsRepo(db),
+		Seed: func(t *testing.T, f repotest.StatsFixture) []int64 { return seedStatsFixture(t, db, f) },
+	})
+}
+
+func TestCheckPagesConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunCheckPagesConformance(t, repotest.CheckPagesHarness{
+		Repo: NewCrawlingSessionCheckRepo(db),
+		Seed: func(t *testing.T, f repotest.StatsFixture) []int64 { return seedStatsFixture(t, db, f) },
+	})
+}
+
+func TestSessionDiffConformance(t *testing.T) {
+	db := openTestDB(t)
+	repotest.RunSessionDiffConformance(t, repotest.SessionDiffHarness{
+		Repo: NewPageSnapshotRepo(db),
+		Seed: func(t *testing.T, f repotest.StatsFixture) []int64 { return seedStatsFixture(t, db, f) },
+	})
+}
+
+func seedStatsFixture(t *testing.T, db *sql.DB, f repotest.StatsFixture) []int64 {
+	t.Helper()
+	ctx := context.Background()
+	t.Cleanup(func() {
+		db.ExecContext(ctx, `DELETE FROM page_links WHERE source_page_id IN (SELECT p.id FROM pages p
+			JOIN crawling_sessions s ON s.id = p.crawling_session_id WHERE s.search_keyword_url_id = $1)`, f.SKU)
+		db.ExecContext(ctx, `DELETE FROM pages WHERE crawling_session_id IN (SELECT id FROM crawling_sessions WHERE search_keyword_url_id = $1)`, f.SKU)
+		db.ExecContext(ctx, `DELETE FROM crawling_sessions WHERE search_keyword_url_id = $1`, f.SKU)
+		db.ExecContext(ctx, `DELETE FROM audit_checks WHERE search_keyword_url_id = $1`, f.SKU)
+	})
+
+	checks := NewAuditRepo(db)
+	for _, c := range f.Checks {
+		c.SearchKeywordURLID = f.SKU
+		if err := checks.Create(ctx, &c); err != nil {
+			t.Fatalf("seed audit check: %v", err)
+		}
+	}
+
+	sessions := NewCrawlingSessionRepo(db)
+	ids := make([]int64, len(f.Sessions))
+	for i, pages := range f.Sessions {
+		session := models.CrawlingSession{SearchKeywordURLID: f.SKU, Status: "finished"}
+		if err := sessions.Create(ctx, &session); err != nil {
+			t.Fatalf("seed crawling session: %v", err)
+		}
+		ids[i] = session.ID
+		pageIDs := make([]int64, len(pages))
+		for j, p := range pages {
+			var redirect sql.NullString
+			if p.RedirectCode != "" {
+				redirect = sql.NullString{String: p.RedirectCode, Valid: true}
+			}
+			if err := db.QueryRowContext(ctx, `INSERT INTO pages (crawling_session_id, url, response_code, redirect_code, depth,
+				indexability, indexability_reason, title, meta_description, canonical, content_hash, og_title, og_description, content_type, response_time_ms)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id`,
+				session.ID, p.URL, p.ResponseCode, redirect, p.Depth, p.Indexability, p.IndexabilityReason,
+				p.Title, p.MetaDescription, p.Canonical, p.ContentHash, p.OGTitle, p.OGDescription, p.ContentType, p.ResponseTimeMs).Scan(&pageIDs[j]); err != nil {
+				t.Fatalf("seed page: %v", err)
+			}
+		}
+		for j, p := range pages {
+			for _, target := range p.LinksTo {
+				if _, err := db.ExecContext(ctx, `INSERT INTO page_links (source_page_id, target_page_id) VALUES ($1, $2)`,
+					pageIDs[j], pageIDs[target]); err != nil {
+					t.Fatalf("seed page link: %v", err)
+				}
+			}
+		}
+	}
+	return ids
+}
@@ -6,12 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/scoring"
 	"sitecrawler/newgo/models"
 )
 
@@ -66,7 +65,7 @@ func (r *CrawlingSessionRepo) GetByID(ctx context.Context, id int64) (*models.Cr
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("crawling session not found")
+			return nil, repository.ErrCrawlingSessionNotFound
 		}
 		return nil, err
 	}
@@ -166,6 +165,29 @@ func (r *CrawlingSessionRepo) MarkDone(ctx context.Context, id int64, reason str
 	return err
 }
 
+func (r *CrawlingSessionRepo) ListDoneBySKU(ctx context.Context, skuID int64, from, to *time.Time) ([]models.CrawlingSession, error) {
+	q := `SELECT id, search_keyword_url_id, url, status, queue, version, started_at, ended_at, created_at, updated_at
+		FROM crawling_sessions WHERE search_keyword_url_id=$1 AND status='done' AND ended_at IS NOT NULL
+		AND ($2::timestamptz IS NULL OR ended_at >= $2) AND ($3::timestamptz IS NULL OR ended_at <= $3)
+		ORDER BY ended_at ASC, id ASC`
+	rows, err := r.db.QueryContext(ctx, q, skuID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.CrawlingSession
+	for rows.Next() {
+		var cs models.CrawlingSession
+		if err := rows.Scan(&cs.ID, &cs.SearchKeywordURLID, &cs.URL, &cs.Status, &cs.Queue, &cs.Version,
+			&cs.StartedAt, &cs.EndedAt, &cs.CreatedAt, &cs.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, cs)
+	}
+	return out, rows.Err()
+}
+
 func (r *CrawlingSessionRepo) UpdateSiteInfo(ctx context.Context, id int64, info repository.SiteInfo) error {
 	q := `UPDATE crawling_sessions SET ips=$2, dns_servers=$3, aliases=$4, location=$5,
 		sitemap=$6, robots=$7, ssl_valid=$8, ssl_valid_until=$9, updated_at=NOW() WHERE id=$1`
@@ -231,20 +253,15 @@ func NewCrawlingSessionPageRepo(db *sql.DB) *CrawlingSessionPageRepo {
 }
 
 func (r *CrawlingSessionPageRepo) List(ctx context.Context, params repository.PageListParams) ([]models.Page, int, error) {
-	var args []any
-	argIndex := 1
-
-	whereClause := fmt.Sprintf("crawling_session_id = $%d", argIndex)
-	args = append(args, params.SessionID)
-	argIndex++
-
-	for _, filter := range params.Filters {
-		for key, value := range filter {
-			whereClause += fmt.Sprintf(" AND %s = $%d", key, argIndex)
-			args = append(args, value)
-			argIndex++
-		}
+	whereClause, args, err := buildPagesWherePostgres(params.SessionID, nil, params.Filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	whereClause, args, err = appendFilterConfigPostgres(whereClause, args, params.FilterConfig)
+	if err != nil {
+		return nil, 0, err
 	}
+	argIndex := len(args) + 1
 
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM pages WHERE %s", whereClause)
 	var total int
@@ -254,11 +271,15 @@ func (r *CrawlingSessionPageRepo) List(ctx context.Context, params repository.Pa
 
 	orderClause := "id ASC"
 	if params.Sort != "" {
+		col, ok := allowedPageColumn(params.Sort)
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid sort column: %s", params.Sort)
+		}
 		direction := "ASC"
 		if strings.ToUpper(params.Direction) == "DESC" {
 			direction = "DESC"
 		}
-		orderClause = fmt.Sprintf("%s %s", params.Sort, direction)
+		orderClause = fmt.Sprintf("%s %s", col, direction)
 	}
 
 	limit := params.PageLimit
@@ -270,7 +291,11 @@ func (r *CrawlingSessionPageRepo) List(ctx context.Context, params repository.Pa
 		offset = (params.Page - 1) * limit
 	}
 
-	query := fmt.Sprintf(`SELECT id, crawling_session_id, url, response_code FROM pages WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d`,
+	query := fmt.Sprintf(`SELECT id, crawling_session_id, url, response_code,
+		COALESCE(indexability, ''), COALESCE(indexability_reason, ''),
+		response_time_ms, COALESCE(dns_ms, 0), COALESCE(connect_ms, 0), COALESCE(tls_ms, 0), COALESCE(ttfb_ms, 0),
+		COALESCE(transfer_size, 0), COALESCE(uncompressed_size, 0), COALESCE(content_encoding, ''), COALESCE(resource_count, 0)
+		FROM pages WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d`,
 		whereClause, orderClause, argIndex, argIndex+1)
 	args = append(args, limit, offset)
 
@@ -283,9 +308,18 @@ func (r *CrawlingSessionPageRepo) List(ctx context.Context, params repository.Pa
 	var pages []models.Page
 	for rows.Next() {
 		var p models.Page
-		if err := rows.Scan(&p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode); err != nil {
+		var perf models.PagePerformance
+		var responseTime sql.NullInt64
+		if err := rows.Scan(&p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode, &p.Indexability, &p.IndexabilityReason,
+			&responseTime, &perf.DNSMs, &perf.ConnectMs, &perf.TLSMs, &perf.TTFBMs,
+			&perf.TransferSize, &perf.UncompressedSize, &perf.ContentEncoding, &perf.ResourceCount); err != nil {
 			return nil, 0, err
 		}
+		// Pages fetched before timings were recorded have no performance data.
+		if responseTime.Valid {
+			perf.ResponseTimeMs = responseTime.Int64
+			p.Performance = &perf
+		}
 		pages = append(pages, p)
 	}
 	return pages, total, rows.Err()
@@ -299,71 +333,162 @@ func NewCrawlingSessionCheckRepo(db *sql.DB) *CrawlingSessionCheckRepo {
 	return &CrawlingSessionCheckRepo{db: db}
 }
 
+// ChecksWithPages evaluates every audit check of the session's SKU against
+// the session's pages, restricted by the view filters. With a comparison
+// session, each check's matches in both sessions are joined by URL into new,
+// resolved and persisting pages. A check whose filter_config cannot be
+// evaluated is returned with its error.
 func (r *CrawlingSessionCheckRepo) ChecksWithPages(ctx context.Context, params repository.ChecksWithPagesParams) ([]models.CheckWithPages, error) {
-	var args []any
-	argIndex := 1
-
-	whereClause := fmt.Sprintf("p.crawling_session_id = $%d", argIndex)
-	args = append(args, params.SessionID)
-	argIndex++
-
-	for _, filter := range params.ViewFilters {
-		for key, value := range filter {
-			whereClause += fmt.Sprintf(" AND p.%s = $%d", key, argIndex)
-			args = append(args, value)
-			argIndex++
-		}
-	}
-
+	// $1 is the session and $2 the comparison session, if any; the view
+	// scope and the check clause are shared by both sides of the comparison.
+	baseArgs := []any{params.SessionID}
 	if params.ComparisonSessionID != nil {
-		whereClause += fmt.Sprintf(" OR p.crawling_session_id = $%d", argIndex)
-		args = append(args, *params.ComparisonSessionID)
+		baseArgs = append(baseArgs, *params.ComparisonSessionID)
 	}
-
-	query := fmt.Sprintf(`SELECT c.id, c.name, p.id, p.crawling_session_id, p.url, p.response_code
-		FROM audit_checks c LEFT JOIN pages p ON p.crawling_session_id = $1
-		WHERE %s ORDER BY c.id ASC, p.id ASC`, whereClause)
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	scope, scopeArgs, _, err := appendFilterMapsPostgres("TRUE", baseArgs, len(baseArgs)+1, params.ViewFilters)
+	if err != nil {
+		return nil, err
+	}
+	scope, scopeArgs, err = appendFilterConfigPostgres(scope, scopeArgs, params.FilterConfig)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	checksMap := make(map[int64]*models.CheckWithPages)
-	var orderedIDs []int64
 
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, filter_config FROM audit_checks
+		WHERE search_keyword_url_id = (SELECT search_keyword_url_id FROM crawling_sessions WHERE id = $1)
+		ORDER BY id ASC`, params.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	type checkRow struct {
+		check models.CheckWithPages
+		cfg   map[string]any
+	}
+	var checks []checkRow
 	for rows.Next() {
-		var checkID int64
-		var checkName string
-		var pageID, pageCrawlingSessionID sql.NullInt64
-		var pageURL sql.NullString
-		var pageResponseCode sql.NullInt64
-
-		if err := rows.Scan(&checkID, &checkName, &pageID, &pageCrawlingSessionID, &pageURL, &pageResponseCode); err != nil {
+		var c checkRow
+		var raw []byte
+		if err := rows.Scan(&c.check.ID, &c.check.Name, &raw); err != nil {
+			rows.Close()
 			return nil, err
 		}
-
-		check, exists := checksMap[checkID]
-		if !exists {
-			check = &models.CheckWithPages{ID: checkID, Name: checkName, Pages: []models.Page{}}
-			checksMap[checkID] = check
-			orderedIDs = append(orderedIDs, checkID)
+		if err := json.Unmarshal(raw, &c.cfg); err != nil {
+			c.check.Error = "invalid filter_config: " + err.Error()
 		}
+		checks = append(checks, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		if pageID.Valid && len(check.Pages) < params.PageLimitPerCheck {
-			check.Pages = append(check.Pages, models.Page{
-				ID: pageID.Int64, CrawlingSessionID: pageCrawlingSessionID.Int64,
-				URL: pageURL.String, ResponseCode: int(pageResponseCode.Int64),
-			})
+	result := make([]models.CheckWithPages, 0, len(checks))
+	for _, c := range checks {
+		check := c.check
+		check.Pages = []models.Page{}
+		if params.ComparisonSessionID != nil {
+			check.Comparison = repository.NewCheckPageDiff(*params.ComparisonSessionID)
+		}
+		if check.Error == "" {
+			if err := r.fillCheckPages(ctx, &check, c.cfg, scope, scopeArgs, params); err != nil {
+				check.Error = err.Error()
+			}
 		}
+		result = append(result, check)
 	}
+	return result, nil
+}
 
-	var result []models.CheckWithPages
-	for _, id := range orderedIDs {
-		result = append(result, *checksMap[id])
+func (r *CrawlingSessionCheckRepo) fillCheckPages(ctx context.Context, check *models.CheckWithPages, cfg map[string]any,
+	scope string, scopeArgs []any, params repository.ChecksWithPagesParams) error {
+	clause, clauseArgs, err := buildFilterConfigClausePostgres(cfg, len(scopeArgs)+1)
+	if err != nil {
+		return err
+	}
+	if clause == "" {
+		return nil
+	}
+	args := append(append([]any{}, scopeArgs...), clauseArgs...)
+	args = append(args, params.PageLimitPerCheck)
+	limitArg := len(args)
+
+	if check.Comparison == nil {
+		q := fmt.Sprintf(`SELECT id, crawling_session_id, url, response_code FROM pages
+			WHERE crawling_session_id = $1 AND %s AND (%s) ORDER BY id ASC LIMIT $%d`, scope, clause, limitArg)
+		rows, err := r.db.QueryContext(ctx, q, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var p models.Page
+			if err := rows.Scan(&p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode); err != nil {
+				return err
+			}
+			check.Pages = append(check.Pages, p)
+		}
+		return rows.Err()
+	}
+
+	// Pages pair up by normalized URL, keeping the first page of each URL in
+	// a session, as the session diff does.
+	q := fmt.Sprintf(`WITH cur AS (
+			SELECT DISTINCT ON (url_key) id, url, response_code, url_key FROM (
+				SELECT id, url, response_code, %[7]s AS url_key FROM pages
+				WHERE crawling_session_id = $1 AND %[1]s AND (%[2]s)
+			) m ORDER BY url_key, id
+		), prev AS (
+			SELECT DISTINCT ON (url_key) id, url, response_code, url_key FROM (
+				SELECT id, url, response_code, %[7]s AS url_key FROM pages
+				WHERE crawling_session_id = $2 AND %[1]s AND (%[2]s)
+			) m ORDER BY url_key, id
+		), diff AS (
+			SELECT CASE WHEN prev.id IS NULL THEN '%[4]s' WHEN cur.id IS NULL THEN '%[5]s' ELSE '%[6]s' END AS status,
+				COALESCE(cur.id, prev.id) AS id,
+				CASE WHEN cur.id IS NULL THEN $2::bigint ELSE $1::bigint END AS session_id,
+				COALESCE(cur.url, prev.url) AS url,
+				COALESCE(cur.response_code, prev.response_code) AS response_code
+			FROM cur FULL OUTER JOIN prev ON cur.url_key = prev.url_key
+		), ranked AS (
+			SELECT diff.*, ROW_NUMBER() OVER (PARTITION BY status ORDER BY id) AS rn,
+				COUNT(*) OVER (PARTITION BY status) AS total
+			FROM diff
+		)
+		SELECT status, id, session_id, url, response_code, total FROM ranked
+		WHERE rn <= $%[3]d ORDER BY status, id`,
+		scope, clause, limitArg, models.CheckPageNew, models.CheckPageResolved, models.CheckPagePersisting, normalizedURLExpr("url"))
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
 	}
-	return result, rows.Err()
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var p models.Page
+		var total int
+		if err := rows.Scan(&status, &p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode, &total); err != nil {
+			return err
+		}
+		repository.AddCheckDiffPage(check.Comparison, status, p, total)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	check.Pages = repository.CurrentCheckPages(check.Comparison, params.PageLimitPerCheck)
+	return nil
+}
+
+// normalizedURLExpr normalizes the URL in col the way analysis.NormalizeURL
+// does: scheme and host lowercased, default ports and the fragment dropped,
+// and an empty path read as "/". Values that are not absolute URLs are kept
+// as they are.
+func normalizedURLExpr(col string) string {
+	return fmt.Sprintf(`COALESCE(
+		regexp_replace(regexp_replace(lower(substring(btrim(%[1]s) from '^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]*')),
+			'^(http://[^/]*):80$', '\1'), '^(https://[^/]*):443$', '\1')
+		|| COALESCE(NULLIF(substring(btrim(%[1]s) from '^[A-Za-z][A-Za-z0-9+.-]*://[^/?#]*([^?#]*)'), ''), '/')
+		|| COALESCE(substring(btrim(%[1]s) from '^[^?#]*(\?[^#]*)'), ''),
+		%[1]s)`, col)
 }
 
 type PageDetailsRepo struct {
@@ -461,75 +586,60 @@ func NewStatsRepo(db *sql.DB) *StatsRepo {
 }
 
 func (r *StatsRepo) Fetch(ctx context.Context, params repository.StatsQueryParams) (map[string]any, error) {
-	whereClause, args, err := buildPagesWherePostgres(params.SessionID, params.Prefilters, params.Filters)
+	whereClause, args, err := buildStatsWherePostgres(params.SessionID, params)
 	if err != nil {
 		return nil, err
 	}
 
-	q := fmt.Sprintf(`SELECT
-		COUNT(*) AS total,
-		COUNT(*) FILTER (WHERE (og_title = '' OR og_title IS NULL OR og_description = '' OR og_description IS NULL)) AS warning,
-		COUNT(*) FILTER (WHERE (response_code >= 400 AND response_code <= 599)) AS error,
-		COUNT(*) FILTER (WHERE (response_code >= 200 AND response_code <= 299 AND redirect_code IS NULL)) AS ok,
-		COUNT(*) FILTER (WHERE (redirect_code IN ('301','302','307','308'))) AS redirection,
-		COUNT(*) FILTER (WHERE (depth = 1)) AS level1,
-		COUNT(*) FILTER (WHERE (depth = 2)) AS level2,
-		COUNT(*) FILTER (WHERE (depth = 3)) AS level3,
-		COUNT(*) FILTER (WHERE (depth = 4)) AS level4,
-		COUNT(*) FILTER (WHERE (response_code >= 200 AND response_code < 300)) AS success_pages,
-		COUNT(*) FILTER (WHERE (response_code >= 300 AND response_code < 400)) AS redirect_pages,
-		COUNT(*) FILTER (WHERE (response_code >= 400 AND response_code < 500)) AS client_error_pages,
-		COUNT(*) FILTER (WHERE (response_code >= 500)) AS server_error_pages
-		FROM pages WHERE %s`, whereClause)
-
-	var rTotal, rWarning, rError, rOK, rRedirection int
-	var rL1, rL2, rL3, rL4 int
-	var rSuccess, rResp3xx, rClientErr, rServerErr int
-	if err := r.db.QueryRowContext(ctx, q, args...).Scan(
-		&rTotal, &rWarning, &rError, &rOK, &rRedirection,
-		&rL1, &rL2, &rL3, &rL4,
-		&rSuccess, &rResp3xx, &rClientErr, &rServerErr,
-	); err != nil {
+	q := fmt.Sprintf(`SELECT %s FROM pages WHERE %s`, statsCountColumns, whereClause)
+	var counts repository.StatsCounts
+	if err := r.db.QueryRowContext(ctx, q, args...).Scan(counts.Dest()...); err != nil {
 		return nil, err
 	}
+	result := counts.Map()
 
-	result := map[string]any{
-		"total": rTotal, "warning": rWarning, "error": rError, "ok": rOK, "redirection": rRedirection,
-		"level1": rL1, "level2": rL2, "level3": rL3, "level4": rL4,
-		"total_pages": rTotal, "success_pages": rSuccess, "redirect_pages": rResp3xx,
-		"client_error_pages": rClientErr, "server_error_pages": rServerErr,
+	// The sections below read columns and tables a database created before
+	// them lacks; one that cannot be computed is left out instead of failing
+	// the request.
+	if indexability, err := r.fetchIndexability(ctx, whereClause, args); err == nil {
+		result["indexability"] = indexability
+	}
+	if structuredData, err := r.fetchStructuredDataCoverage(ctx, whereClause, args, counts.Total); err == nil {
+		result["structured_data"] = structuredData
+	}
+	if security, err := r.fetchSecuritySummary(ctx, whereClause, args, params.SessionID); err == nil {
+		result["security"] = security
+	}
+	if performance, err := r.fetchPerformanceSummary(ctx, whereClause, args); err == nil {
+		result["performance"] = performance
+	}
+	if depths, err := r.fetchDepthHistogram(ctx, whereClause, args); err == nil {
+		result["depth_histogram"] = depths
 	}
 
-	problematicCount, err := r.fetchProblematicCount(ctx, whereClause, args, params.SessionID)
+	checkResults, problematicCount, err := r.fetchCheckResults(ctx, whereClause, args, params.SessionID)
 	if err == nil {
 		result["problematic"] = problematicCount
-		if rTotal > 0 && problematicCount > 0 {
-			result["site_health"] = 100 - (problematicCount * 100 / rTotal)
-		} else if rTotal > 0 {
-			result["site_health"] = 100
-		} else {
-			result["site_health"] = 0
+		health, formula := scoring.SiteHealth(counts.Total, checkResults)
+		result["site_health"] = health
+		result["site_health_formula"] = formula
+	}
+
+	if params.GroupBy != nil {
+		if groups, err := r.fetchGroups(ctx, whereClause, args, *params.GroupBy, params.SessionID); err == nil {
+			result["group_by"] = params.GroupBy.Field
+			result["groups"] = groups
 		}
 	}
 
 	if params.ComparisonSessionID != nil {
-		cWhere, cArgs, err := buildPagesWherePostgres(*params.ComparisonSessionID, params.Prefilters, params.Filters)
+		cWhere, cArgs, err := buildStatsWherePostgres(*params.ComparisonSessionID, params)
 		if err == nil {
-			cq := fmt.Sprintf(`SELECT
-				COUNT(*) AS total,
-				COUNT(*) FILTER (WHERE (og_title = '' OR og_title IS NULL OR og_description = '' OR og_description IS NULL)) AS warning,
-				COUNT(*) FILTER (WHERE (response_code >= 400 AND response_code <= 599)) AS error,
-				COUNT(*) FILTER (WHERE (response_code >= 200 AND response_code <= 299 AND redirect_code IS NULL)) AS ok,
-				COUNT(*) FILTER (WHERE (redirect_code IN ('301','302','307','308'))) AS redirection
-				FROM pages WHERE %s`, cWhere)
-			var cTotal, cWarning, cError, cOK, cRedirection int
-			if err := r.db.QueryRowContext(ctx, cq, cArgs...).Scan(&cTotal, &cWarning, &cError, &cOK, &cRedirection); err == nil {
-				result["comparison"] = map[string]any{
-					"total": cTotal, "warning": cWarning, "error": cError, "ok": cOK, "redirection": cRedirection,
-				}
-				result["changes"] = map[string]int{
-					"total": rTotal - cTotal, "warning": rWarning - cWarning, "error": rError - cError, "ok": rOK - cOK, "redirection": rRedirection - cRedirection,
-				}
+			cq := fmt.Sprintf(`SELECT %s FROM pages WHERE %s`, statsCountColumns, cWhere)
+			var comparison repository.StatsCounts
+			if err := r.db.QueryRowContext(ctx, cq, cArgs...).Scan(comparison.Dest()...); err == nil {
+				result["comparison"] = comparison.Map()
+				result["changes"] = counts.Changes(comparison)
 			}
 		}
 	}
@@ -537,55 +647,277 @@ func (r *StatsRepo) Fetch(ctx context.Context, params repository.StatsQueryParam
 	return result, nil
 }
 
-func (r *StatsRepo) fetchProblematicCount(ctx context.Context, baseWhere string, baseArgs []any, sessionID int64) (int, error) {
-	var skuID int64
-	if err := r.db.QueryRowContext(ctx, `SELECT search_keyword_url_id FROM crawling_sessions WHERE id = $1`, sessionID).Scan(&skuID); err != nil {
-		return 0, err
+// fetchIndexability counts the indexable and non-indexable filtered pages,
+// the latter also per reason.
+func (r *StatsRepo) fetchIndexability(ctx context.Context, baseWhere string, baseArgs []any) (map[string]any, error) {
+	q := fmt.Sprintf(`SELECT %s FROM pages WHERE %s`, indexabilityColumns, baseWhere)
+	var indexable, nonIndexable int
+	if err := r.db.QueryRowContext(ctx, q, baseArgs...).Scan(&indexable, &nonIndexable); err != nil {
+		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, `SELECT filter_config FROM audit_checks WHERE search_keyword_url_id = $1 AND category = 'problematic'`, skuID)
+	q = fmt.Sprintf(`SELECT indexability_reason, COUNT(*) FROM pages
+		WHERE %s AND indexability = 'non_indexable' GROUP BY indexability_reason`, baseWhere)
+	rows, err := r.db.QueryContext(ctx, q, baseArgs...)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var rawConfigs [][]byte
+	reasons := map[string]int{}
 	for rows.Next() {
-		var raw []byte
-		if err := rows.Scan(&raw); err != nil {
-			return 0, err
+		var reason sql.NullString
+		var count int
+		if err := rows.Scan(&reason, &count); err != nil {
+			return nil, err
 		}
-		if len(raw) > 0 {
-			rawConfigs = append(rawConfigs, raw)
+		reasons[reason.String] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return map[string]any{"indexable": indexable, "non_indexable": nonIndexable, "reasons": reasons}, nil
+}
+
+// fetchStructuredDataCoverage reports how many of the filtered pages carry
+// structured data, overall and per schema.org type, and how many have errors.
+func (r *StatsRepo) fetchStructuredDataCoverage(ctx context.Context, baseWhere string, baseArgs []any, total int) (map[string]any, error) {
+	q := fmt.Sprintf(`SELECT
+		COUNT(*) FILTER (WHERE structured_data_types <> '') AS with_data,
+		COUNT(*) FILTER (WHERE structured_data_errors > 0) AS with_errors
+		FROM pages WHERE %s`, baseWhere)
+	var withData, withErrors int
+	if err := r.db.QueryRowContext(ctx, q, baseArgs...).Scan(&withData, &withErrors); err != nil {
+		return nil, err
+	}
+
+	q = fmt.Sprintf(`SELECT type, COUNT(DISTINCT page_id),
+		COUNT(DISTINCT page_id) FILTER (WHERE missing_properties <> '' OR error <> '')
+		FROM page_structured_data WHERE type <> '' AND page_id IN (SELECT id FROM pages WHERE %s)
+		GROUP BY type`, baseWhere)
+	rows, err := r.db.QueryContext(ctx, q, baseArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := map[string]any{}
+	for rows.Next() {
+		var typ string
+		var pages, errored int
+		if err := rows.Scan(&typ, &pages, &errored); err != nil {
+			return nil, err
+		}
+		coverage := 0
+		if total > 0 {
+			coverage = pages * 100 / total
 		}
+		types[typ] = map[string]any{"pages": pages, "pages_with_errors": errored, "coverage": coverage}
 	}
 	if err := rows.Err(); err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	probClause, probArgs, err := buildProblematicClausePostgres(rawConfigs)
-	if err != nil || probClause == "" {
-		return 0, err
+	return map[string]any{"pages": withData, "pages_with_errors": withErrors, "types": types}, nil
+}
+
+// fetchSecuritySummary counts the filtered pages per security issue. The
+// session-level certificate check is reported next to the per-page findings.
+func (r *StatsRepo) fetchSecuritySummary(ctx context.Context, baseWhere string, baseArgs []any, sessionID int64) (map[string]any, error) {
+	q := fmt.Sprintf(`SELECT
+		COUNT(*) FILTER (WHERE security_issues <> '') AS with_issues,
+		COUNT(*) FILTER (WHERE mixed_content_count > 0) AS mixed_content
+		FROM pages WHERE %s`, baseWhere)
+	var withIssues, mixedContent int
+	if err := r.db.QueryRowContext(ctx, q, baseArgs...).Scan(&withIssues, &mixedContent); err != nil {
+		return nil, err
 	}
 
-	renumbered := renumberPostgresPlaceholders(probClause, len(baseArgs)+1)
-	args := append(append([]any{}, baseArgs...), probArgs...)
-	q := fmt.Sprintf("SELECT COUNT(*) FROM pages WHERE %s AND (%s)", baseWhere, renumbered)
-	var count int
-	if err := r.db.QueryRowContext(ctx, q, args...).Scan(&count); err != nil {
-		return 0, err
+	q = fmt.Sprintf(`SELECT issue, COUNT(*) FROM pages,
+		unnest(string_to_array(NULLIF(security_issues, ''), ',')) AS issue
+		WHERE %s GROUP BY issue`, baseWhere)
+	rows, err := r.db.QueryContext(ctx, q, baseArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issues := map[string]int{}
+	for rows.Next() {
+		var issue string
+		var count int
+		if err := rows.Scan(&issue, &count); err != nil {
+			return nil, err
+		}
+		issues[issue] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{"pages_with_issues": withIssues, "mixed_content_pages": mixedContent, "issues": issues}
+	var sslValid sql.NullBool
+	if err := r.db.QueryRowContext(ctx, `SELECT ssl_valid FROM crawling_sessions WHERE id = $1`, sessionID).Scan(&sslValid); err == nil {
+		out["ssl_valid"] = sslValid.Bool
 	}
-	return count, nil
+	return out, nil
 }
 
-func renumberPostgresPlaceholders(clause string, start int) string {
-	// buildProblematicClausePostgres uses $1..$N, but we need to append after existing args.
-	re := regexp.MustCompile(`\$(\d+)`)
-	return re.ReplaceAllStringFunc(clause, func(m string) string {
-		n, err := strconv.Atoi(strings.TrimPrefix(m, "$"))
-		if err != nil {
-			return m
+// fetchDepthHistogram counts the filtered pages at every crawl depth, in
+// depth order, split by response status class.
+func (r *StatsRepo) fetchDepthHistogram(ctx context.Context, baseWhere string, baseArgs []any) ([]models.DepthLevel, error) {
+	q := fmt.Sprintf(`SELECT depth, COUNT(*),
+		COUNT(*) FILTER (WHERE response_code >= 200 AND response_code < 300),
+		COUNT(*) FILTER (WHERE response_code >= 300 AND response_code < 400),
+		COUNT(*) FILTER (WHERE response_code >= 400 AND response_code < 500),
+		COUNT(*) FILTER (WHERE response_code >= 500 AND response_code < 600)
+		FROM pages WHERE %s AND depth IS NOT NULL GROUP BY depth ORDER BY depth`, baseWhere)
+	rows, err := r.db.QueryContext(ctx, q, baseArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []models.DepthLevel{}
+	for rows.Next() {
+		var level models.DepthLevel
+		var s2xx, s3xx, s4xx, s5xx int
+		if err := rows.Scan(&level.Depth, &level.Pages, &s2xx, &s3xx, &s4xx, &s5xx); err != nil {
+			return nil, err
 		}
-		return fmt.Sprintf("$%d", start+n-1)
-	})
+		level.StatusClasses = map[string]int{"2xx": s2xx, "3xx": s3xx, "4xx": s4xx, "5xx": s5xx}
+		levels = append(levels, level)
+	}
+	return levels, rows.Err()
+}
+
+// timedPages keeps the pages with a recorded response time. Pages that were
+// not timed hold NULL or 0.
+const timedPages = "response_time_ms > 0"
+
+// fetchPerformanceSummary reports response time percentiles, average page
+// weight and the number of slow pages. Pages without recorded timings are
+// left out.
+func (r *StatsRepo) fetchPerformanceSummary(ctx context.Context, baseWhere string, baseArgs []any) (map[string]any, error) {
+	q := fmt.Sprintf(`SELECT %s FROM pages WHERE %s AND %s`,
+		performanceColumns(len(baseArgs)+1), baseWhere, timedPages)
+	args := append(append([]any{}, baseArgs...), repository.SlowPageThresholdMs)
+
+	var p performanceRow
+	if err := r.db.QueryRowContext(ctx, q, args...).Scan(p.dest()...); err != nil {
+		return nil, err
+	}
+	return p.summary(), nil
+}
+
+// performanceColumns selects the aggregates of a performanceRow, reading
+// the slow page threshold from $slowArg.
+func performanceColumns(slowArg int) string {
+	return fmt.Sprintf(`COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY response_time_ms), 0),
+		COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY response_time_ms), 0),
+		COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY response_time_ms), 0),
+		COALESCE(AVG(transfer_size), 0), COALESCE(AVG(uncompressed_size), 0),
+		COUNT(*) FILTER (WHERE response_time_ms > $%d)`, slowArg)
+}
+
+// performanceRow holds the aggregates selected by performanceColumns.
+type performanceRow struct {
+	p50, p90, p99, avgTransfer, avgUncompressed float64
+	slow                                        int
+}
+
+func (p *performanceRow) dest() []any {
+	return []any{&p.p50, &p.p90, &p.p99, &p.avgTransfer, &p.avgUncompressed, &p.slow}
+}
+
+func (p performanceRow) summary() map[string]any {
+	return map[string]any{
+		"response_time_ms":       map[string]float64{"p50": p.p50, "p90": p.p90, "p99": p.p99},
+		"avg_page_weight":        int64(p.avgTransfer),
+		"avg_uncompressed_size":  int64(p.avgUncompressed),
+		"slow_pages":             p.slow,
+		"slow_page_threshold_ms": repository.SlowPageThresholdMs,
+	}
+}
+
+// fetchCheckResults counts the filtered pages matched by every audit check of
+// the session's SKU in a single scan, together with the pages matched by any
+// problematic check.
+func (r *StatsRepo) fetchCheckResults(ctx context.Context, baseWhere string, baseArgs []any, sessionID int64) ([]scoring.CheckResult, int, error) {
+	results, selects, selectArgs, err := r.checkCountSelects(ctx, sessionID, len(baseArgs)+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	counts := make([]int, len(selects))
+	dest := make([]any, len(selects))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	q := fmt.Sprintf("SELECT %s FROM pages WHERE %s", strings.Join(selects, ", "), baseWhere)
+	if err := r.db.QueryRowContext(ctx, q, append(append([]any{}, baseArgs...), selectArgs...)...).Scan(dest...); err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		results[i].MatchedPages = counts[i]
+	}
+	return results, counts[len(counts)-1], nil
+}
+
+// checkCountSelects returns the audit checks of the session's SKU with one
+// count per check, followed by the count of pages matched by any problematic
+// check, and their args numbered from $start. Configs are evaluated like
+// PageMatchRepo does; checks whose filter_config does not build are left out.
+func (r *StatsRepo) checkCountSelects(ctx context.Context, sessionID int64, start int) ([]scoring.CheckResult, []string, []any, error) {
+	var skuID int64
+	if err := r.db.QueryRowContext(ctx, `SELECT search_keyword_url_id FROM crawling_sessions WHERE id = $1`, sessionID).Scan(&skuID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, category, COALESCE(severity, ''), COALESCE(weight, 0), filter_config
+		FROM audit_checks WHERE search_keyword_url_id = $1 ORDER BY id ASC`, skuID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	var results []scoring.CheckResult
+	var selects []string
+	var problematicGroups []any
+	var args []any
+	for rows.Next() {
+		var c scoring.CheckResult
+		var raw []byte
+		if err := rows.Scan(&c.ID, &c.Name, &c.Category, &c.Severity, &c.Weight, &raw); err != nil {
+			return nil, nil, nil, err
+		}
+		var cfg map[string]any
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			continue
+		}
+		clause, clauseArgs, err := buildFilterConfigClausePostgres(cfg, start+len(args))
+		if err != nil || clause == "" {
+			continue
+		}
+		selects = append(selects, fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", clause))
+		args = append(args, clauseArgs...)
+		results = append(results, c)
+		if c.Category == scoring.CategoryProblematic {
+			problematicGroups = append(problematicGroups, cfg["filter_groups"].([]any)...)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// A page is problematic when any group of any problematic check matches
+	// it, which is the union of their groups evaluated as a single config.
+	probClause, probArgs, err := buildFilterConfigClausePostgres(map[string]any{"filter_groups": problematicGroups}, start+len(args))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if probClause == "" {
+		probClause = "FALSE"
+	}
+	selects = append(selects, fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", probClause))
+	return results, selects, append(args, probArgs...), nil
 }
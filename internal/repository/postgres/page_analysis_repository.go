@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"sitecrawler/newgo/internal/analysis"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+type PageAnalysisRepo struct {
+	db *sql.DB
+}
+
+func NewPageAnalysisRepo(db *sql.DB) *PageAnalysisRepo {
+	return &PageAnalysisRepo{db: db}
+}
+
+func (r *PageAnalysisRepo) SaveIndexability(ctx context.Context, pageID int64, result models.PageIndexability) error {
+	q := `UPDATE pages SET indexability=$2, indexability_reason=$3 WHERE id=$1`
+	_, err := r.db.ExecContext(ctx, q, pageID, result.Status, result.Reason)
+	return err
+}
+
+func (r *PageAnalysisRepo) SaveContentFingerprint(ctx context.Context, pageID int64, fp models.PageContentFingerprint) error {
+	// simhash is stored as BIGINT; the uint64 bit pattern round-trips through int64.
+	q := `UPDATE pages SET content_hash=$2, simhash=$3 WHERE id=$1`
+	_, err := r.db.ExecContext(ctx, q, pageID, fp.ContentHash, int64(fp.SimHash))
+	return err
+}
+
+func (r *PageAnalysisRepo) SaveHreflangLinks(ctx context.Context, pageID int64, links []models.HreflangLink) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM page_hreflangs WHERE page_id=$1`, pageID); err != nil {
+		return err
+	}
+	for _, l := range links {
+		q := `INSERT INTO page_hreflangs (page_id, hreflang, href, source) VALUES ($1,$2,$3,$4)`
+		if _, err := tx.ExecContext(ctx, q, pageID, l.Hreflang, l.Href, l.Source); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveHreflangIssues stores the issue types the session-wide hreflang audit
+// found for the page, so the built-in hreflang checks can filter on them.
+func (r *PageAnalysisRepo) SaveHreflangIssues(ctx context.Context, pageID int64, issues []models.HreflangIssue) error {
+	q := `UPDATE pages SET hreflang_issues=$2 WHERE id=$1`
+	_, err := r.db.ExecContext(ctx, q, pageID, strings.Join(analysis.HreflangIssueTypes(issues), ","))
+	return err
+}
+
+// SaveStructuredData stores the item breakdown in page_structured_data and
+// denormalizes the types and error count onto pages for filtering.
+func (r *PageAnalysisRepo) SaveStructuredData(ctx context.Context, pageID int64, items []models.StructuredDataItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `UPDATE pages SET structured_data_types=$2, structured_data_errors=$3 WHERE id=$1`
+	types := strings.Join(analysis.StructuredDataTypes(items), ",")
+	if _, err := tx.ExecContext(ctx, q, pageID, types, analysis.StructuredDataErrorCount(items)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM page_structured_data WHERE page_id=$1`, pageID); err != nil {
+		return err
+	}
+	for _, it := range items {
+		q := `INSERT INTO page_structured_data (page_id, format, type, missing_properties, error) VALUES ($1,$2,$3,$4,$5)`
+		if _, err := tx.ExecContext(ctx, q, pageID, it.Format, it.Type, strings.Join(it.Missing, ","), it.Error); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveSecurity stores the issue list on pages and replaces the page's
+// mixed-content resources.
+func (r *PageAnalysisRepo) SaveSecurity(ctx context.Context, pageID int64, result models.PageSecurity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `UPDATE pages SET security_issues=$2, mixed_content_count=$3 WHERE id=$1`
+	if _, err := tx.ExecContext(ctx, q, pageID, strings.Join(result.Issues, ","), len(result.MixedContent)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM page_mixed_content WHERE page_id=$1`, pageID); err != nil {
+		return err
+	}
+	for _, u := range result.MixedContent {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO page_mixed_content (page_id, url) VALUES ($1,$2)`, pageID, u); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PageAnalysisRepo) SavePerformance(ctx context.Context, pageID int64, perf models.PagePerformance) error {
+	q := `UPDATE pages SET dns_ms=$2, connect_ms=$3, tls_ms=$4, ttfb_ms=$5, response_time_ms=$6,
+		transfer_size=$7, uncompressed_size=$8, content_encoding=$9, resource_count=$10 WHERE id=$1`
+	_, err := r.db.ExecContext(ctx, q, pageID, perf.DNSMs, perf.ConnectMs, perf.TLSMs, perf.TTFBMs, perf.ResponseTimeMs,
+		perf.TransferSize, perf.UncompressedSize, perf.ContentEncoding, perf.ResourceCount)
+	return err
+}
+
+type PageSnapshotRepo struct {
+	db *sql.DB
+}
+
+func NewPageSnapshotRepo(db *sql.DB) *PageSnapshotRepo {
+	return &PageSnapshotRepo{db: db}
+}
+
+func (r *PageSnapshotRepo) ListSnapshots(ctx context.Context, sessionID int64) ([]models.PageSnapshot, error) {
+	q := `SELECT id, url, response_code, COALESCE(title, ''), COALESCE(meta_description, ''),
+		COALESCE(content_hash, ''), COALESCE(simhash, 0), COALESCE(canonical, ''), COALESCE(indexability, '')
+		FROM pages WHERE crawling_session_id = $1 ORDER BY id ASC`
+	rows, err := r.db.QueryContext(ctx, q, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.PageSnapshot
+	for rows.Next() {
+		var s models.PageSnapshot
+		var simhash int64
+		if err := rows.Scan(&s.ID, &s.URL, &s.ResponseCode, &s.Title, &s.MetaDescription, &s.ContentHash, &simhash, &s.Canonical, &s.Indexability); err != nil {
+			return nil, err
+		}
+		s.SimHash = uint64(simhash)
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *PageSnapshotRepo) ListHreflangLinks(ctx context.Context, sessionID int64) (map[int64][]models.HreflangLink, error) {
+	q := `SELECT h.page_id, h.hreflang, h.href, h.source
+		FROM page_hreflangs h JOIN pages p ON p.id = h.page_id
+		WHERE p.crawling_session_id = $1 ORDER BY h.page_id ASC, h.hreflang ASC`
+	rows, err := r.db.QueryContext(ctx, q, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64][]models.HreflangLink{}
+	for rows.Next() {
+		var pageID int64
+		var l models.HreflangLink
+		if err := rows.Scan(&pageID, &l.Hreflang, &l.Href, &l.Source); err != nil {
+			return nil, err
+		}
+		out[pageID] = append(out[pageID], l)
+	}
+	return out, rows.Err()
+}
+
+// DiffSessions pairs the pages of both sessions by normalized URL, keeping
+// the first page of each URL, and compares them as analysis.DiffPages does.
+// Every change list is numbered in page id order and only the requested page
+// of it is returned.
+func (r *PageSnapshotRepo) DiffSessions(ctx context.Context, params repository.SessionDiffParams) (map[string]*models.PageChangeSet, error) {
+	diff := repository.NewSessionDiff(params)
+	if len(diff) == 0 {
+		return diff, nil
+	}
+	args := []any{params.SessionID, params.AgainstSessionID, params.Offset(), params.PageLimit}
+	changes := make([]string, 0, len(diff))
+	for change := range diff {
+		args = append(args, change)
+		changes = append(changes, fmt.Sprintf("$%d", len(args)))
+	}
+
+	keyed := func(sessionArg string) string {
+		return fmt.Sprintf(`SELECT DISTINCT ON (url_key) id, url, response_code, title, canonical, indexability, content_hash, url_key FROM (
+			SELECT id, url, response_code, COALESCE(title, '') AS title, COALESCE(canonical, '') AS canonical,
+				COALESCE(indexability, '') AS indexability, COALESCE(content_hash, '') AS content_hash, %s AS url_key
+			FROM pages WHERE crawling_session_id = %s
+		) p ORDER BY url_key, id`, normalizedURLExpr("url"), sessionArg)
+	}
+	q := fmt.Sprintf(`WITH cur AS (%[1]s), prev AS (%[2]s), diff AS (
+			SELECT CASE WHEN prev.id IS NULL THEN '%[3]s' WHEN cur.id IS NULL THEN '%[4]s' ELSE '%[5]s' END AS change,
+				COALESCE(cur.id, 0) AS cur_id, COALESCE(cur.url, '') AS cur_url, COALESCE(cur.response_code, 0) AS cur_response_code,
+				COALESCE(cur.title, '') AS cur_title, COALESCE(cur.canonical, '') AS cur_canonical,
+				COALESCE(cur.indexability, '') AS cur_indexability, COALESCE(cur.content_hash, '') AS cur_content_hash,
+				COALESCE(prev.id, 0) AS prev_id, COALESCE(prev.url, '') AS prev_url, COALESCE(prev.response_code, 0) AS prev_response_code,
+				COALESCE(prev.title, '') AS prev_title, COALESCE(prev.canonical, '') AS prev_canonical,
+				COALESCE(prev.indexability, '') AS prev_indexability, COALESCE(prev.content_hash, '') AS prev_content_hash
+			FROM cur FULL OUTER JOIN prev ON cur.url_key = prev.url_key
+			WHERE cur.id IS NULL OR prev.id IS NULL
+				OR cur.response_code <> prev.response_code OR cur.title <> prev.title
+				OR (cur.canonical <> prev.canonical AND (cur.canonical = '' OR prev.canonical = '' OR %[6]s <> %[7]s))
+				OR (cur.indexability <> '' AND prev.indexability <> '' AND cur.indexability <> prev.indexability)
+				OR (cur.content_hash <> '' AND prev.content_hash <> '' AND cur.content_hash <> prev.content_hash)
+		), ranked AS (
+			SELECT diff.*, ROW_NUMBER() OVER (PARTITION BY change ORDER BY COALESCE(NULLIF(cur_id, 0), prev_id)) AS rn,
+				COUNT(*) OVER (PARTITION BY change) AS total
+			FROM diff WHERE change IN (%[8]s)
+		)
+		SELECT change, rn, total, cur_id, cur_url, cur_response_code, cur_title, cur_canonical, cur_indexability, cur_content_hash,
+			prev_id, prev_url, prev_response_code, prev_title, prev_canonical, prev_indexability, prev_content_hash
+		FROM ranked WHERE rn = 1 OR (rn > $3::bigint AND rn <= $3::bigint + $4::bigint) ORDER BY change, rn`,
+		keyed("$1"), keyed("$2"),
+		models.PageChangeAdded, models.PageChangeRemoved, models.PageChangeChanged,
+		normalizedURLExpr("cur.canonical"), normalizedURLExpr("prev.canonical"), strings.Join(changes, ", "))
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var change string
+		var row, total int
+		var cur, prev models.PageSnapshot
+		if err := rows.Scan(&change, &row, &total,
+			&cur.ID, &cur.URL, &cur.ResponseCode, &cur.Title, &cur.Canonical, &cur.Indexability, &cur.ContentHash,
+			&prev.ID, &prev.URL, &prev.ResponseCode, &prev.Title, &prev.Canonical, &prev.Indexability, &prev.ContentHash); err != nil {
+			return nil, err
+		}
+		repository.AddSessionDiffRow(diff, change, row, total, params.Offset(), cur, prev)
+	}
+	return diff, rows.Err()
+}
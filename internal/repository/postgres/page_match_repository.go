@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+type PageMatchRepo struct {
+	db *sql.DB
+}
+
+func NewPageMatchRepo(db *sql.DB) *PageMatchRepo {
+	return &PageMatchRepo{db: db}
+}
+
+func (r *PageMatchRepo) CountMatches(ctx context.Context, sessionID int64, configs []map[string]any) ([]int, error) {
+	return countFilterConfigMatchesPostgres(ctx, r.db, "crawling_session_id = $1", []any{sessionID}, configs)
+}
+
+func (r *PageMatchRepo) CountMatchesBySession(ctx context.Context, sessionIDs []int64, configs []map[string]any) (map[int64][]int, error) {
+	out := repository.ZeroCountsBySession(sessionIDs, len(configs))
+	if len(sessionIDs) == 0 || len(configs) == 0 {
+		return out, nil
+	}
+
+	ph := make([]string, len(sessionIDs))
+	args := make([]any, len(sessionIDs))
+	for i, id := range sessionIDs {
+		ph[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	selects, args, err := filterConfigCountSelectsPostgres(configs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf("SELECT crawling_session_id, %s FROM pages WHERE crawling_session_id IN (%s) GROUP BY crawling_session_id",
+		strings.Join(selects, ", "), strings.Join(ph, ","))
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sessionID int64
+		counts := make([]int, len(configs))
+		dest := make([]any, 0, len(counts)+1)
+		dest = append(dest, &sessionID)
+		for i := range counts {
+			dest = append(dest, &counts[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		out[sessionID] = counts
+	}
+	return out, rows.Err()
+}
+
+func (r *PageMatchRepo) ListMatches(ctx context.Context, sessionID int64, config map[string]any, page, limit int) ([]models.Page, int, error) {
+	clause, clauseArgs, err := buildFilterConfigClausePostgres(config, 2)
+	if err != nil {
+		return nil, 0, err
+	}
+	if clause == "" {
+		return []models.Page{}, 0, nil
+	}
+	where := fmt.Sprintf("crawling_session_id = $1 AND (%s)", clause)
+	args := append([]any{sessionID}, clauseArgs...)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pages WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := 0
+	if page > 1 {
+		offset = (page - 1) * limit
+	}
+	q := fmt.Sprintf(`SELECT id, crawling_session_id, url, response_code FROM pages WHERE %s
+		ORDER BY id ASC LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	pages := []models.Page{}
+	for rows.Next() {
+		var p models.Page
+		if err := rows.Scan(&p.ID, &p.CrawlingSessionID, &p.URL, &p.ResponseCode); err != nil {
+			return nil, 0, err
+		}
+		pages = append(pages, p)
+	}
+	return pages, total, rows.Err()
+}
+
+// countFilterConfigMatchesPostgres counts, in a single scan of the pages
+// selected by baseWhere, the pages matching each config. Configs without
+// filter groups count 0.
+func countFilterConfigMatchesPostgres(ctx context.Context, db *sql.DB, baseWhere string, baseArgs []any, configs []map[string]any) ([]int, error) {
+	counts := make([]int, len(configs))
+	if len(configs) == 0 {
+		return counts, nil
+	}
+
+	selects, args, err := filterConfigCountSelectsPostgres(configs, append([]any{}, baseArgs...))
+	if err != nil {
+		return nil, err
+	}
+
+	dest := make([]any, len(counts))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	q := fmt.Sprintf("SELECT %s FROM pages WHERE %s", strings.Join(selects, ", "), baseWhere)
+	if err := db.QueryRowContext(ctx, q, args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// filterConfigCountSelectsPostgres returns one COUNT select per config,
+// numbering placeholders after args and returning args extended with theirs.
+func filterConfigCountSelectsPostgres(configs []map[string]any, args []any) ([]string, []any, error) {
+	selects := make([]string, len(configs))
+	for i, cfg := range configs {
+		clause, clauseArgs, err := buildFilterConfigClausePostgres(cfg, len(args)+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if clause == "" {
+			selects[i] = "0"
+			continue
+		}
+		selects[i] = fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", clause)
+		args = append(args, clauseArgs...)
+	}
+	return selects, args, nil
+}
@@ -1,11 +1,12 @@
 package postgres
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+
+	"sitecrawler/newgo/internal/repository"
 )
 
 var pageIdentRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
@@ -37,43 +38,69 @@ func buildPagesWherePostgres(sessionID int64, prefilters, filters []map[string]a
 	return where, args, nil
 }
 
-func buildProblematicClausePostgres(rawFilterConfigs [][]byte) (string, []any, error) {
+// buildStatsWherePostgres restricts the pages of sessionID to the stats
+// request's prefilters, filters and view filter config.
+func buildStatsWherePostgres(sessionID int64, params repository.StatsQueryParams) (string, []any, error) {
+	where, args, err := buildPagesWherePostgres(sessionID, params.Prefilters, params.Filters)
+	if err != nil {
+		return "", nil, err
+	}
+	return appendFilterConfigPostgres(where, args, params.FilterConfig)
+}
+
+// buildFilterConfigClausePostgres turns an audit check or view filter_config
+// into a WHERE fragment with placeholders starting at $start: filter groups
+// are ORed, the filters of a group ANDed. A config without filter groups
+// yields an empty clause, which callers treat as matching nothing.
+func buildFilterConfigClausePostgres(cfg map[string]any, start int) (string, []any, error) {
+	raw, ok := cfg["filter_groups"]
+	if !ok || raw == nil {
+		return "", nil, nil
+	}
+	if _, ok := raw.([]any); !ok {
+		return "", nil, errors.New("filter_groups must be a list")
+	}
+
 	var orParts []string
 	var args []any
-	next := 1
-
-	for _, raw := range rawFilterConfigs {
-		var cfg map[string]any
-		if err := json.Unmarshal(raw, &cfg); err != nil {
-			continue
+	next := start
+	for _, g := range normalizeFilterGroups(raw) {
+		var clause string
+		var groupArgs []any
+		var err error
+		if _, ok := g["filters"]; ok {
+			clause, groupArgs, err = buildGroupClausePostgres(g, next)
+		} else {
+			clause, groupArgs, err = buildEqualityClausePostgres(g, next)
 		}
-		filterGroupsRaw, ok := cfg["filter_groups"]
-		if !ok {
-			continue
+		if err != nil {
+			return "", nil, err
 		}
-		groups := normalizeFilterGroups(filterGroupsRaw)
-		for _, g := range groups {
-			var groupClause string
-			var groupArgs []any
-			var err error
-			if _, ok := g["filters"]; ok {
-				groupClause, groupArgs, err = buildGroupClausePostgres(g, next)
-			} else {
-				groupClause, groupArgs, err = buildEqualityClausePostgres(g, next)
-			}
-			if err != nil || groupClause == "" {
-				continue
-			}
-			next += len(groupArgs)
-			args = append(args, groupArgs...)
-			orParts = append(orParts, "("+groupClause+")")
+		if clause == "" {
+			continue
 		}
+		next += len(groupArgs)
+		args = append(args, groupArgs...)
+		orParts = append(orParts, "("+clause+")")
 	}
+	return strings.Join(orParts, " OR "), args, nil
+}
 
-	if len(orParts) == 0 {
-		return "", nil, nil
+// appendFilterConfigPostgres ANDs cfg onto where, numbering its placeholders
+// after args. A nil cfg leaves where unchanged; a config without filter
+// groups matches nothing.
+func appendFilterConfigPostgres(where string, args []any, cfg map[string]any) (string, []any, error) {
+	if cfg == nil {
+		return where, args, nil
 	}
-	return strings.Join(orParts, " OR "), args, nil
+	clause, clauseArgs, err := buildFilterConfigClausePostgres(cfg, len(args)+1)
+	if err != nil {
+		return "", nil, err
+	}
+	if clause == "" {
+		clause = "FALSE"
+	}
+	return where + " AND (" + clause + ")", append(args, clauseArgs...), nil
 }
 
 func buildEqualityClausePostgres(m map[string]any, start int) (string, []any, error) {
@@ -252,7 +279,29 @@ func toAnySlice(v any) ([]any, bool) {
 	}
 }
 
+// virtualPageColumns are filterable names that do not exist on the pages table
+// and are computed per row instead.
+var virtualPageColumns = map[string]string{
+	"duplicate_title_count":       duplicateCountExpr("title"),
+	"duplicate_description_count": duplicateCountExpr("meta_description"),
+	"duplicate_content_count":     duplicateCountExpr("content_hash"),
+	"title_length":                "COALESCE(LENGTH(pages.title), 0)",
+	"meta_description_length":     "COALESCE(LENGTH(pages.meta_description), 0)",
+	"h1_length":                   "COALESCE(LENGTH(pages.h1), 0)",
+	"inlinks_count":               "(SELECT COUNT(*) FROM page_links pl WHERE pl.target_page_id = pages.id)",
+}
+
+// duplicateCountExpr counts the pages of the same session sharing col with the
+// current row, the row itself included. Empty values never count as duplicates.
+func duplicateCountExpr(col string) string {
+	return fmt.Sprintf(`(SELECT COUNT(*) FROM pages dup WHERE dup.crawling_session_id = pages.crawling_session_id
+		AND pages.%[1]s <> '' AND dup.%[1]s = pages.%[1]s)`, col)
+}
+
 func allowedPageColumn(name string) (string, bool) {
+	if expr, ok := virtualPageColumns[name]; ok {
+		return expr, true
+	}
 	if !pageIdentRE.MatchString(name) {
 		return "", false
 	}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+
+	"sitecrawler/newgo/internal/analysis"
+	"sitecrawler/newgo/internal/catalog"
 )
 
 func TestBuildPagesWherePostgres_EqualityMap(t *testing.T) {
@@ -36,31 +39,77 @@ func TestBuildPagesWherePostgres_FilterGroup(t *testing.T) {
 	}
 }
 
-func TestBuildProblematicClausePostgres(t *testing.T) {
-	raw, _ := json.Marshal(map[string]any{
-		"filter_groups": []any{
-			map[string]any{
-				"filters": []any{
-					map[string]any{"name": "response_code", "operator": "gte", "value": 400},
-				},
-			},
-		},
+func TestBuildPagesWherePostgres_VirtualColumn(t *testing.T) {
+	where, args, err := buildPagesWherePostgres(1, nil, []map[string]any{
+		{"filters": []any{map[string]any{"name": "duplicate_title_count", "operator": "gt", "value": 1}}},
 	})
-	clause, args, err := buildProblematicClausePostgres([][]byte{raw})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if want := "(response_code >= $1)"; clause != want {
+	if want := "crawling_session_id = $1 AND (" + duplicateCountExpr("title") + " > $2)"; where != want {
+		t.Fatalf("expected where %q got %q", want, where)
+	}
+	if !reflect.DeepEqual(args, []any{int64(1), 1}) {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+}
+
+func TestBuildFilterConfigClausePostgres(t *testing.T) {
+	cfg := map[string]any{"filter_groups": []any{
+		map[string]any{"filters": []any{
+			map[string]any{"name": "response_code", "operator": "gte", "value": 400},
+			map[string]any{"name": "depth", "operator": "lt", "value": 3},
+		}},
+		map[string]any{"filters": []any{map[string]any{"name": "title", "operator": "isnull"}}},
+	}}
+	clause, args, err := buildFilterConfigClausePostgres(cfg, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "(response_code >= $2 AND depth < $3) OR (title IS NULL)"; clause != want {
 		t.Fatalf("expected clause %q got %q", want, clause)
 	}
-	if !reflect.DeepEqual(args, []any{float64(400)}) {
+	if !reflect.DeepEqual(args, []any{400, 3}) {
 		t.Fatalf("unexpected args: %#v", args)
 	}
+
+	if clause, _, err := buildFilterConfigClausePostgres(map[string]any{}, 1); err != nil || clause != "" {
+		t.Fatalf("expected empty clause, got %q %v", clause, err)
+	}
+	bad := map[string]any{"filter_groups": []any{
+		map[string]any{"filters": []any{map[string]any{"name": "title; drop", "operator": "eq", "value": 1}}},
+	}}
+	if _, _, err := buildFilterConfigClausePostgres(bad, 1); err == nil {
+		t.Fatalf("expected invalid column error")
+	}
+	if _, _, err := buildFilterConfigClausePostgres(map[string]any{"filter_groups": "x"}, 1); err == nil {
+		t.Fatalf("expected filter_groups type error")
+	}
 }
 
-func TestRenumberPostgresPlaceholders(t *testing.T) {
-	got := renumberPostgresPlaceholders("a = $1 AND b = $2", 5)
-	if want := "a = $5 AND b = $6"; got != want {
-		t.Fatalf("expected %q got %q", want, got)
+func TestCatalogFilterConfigsBuild(t *testing.T) {
+	seen := map[string]bool{}
+	for _, c := range catalog.Checks() {
+		if seen[c.Key] {
+			t.Fatalf("duplicate catalogue key %s", c.Key)
+		}
+		seen[c.Key] = true
+		if c.Version < 1 || c.Version > catalog.Version {
+			t.Fatalf("%s: version %d outside 1..%d", c.Key, c.Version, catalog.Version)
+		}
+
+		// Round-trip through JSON as the configs are stored as jsonb.
+		raw, _ := json.Marshal(c.FilterConfig)
+		var cfg map[string]any
+		_ = json.Unmarshal(raw, &cfg)
+		clause, _, err := buildFilterConfigClausePostgres(cfg, 1)
+		if err != nil || clause == "" {
+			t.Fatalf("%s: filter_config does not build: %q %v", c.Key, clause, err)
+		}
+	}
+	for _, c := range analysis.HreflangChecks {
+		if !seen[c.Key] {
+			t.Fatalf("hreflang check %s missing from the catalogue", c.Key)
+		}
 	}
 }
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/scoring"
+)
+
+// statsCountColumns selects the page counts of repository.StatsCounts.
+const statsCountColumns = `COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE (og_title = '' OR og_title IS NULL OR og_description = '' OR og_description IS NULL)) AS warning,
+		COUNT(*) FILTER (WHERE (response_code >= 400 AND response_code <= 599)) AS error,
+		COUNT(*) FILTER (WHERE (response_code >= 200 AND response_code <= 299 AND redirect_code IS NULL)) AS ok,
+		COUNT(*) FILTER (WHERE (redirect_code IN ('301','302','307','308'))) AS redirection,
+		COUNT(*) FILTER (WHERE (depth = 1)) AS level1,
+		COUNT(*) FILTER (WHERE (depth = 2)) AS level2,
+		COUNT(*) FILTER (WHERE (depth = 3)) AS level3,
+		COUNT(*) FILTER (WHERE (depth = 4)) AS level4,
+		COUNT(*) FILTER (WHERE (response_code >= 200 AND response_code < 300)) AS success_pages,
+		COUNT(*) FILTER (WHERE (response_code >= 300 AND response_code < 400)) AS redirect_pages,
+		COUNT(*) FILTER (WHERE (response_code >= 400 AND response_code < 500)) AS client_error_pages,
+		COUNT(*) FILTER (WHERE (response_code >= 500)) AS server_error_pages`
+
+// indexabilityColumns selects the indexable and non-indexable page counts.
+const indexabilityColumns = `COUNT(*) FILTER (WHERE indexability = 'indexable') AS indexable,
+		COUNT(*) FILTER (WHERE indexability = 'non_indexable') AS non_indexable`
+
+// statsGroupExprPostgres returns the SQL expression keying a page by group,
+// with its args numbered from $start. Pages without a key fall into "".
+func statsGroupExprPostgres(g repository.StatsGroupBy, start int) (string, []any, error) {
+	switch g.Field {
+	case repository.StatsGroupDirectory:
+		return fmt.Sprintf(`COALESCE(substring(url from '^[^:]+://[^/?#]+(/(?:[^/?#]+/){0,%d})'), '/')`, g.Segments), nil, nil
+	case repository.StatsGroupSubdomain:
+		return `COALESCE(lower(substring(url from '^[^:]+://([^/?#:]+)')), '')`, nil, nil
+	case repository.StatsGroupDepth:
+		return `COALESCE(depth::text, '')`, nil, nil
+	case repository.StatsGroupContentType:
+		return `lower(trim(split_part(COALESCE(content_type, ''), ';', 1)))`, nil, nil
+	case repository.StatsGroupStatusClass:
+		return `CASE WHEN response_code >= 100 THEN (response_code / 100)::text || 'xx' ELSE '' END`, nil, nil
+	case repository.StatsGroupRegex:
+		return fmt.Sprintf(`COALESCE(substring(url from $%d), '')`, start), []any{g.Pattern}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported group_by: %s", g.Field)
+	}
+}
+
+// fetchGroups returns the metrics of the largest groups of the filtered
+// pages, at most repository.MaxStatsGroups: the page counts, the security
+// and performance summaries, and the problematic count and site health from
+// the session's audit checks. Indexability reasons, structured data, the
+// depth histogram and the certificate check are only reported for the
+// whole selection. Like the sections of the whole selection, a group metric
+// the database cannot compute is left out of every group.
+func (r *StatsRepo) fetchGroups(ctx context.Context, baseWhere string, baseArgs []any, g repository.StatsGroupBy, sessionID int64) ([]map[string]any, error) {
+	expr, exprArgs, err := statsGroupExprPostgres(g, len(baseArgs)+1)
+	if err != nil {
+		return nil, err
+	}
+	args := append(append([]any{}, baseArgs...), exprArgs...)
+	args = append(args, repository.MaxStatsGroups)
+	q := fmt.Sprintf(`SELECT %s AS group_key, %s FROM pages WHERE %s
+		GROUP BY group_key ORDER BY total DESC, group_key ASC LIMIT $%d`, expr, statsCountColumns, baseWhere, len(args))
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []map[string]any{}
+	byKey := map[string]map[string]any{}
+	for rows.Next() {
+		var key string
+		var counts repository.StatsCounts
+		if err := rows.Scan(append([]any{&key}, counts.Dest()...)...); err != nil {
+			return nil, err
+		}
+		group := counts.Map()
+		group["key"] = key
+		groups = append(groups, group)
+		byKey[key] = group
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_ = r.fillGroupIndexability(ctx, baseWhere, baseArgs, g, byKey)
+	_ = r.fillGroupSecurity(ctx, baseWhere, baseArgs, g, byKey)
+	_ = r.fillGroupPerformance(ctx, baseWhere, baseArgs, g, byKey)
+	_ = r.fillGroupCheckResults(ctx, baseWhere, baseArgs, g, sessionID, byKey)
+	return groups, nil
+}
+
+// fillGroupIndexability sets the indexable and non-indexable page counts of
+// every group.
+func (r *StatsRepo) fillGroupIndexability(ctx context.Context, baseWhere string, baseArgs []any, g repository.StatsGroupBy, byKey map[string]map[string]any) error {
+	expr, exprArgs, err := statsGroupExprPostgres(g, len(baseArgs)+1)
+	if err != nil {
+		return err
+	}
+	q := fmt.Sprintf(`SELECT %s AS group_key, %s FROM pages WHERE %s GROUP BY group_key`, expr, indexabilityColumns, baseWhere)
+	rows, err := r.db.QueryContext(ctx, q, append(append([]any{}, baseArgs...), exprArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	indexability := map[string]map[string]any{}
+	for rows.Next() {
+		var key string
+		var indexable, nonIndexable int
+		if err := rows.Scan(&key, &indexable, &nonIndexable); err != nil {
+			return err
+		}
+		indexability[key] = map[string]any{"indexable": indexable, "non_indexable": nonIndexable}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for key, group := range byKey {
+		group["indexability"] = indexability[key]
+	}
+	return nil
+}
+
+// fillGroupSecurity sets the security summary of every group: the pages with
+// issues or mixed content and the pages per security issue.
+func (r *StatsRepo) fillGroupSecurity(ctx context.Context, baseWhere string, baseArgs []any, g repository.StatsGroupBy, byKey map[string]map[string]any) error {
+	expr, exprArgs, err := statsGroupExprPostgres(g, len(baseArgs)+1)
+	if err != nil {
+		return err
+	}
+	args := append(append([]any{}, baseArgs...), exprArgs...)
+	q := fmt.Sprintf(`SELECT %s AS group_key,
+		COUNT(*) FILTER (WHERE security_issues <> '') AS with_issues,
+		COUNT(*) FILTER (WHERE mixed_content_count > 0) AS mixed_content
+		FROM pages WHERE %s GROUP BY group_key`, expr, baseWhere)
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	security := map[string]map[string]any{}
+	for rows.Next() {
+		var key string
+		var withIssues, mixedContent int
+		if err := rows.Scan(&key, &withIssues, &mixedContent); err != nil {
+			return err
+		}
+		security[key] = map[string]any{"pages_with_issues": withIssues, "mixed_content_pages": mixedContent, "issues": map[string]int{}}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	q = fmt.Sprintf(`SELECT %s AS group_key, issue, COUNT(*) FROM pages,
+		unnest(string_to_array(NULLIF(security_issues, ''), ',')) AS issue
+		WHERE %s GROUP BY group_key, issue`, expr, baseWhere)
+	issueRows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer issueRows.Close()
+	for issueRows.Next() {
+		var key, issue string
+		var count int
+		if err := issueRows.Scan(&key, &issue, &count); err != nil {
+			return err
+		}
+		if s, ok := security[key]; ok {
+			s["issues"].(map[string]int)[issue] = count
+		}
+	}
+	if err := issueRows.Err(); err != nil {
+		return err
+	}
+	for key, group := range byKey {
+		group["security"] = security[key]
+	}
+	return nil
+}
+
+// fillGroupPerformance sets the performance summary of every group, empty
+// for groups without recorded timings.
+func (r *StatsRepo) fillGroupPerformance(ctx context.Context, baseWhere string, baseArgs []any, g repository.StatsGroupBy, byKey map[string]map[string]any) error {
+	expr, exprArgs, err := statsGroupExprPostgres(g, len(baseArgs)+2)
+	if err != nil {
+		return err
+	}
+	q := fmt.Sprintf(`SELECT %s AS group_key, %s FROM pages WHERE %s AND %s GROUP BY group_key`,
+		expr, performanceColumns(len(baseArgs)+1), baseWhere, timedPages)
+	args := append(append([]any{}, baseArgs...), repository.SlowPageThresholdMs)
+	rows, err := r.db.QueryContext(ctx, q, append(args, exprArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	performance := map[string]performanceRow{}
+	for rows.Next() {
+		var key string
+		var p performanceRow
+		if err := rows.Scan(append([]any{&key}, p.dest()...)...); err != nil {
+			return err
+		}
+		performance[key] = p
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for key, group := range byKey {
+		group["performance"] = performance[key].summary()
+	}
+	return nil
+}
+
+// fillGroupCheckResults sets the problematic count and site health of every
+// group from the session's audit checks, evaluated in a single scan.
+func (r *StatsRepo) fillGroupCheckResults(ctx context.Context, baseWhere string, baseArgs []any, g repository.StatsGroupBy, sessionID int64, byKey map[string]map[string]any) error {
+	results, selects, selectArgs, err := r.checkCountSelects(ctx, sessionID, len(baseArgs)+1)
+	if err != nil {
+		return err
+	}
+	args := append(append([]any{}, baseArgs...), selectArgs...)
+	expr, exprArgs, err := statsGroupExprPostgres(g, len(args)+1)
+	if err != nil {
+		return err
+	}
+	q := fmt.Sprintf("SELECT %s AS group_key, %s FROM pages WHERE %s GROUP BY group_key", expr, strings.Join(selects, ", "), baseWhere)
+	rows, err := r.db.QueryContext(ctx, q, append(args, exprArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		counts := make([]int, len(selects))
+		dest := []any{&key}
+		for i := range counts {
+			dest = append(dest, &counts[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		group, ok := byKey[key]
+		if !ok {
+			continue
+		}
+		groupResults := append([]scoring.CheckResult{}, results...)
+		for i := range groupResults {
+			groupResults[i].MatchedPages = counts[i]
+		}
+		health, formula := scoring.SiteHealth(group["total"].(int), groupResults)
+		group["problematic"] = counts[len(counts)-1]
+		group["site_health"] = health
+		group["site_health_formula"] = formula
+	}
+	return rows.Err()
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+const auditCheckTemplateColumns = `id, name, category, COALESCE(severity, ''), COALESCE(weight, 0), filter_config, version, created_at, updated_at`
+
+type AuditCheckTemplateRepo struct {
+	db *sql.DB
+}
+
+func NewAuditCheckTemplateRepo(db *sql.DB) *AuditCheckTemplateRepo {
+	return &AuditCheckTemplateRepo{db: db}
+}
+
+func (r *AuditCheckTemplateRepo) Create(ctx context.Context, t *models.AuditCheckTemplate) error {
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	q := `INSERT INTO audit_check_templates (name, category, severity, weight, filter_config, version, created_at, updated_at)
+          VALUES ($1,$2,$3,$4,$5,$6,NOW(),NOW()) RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, q, t.Name, t.Category, t.Severity, t.Weight, filterJSON, t.Version).
+		Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *AuditCheckTemplateRepo) Update(ctx context.Context, t *models.AuditCheckTemplate) error {
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	q := `UPDATE audit_check_templates SET name=$2, category=$3, severity=$4, weight=$5, filter_config=$6, version=$7, updated_at=NOW()
+          WHERE id=$1 RETURNING updated_at`
+	err = r.db.QueryRowContext(ctx, q, t.ID, t.Name, t.Category, t.Severity, t.Weight, filterJSON, t.Version).Scan(&t.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrTemplateNotFound
+	}
+	return err
+}
+
+func (r *AuditCheckTemplateRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM audit_check_templates WHERE id=$1`, id)
+	return err
+}
+
+func (r *AuditCheckTemplateRepo) Get(ctx context.Context, id int64) (*models.AuditCheckTemplate, error) {
+	t, err := scanAuditCheckTemplate(r.db.QueryRowContext(ctx, `SELECT `+auditCheckTemplateColumns+` FROM audit_check_templates WHERE id=$1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrTemplateNotFound
+	}
+	return t, err
+}
+
+func (r *AuditCheckTemplateRepo) List(ctx context.Context) ([]models.AuditCheckTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+auditCheckTemplateColumns+` FROM audit_check_templates ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.AuditCheckTemplate{}
+	for rows.Next() {
+		t, err := scanAuditCheckTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+func scanAuditCheckTemplate(row rowScanner) (*models.AuditCheckTemplate, error) {
+	var t models.AuditCheckTemplate
+	var filterJSON []byte
+	if err := row.Scan(&t.ID, &t.Name, &t.Category, &t.Severity, &t.Weight, &filterJSON, &t.Version, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if len(filterJSON) > 0 {
+		if err := json.Unmarshal(filterJSON, &t.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	return &t, nil
+}
+
+const viewTemplateColumns = `id, name, filter_config, version, created_at, updated_at`
+
+type ViewTemplateRepo struct {
+	db *sql.DB
+}
+
+func NewViewTemplateRepo(db *sql.DB) *ViewTemplateRepo {
+	return &ViewTemplateRepo{db: db}
+}
+
+func (r *ViewTemplateRepo) Create(ctx context.Context, t *models.ViewTemplate) error {
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	q := `INSERT INTO view_templates (name, filter_config, version, created_at, updated_at)
+          VALUES ($1,$2,$3,NOW(),NOW()) RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, q, t.Name, filterJSON, t.Version).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *ViewTemplateRepo) Update(ctx context.Context, t *models.ViewTemplate) error {
+	filterJSON, err := json.Marshal(t.FilterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	q := `UPDATE view_templates SET name=$2, filter_config=$3, version=$4, updated_at=NOW() WHERE id=$1 RETURNING updated_at`
+	err = r.db.QueryRowContext(ctx, q, t.ID, t.Name, filterJSON, t.Version).Scan(&t.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrTemplateNotFound
+	}
+	return err
+}
+
+func (r *ViewTemplateRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM view_templates WHERE id=$1`, id)
+	return err
+}
+
+func (r *ViewTemplateRepo) Get(ctx context.Context, id int64) (*models.ViewTemplate, error) {
+	t, err := scanViewTemplate(r.db.QueryRowContext(ctx, `SELECT `+viewTemplateColumns+` FROM view_templates WHERE id=$1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrTemplateNotFound
+	}
+	return t, err
+}
+
+func (r *ViewTemplateRepo) List(ctx context.Context) ([]models.ViewTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+viewTemplateColumns+` FROM view_templates ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.ViewTemplate{}
+	for rows.Next() {
+		t, err := scanViewTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+func scanViewTemplate(row rowScanner) (*models.ViewTemplate, error) {
+	var t models.ViewTemplate
+	var filterJSON []byte
+	if err := row.Scan(&t.ID, &t.Name, &filterJSON, &t.Version, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if len(filterJSON) > 0 {
+		if err := json.Unmarshal(filterJSON, &t.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	return &t, nil
+}
@@ -0,0 +1,225 @@
+-- Tables the conformance suites expect, as the repositories in this package
+-- read and write them. Every statement is idempotent so the suites can apply
+-- the file to a database that already holds the schema.
+
+CREATE TABLE IF NOT EXISTS crawling_sessions (
+    id                    BIGSERIAL PRIMARY KEY,
+    search_keyword_url_id BIGINT      NOT NULL,
+    url                   TEXT        NOT NULL DEFAULT '',
+    status                TEXT        NOT NULL,
+    queue                 INTEGER     NOT NULL DEFAULT 0,
+    version               INTEGER     NOT NULL DEFAULT 0,
+    options               JSONB,
+    started_at            TIMESTAMPTZ,
+    ended_at              TIMESTAMPTZ,
+    end_reason            TEXT        NOT NULL DEFAULT '',
+    error                 TEXT        NOT NULL DEFAULT '',
+    ips                   TEXT[],
+    dns_servers           TEXT[],
+    aliases               TEXT[],
+    location              TEXT        NOT NULL DEFAULT '',
+    sitemap               BOOLEAN     NOT NULL DEFAULT FALSE,
+    robots                BOOLEAN     NOT NULL DEFAULT FALSE,
+    ssl_valid             BOOLEAN     NOT NULL DEFAULT FALSE,
+    ssl_valid_until       TIMESTAMPTZ,
+    pages_count           INTEGER     NOT NULL DEFAULT 0,
+    internal_urls_count   INTEGER     NOT NULL DEFAULT 0,
+    ignored_urls_count    INTEGER     NOT NULL DEFAULT 0,
+    external_urls_count   INTEGER     NOT NULL DEFAULT 0,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS crawling_sessions_sku_idx ON crawling_sessions (search_keyword_url_id, status);
+
+-- redirect_code is NULL for pages that were not redirected.
+CREATE TABLE IF NOT EXISTS pages (
+    id                     BIGSERIAL PRIMARY KEY,
+    crawling_session_id    BIGINT      NOT NULL,
+    url                    TEXT        NOT NULL,
+    response_code          INTEGER     NOT NULL DEFAULT 0,
+    redirect_code          TEXT,
+    redirect_chain_length  INTEGER     NOT NULL DEFAULT 0,
+    depth                  INTEGER,
+    indexability           TEXT        NOT NULL DEFAULT '',
+    indexability_reason    TEXT        NOT NULL DEFAULT '',
+    title                  TEXT        NOT NULL DEFAULT '',
+    meta_description       TEXT        NOT NULL DEFAULT '',
+    h1                     TEXT        NOT NULL DEFAULT '',
+    canonical              TEXT        NOT NULL DEFAULT '',
+    og_title               TEXT        NOT NULL DEFAULT '',
+    og_description         TEXT        NOT NULL DEFAULT '',
+    content_type           TEXT        NOT NULL DEFAULT '',
+    word_count             INTEGER     NOT NULL DEFAULT 0,
+    content_hash           TEXT        NOT NULL DEFAULT '',
+    simhash                BIGINT      NOT NULL DEFAULT 0,
+    hreflang_issues        TEXT        NOT NULL DEFAULT '',
+    structured_data_types  TEXT        NOT NULL DEFAULT '',
+    structured_data_errors INTEGER     NOT NULL DEFAULT 0,
+    security_issues        TEXT        NOT NULL DEFAULT '',
+    mixed_content_count    INTEGER     NOT NULL DEFAULT 0,
+    response_time_ms       BIGINT,
+    dns_ms                 BIGINT,
+    connect_ms             BIGINT,
+    tls_ms                 BIGINT,
+    ttfb_ms                BIGINT,
+    transfer_size          BIGINT,
+    uncompressed_size      BIGINT,
+    content_encoding       TEXT,
+    resource_count         INTEGER,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS pages_session_idx ON pages (crawling_session_id, id);
+
+CREATE TABLE IF NOT EXISTS page_links (
+    source_page_id BIGINT NOT NULL,
+    target_page_id BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS page_links_source_idx ON page_links (source_page_id);
+CREATE INDEX IF NOT EXISTS page_links_target_idx ON page_links (target_page_id);
+
+CREATE TABLE IF NOT EXISTS page_images (
+    id      BIGSERIAL PRIMARY KEY,
+    page_id BIGINT NOT NULL,
+    url     TEXT   NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS page_images_page_idx ON page_images (page_id);
+
+CREATE TABLE IF NOT EXISTS page_hreflangs (
+    page_id  BIGINT NOT NULL,
+    hreflang TEXT   NOT NULL,
+    href     TEXT   NOT NULL,
+    source   TEXT   NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS page_hreflangs_page_idx ON page_hreflangs (page_id);
+
+CREATE TABLE IF NOT EXISTS page_structured_data (
+    page_id            BIGINT NOT NULL,
+    format             TEXT   NOT NULL,
+    type               TEXT   NOT NULL DEFAULT '',
+    missing_properties TEXT   NOT NULL DEFAULT '',
+    error              TEXT   NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS page_structured_data_page_idx ON page_structured_data (page_id);
+
+CREATE TABLE IF NOT EXISTS page_mixed_content (
+    page_id BIGINT NOT NULL,
+    url     TEXT   NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS page_mixed_content_page_idx ON page_mixed_content (page_id);
+
+-- builtin_key is NULL for checks that were not installed from the catalogue.
+CREATE TABLE IF NOT EXISTS audit_checks (
+    id                    BIGSERIAL PRIMARY KEY,
+    search_keyword_url_id BIGINT           NOT NULL,
+    name                  TEXT             NOT NULL,
+    category              TEXT             NOT NULL,
+    severity              TEXT,
+    weight                DOUBLE PRECISION,
+    filter_config         JSONB,
+    builtin_key           TEXT,
+    builtin_version       INTEGER,
+    customized            BOOLEAN,
+    template_id           BIGINT,
+    template_version      INTEGER,
+    created_at            TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_checks_sku_idx ON audit_checks (search_keyword_url_id);
+
+CREATE TABLE IF NOT EXISTS audit_results (
+    crawling_session_id BIGINT      NOT NULL,
+    audit_check_id      BIGINT      NOT NULL,
+    name                TEXT        NOT NULL,
+    category            TEXT        NOT NULL,
+    severity            TEXT,
+    filter_config       JSONB,
+    matched_pages       INTEGER     NOT NULL DEFAULT 0,
+    sample_page_ids     JSONB,
+    error               TEXT,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (crawling_session_id, audit_check_id)
+);
+
+CREATE TABLE IF NOT EXISTS audit_check_templates (
+    id            BIGSERIAL PRIMARY KEY,
+    name          TEXT             NOT NULL,
+    category      TEXT             NOT NULL,
+    severity      TEXT,
+    weight        DOUBLE PRECISION,
+    filter_config JSONB,
+    version       INTEGER          NOT NULL DEFAULT 1,
+    created_at    TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS views (
+    id                    BIGSERIAL PRIMARY KEY,
+    search_keyword_url_id BIGINT      NOT NULL,
+    name                  TEXT        NOT NULL,
+    filter_config         JSONB,
+    columns               JSONB,
+    sort_field            TEXT,
+    sort_direction        TEXT,
+    page_size             INTEGER,
+    compare_with_previous BOOLEAN,
+    template_id           BIGINT,
+    template_version      INTEGER,
+    customized            BOOLEAN,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS views_sku_idx ON views (search_keyword_url_id);
+
+CREATE TABLE IF NOT EXISTS view_templates (
+    id            BIGSERIAL PRIMARY KEY,
+    name          TEXT        NOT NULL,
+    filter_config JSONB,
+    version       INTEGER     NOT NULL DEFAULT 1,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- metric is NULL for rules on an audit check, webhook_url for rules that
+-- only send email.
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id                    BIGSERIAL PRIMARY KEY,
+    search_keyword_url_id BIGINT           NOT NULL,
+    name                  TEXT             NOT NULL,
+    audit_check_id        BIGINT,
+    metric                TEXT,
+    mode                  TEXT             NOT NULL,
+    operator              TEXT             NOT NULL,
+    threshold             DOUBLE PRECISION NOT NULL,
+    webhook_url           TEXT,
+    emails                JSONB,
+    enabled               BOOLEAN          NOT NULL DEFAULT TRUE,
+    created_at            TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS alert_rules_sku_idx ON alert_rules (search_keyword_url_id);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id                    BIGSERIAL PRIMARY KEY,
+    rule_id               BIGINT           NOT NULL,
+    search_keyword_url_id BIGINT           NOT NULL,
+    crawling_session_id   BIGINT           NOT NULL,
+    rule_name             TEXT             NOT NULL,
+    value                 DOUBLE PRECISION NOT NULL,
+    previous_value        DOUBLE PRECISION,
+    threshold             DOUBLE PRECISION NOT NULL,
+    message               TEXT             NOT NULL,
+    deliveries            JSONB,
+    created_at            TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS alerts_sku_idx ON alerts (search_keyword_url_id, created_at);
@@ -3,10 +3,18 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 
+	"sitecrawler/newgo/internal/repository"
 	"sitecrawler/newgo/models"
 )
 
+const viewColumns = `id, search_keyword_url_id, name, filter_config, columns, COALESCE(sort_field, ''), COALESCE(sort_direction, ''),
+	COALESCE(page_size, 0), COALESCE(compare_with_previous, false), template_id, COALESCE(template_version, 0),
+	COALESCE(customized, false), created_at, updated_at`
+
 type ViewRepo struct {
 	db *sql.DB
 }
@@ -16,14 +24,26 @@ func NewViewRepo(db *sql.DB) *ViewRepo {
 }
 
 func (r *ViewRepo) Create(ctx context.Context, v *models.View) error {
-	q := `INSERT INTO views (search_keyword_url_id, name, filter_config, created_at, updated_at)
-          VALUES ($1,$2,$3,NOW(),NOW()) RETURNING id, created_at, updated_at`
-	return r.db.QueryRowContext(ctx, q, v.SearchKeywordURLID, v.Name, v.FilterConfig).Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt)
+	filterJSON, columnsJSON, err := marshalView(v)
+	if err != nil {
+		return err
+	}
+	q := `INSERT INTO views (search_keyword_url_id, name, filter_config, columns, sort_field, sort_direction, page_size, compare_with_previous,
+          template_id, template_version, customized, created_at, updated_at)
+          VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NOW(),NOW()) RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, q, v.SearchKeywordURLID, v.Name, filterJSON, columnsJSON, v.SortField, v.SortDirection, v.PageSize,
+		v.CompareWithPrevious, v.TemplateID, v.TemplateVersion, v.Customized).Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt)
 }
 
 func (r *ViewRepo) Update(ctx context.Context, v *models.View) error {
-	q := `UPDATE views SET name=$2, filter_config=$3, updated_at=NOW() WHERE id=$1`
-	_, err := r.db.ExecContext(ctx, q, v.ID, v.Name, v.FilterConfig)
+	filterJSON, columnsJSON, err := marshalView(v)
+	if err != nil {
+		return err
+	}
+	q := `UPDATE views SET name=$2, filter_config=$3, columns=$4, sort_field=$5, sort_direction=$6, page_size=$7, compare_with_previous=$8,
+          template_id=$9, template_version=$10, customized=$11, updated_at=NOW() WHERE id=$1`
+	_, err = r.db.ExecContext(ctx, q, v.ID, v.Name, filterJSON, columnsJSON, v.SortField, v.SortDirection, v.PageSize, v.CompareWithPrevious,
+		v.TemplateID, v.TemplateVersion, v.Customized)
 	return err
 }
 
@@ -33,27 +53,72 @@ func (r *ViewRepo) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *ViewRepo) Get(ctx context.Context, id int64) (*models.View, error) {
-	var v models.View
-	q := `SELECT id, search_keyword_url_id, name, filter_config, created_at, updated_at FROM views WHERE id=$1`
-	if err := r.db.QueryRowContext(ctx, q, id).Scan(&v.ID, &v.SearchKeywordURLID, &v.Name, &v.FilterConfig, &v.CreatedAt, &v.UpdatedAt); err != nil {
-		return nil, err
+	v, err := scanView(r.db.QueryRowContext(ctx, `SELECT `+viewColumns+` FROM views WHERE id=$1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrViewNotFound
 	}
-	return &v, nil
+	return v, err
 }
 
 func (r *ViewRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.View, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, search_keyword_url_id, name, filter_config, created_at, updated_at FROM views WHERE search_keyword_url_id=$1 ORDER BY created_at ASC`, skuID)
+	return r.list(ctx, `SELECT `+viewColumns+` FROM views WHERE search_keyword_url_id=$1 ORDER BY id ASC`, skuID)
+}
+
+func (r *ViewRepo) ListByTemplate(ctx context.Context, templateID int64) ([]models.View, error) {
+	return r.list(ctx, `SELECT `+viewColumns+` FROM views WHERE template_id=$1 ORDER BY id ASC`, templateID)
+}
+
+func (r *ViewRepo) list(ctx context.Context, q string, args ...any) ([]models.View, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []models.View
 	for rows.Next() {
-		var v models.View
-		if err := rows.Scan(&v.ID, &v.SearchKeywordURLID, &v.Name, &v.FilterConfig, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		v, err := scanView(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, v)
+		out = append(out, *v)
 	}
 	return out, rows.Err()
 }
+
+// marshalView encodes the JSONB columns of a view.
+func marshalView(v *models.View) ([]byte, []byte, error) {
+	filterJSON, err := json.Marshal(v.FilterConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal filter config: %w", err)
+	}
+	columnsJSON, err := json.Marshal(v.Columns)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal columns: %w", err)
+	}
+	return filterJSON, columnsJSON, nil
+}
+
+// scanView reads a row selected with viewColumns.
+func scanView(row rowScanner) (*models.View, error) {
+	var v models.View
+	var filterJSON, columnsJSON []byte
+	var templateID sql.NullInt64
+	if err := row.Scan(&v.ID, &v.SearchKeywordURLID, &v.Name, &filterJSON, &columnsJSON, &v.SortField, &v.SortDirection,
+		&v.PageSize, &v.CompareWithPrevious, &templateID, &v.TemplateVersion, &v.Customized, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if templateID.Valid {
+		v.TemplateID = &templateID.Int64
+	}
+	if len(filterJSON) > 0 {
+		if err := json.Unmarshal(filterJSON, &v.FilterConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter config: %w", err)
+		}
+	}
+	if len(columnsJSON) > 0 {
+		if err := json.Unmarshal(columnsJSON, &v.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal columns: %w", err)
+		}
+	}
+	return &v, nil
+}
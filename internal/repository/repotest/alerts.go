@@ -0,0 +1,171 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// RunAlertRuleConformance checks the AlertRuleRepository contract. The
+// rules it creates are deleted when the test ends.
+func RunAlertRuleConformance(t *testing.T, repo repository.AlertRuleRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, rule models.AlertRule) models.AlertRule {
+		t.Helper()
+		if err := repo.Create(ctx, &rule); err != nil {
+			t.Fatalf("create alert rule: %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(ctx, rule.ID) })
+		return rule
+	}
+	rule := func(sku int64, name string) models.AlertRule {
+		return models.AlertRule{SearchKeywordURLID: sku, Name: name, Metric: "site_health", Mode: "absolute", Operator: "lt",
+			Threshold: 80, WebhookURL: "https://hooks.example.com/alerts", Emails: []string{"ops@example.com"}, Enabled: true}
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		r := create(t, rule(UniqueSKU(), "health"))
+		if r.ID <= 0 {
+			t.Fatalf("expected a positive id got %d", r.ID)
+		}
+		got, err := repo.Get(ctx, r.ID)
+		if err != nil {
+			t.Fatalf("get alert rule: %v", err)
+		}
+		if got.Name != "health" || got.Metric != "site_health" || got.Mode != "absolute" || got.Operator != "lt" ||
+			got.Threshold != 80 || got.WebhookURL != r.WebhookURL || !got.Enabled {
+			t.Errorf("unexpected alert rule %+v", got)
+		}
+		if !reflect.DeepEqual(got.Emails, []string{"ops@example.com"}) {
+			t.Errorf("unexpected emails %v", got.Emails)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := repo.Get(ctx, missingID)
+		if !errors.Is(err, repository.ErrAlertRuleNotFound) {
+			t.Fatalf("expected ErrAlertRuleNotFound got %v", err)
+		}
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		r := create(t, rule(UniqueSKU(), "before"))
+		r.Name, r.Threshold, r.Enabled = "after", 50, false
+		if err := repo.Update(ctx, &r); err != nil {
+			t.Fatalf("update alert rule: %v", err)
+		}
+		got, err := repo.Get(ctx, r.ID)
+		if err != nil {
+			t.Fatalf("get alert rule: %v", err)
+		}
+		if got.Name != "after" || got.Threshold != 50 || got.Enabled {
+			t.Errorf("update not persisted %+v", got)
+		}
+
+		if err := repo.Delete(ctx, r.ID); err != nil {
+			t.Fatalf("delete alert rule: %v", err)
+		}
+		if _, err := repo.Get(ctx, r.ID); !errors.Is(err, repository.ErrAlertRuleNotFound) {
+			t.Fatalf("expected ErrAlertRuleNotFound after delete got %v", err)
+		}
+	})
+
+	t.Run("list by sku", func(t *testing.T) {
+		sku := UniqueSKU()
+		a, b := create(t, rule(sku, "a")), create(t, rule(sku, "b"))
+		create(t, rule(UniqueSKU(), "other"))
+
+		rules, err := repo.ListBySKU(ctx, sku)
+		if err != nil {
+			t.Fatalf("list by sku: %v", err)
+		}
+		ids := make([]int64, len(rules))
+		for i, r := range rules {
+			ids[i] = r.ID
+		}
+		if !equalIDs(ids, []int64{a.ID, b.ID}) {
+			t.Errorf("expected rules %v got %v", []int64{a.ID, b.ID}, ids)
+		}
+	})
+}
+
+// RunAlertConformance checks the AlertRepository contract. Alerts cannot be
+// deleted, so the ones it creates are left under a SKU of their own.
+func RunAlertConformance(t *testing.T, repo repository.AlertRepository) {
+	ctx := context.Background()
+	sku := UniqueSKU()
+
+	var created []models.Alert
+	for i, a := range []models.Alert{
+		{RuleID: 1, CrawlingSessionID: 10, RuleName: "health", Value: 70, Threshold: 80, Message: "first"},
+		{RuleID: 2, CrawlingSessionID: 10, RuleName: "errors", Value: 5, Threshold: 1, Message: "second"},
+		{RuleID: 1, CrawlingSessionID: 11, RuleName: "health", Value: 60, Threshold: 80, Message: "third"},
+	} {
+		a.SearchKeywordURLID = sku
+		if i == 2 {
+			previous := 70.0
+			a.PreviousValue = &previous
+		}
+		if err := repo.Create(ctx, &a); err != nil {
+			t.Fatalf("create alert: %v", err)
+		}
+		if a.ID <= 0 || a.CreatedAt.IsZero() {
+			t.Fatalf("expected id and created_at to be set got %+v", a)
+		}
+		created = append(created, a)
+	}
+
+	list := func(t *testing.T, params repository.AlertListParams) []int64 {
+		t.Helper()
+		params.SearchKeywordURLID = sku
+		alerts, err := repo.List(ctx, params)
+		if err != nil {
+			t.Fatalf("list alerts: %v", err)
+		}
+		ids := make([]int64, len(alerts))
+		for i, a := range alerts {
+			ids[i] = a.ID
+		}
+		return ids
+	}
+
+	t.Run("newest first", func(t *testing.T) {
+		want := []int64{created[2].ID, created[1].ID, created[0].ID}
+		if got := list(t, repository.AlertListParams{}); !equalIDs(got, want) {
+			t.Errorf("expected alerts %v got %v", want, got)
+		}
+	})
+
+	t.Run("filters and limit", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			params repository.AlertListParams
+			want   []int64
+		}{
+			{"rule", repository.AlertListParams{RuleID: 1}, []int64{created[2].ID, created[0].ID}},
+			{"session", repository.AlertListParams{CrawlingSessionID: 10}, []int64{created[1].ID, created[0].ID}},
+			{"limit", repository.AlertListParams{Limit: 1}, []int64{created[2].ID}},
+			{"unknown rule", repository.AlertListParams{RuleID: missingID}, nil},
+		}
+		for _, tc := range cases {
+			if got := list(t, tc.params); !equalIDs(got, tc.want) {
+				t.Errorf("%s: expected alerts %v got %v", tc.name, tc.want, got)
+			}
+		}
+	})
+
+	t.Run("previous value", func(t *testing.T) {
+		alerts, err := repo.List(ctx, repository.AlertListParams{SearchKeywordURLID: sku, Limit: 1})
+		if err != nil {
+			t.Fatalf("list alerts: %v", err)
+		}
+		if len(alerts) != 1 || alerts[0].PreviousValue == nil || *alerts[0].PreviousValue != 70 {
+			t.Errorf("expected the previous value to round-trip got %+v", alerts)
+		}
+	})
+}
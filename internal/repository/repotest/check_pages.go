@@ -0,0 +1,130 @@
+package repotest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/scoring"
+	"sitecrawler/newgo/models"
+)
+
+// CheckPagesHarness connects the checks with pages suite to a backend. Seed
+// works as StatsHarness.Seed does.
+type CheckPagesHarness struct {
+	Repo repository.CrawlingSessionCheckRepository
+	Seed func(t *testing.T, f StatsFixture) []int64
+}
+
+// checkPagesFixture is a SKU with a 4xx check and two sessions. Pages pair
+// up by normalized URL, so the spellings differ between the sessions.
+func checkPagesFixture() StatsFixture {
+	page := func(url string, code int) StatsPage {
+		return StatsPage{URL: url, ResponseCode: code, Indexability: models.IndexabilityIndexable}
+	}
+	return StatsFixture{
+		SKU: UniqueSKU(),
+		Checks: []models.AuditCheck{
+			{Name: "4xx pages", Category: scoring.CategoryProblematic, FilterConfig: map[string]any{"filter_groups": []any{
+				map[string]any{"filters": []any{
+					map[string]any{"name": "response_code", "operator": "gte", "value": 400},
+					map[string]any{"name": "response_code", "operator": "lt", "value": 500},
+				}},
+			}}},
+		},
+		Sessions: [][]StatsPage{
+			{
+				page("https://example.com", 404),
+				page("https://example.com/new", 404),
+				// The same URL again; only the first page of a URL counts.
+				page("https://example.com/new#reviews", 404),
+				page("HTTPS://Example.COM:443/gone", 410),
+				page("https://example.com/fixed", 200),
+			},
+			{
+				page("https://example.com/", 404),
+				page("https://example.com/new", 200),
+				page("https://example.com/gone", 404),
+				page("https://example.com/fixed", 404),
+			},
+		},
+	}
+}
+
+// RunCheckPagesConformance checks that ChecksWithPages splits a check's
+// matches into new, resolved and persisting pages the way every backend
+// must.
+func RunCheckPagesConformance(t *testing.T, h CheckPagesHarness) {
+	ids := h.Seed(t, checkPagesFixture())
+	current, previous := ids[0], ids[1]
+	ctx := context.Background()
+
+	fetch := func(t *testing.T, limit int) models.CheckWithPages {
+		t.Helper()
+		checks, err := h.Repo.ChecksWithPages(ctx, repository.ChecksWithPagesParams{
+			SessionID: current, ComparisonSessionID: &previous, PageLimitPerCheck: limit,
+		})
+		if err != nil {
+			t.Fatalf("checks with pages: %v", err)
+		}
+		if len(checks) != 1 {
+			t.Fatalf("expected 1 check got %d", len(checks))
+		}
+		if checks[0].Error != "" || checks[0].Comparison == nil {
+			t.Fatalf("unexpected check %+v", checks[0])
+		}
+		return checks[0]
+	}
+
+	type set struct {
+		total   int
+		session int64
+		urls    []string
+	}
+	assertSet := func(t *testing.T, name string, got models.CheckPageSet, want set) {
+		t.Helper()
+		urls := pageURLs(got.Pages)
+		if got.Total != want.total || !reflect.DeepEqual(urls, want.urls) {
+			t.Errorf("%s: expected %d pages %v got %d pages %v", name, want.total, want.urls, got.Total, urls)
+		}
+		for _, p := range got.Pages {
+			if p.CrawlingSessionID != want.session {
+				t.Errorf("%s: expected page %s of session %d got %d", name, p.URL, want.session, p.CrawlingSessionID)
+			}
+		}
+	}
+
+	t.Run("new resolved and persisting", func(t *testing.T) {
+		check := fetch(t, 10)
+		if check.Comparison.ComparisonSessionID != previous {
+			t.Errorf("expected comparison session %d got %d", previous, check.Comparison.ComparisonSessionID)
+		}
+		assertSet(t, "new", check.Comparison.New, set{1, current, []string{"https://example.com/new"}})
+		assertSet(t, "resolved", check.Comparison.Resolved, set{1, previous, []string{"https://example.com/fixed"}})
+		assertSet(t, "persisting", check.Comparison.Persisting,
+			set{2, current, []string{"https://example.com", "HTTPS://Example.COM:443/gone"}})
+
+		want := []string{"https://example.com", "https://example.com/new", "HTTPS://Example.COM:443/gone"}
+		if got := pageURLs(check.Pages); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected current pages %v got %v", want, got)
+		}
+	})
+
+	t.Run("limit keeps totals", func(t *testing.T) {
+		check := fetch(t, 1)
+		assertSet(t, "persisting", check.Comparison.Persisting, set{2, current, []string{"https://example.com"}})
+		assertSet(t, "new", check.Comparison.New, set{1, current, []string{"https://example.com/new"}})
+		if got := pageURLs(check.Pages); !reflect.DeepEqual(got, []string{"https://example.com"}) {
+			t.Errorf("expected current pages [https://example.com] got %v", got)
+		}
+	})
+}
+
+func pageURLs(pages []models.Page) []string {
+	urls := make([]string, len(pages))
+	for i, p := range pages {
+		urls[i] = p.URL
+	}
+	return urls
+}
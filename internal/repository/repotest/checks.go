@@ -0,0 +1,202 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+func auditCheckFilterConfig() map[string]any {
+	return map[string]any{"filter_groups": []any{
+		map[string]any{"filters": []any{map[string]any{"name": "response_code", "operator": "gte", "value": float64(400)}}},
+	}}
+}
+
+// RunAuditCheckConformance checks the AuditCheckRepository contract. The
+// checks it creates are deleted when the test ends.
+func RunAuditCheckConformance(t *testing.T, repo repository.AuditCheckRepository) {
+	ctx := context.Background()
+	sku, otherSKU := UniqueSKU(), UniqueSKU()
+
+	create := func(t *testing.T, ac models.AuditCheck) models.AuditCheck {
+		t.Helper()
+		if err := repo.Create(ctx, &ac); err != nil {
+			t.Fatalf("create audit check: %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(ctx, ac.ID) })
+		return ac
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		a := create(t, models.AuditCheck{SearchKeywordURLID: sku, Name: "errors", Category: "problematic", Severity: "high",
+			Weight: 2, FilterConfig: auditCheckFilterConfig()})
+		b := create(t, models.AuditCheck{SearchKeywordURLID: sku, Name: "other", Category: "warning"})
+		if a.ID <= 0 || b.ID <= 0 || a.ID == b.ID {
+			t.Fatalf("expected distinct positive ids got %d and %d", a.ID, b.ID)
+		}
+
+		got, err := repo.Get(ctx, a.ID)
+		if err != nil {
+			t.Fatalf("get audit check: %v", err)
+		}
+		if got.ID != a.ID || got.SearchKeywordURLID != sku || got.Name != "errors" || got.Category != "problematic" ||
+			got.Severity != "high" || got.Weight != 2 {
+			t.Errorf("unexpected audit check %+v", got)
+		}
+		if !reflect.DeepEqual(got.FilterConfig, auditCheckFilterConfig()) {
+			t.Errorf("unexpected filter config %v", got.FilterConfig)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := repo.Get(ctx, missingID)
+		if !errors.Is(err, repository.ErrAuditCheckNotFound) {
+			t.Fatalf("expected ErrAuditCheckNotFound got %v", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		ac := create(t, models.AuditCheck{SearchKeywordURLID: sku, Name: "before", Category: "warning"})
+		ac.Name, ac.Category, ac.Customized = "after", "problematic", true
+		if err := repo.Update(ctx, &ac); err != nil {
+			t.Fatalf("update audit check: %v", err)
+		}
+		got, err := repo.Get(ctx, ac.ID)
+		if err != nil {
+			t.Fatalf("get audit check: %v", err)
+		}
+		if got.Name != "after" || got.Category != "problematic" || !got.Customized {
+			t.Errorf("update not persisted %+v", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ac := create(t, models.AuditCheck{SearchKeywordURLID: sku, Name: "gone", Category: "warning"})
+		if err := repo.Delete(ctx, ac.ID); err != nil {
+			t.Fatalf("delete audit check: %v", err)
+		}
+		if _, err := repo.Get(ctx, ac.ID); !errors.Is(err, repository.ErrAuditCheckNotFound) {
+			t.Fatalf("expected ErrAuditCheckNotFound after delete got %v", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		listSKU := UniqueSKU()
+		a := create(t, models.AuditCheck{SearchKeywordURLID: listSKU, Name: "a", Category: "warning"})
+		b := create(t, models.AuditCheck{SearchKeywordURLID: listSKU, Name: "b", Category: "warning"})
+		other := create(t, models.AuditCheck{SearchKeywordURLID: otherSKU, Name: "other", Category: "warning"})
+
+		checks, err := repo.ListBySKU(ctx, listSKU)
+		if err != nil {
+			t.Fatalf("list by sku: %v", err)
+		}
+		if got := auditCheckIDs(checks); !equalIDs(got, []int64{a.ID, b.ID}) {
+			t.Errorf("expected checks %v got %v", []int64{a.ID, b.ID}, got)
+		}
+
+		checks, err = repo.ListByIDsAndSKUs(ctx, []int64{b.ID, other.ID, a.ID, missingID}, []int64{listSKU})
+		if err != nil {
+			t.Fatalf("list by ids and skus: %v", err)
+		}
+		if got := auditCheckIDs(checks); !equalIDs(got, []int64{a.ID, b.ID}) {
+			t.Errorf("expected checks %v got %v", []int64{a.ID, b.ID}, got)
+		}
+
+		checks, err = repo.ListByIDsAndSKUs(ctx, nil, []int64{listSKU})
+		if err != nil {
+			t.Fatalf("list by no ids: %v", err)
+		}
+		if len(checks) != 0 {
+			t.Errorf("expected no checks for no ids got %v", auditCheckIDs(checks))
+		}
+	})
+}
+
+// RunAuditResultConformance checks the AuditResultRepository contract.
+func RunAuditResultConformance(t *testing.T, repo repository.AuditResultRepository) {
+	ctx := context.Background()
+	sessionID := UniqueSKU()
+	t.Cleanup(func() { repo.Save(ctx, sessionID, nil) })
+
+	result := func(checkID int64, matched int) models.AuditResult {
+		return models.AuditResult{AuditCheckID: checkID, Name: "check", Category: "warning", FilterConfig: auditCheckFilterConfig(),
+			MatchedPages: matched, SamplePageIDs: []int64{1, 2}}
+	}
+
+	t.Run("unevaluated session", func(t *testing.T) {
+		results, err := repo.ListBySession(ctx, missingID)
+		if err != nil {
+			t.Fatalf("list results: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no results got %+v", results)
+		}
+	})
+
+	t.Run("save replaces and orders by check", func(t *testing.T) {
+		if err := repo.Save(ctx, sessionID, []models.AuditResult{result(1, 5)}); err != nil {
+			t.Fatalf("save results: %v", err)
+		}
+		if err := repo.Save(ctx, sessionID, []models.AuditResult{result(3, 1), result(2, 4)}); err != nil {
+			t.Fatalf("save results again: %v", err)
+		}
+
+		results, err := repo.ListBySession(ctx, sessionID)
+		if err != nil {
+			t.Fatalf("list results: %v", err)
+		}
+		checks := make([]int64, len(results))
+		for i, r := range results {
+			checks[i] = r.AuditCheckID
+			if r.CrawlingSessionID != sessionID {
+				t.Errorf("expected session %d got %d", sessionID, r.CrawlingSessionID)
+			}
+		}
+		if !equalIDs(checks, []int64{2, 3}) {
+			t.Fatalf("expected results for checks [2 3] got %v", checks)
+		}
+		got := results[0]
+		if got.MatchedPages != 4 || !reflect.DeepEqual(got.SamplePageIDs, []int64{1, 2}) ||
+			!reflect.DeepEqual(got.FilterConfig, auditCheckFilterConfig()) {
+			t.Errorf("unexpected result %+v", got)
+		}
+	})
+
+	t.Run("lookups by check and session", func(t *testing.T) {
+		if err := repo.Save(ctx, sessionID, []models.AuditResult{result(3, 1), result(2, 4)}); err != nil {
+			t.Fatalf("save results: %v", err)
+		}
+		sessions := []int64{sessionID, missingID}
+
+		byCheck, err := repo.ListByCheck(ctx, 2, sessions)
+		if err != nil {
+			t.Fatalf("list by check: %v", err)
+		}
+		if len(byCheck) != 1 || byCheck[sessionID].AuditCheckID != 2 || byCheck[sessionID].MatchedPages != 4 {
+			t.Errorf("expected check 2's result in session %d only got %+v", sessionID, byCheck)
+		}
+		if byCheck, err := repo.ListByCheck(ctx, 1, sessions); err != nil || len(byCheck) != 0 {
+			t.Errorf("expected no results for a check outside the snapshot got %+v, %v", byCheck, err)
+		}
+
+		evaluated, err := repo.ListEvaluatedSessions(ctx, sessions)
+		if err != nil {
+			t.Fatalf("list evaluated sessions: %v", err)
+		}
+		if !reflect.DeepEqual(evaluated, map[int64]bool{sessionID: true}) {
+			t.Errorf("expected only session %d evaluated got %v", sessionID, evaluated)
+		}
+	})
+}
+
+func auditCheckIDs(checks []models.AuditCheck) []int64 {
+	ids := make([]int64, len(checks))
+	for i, c := range checks {
+		ids[i] = c.ID
+	}
+	return ids
+}
@@ -0,0 +1,86 @@
+// Package repotest holds the conformance suites every implementation of the
+// repository interfaces must pass. The in-memory repositories run them on
+// every test run; the database backends run them from their own tests
+// against a live database named by an env DSN, seeding fixtures the suites
+// cannot create through the interfaces their own way. Each backend ships
+// the tables its suites expect in testdata/schema.sql and applies it before
+// running them.
+package repotest
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// missingID is an id no suite ever creates.
+const missingID = 1 << 62
+
+// OpenDB connects to the database whose DSN is in dsnEnv, skipping the test
+// when it is unset. driverEnv may name the database/sql driver to use
+// instead of defaultDriver; either way the driver must be linked into the
+// test binary.
+func OpenDB(t *testing.T, dsnEnv, driverEnv, defaultDriver string) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", dsnEnv)
+	}
+	driver := defaultDriver
+	if name := os.Getenv(driverEnv); name != "" {
+		driver = name
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatalf("open %s database: %v", driver, err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("connect to %s database: %v", driver, err)
+	}
+	return db
+}
+
+// ApplySchema runs the statements of the SQL file at path, separated by
+// semicolons, against db. Lines starting with -- are comments. The
+// statements should be idempotent since suites share a database.
+func ApplySchema(t *testing.T, db *sql.DB, path string) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read schema: %v", err)
+	}
+	for _, stmt := range strings.Split(string(raw), ";") {
+		var lines []string
+		for _, line := range strings.Split(stmt, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+				lines = append(lines, line)
+			}
+		}
+		stmt = strings.TrimSpace(strings.Join(lines, "\n"))
+		if stmt == "" {
+			continue
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("apply schema %s: %v\n%s", path, err, stmt)
+		}
+	}
+}
+
+// UniqueSKU returns a search keyword url id no other run is likely to use,
+// so suites can share a database.
+func UniqueSKU() int64 {
+	return 1_000_000_000 + time.Now().UnixNano()%1_000_000_000
+}
+
+// equalIDs reports whether two id lists hold the same ids in the same order,
+// treating nil and empty alike.
+func equalIDs(got, want []int64) bool {
+	if len(got) == 0 && len(want) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(got, want)
+}
@@ -0,0 +1,147 @@
+package repotest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// SessionDiffHarness connects the session diff suite to a backend. Seed
+// works as StatsHarness.Seed does.
+type SessionDiffHarness struct {
+	Repo repository.SessionDiffRepository
+	Seed func(t *testing.T, f StatsFixture) []int64
+}
+
+// sessionDiffFixture is a SKU with a session and the one it is compared
+// with. Pages pair up by normalized URL, and only the fields the diff
+// compares set a page apart.
+func sessionDiffFixture() StatsFixture {
+	page := func(url string) StatsPage {
+		return StatsPage{URL: url, ResponseCode: 200, Indexability: models.IndexabilityIndexable, ContentHash: "hash"}
+	}
+	home, welcome := page("https://example.com"), page("https://example.com/")
+	home.Title, welcome.Title = "Home", "Welcome"
+	// The same URL again; only the first page of a URL counts.
+	repeated := page("https://example.com/a#top")
+	repeated.Title = "Other"
+	canonical, sameCanonical := page("https://example.com/d"), page("https://example.com/d")
+	canonical.Canonical, sameCanonical.Canonical = "https://example.com/d", "HTTPS://Example.COM:443/d"
+	// Indexability is only compared when both sessions recorded it.
+	unrecorded := page("https://example.com/e")
+	unrecorded.Indexability = ""
+	rehashed, hashed := page("https://example.com/f"), page("https://example.com/f")
+	rehashed.ContentHash, hashed.ContentHash = "new", "old"
+
+	return StatsFixture{
+		SKU: UniqueSKU(),
+		Sessions: [][]StatsPage{
+			{
+				home,
+				page("https://example.com/a"),
+				repeated,
+				page("https://example.com/b"),
+				canonical,
+				page("https://example.com/e"),
+				rehashed,
+				page("https://example.com/c"),
+			},
+			{
+				welcome,
+				page("https://example.com/a"),
+				page("https://example.com/gone"),
+				sameCanonical,
+				unrecorded,
+				hashed,
+			},
+		},
+	}
+}
+
+// RunSessionDiffConformance checks that DiffSessions classifies and pages
+// the changes between two sessions the way every backend must.
+func RunSessionDiffConformance(t *testing.T, h SessionDiffHarness) {
+	ids := h.Seed(t, sessionDiffFixture())
+	current, previous := ids[0], ids[1]
+	ctx := context.Background()
+
+	fetch := func(t *testing.T, changes []string, page, limit int) map[string]*models.PageChangeSet {
+		t.Helper()
+		diff, err := h.Repo.DiffSessions(ctx, repository.SessionDiffParams{
+			SessionID: current, AgainstSessionID: previous, Changes: changes, Page: page, PageLimit: limit,
+		})
+		if err != nil {
+			t.Fatalf("diff sessions: %v", err)
+		}
+		return diff
+	}
+
+	type set struct {
+		total  int
+		urls   []string
+		fields []string
+	}
+	assertSet := func(t *testing.T, name string, got *models.PageChangeSet, want set) {
+		t.Helper()
+		if got == nil {
+			t.Errorf("%s: expected a change list", name)
+			return
+		}
+		var urls, fields []string
+		for _, p := range got.Pages {
+			urls = append(urls, p.URL)
+			if p.Change != name {
+				t.Errorf("%s: expected page %s to be %s got %s", name, p.URL, name, p.Change)
+			}
+			for _, f := range p.Fields {
+				fields = append(fields, f.Field)
+			}
+		}
+		if got.Total != want.total || !reflect.DeepEqual(urls, want.urls) || !reflect.DeepEqual(fields, want.fields) {
+			t.Errorf("%s: expected %d pages %v with fields %v got %d pages %v with fields %v",
+				name, want.total, want.urls, want.fields, got.Total, urls, fields)
+		}
+	}
+
+	t.Run("added removed and changed", func(t *testing.T) {
+		diff := fetch(t, nil, 1, 10)
+		assertSet(t, models.PageChangeAdded, diff[models.PageChangeAdded],
+			set{2, []string{"https://example.com/b", "https://example.com/c"}, nil})
+		assertSet(t, models.PageChangeRemoved, diff[models.PageChangeRemoved],
+			set{1, []string{"https://example.com/gone"}, nil})
+		assertSet(t, models.PageChangeChanged, diff[models.PageChangeChanged],
+			set{2, []string{"https://example.com", "https://example.com/f"}, []string{"title", "content_hash"}})
+
+		changed := diff[models.PageChangeChanged]
+		if len(changed.Pages) > 0 {
+			if f := changed.Pages[0].Fields[0]; f.Before != "Welcome" || f.After != "Home" {
+				t.Errorf("expected title Welcome -> Home got %v -> %v", f.Before, f.After)
+			}
+			if changed.Pages[0].PageID == 0 || changed.Pages[0].PreviousPageID == 0 {
+				t.Errorf("expected both page ids got %+v", changed.Pages[0])
+			}
+		}
+	})
+
+	t.Run("page of the requested changes", func(t *testing.T) {
+		diff := fetch(t, []string{models.PageChangeAdded, models.PageChangeChanged}, 2, 1)
+		if _, ok := diff[models.PageChangeRemoved]; ok {
+			t.Errorf("expected no removed list got %+v", diff[models.PageChangeRemoved])
+		}
+		assertSet(t, models.PageChangeAdded, diff[models.PageChangeAdded], set{2, []string{"https://example.com/c"}, nil})
+		assertSet(t, models.PageChangeChanged, diff[models.PageChangeChanged],
+			set{2, []string{"https://example.com/f"}, []string{"content_hash"}})
+	})
+
+	t.Run("page past the end keeps totals", func(t *testing.T) {
+		diff := fetch(t, nil, 5, 1)
+		assertSet(t, models.PageChangeAdded, diff[models.PageChangeAdded], set{2, nil, nil})
+		assertSet(t, models.PageChangeRemoved, diff[models.PageChangeRemoved], set{1, nil, nil})
+		if pages := diff[models.PageChangeAdded].Pages; pages == nil {
+			t.Errorf("expected an empty page list, not nil")
+		}
+	})
+}
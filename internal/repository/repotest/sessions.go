@@ -0,0 +1,113 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// RunCrawlingSessionConformance checks the CrawlingSessionRepository
+// contract. The sessions it creates are left under a SKU of their own.
+func RunCrawlingSessionConformance(t *testing.T, repo repository.CrawlingSessionRepository) {
+	ctx := context.Background()
+	sku := UniqueSKU()
+	start := time.Now().UTC().Add(-time.Minute)
+
+	create := func(t *testing.T, skuID int64) models.CrawlingSession {
+		t.Helper()
+		s := models.CrawlingSession{SearchKeywordURLID: skuID, URL: "https://example.com/"}
+		if err := repo.Create(ctx, &s); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+		return s
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		a, b := create(t, sku), create(t, sku)
+		if a.ID <= 0 || b.ID <= 0 || a.ID == b.ID {
+			t.Fatalf("expected distinct positive ids got %d and %d", a.ID, b.ID)
+		}
+		if a.CreatedAt.IsZero() || a.UpdatedAt.IsZero() {
+			t.Errorf("expected timestamps to be set got %+v", a)
+		}
+
+		got, err := repo.GetByID(ctx, a.ID)
+		if err != nil {
+			t.Fatalf("get session: %v", err)
+		}
+		if got.ID != a.ID || got.SearchKeywordURLID != sku || got.URL != a.URL {
+			t.Errorf("unexpected session %+v", got)
+		}
+		if got.Status != "pending" {
+			t.Errorf("expected status pending got %q", got.Status)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, missingID)
+		if !errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+			t.Fatalf("expected ErrCrawlingSessionNotFound got %v", err)
+		}
+	})
+
+	t.Run("mark done", func(t *testing.T) {
+		s := create(t, sku)
+		if err := repo.MarkDone(ctx, s.ID, "finished"); err != nil {
+			t.Fatalf("mark done: %v", err)
+		}
+		got, err := repo.GetByID(ctx, s.ID)
+		if err != nil {
+			t.Fatalf("get session: %v", err)
+		}
+		if got.Status != "done" || got.EndReason != "finished" || got.EndedAt == nil {
+			t.Errorf("unexpected finished session %+v", got)
+		}
+	})
+
+	t.Run("list done by sku", func(t *testing.T) {
+		doneSKU, otherSKU := UniqueSKU(), UniqueSKU()
+		first, second := create(t, doneSKU), create(t, doneSKU)
+		create(t, doneSKU)
+		other := create(t, otherSKU)
+		for _, id := range []int64{first.ID, second.ID, other.ID} {
+			if err := repo.MarkDone(ctx, id, "finished"); err != nil {
+				t.Fatalf("mark done: %v", err)
+			}
+		}
+
+		done, err := repo.ListDoneBySKU(ctx, doneSKU, nil, nil)
+		if err != nil {
+			t.Fatalf("list done: %v", err)
+		}
+		if got := sessionIDs(done); !equalIDs(got, []int64{first.ID, second.ID}) {
+			t.Errorf("expected done sessions %v got %v", []int64{first.ID, second.ID}, got)
+		}
+
+		done, err = repo.ListDoneBySKU(ctx, doneSKU, &start, nil)
+		if err != nil {
+			t.Fatalf("list done from: %v", err)
+		}
+		if len(done) != 2 {
+			t.Errorf("expected 2 sessions ended after %v got %d", start, len(done))
+		}
+		done, err = repo.ListDoneBySKU(ctx, doneSKU, nil, &start)
+		if err != nil {
+			t.Fatalf("list done to: %v", err)
+		}
+		if len(done) != 0 {
+			t.Errorf("expected no sessions ended before %v got %v", start, sessionIDs(done))
+		}
+	})
+}
+
+func sessionIDs(sessions []models.CrawlingSession) []int64 {
+	ids := make([]int64, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.ID
+	}
+	return ids
+}
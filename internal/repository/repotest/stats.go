@@ -0,0 +1,373 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/scoring"
+	"sitecrawler/newgo/models"
+)
+
+// StatsPage is a crawled page of a stats fixture. RedirectCode is empty
+// for pages that were not redirected and ResponseTimeMs 0 for pages that
+// were not timed; LinksTo holds the indexes of the pages of the same
+// session it links to.
+type StatsPage struct {
+	URL                string
+	ResponseCode       int
+	RedirectCode       string
+	Depth              int
+	Indexability       string
+	IndexabilityReason string
+	Title              string
+	MetaDescription    string
+	Canonical          string
+	ContentHash        string
+	OGTitle            string
+	OGDescription      string
+	ContentType        string
+	ResponseTimeMs     int64
+	LinksTo            []int
+}
+
+// StatsFixture is a SKU with its audit checks and crawling sessions, each
+// session given as its pages.
+type StatsFixture struct {
+	SKU      int64
+	Checks   []models.AuditCheck
+	Sessions [][]StatsPage
+}
+
+// StatsHarness connects the stats conformance suite to a backend. Seed
+// stores the fixture, removes it when the test ends and returns the ids of
+// its sessions in order.
+type StatsHarness struct {
+	Repo repository.StatsRepository
+	Seed func(t *testing.T, f StatsFixture) []int64
+}
+
+func statsFixture() StatsFixture {
+	page := func(url string, code int, redirect string, depth int, reason string) StatsPage {
+		p := StatsPage{URL: url, ResponseCode: code, RedirectCode: redirect, Depth: depth,
+			Indexability: models.IndexabilityIndexable, Title: url, MetaDescription: "description", ContentHash: url,
+			OGTitle: "title", OGDescription: "description", ContentType: "text/html; charset=utf-8"}
+		if reason != "" {
+			p.Indexability, p.IndexabilityReason = models.IndexabilityNonIndexable, reason
+		}
+		return p
+	}
+	home := page("https://example.com/", 200, "", 0, "")
+	home.LinksTo = []int{1, 2}
+	warning := page("https://example.com/blog/a", 200, "", 1, "")
+	warning.LinksTo = []int{2}
+	warning.OGDescription = ""
+	warning.Title = "Blog"
+	notFound := page("https://example.com/blog/b", 404, "", 1, "client_error")
+	notFound.Title = "Blog"
+	redirected := page("https://example.com/blog/old", 301, "301", 2, "redirect")
+	redirected.Title, redirected.MetaDescription = "", ""
+	redirected.LinksTo = []int{2}
+	shop := page("https://shop.example.com/p/1", 500, "", 5, "server_error")
+	shop.ContentHash = "https://example.com/"
+	// The previous session shares a title with the current one; duplicates
+	// are only counted within a session.
+	previousBlog := page("https://example.com/blog/a", 404, "", 1, "client_error")
+	previousBlog.Title = "Blog"
+
+	return StatsFixture{
+		SKU: UniqueSKU(),
+		Checks: []models.AuditCheck{
+			{Name: "4xx pages", Category: scoring.CategoryProblematic, FilterConfig: map[string]any{"filter_groups": []any{
+				map[string]any{"filters": []any{
+					map[string]any{"name": "response_code", "operator": "gte", "value": 400},
+					map[string]any{"name": "response_code", "operator": "lt", "value": 500},
+				}},
+			}}},
+			{Name: "5xx pages", Category: scoring.CategoryProblematic, FilterConfig: map[string]any{"filter_groups": []any{
+				map[string]any{"filters": []any{map[string]any{"name": "response_code", "operator": "gte", "value": 500}}},
+			}}},
+			{Name: "redirects", Category: scoring.CategoryWarning, FilterConfig: map[string]any{"filter_groups": []any{
+				map[string]any{"response_code": 301},
+			}}},
+		},
+		Sessions: [][]StatsPage{
+			{
+				home,
+				warning,
+				notFound,
+				redirected,
+				shop,
+			},
+			{
+				page("https://example.com/", 200, "", 0, ""),
+				previousBlog,
+			},
+		},
+	}
+}
+
+// depthFixture is a SKU with a single session whose pages sit deeper than
+// the level1 to level4 counts reach, mixing status classes at each depth.
+// The page without a response code was never fetched.
+func depthFixture() StatsFixture {
+	page := func(depth, code int) StatsPage {
+		return StatsPage{URL: fmt.Sprintf("https://example.com/%d/%d", depth, code), ResponseCode: code, Depth: depth,
+			Indexability: models.IndexabilityIndexable}
+	}
+	return StatsFixture{
+		SKU: UniqueSKU(),
+		Sessions: [][]StatsPage{{
+			page(0, 200),
+			page(5, 200), page(5, 301), page(5, 404), page(5, 503),
+			page(6, 200), page(6, 204), page(6, 500),
+			page(7, 0),
+			page(9, 302), page(9, 410),
+		}},
+	}
+}
+
+// performanceFixture is a SKU with a single session whose first page was
+// never timed.
+func performanceFixture() StatsFixture {
+	page := func(path string, ms int64) StatsPage {
+		return StatsPage{URL: "https://example.com/" + path, ResponseCode: 200, ResponseTimeMs: ms}
+	}
+	return StatsFixture{
+		SKU:      UniqueSKU(),
+		Sessions: [][]StatsPage{{page("untimed", 0), page("a", 100), page("b", 200), page("slow", 1500)}},
+	}
+}
+
+// RunStatsConformance checks that a StatsRepository computes /api/stats the
+// way every backend must.
+func RunStatsConformance(t *testing.T, h StatsHarness) {
+	ids := h.Seed(t, statsFixture())
+	current, previous := ids[0], ids[1]
+	ctx := context.Background()
+
+	fetch := func(t *testing.T, params repository.StatsQueryParams) map[string]any {
+		t.Helper()
+		params.SessionID = current
+		data, err := h.Repo.Fetch(ctx, params)
+		if err != nil {
+			t.Fatalf("fetch stats: %v", err)
+		}
+		return data
+	}
+
+	t.Run("totals", func(t *testing.T) {
+		data := fetch(t, repository.StatsQueryParams{})
+		want := repository.StatsCounts{
+			Total: 5, Warning: 1, Error: 2, OK: 2, Redirection: 1,
+			Level1: 2, Level2: 1,
+			Success: 2, Redirect: 1, ClientError: 1, ServerError: 1,
+		}.Map()
+		for key, value := range want {
+			if data[key] != value {
+				t.Errorf("%s: expected %v got %v", key, value, data[key])
+			}
+		}
+		wantIndexability := map[string]any{
+			"indexable": 2, "non_indexable": 3,
+			"reasons": map[string]int{"client_error": 1, "redirect": 1, "server_error": 1},
+		}
+		if !reflect.DeepEqual(data["indexability"], wantIndexability) {
+			t.Errorf("unexpected indexability %v", data["indexability"])
+		}
+	})
+
+	t.Run("depth histogram", func(t *testing.T) {
+		data := fetch(t, repository.StatsQueryParams{})
+		level := func(depth, pages, s2xx, s3xx, s4xx, s5xx int) models.DepthLevel {
+			return models.DepthLevel{Depth: depth, Pages: pages, StatusClasses: map[string]int{"2xx": s2xx, "3xx": s3xx, "4xx": s4xx, "5xx": s5xx}}
+		}
+		want := []models.DepthLevel{level(0, 1, 1, 0, 0, 0), level(1, 2, 1, 0, 1, 0), level(2, 1, 0, 1, 0, 0), level(5, 1, 0, 0, 0, 1)}
+		if !reflect.DeepEqual(data["depth_histogram"], want) {
+			t.Errorf("unexpected depth histogram %+v", data["depth_histogram"])
+		}
+	})
+
+	t.Run("performance leaves out untimed pages", func(t *testing.T) {
+		session := h.Seed(t, performanceFixture())[0]
+		data, err := h.Repo.Fetch(ctx, repository.StatsQueryParams{SessionID: session})
+		if err != nil {
+			t.Fatalf("fetch stats: %v", err)
+		}
+		performance, _ := data["performance"].(map[string]any)
+		times, _ := performance["response_time_ms"].(map[string]float64)
+		if times["p50"] != 200 || performance["slow_pages"] != 1 {
+			t.Errorf("expected p50 200 and 1 slow page got %v", performance)
+		}
+	})
+
+	t.Run("depth histogram beyond level 4", func(t *testing.T) {
+		session := h.Seed(t, depthFixture())[0]
+		data, err := h.Repo.Fetch(ctx, repository.StatsQueryParams{SessionID: session})
+		if err != nil {
+			t.Fatalf("fetch stats: %v", err)
+		}
+		level := func(depth, pages, s2xx, s3xx, s4xx, s5xx int) models.DepthLevel {
+			return models.DepthLevel{Depth: depth, Pages: pages, StatusClasses: map[string]int{"2xx": s2xx, "3xx": s3xx, "4xx": s4xx, "5xx": s5xx}}
+		}
+		want := []models.DepthLevel{
+			level(0, 1, 1, 0, 0, 0),
+			level(5, 4, 1, 1, 1, 1),
+			level(6, 3, 2, 0, 0, 1),
+			level(7, 1, 0, 0, 0, 0),
+			level(9, 2, 0, 1, 1, 0),
+		}
+		if !reflect.DeepEqual(data["depth_histogram"], want) {
+			t.Errorf("unexpected depth histogram %+v", data["depth_histogram"])
+		}
+	})
+
+	t.Run("site health", func(t *testing.T) {
+		data := fetch(t, repository.StatsQueryParams{})
+		if data["problematic"] != 2 {
+			t.Errorf("expected 2 problematic pages got %v", data["problematic"])
+		}
+		f := statsFixture()
+		results := make([]scoring.CheckResult, len(f.Checks))
+		for i, c := range f.Checks {
+			results[i] = scoring.CheckResult{Name: c.Name, Category: c.Category, MatchedPages: 1}
+		}
+		health, _ := scoring.SiteHealth(5, results)
+		if data["site_health"] != health {
+			t.Errorf("expected site health %d got %v", health, data["site_health"])
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		filterBy := func(name, op string, value int) repository.StatsQueryParams {
+			return repository.StatsQueryParams{Filters: []map[string]any{
+				{"filters": []any{map[string]any{"name": name, "operator": op, "value": value}}},
+			}}
+		}
+		cases := []struct {
+			name   string
+			params repository.StatsQueryParams
+			total  int
+		}{
+			{"equality", repository.StatsQueryParams{Filters: []map[string]any{{"response_code": 200}}}, 2},
+			{"filter group", repository.StatsQueryParams{Filters: []map[string]any{
+				{"filters": []any{map[string]any{"name": "depth", "operator": "gte", "value": 1}}},
+			}}, 4},
+			{"prefilters and filters", repository.StatsQueryParams{
+				Prefilters: []map[string]any{{"depth": 1}},
+				Filters:    []map[string]any{{"response_code": 404}},
+			}, 1},
+			{"view filter config", repository.StatsQueryParams{FilterConfig: map[string]any{"filter_groups": []any{
+				map[string]any{"response_code": 404}, map[string]any{"response_code": 500},
+			}}}, 2},
+			{"empty view filter config", repository.StatsQueryParams{FilterConfig: map[string]any{}}, 0},
+			{"duplicate titles", filterBy("duplicate_title_count", "eq", 2), 2},
+			{"empty titles are no duplicates", filterBy("duplicate_title_count", "eq", 0), 1},
+			{"duplicate descriptions", filterBy("duplicate_description_count", "eq", 4), 4},
+			{"duplicate content", filterBy("duplicate_content_count", "eq", 2), 2},
+			{"inlinks", filterBy("inlinks_count", "eq", 3), 1},
+			{"pages without inlinks", filterBy("inlinks_count", "eq", 0), 3},
+		}
+		for _, tc := range cases {
+			data := fetch(t, tc.params)
+			if data["total"] != tc.total {
+				t.Errorf("%s: expected total %d got %v", tc.name, tc.total, data["total"])
+			}
+		}
+	})
+
+	t.Run("invalid filter column", func(t *testing.T) {
+		for _, filters := range [][]map[string]any{
+			{{"response_code = 1 OR 1": 1}},
+			{{"filters": []any{map[string]any{"name": "depth;", "operator": "eq", "value": 1}}}},
+		} {
+			if _, err := h.Repo.Fetch(ctx, repository.StatsQueryParams{SessionID: current, Filters: filters}); err == nil {
+				t.Errorf("expected an error for filters %v", filters)
+			}
+		}
+	})
+
+	t.Run("comparison applies filters", func(t *testing.T) {
+		data := fetch(t, repository.StatsQueryParams{ComparisonSessionID: &previous, Filters: []map[string]any{{"depth": 1}}})
+		comparison, ok := data["comparison"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected comparison, got %v", data["comparison"])
+		}
+		if comparison["total"] != 1 || comparison["error"] != 1 {
+			t.Errorf("unexpected comparison %v", comparison)
+		}
+		wantChanges := map[string]int{"total": 1, "warning": 1, "error": 0, "ok": 1, "redirection": 0}
+		if !reflect.DeepEqual(data["changes"], wantChanges) {
+			t.Errorf("unexpected changes %v", data["changes"])
+		}
+	})
+
+	t.Run("group by", func(t *testing.T) {
+		cases := []struct {
+			groupBy repository.StatsGroupBy
+			want    map[string]int
+		}{
+			{repository.StatsGroupBy{Field: repository.StatsGroupStatusClass}, map[string]int{"2xx": 2, "3xx": 1, "4xx": 1, "5xx": 1}},
+			{repository.StatsGroupBy{Field: repository.StatsGroupDirectory, Segments: 1}, map[string]int{"/blog/": 3, "/": 1, "/p/": 1}},
+			{repository.StatsGroupBy{Field: repository.StatsGroupSubdomain}, map[string]int{"example.com": 4, "shop.example.com": 1}},
+			{repository.StatsGroupBy{Field: repository.StatsGroupDepth}, map[string]int{"0": 1, "1": 2, "2": 1, "5": 1}},
+			{repository.StatsGroupBy{Field: repository.StatsGroupContentType}, map[string]int{"text/html": 5}},
+			{repository.StatsGroupBy{Field: repository.StatsGroupRegex, Pattern: `/blog/([a-z]+)`}, map[string]int{"": 2, "a": 1, "b": 1, "old": 1}},
+		}
+		for _, tc := range cases {
+			groupBy := tc.groupBy
+			data := fetch(t, repository.StatsQueryParams{GroupBy: &groupBy})
+			groups, ok := data["groups"].([]map[string]any)
+			if !ok {
+				t.Fatalf("%s: expected groups, got %v", tc.groupBy.Field, data["groups"])
+			}
+			got := map[string]int{}
+			last := -1
+			for _, g := range groups {
+				total, _ := g["total"].(int)
+				if last >= 0 && total > last {
+					t.Errorf("%s: groups not ordered by size: %v", tc.groupBy.Field, groups)
+				}
+				last = total
+				got[g["key"].(string)] = total
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s: expected groups %v got %v", tc.groupBy.Field, tc.want, got)
+			}
+		}
+	})
+
+	t.Run("group metrics", func(t *testing.T) {
+		groupBy := repository.StatsGroupBy{Field: repository.StatsGroupDirectory, Segments: 1}
+		data := fetch(t, repository.StatsQueryParams{GroupBy: &groupBy})
+		groups, _ := data["groups"].([]map[string]any)
+		// Matched pages of the 4xx, 5xx and redirects checks per directory.
+		matched := map[string][]int{"/blog/": {1, 0, 1}, "/": {0, 0, 0}, "/p/": {0, 1, 0}}
+		f := statsFixture()
+		for _, g := range groups {
+			key := g["key"].(string)
+			want, ok := matched[key]
+			if !ok {
+				t.Errorf("unexpected group %q", key)
+				continue
+			}
+			results := make([]scoring.CheckResult, len(f.Checks))
+			for i, c := range f.Checks {
+				results[i] = scoring.CheckResult{Name: c.Name, Category: c.Category, MatchedPages: want[i]}
+			}
+			health, _ := scoring.SiteHealth(g["total"].(int), results)
+			if g["problematic"] != want[0]+want[1] || g["site_health"] != health {
+				t.Errorf("%s: expected %d problematic pages and site health %d got %v and %v",
+					key, want[0]+want[1], health, g["problematic"], g["site_health"])
+			}
+			// The fixture records no timings or security findings.
+			performance, _ := g["performance"].(map[string]any)
+			security, _ := g["security"].(map[string]any)
+			if performance["slow_pages"] != 0 || security["pages_with_issues"] != 0 {
+				t.Errorf("%s: unexpected performance %v or security %v", key, performance, security)
+			}
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// RunAuditCheckTemplateConformance checks the AuditCheckTemplateRepository
+// contract. Templates are global, so List is only checked for the ones the
+// suite creates; they are deleted when the test ends.
+func RunAuditCheckTemplateConformance(t *testing.T, repo repository.AuditCheckTemplateRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, name string) models.AuditCheckTemplate {
+		t.Helper()
+		tmpl := models.AuditCheckTemplate{Name: name, Category: "problematic", Severity: "high", Weight: 2,
+			FilterConfig: auditCheckFilterConfig(), Version: 1}
+		if err := repo.Create(ctx, &tmpl); err != nil {
+			t.Fatalf("create template: %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(ctx, tmpl.ID) })
+		return tmpl
+	}
+
+	t.Run("create, get and update", func(t *testing.T) {
+		tmpl := create(t, "errors")
+		got, err := repo.Get(ctx, tmpl.ID)
+		if err != nil {
+			t.Fatalf("get template: %v", err)
+		}
+		if got.Name != "errors" || got.Category != "problematic" || got.Severity != "high" || got.Weight != 2 || got.Version != 1 ||
+			!reflect.DeepEqual(got.FilterConfig, auditCheckFilterConfig()) {
+			t.Errorf("unexpected template %+v", got)
+		}
+
+		tmpl.Name, tmpl.Version = "renamed", 2
+		if err := repo.Update(ctx, &tmpl); err != nil {
+			t.Fatalf("update template: %v", err)
+		}
+		if got, err = repo.Get(ctx, tmpl.ID); err != nil || got.Name != "renamed" || got.Version != 2 {
+			t.Errorf("update not persisted %+v %v", got, err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, err := repo.Get(ctx, missingID); !errors.Is(err, repository.ErrTemplateNotFound) {
+			t.Fatalf("expected ErrTemplateNotFound got %v", err)
+		}
+		tmpl := create(t, "gone")
+		if err := repo.Delete(ctx, tmpl.ID); err != nil {
+			t.Fatalf("delete template: %v", err)
+		}
+		if _, err := repo.Get(ctx, tmpl.ID); !errors.Is(err, repository.ErrTemplateNotFound) {
+			t.Fatalf("expected ErrTemplateNotFound after delete got %v", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		a, b := create(t, "a"), create(t, "b")
+		templates, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("list templates: %v", err)
+		}
+		var ids []int64
+		for _, tmpl := range templates {
+			if tmpl.ID == a.ID || tmpl.ID == b.ID {
+				ids = append(ids, tmpl.ID)
+			}
+		}
+		if !equalIDs(ids, []int64{a.ID, b.ID}) {
+			t.Errorf("expected templates %v in id order got %v", []int64{a.ID, b.ID}, ids)
+		}
+	})
+}
+
+// RunViewTemplateConformance checks the ViewTemplateRepository contract the
+// way RunAuditCheckTemplateConformance does for audit check templates.
+func RunViewTemplateConformance(t *testing.T, repo repository.ViewTemplateRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, name string) models.ViewTemplate {
+		t.Helper()
+		tmpl := models.ViewTemplate{Name: name, FilterConfig: auditCheckFilterConfig(), Version: 1}
+		if err := repo.Create(ctx, &tmpl); err != nil {
+			t.Fatalf("create template: %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(ctx, tmpl.ID) })
+		return tmpl
+	}
+
+	t.Run("create, get and update", func(t *testing.T) {
+		tmpl := create(t, "errors")
+		got, err := repo.Get(ctx, tmpl.ID)
+		if err != nil {
+			t.Fatalf("get template: %v", err)
+		}
+		if got.Name != "errors" || got.Version != 1 || !reflect.DeepEqual(got.FilterConfig, auditCheckFilterConfig()) {
+			t.Errorf("unexpected template %+v", got)
+		}
+
+		tmpl.Name, tmpl.Version = "renamed", 2
+		if err := repo.Update(ctx, &tmpl); err != nil {
+			t.Fatalf("update template: %v", err)
+		}
+		if got, err = repo.Get(ctx, tmpl.ID); err != nil || got.Name != "renamed" || got.Version != 2 {
+			t.Errorf("update not persisted %+v %v", got, err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, err := repo.Get(ctx, missingID); !errors.Is(err, repository.ErrTemplateNotFound) {
+			t.Fatalf("expected ErrTemplateNotFound got %v", err)
+		}
+		tmpl := create(t, "gone")
+		if err := repo.Delete(ctx, tmpl.ID); err != nil {
+			t.Fatalf("delete template: %v", err)
+		}
+		if _, err := repo.Get(ctx, tmpl.ID); !errors.Is(err, repository.ErrTemplateNotFound) {
+			t.Fatalf("expected ErrTemplateNotFound after delete got %v", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		a, b := create(t, "a"), create(t, "b")
+		templates, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("list templates: %v", err)
+		}
+		var ids []int64
+		for _, tmpl := range templates {
+			if tmpl.ID == a.ID || tmpl.ID == b.ID {
+				ids = append(ids, tmpl.ID)
+			}
+		}
+		if !equalIDs(ids, []int64{a.ID, b.ID}) {
+			t.Errorf("expected templates %v in id order got %v", []int64{a.ID, b.ID}, ids)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// RunViewConformance checks the ViewRepository contract. The views it
+// creates are deleted when the test ends.
+func RunViewConformance(t *testing.T, repo repository.ViewRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, v models.View) models.View {
+		t.Helper()
+		if err := repo.Create(ctx, &v); err != nil {
+			t.Fatalf("create view: %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(ctx, v.ID) })
+		return v
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		sku := UniqueSKU()
+		a := create(t, models.View{SearchKeywordURLID: sku, Name: "errors", FilterConfig: auditCheckFilterConfig(),
+			Columns: []string{"url", "response_code"}, SortField: "depth", SortDirection: "desc", PageSize: 50, CompareWithPrevious: true})
+		b := create(t, models.View{SearchKeywordURLID: sku, Name: "all"})
+		if a.ID <= 0 || b.ID <= 0 || a.ID == b.ID {
+			t.Fatalf("expected distinct positive ids got %d and %d", a.ID, b.ID)
+		}
+
+		got, err := repo.Get(ctx, a.ID)
+		if err != nil {
+			t.Fatalf("get view: %v", err)
+		}
+		if got.ID != a.ID || got.SearchKeywordURLID != sku || got.Name != "errors" || got.SortField != "depth" ||
+			got.SortDirection != "desc" || got.PageSize != 50 || !got.CompareWithPrevious {
+			t.Errorf("unexpected view %+v", got)
+		}
+		if !reflect.DeepEqual(got.Columns, []string{"url", "response_code"}) {
+			t.Errorf("unexpected columns %v", got.Columns)
+		}
+		if !reflect.DeepEqual(got.FilterConfig, auditCheckFilterConfig()) {
+			t.Errorf("unexpected filter config %v", got.FilterConfig)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := repo.Get(ctx, missingID)
+		if !errors.Is(err, repository.ErrViewNotFound) {
+			t.Fatalf("expected ErrViewNotFound got %v", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		v := create(t, models.View{SearchKeywordURLID: UniqueSKU(), Name: "before"})
+		v.Name, v.PageSize, v.Customized = "after", 20, true
+		if err := repo.Update(ctx, &v); err != nil {
+			t.Fatalf("update view: %v", err)
+		}
+		got, err := repo.Get(ctx, v.ID)
+		if err != nil {
+			t.Fatalf("get view: %v", err)
+		}
+		if got.Name != "after" || got.PageSize != 20 || !got.Customized {
+			t.Errorf("update not persisted %+v", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		v := create(t, models.View{SearchKeywordURLID: UniqueSKU(), Name: "gone"})
+		if err := repo.Delete(ctx, v.ID); err != nil {
+			t.Fatalf("delete view: %v", err)
+		}
+		if _, err := repo.Get(ctx, v.ID); !errors.Is(err, repository.ErrViewNotFound) {
+			t.Fatalf("expected ErrViewNotFound after delete got %v", err)
+		}
+	})
+
+	t.Run("list by sku", func(t *testing.T) {
+		sku := UniqueSKU()
+		a := create(t, models.View{SearchKeywordURLID: sku, Name: "a"})
+		b := create(t, models.View{SearchKeywordURLID: sku, Name: "b"})
+		create(t, models.View{SearchKeywordURLID: UniqueSKU(), Name: "other"})
+
+		views, err := repo.ListBySKU(ctx, sku)
+		if err != nil {
+			t.Fatalf("list by sku: %v", err)
+		}
+		ids := make([]int64, len(views))
+		for i, v := range views {
+			ids[i] = v.ID
+		}
+		if !equalIDs(ids, []int64{a.ID, b.ID}) {
+			t.Errorf("expected views %v got %v", []int64{a.ID, b.ID}, ids)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+type AuditCheckTemplateRepository interface {
+	Create(ctx context.Context, t *models.AuditCheckTemplate) error
+	Update(ctx context.Context, t *models.AuditCheckTemplate) error
+	Delete(ctx context.Context, id int64) error
+	Get(ctx context.Context, id int64) (*models.AuditCheckTemplate, error)
+	List(ctx context.Context) ([]models.AuditCheckTemplate, error)
+}
+
+type ViewTemplateRepository interface {
+	Create(ctx context.Context, t *models.ViewTemplate) error
+	Update(ctx context.Context, t *models.ViewTemplate) error
+	Delete(ctx context.Context, id int64) error
+	Get(ctx context.Context, id int64) (*models.ViewTemplate, error)
+	List(ctx context.Context) ([]models.ViewTemplate, error)
+}
+
+type InMemoryAuditCheckTemplateRepository struct {
+	mu    sync.Mutex
+	seq   int64
+	items map[int64]models.AuditCheckTemplate
+}
+
+func NewInMemoryAuditCheckTemplateRepository() *InMemoryAuditCheckTemplateRepository {
+	return &InMemoryAuditCheckTemplateRepository{items: map[int64]models.AuditCheckTemplate{}}
+}
+
+func (r *InMemoryAuditCheckTemplateRepository) Create(ctx context.Context, t *models.AuditCheckTemplate) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	t.ID = r.seq
+	t.CreatedAt = time.Now().UTC()
+	t.UpdatedAt = t.CreatedAt
+	r.items[t.ID] = *t
+	return nil
+}
+
+func (r *InMemoryAuditCheckTemplateRepository) Update(ctx context.Context, t *models.AuditCheckTemplate) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[t.ID]; !ok {
+		return ErrTemplateNotFound
+	}
+	t.UpdatedAt = time.Now().UTC()
+	r.items[t.ID] = *t
+	return nil
+}
+
+func (r *InMemoryAuditCheckTemplateRepository) Delete(ctx context.Context, id int64) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, id)
+	return nil
+}
+
+func (r *InMemoryAuditCheckTemplateRepository) Get(ctx context.Context, id int64) (*models.AuditCheckTemplate, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.items[id]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+	return &t, nil
+}
+
+func (r *InMemoryAuditCheckTemplateRepository) List(ctx context.Context) ([]models.AuditCheckTemplate, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]models.AuditCheckTemplate, 0, len(r.items))
+	for _, t := range r.items {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+type InMemoryViewTemplateRepository struct {
+	mu    sync.Mutex
+	seq   int64
+	items map[int64]models.ViewTemplate
+}
+
+func NewInMemoryViewTemplateRepository() *InMemoryViewTemplateRepository {
+	return &InMemoryViewTemplateRepository{items: map[int64]models.ViewTemplate{}}
+}
+
+func (r *InMemoryViewTemplateRepository) Create(ctx context.Context, t *models.ViewTemplate) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	t.ID = r.seq
+	t.CreatedAt = time.Now().UTC()
+	t.UpdatedAt = t.CreatedAt
+	r.items[t.ID] = *t
+	return nil
+}
+
+func (r *InMemoryViewTemplateRepository) Update(ctx context.Context, t *models.ViewTemplate) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[t.ID]; !ok {
+		return ErrTemplateNotFound
+	}
+	t.UpdatedAt = time.Now().UTC()
+	r.items[t.ID] = *t
+	return nil
+}
+
+func (r *InMemoryViewTemplateRepository) Delete(ctx context.Context, id int64) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, id)
+	return nil
+}
+
+func (r *InMemoryViewTemplateRepository) Get(ctx context.Context, id int64) (*models.ViewTemplate, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.items[id]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+	return &t, nil
+}
+
+func (r *InMemoryViewTemplateRepository) List(ctx context.Context) ([]models.ViewTemplate, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]models.ViewTemplate, 0, len(r.items))
+	for _, t := range r.items {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
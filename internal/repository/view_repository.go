@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 
 	"sitecrawler/newgo/models"
@@ -16,6 +17,8 @@ type ViewRepository interface {
 	Delete(ctx context.Context, id int64) error
 	Get(ctx context.Context, id int64) (*models.View, error)
 	ListBySKU(ctx context.Context, skuID int64) ([]models.View, error)
+	// ListByTemplate returns the views applied from a template, across SKUs.
+	ListByTemplate(ctx context.Context, templateID int64) ([]models.View, error)
 }
 
 type InMemoryViewRepository struct {
@@ -77,10 +80,26 @@ func (r *InMemoryViewRepository) ListBySKU(ctx context.Context, skuID int64) ([]
 			out = append(out, *cloneView(v))
 		}
 	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *InMemoryViewRepository) ListByTemplate(ctx context.Context, templateID int64) ([]models.View, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.View{}
+	for _, v := range r.items {
+		if v.TemplateID != nil && *v.TemplateID == templateID {
+			out = append(out, *cloneView(v))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
 	return out, nil
 }
 
 func cloneView(v *models.View) *models.View {
 	c := *v
+	c.Columns = append([]string(nil), v.Columns...)
 	return &c
 }
@@ -0,0 +1,139 @@
+// Package scoring turns audit check results into the site health score.
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Audit check categories.
+const (
+	CategoryError       = "error"
+	CategoryWarning     = "warning"
+	CategoryNotice      = "notice"
+	CategoryProblematic = "problematic"
+)
+
+// Audit check severities.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// SeverityFactors scale how much a failing page of a check costs.
+var SeverityFactors = map[string]float64{
+	SeverityCritical: 1,
+	SeverityHigh:     0.75,
+	SeverityMedium:   0.5,
+	SeverityLow:      0.25,
+}
+
+// MaxWeight bounds the per-check weight multiplier.
+const MaxWeight = 100
+
+// Formula is the site health expression reported next to the score.
+const Formula = "100 * (1 - sum(weight * severity_factor * matched_pages / total_pages) / sum(weight * severity_factor))"
+
+var categorySeverity = map[string]string{
+	CategoryError:       SeverityHigh,
+	CategoryProblematic: SeverityHigh,
+	CategoryWarning:     SeverityMedium,
+	CategoryNotice:      SeverityLow,
+}
+
+// DefaultSeverity is the severity used for a check of category that does not
+// set one.
+func DefaultSeverity(category string) string {
+	return categorySeverity[category]
+}
+
+// ValidateCategory reports whether category is a known audit check category.
+func ValidateCategory(category string) error {
+	if _, ok := categorySeverity[category]; !ok {
+		return fmt.Errorf("category must be one of %s, %s, %s, %s", CategoryError, CategoryWarning, CategoryNotice, CategoryProblematic)
+	}
+	return nil
+}
+
+// ValidateSeverity accepts a known severity or "" for the category default.
+func ValidateSeverity(severity string) error {
+	if _, ok := SeverityFactors[severity]; severity != "" && !ok {
+		return fmt.Errorf("severity must be one of %s, %s, %s, %s", SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow)
+	}
+	return nil
+}
+
+// ValidateWeight accepts 0 (meaning the default of 1) up to MaxWeight.
+func ValidateWeight(weight float64) error {
+	if weight < 0 || weight > MaxWeight || math.IsNaN(weight) {
+		return errors.New("weight must be between 0 and 100")
+	}
+	return nil
+}
+
+// CheckResult is the number of pages one audit check matched.
+type CheckResult struct {
+	ID           int64
+	Name         string
+	Category     string
+	Severity     string
+	Weight       float64
+	MatchedPages int
+}
+
+// SiteHealth combines the check results into a 0-100 score: the pass rate of
+// every check, averaged with weight * severity_factor as the weight. No checks
+// means a perfect score; no pages means 0. The returned breakdown explains
+// the number and is meant to be returned to clients as-is.
+func SiteHealth(totalPages int, results []CheckResult) (int, map[string]any) {
+	breakdown := make([]map[string]any, 0, len(results))
+	var penalty, maxPenalty float64
+	for _, r := range results {
+		severity := r.Severity
+		if severity == "" {
+			severity = DefaultSeverity(r.Category)
+		}
+		weight := r.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		factor := SeverityFactors[severity]
+
+		share := 0.0
+		if totalPages > 0 {
+			share = math.Min(1, float64(r.MatchedPages)/float64(totalPages))
+		}
+		penalty += weight * factor * share
+		maxPenalty += weight * factor
+
+		breakdown = append(breakdown, map[string]any{
+			"id": r.ID, "name": r.Name, "category": r.Category, "severity": severity,
+			"severity_factor": factor, "weight": weight, "matched_pages": r.MatchedPages,
+			"penalty": round2(weight * factor * share),
+		})
+	}
+
+	score := 100
+	switch {
+	case totalPages == 0:
+		score = 0
+	case maxPenalty > 0:
+		score = int(math.Round(100 * (1 - penalty/maxPenalty)))
+	}
+
+	return score, map[string]any{
+		"expression":       Formula,
+		"total_pages":      totalPages,
+		"severity_factors": SeverityFactors,
+		"penalty":          round2(penalty),
+		"max_penalty":      round2(maxPenalty),
+		"checks":           breakdown,
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
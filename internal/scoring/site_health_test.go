@@ -0,0 +1,44 @@
+package scoring
+
+import "testing"
+
+func TestSiteHealth(t *testing.T) {
+	results := []CheckResult{
+		{Name: "5xx", Category: CategoryError, Severity: SeverityCritical, MatchedPages: 10},
+		{Name: "thin", Category: CategoryNotice, Weight: 2, MatchedPages: 50},
+		{Name: "missing h1", Category: CategoryWarning, MatchedPages: 0},
+	}
+	// penalty = 1*1*0.1 + 2*0.25*0.5 + 0 = 0.35; max = 1 + 0.5 + 0.5 = 2
+	score, formula := SiteHealth(100, results)
+	if score != 83 {
+		t.Fatalf("expected 83 got %d", score)
+	}
+	if formula["penalty"] != 0.35 || formula["max_penalty"] != 2.0 || formula["expression"] != Formula {
+		t.Fatalf("unexpected formula %+v", formula)
+	}
+
+	if score, _ := SiteHealth(100, nil); score != 100 {
+		t.Fatalf("expected 100 without checks got %d", score)
+	}
+	if score, _ := SiteHealth(0, results); score != 0 {
+		t.Fatalf("expected 0 without pages got %d", score)
+	}
+}
+
+func TestValidateClassification(t *testing.T) {
+	if err := ValidateCategory("problematic"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ValidateCategory("cat-1"); err == nil {
+		t.Fatalf("expected unknown category to fail")
+	}
+	if err := ValidateSeverity(""); err != nil {
+		t.Fatalf("expected empty severity to default: %v", err)
+	}
+	if err := ValidateSeverity("urgent"); err == nil {
+		t.Fatalf("expected unknown severity to fail")
+	}
+	if err := ValidateWeight(101); err == nil {
+		t.Fatalf("expected weight above the maximum to fail")
+	}
+}
@@ -0,0 +1,102 @@
+// Package series builds the per-session time series behind the history
+// endpoints.
+package series
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+// Downsample reduces points to at most max points by averaging consecutive
+// runs of (nearly) equal size, keeping chronological order. A max of 0 or
+// less returns the points unchanged.
+func Downsample(points []models.HistoryPoint, max int) []models.HistoryPoint {
+	if max <= 0 || len(points) <= max {
+		return points
+	}
+
+	out := make([]models.HistoryPoint, 0, max)
+	for b := 0; b < max; b++ {
+		start := b * len(points) / max
+		end := (b + 1) * len(points) / max
+		bucket := points[start:end]
+
+		var sum float64
+		sessions := 0
+		for _, p := range bucket {
+			sum += p.Value * float64(p.Sessions)
+			sessions += p.Sessions
+		}
+		last := bucket[len(bucket)-1]
+		out = append(out, models.HistoryPoint{
+			SessionID: last.SessionID,
+			EndedAt:   last.EndedAt,
+			Value:     sum / float64(sessions),
+			Sessions:  sessions,
+		})
+	}
+	return out
+}
+
+// MetricValue resolves a dotted metric path such as "site_health" or
+// "performance.response_time_ms.p90" in a /api/stats result. It reports
+// false when the path does not lead to a number.
+func MetricValue(stats map[string]any, metric string) (float64, bool) {
+	var cur any = stats
+	for _, key := range strings.Split(metric, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return 0, false
+		}
+		if cur, ok = m[key]; !ok {
+			return 0, false
+		}
+	}
+	return toFloat(cur)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// ParseBound parses a from/to query value given as RFC 3339 or as a plain
+// date. A plain date used as an upper bound covers the whole day.
+func ParseBound(raw string, upper bool) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", raw)
+	}
+	if upper {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
@@ -0,0 +1,74 @@
+package series
+
+import (
+	"testing"
+	"time"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestDownsample(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var points []models.HistoryPoint
+	for i := 0; i < 5; i++ {
+		points = append(points, models.HistoryPoint{SessionID: int64(i + 1), EndedAt: base.AddDate(0, 0, i), Value: float64(i * 10), Sessions: 1})
+	}
+
+	if got := Downsample(points, 0); len(got) != 5 {
+		t.Fatalf("expected unchanged series, got %d points", len(got))
+	}
+	got := Downsample(points, 2)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 points got %d", len(got))
+	}
+	// Buckets are sessions 1-2 and 3-5.
+	if got[0].SessionID != 2 || got[0].Value != 5 || got[0].Sessions != 2 {
+		t.Fatalf("unexpected first bucket %+v", got[0])
+	}
+	if got[1].SessionID != 5 || got[1].Value != 30 || got[1].Sessions != 3 || !got[1].EndedAt.Equal(base.AddDate(0, 0, 4)) {
+		t.Fatalf("unexpected second bucket %+v", got[1])
+	}
+}
+
+func TestMetricValue(t *testing.T) {
+	stats := map[string]any{
+		"site_health": 87,
+		"performance": map[string]any{"response_time_ms": map[string]any{"p90": 412.5}},
+		"ssl_valid":   true,
+		"label":       "n/a",
+	}
+	cases := map[string]struct {
+		want float64
+		ok   bool
+	}{
+		"site_health":                      {87, true},
+		"performance.response_time_ms.p90": {412.5, true},
+		"ssl_valid":                        {1, true},
+		"performance.missing":              {0, false},
+		"site_health.nested":               {0, false},
+		"label":                            {0, false},
+	}
+	for metric, tc := range cases {
+		got, ok := MetricValue(stats, metric)
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("%s: expected %v/%v got %v/%v", metric, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestParseBound(t *testing.T) {
+	to, err := ParseBound("2026-03-01", true)
+	if err != nil || !to.Equal(time.Date(2026, 3, 1, 23, 59, 59, 999999999, time.UTC)) {
+		t.Fatalf("unexpected upper bound %v %v", to, err)
+	}
+	from, err := ParseBound("2026-03-01T10:00:00Z", false)
+	if err != nil || !from.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected lower bound %v %v", from, err)
+	}
+	if b, err := ParseBound("", false); b != nil || err != nil {
+		t.Fatalf("expected no bound")
+	}
+	if _, err := ParseBound("yesterday", false); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
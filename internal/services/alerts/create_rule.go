@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/alerting"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+func (s *service) CreateRule(ctx context.Context, req alertsDto.CreateAlertRuleRequest) (*dto.Response[alertsDto.AlertRuleResponse], error) {
+	emails, err := alerting.NormalizeEmails(req.Data.Emails)
+	if err != nil {
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusBadRequest, nil), nil
+	}
+	rule := &models.AlertRule{
+		SearchKeywordURLID: req.Data.SearchKeywordURLID,
+		Name:               req.Data.Name,
+		AuditCheckID:       req.Data.AuditCheckID,
+		Metric:             req.Data.Metric,
+		Mode:               req.Data.Mode,
+		Operator:           req.Data.Operator,
+		Threshold:          req.Data.Threshold,
+		WebhookURL:         req.Data.WebhookURL,
+		Emails:             emails,
+		Enabled:            true,
+	}
+	if rule.Mode == "" {
+		rule.Mode = alerting.ModeValue
+	}
+	if req.Data.Enabled != nil {
+		rule.Enabled = *req.Data.Enabled
+	}
+
+	if status, err := s.checkRuleTarget(ctx, rule); err != nil {
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), status, nil), nil
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(alertsDto.AlertRuleResponse{Data: *rule}, http.StatusCreated), nil
+}
+
+// checkRuleTarget makes sure a rule's audit check exists and belongs to the
+// rule's SKU.
+func (s *service) checkRuleTarget(ctx context.Context, rule *models.AlertRule) (int, error) {
+	if rule.AuditCheckID == nil {
+		return http.StatusOK, nil
+	}
+	check, err := s.auditRepo.Get(ctx, *rule.AuditCheckID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuditCheckNotFound) {
+			return http.StatusBadRequest, err
+		}
+		return http.StatusUnprocessableEntity, err
+	}
+	if check.SearchKeywordURLID != rule.SearchKeywordURLID {
+		return http.StatusBadRequest, fmt.Errorf("audit check %d belongs to another search keyword url", check.ID)
+	}
+	return http.StatusOK, nil
+}
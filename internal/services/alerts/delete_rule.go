@@ -0,0 +1,23 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/repository"
+)
+
+func (s *service) DeleteRule(ctx context.Context, req alertsDto.DeleteAlertRuleRequest) (*dto.Response[alertsDto.DeleteAlertRuleResponse], error) {
+	if err := s.ruleRepo.Delete(ctx, req.ID); err != nil {
+		if errors.Is(err, repository.ErrAlertRuleNotFound) {
+			return dto.NewResponse[alertsDto.DeleteAlertRuleResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[alertsDto.DeleteAlertRuleResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	result := alertsDto.DeleteAlertRuleData(req)
+	return dto.NewSuccessResponse(alertsDto.DeleteAlertRuleResponse{Data: result}, http.StatusOK), nil
+}
@@ -0,0 +1,23 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/repository"
+)
+
+func (s *service) GetRule(ctx context.Context, req alertsDto.GetAlertRuleRequest) (*dto.Response[alertsDto.AlertRuleResponse], error) {
+	rule, err := s.ruleRepo.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlertRuleNotFound) {
+			return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	return dto.NewSuccessResponse(alertsDto.AlertRuleResponse{Data: *rule}, http.StatusOK), nil
+}
@@ -0,0 +1,31 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/repository"
+)
+
+const defaultAlertsLimit = 100
+
+func (s *service) ListAlerts(ctx context.Context, req alertsDto.ListAlertsRequest) (*dto.Response[alertsDto.AlertsResponse], error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultAlertsLimit
+	}
+
+	alerts, err := s.alertRepo.List(ctx, repository.AlertListParams{
+		SearchKeywordURLID: req.SearchKeywordURLID,
+		CrawlingSessionID:  req.CrawlingSessionID,
+		RuleID:             req.RuleID,
+		Limit:              limit,
+	})
+	if err != nil {
+		return dto.NewResponse[alertsDto.AlertsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(alertsDto.AlertsResponse{Data: alerts}, http.StatusOK), nil
+}
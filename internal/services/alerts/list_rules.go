@@ -0,0 +1,18 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+)
+
+func (s *service) ListRules(ctx context.Context, req alertsDto.ListAlertRulesRequest) (*dto.Response[alertsDto.AlertRulesResponse], error) {
+	rules, err := s.ruleRepo.ListBySKU(ctx, req.SearchKeywordURLID)
+	if err != nil {
+		return dto.NewResponse[alertsDto.AlertRulesResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(alertsDto.AlertRulesResponse{Data: rules}, http.StatusOK), nil
+}
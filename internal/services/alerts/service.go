@@ -0,0 +1,43 @@
+package alerts
+
+import (
+	"context"
+	"sitecrawler/newgo/dto"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/repository"
+)
+
+type service struct {
+	ruleRepo  repository.AlertRuleRepository
+	alertRepo repository.AlertRepository
+	auditRepo repository.AuditCheckRepository
+}
+
+// NewService creates a new alert service.
+func NewService(
+	ruleRepo repository.AlertRuleRepository,
+	alertRepo repository.AlertRepository,
+	auditRepo repository.AuditCheckRepository,
+) Service {
+	if ruleRepo == nil {
+		panic("alert rule repository required")
+	}
+	if alertRepo == nil {
+		panic("alert repository required")
+	}
+	if auditRepo == nil {
+		panic("audit check repository required")
+	}
+	return &service{ruleRepo: ruleRepo, alertRepo: alertRepo, auditRepo: auditRepo}
+}
+
+// Service defines all alert rule and alert operations.
+type Service interface {
+	ListRules(ctx context.Context, req alertsDto.ListAlertRulesRequest) (*dto.Response[alertsDto.AlertRulesResponse], error)
+	CreateRule(ctx context.Context, req alertsDto.CreateAlertRuleRequest) (*dto.Response[alertsDto.AlertRuleResponse], error)
+	GetRule(ctx context.Context, req alertsDto.GetAlertRuleRequest) (*dto.Response[alertsDto.AlertRuleResponse], error)
+	UpdateRule(ctx context.Context, req alertsDto.UpdateAlertRuleRequest) (*dto.Response[alertsDto.AlertRuleResponse], error)
+	DeleteRule(ctx context.Context, req alertsDto.DeleteAlertRuleRequest) (*dto.Response[alertsDto.DeleteAlertRuleResponse], error)
+	ListAlerts(ctx context.Context, req alertsDto.ListAlertsRequest) (*dto.Response[alertsDto.AlertsResponse], error)
+}
@@ -0,0 +1,69 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/alerting"
+	"sitecrawler/newgo/internal/repository"
+)
+
+func (s *service) UpdateRule(ctx context.Context, req alertsDto.UpdateAlertRuleRequest) (*dto.Response[alertsDto.AlertRuleResponse], error) {
+	rule, err := s.ruleRepo.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlertRuleNotFound) {
+			return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	if req.Data.Name != nil {
+		rule.Name = *req.Data.Name
+	}
+	if req.Data.AuditCheckID != nil {
+		rule.AuditCheckID = req.Data.AuditCheckID
+		rule.Metric = ""
+	}
+	if req.Data.Metric != nil {
+		rule.Metric = *req.Data.Metric
+		rule.AuditCheckID = nil
+	}
+	if req.Data.Mode != nil {
+		rule.Mode = *req.Data.Mode
+	}
+	if req.Data.Operator != nil {
+		rule.Operator = *req.Data.Operator
+	}
+	if req.Data.Threshold != nil {
+		rule.Threshold = *req.Data.Threshold
+	}
+	if req.Data.WebhookURL != nil {
+		rule.WebhookURL = *req.Data.WebhookURL
+	}
+	if req.Data.Emails != nil {
+		emails, err := alerting.NormalizeEmails(*req.Data.Emails)
+		if err != nil {
+			return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusBadRequest, nil), nil
+		}
+		rule.Emails = emails
+	}
+	if req.Data.Enabled != nil {
+		rule.Enabled = *req.Data.Enabled
+	}
+
+	if err := alerting.ValidateRule(*rule); err != nil {
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusBadRequest, nil), nil
+	}
+	if status, err := s.checkRuleTarget(ctx, rule); err != nil {
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), status, nil), nil
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return dto.NewResponse[alertsDto.AlertRuleResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(alertsDto.AlertRuleResponse{Data: *rule}, http.StatusOK), nil
+}
@@ -0,0 +1,71 @@
+package audits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// ApplyTemplate creates a linked copy of the template for every SKU that
+// does not have one yet.
+func (s *service) ApplyTemplate(ctx context.Context, req auditsDto.ApplyAuditCheckTemplateRequest) (*dto.Response[auditsDto.ApplyAuditCheckTemplateResponse], error) {
+	template, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[auditsDto.ApplyAuditCheckTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[auditsDto.ApplyAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	applied, err := s.repo.ListByTemplate(ctx, template.ID)
+	if err != nil {
+		return dto.NewResponse[auditsDto.ApplyAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	bySKU := make(map[int64]models.AuditCheck, len(applied))
+	for _, ac := range applied {
+		bySKU[ac.SearchKeywordURLID] = ac
+	}
+
+	out := auditsDto.ApplyAuditCheckTemplateResult{
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Applied:    []int64{},
+		Existing:   []int64{},
+		Checks:     []models.AuditCheck{},
+	}
+	seen := make(map[int64]bool, len(req.Data.SearchKeywordURLIDs))
+	for _, skuID := range req.Data.SearchKeywordURLIDs {
+		if seen[skuID] {
+			continue
+		}
+		seen[skuID] = true
+		if ac, ok := bySKU[skuID]; ok {
+			out.Existing = append(out.Existing, skuID)
+			out.Checks = append(out.Checks, ac)
+			continue
+		}
+		templateID := template.ID
+		ac := models.AuditCheck{
+			SearchKeywordURLID: skuID,
+			Name:               template.Name,
+			Category:           template.Category,
+			Severity:           template.Severity,
+			Weight:             template.Weight,
+			FilterConfig:       template.FilterConfig,
+			TemplateID:         &templateID,
+			TemplateVersion:    template.Version,
+		}
+		if err := s.repo.Create(ctx, &ac); err != nil {
+			return dto.NewResponse[auditsDto.ApplyAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+		out.Applied = append(out.Applied, skuID)
+		out.Checks = append(out.Checks, ac)
+	}
+
+	return dto.NewSuccessResponse(auditsDto.ApplyAuditCheckTemplateResponse{Data: out}, http.StatusOK), nil
+}
@@ -13,6 +13,10 @@ func (s *service) Create(ctx context.Context, req auditsDto.CreateAuditCheckRequ
 	check := &models.AuditCheck{
 		SearchKeywordURLID: req.Data.SearchKeywordURLID,
 		Name:               req.Data.Name,
+		Category:           req.Data.Category,
+		Severity:           req.Data.Severity,
+		Weight:             req.Data.Weight,
+		FilterConfig:       req.Data.FilterConfig,
 	}
 
 	if err := s.repo.Create(ctx, check); err != nil {
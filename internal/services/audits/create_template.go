@@ -0,0 +1,27 @@
+package audits
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/models"
+)
+
+func (s *service) CreateTemplate(ctx context.Context, req auditsDto.CreateAuditCheckTemplateRequest) (*dto.Response[auditsDto.AuditCheckTemplateResponse], error) {
+	template := &models.AuditCheckTemplate{
+		Name:         req.Data.Name,
+		Category:     req.Data.Category,
+		Severity:     req.Data.Severity,
+		Weight:       req.Data.Weight,
+		FilterConfig: req.Data.FilterConfig,
+		Version:      1,
+	}
+
+	if err := s.templates.Create(ctx, template); err != nil {
+		return dto.NewResponse[auditsDto.AuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(auditsDto.AuditCheckTemplateResponse{Data: *template}, http.StatusCreated), nil
+}
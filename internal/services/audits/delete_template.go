@@ -0,0 +1,41 @@
+package audits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/repository"
+)
+
+// DeleteTemplate unlinks the applied checks before removing the template;
+// the checks themselves stay in place.
+func (s *service) DeleteTemplate(ctx context.Context, req auditsDto.DeleteAuditCheckTemplateRequest) (*dto.Response[auditsDto.DeleteAuditCheckTemplateResponse], error) {
+	if _, err := s.templates.Get(ctx, req.ID); err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[auditsDto.DeleteAuditCheckTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[auditsDto.DeleteAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	applied, err := s.repo.ListByTemplate(ctx, req.ID)
+	if err != nil {
+		return dto.NewResponse[auditsDto.DeleteAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	for i := range applied {
+		applied[i].TemplateID = nil
+		applied[i].TemplateVersion = 0
+		if err := s.repo.Update(ctx, &applied[i]); err != nil {
+			return dto.NewResponse[auditsDto.DeleteAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+	}
+
+	if err := s.templates.Delete(ctx, req.ID); err != nil {
+		return dto.NewResponse[auditsDto.DeleteAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	result := auditsDto.DeleteAuditCheckTemplateData{ID: req.ID, Unlinked: len(applied)}
+	return dto.NewSuccessResponse(auditsDto.DeleteAuditCheckTemplateResponse{Data: result}, http.StatusOK), nil
+}
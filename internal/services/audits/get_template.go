@@ -0,0 +1,23 @@
+package audits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/repository"
+)
+
+func (s *service) GetTemplate(ctx context.Context, req auditsDto.GetAuditCheckTemplateRequest) (*dto.Response[auditsDto.AuditCheckTemplateResponse], error) {
+	template, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[auditsDto.AuditCheckTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[auditsDto.AuditCheckTemplateResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	return dto.NewSuccessResponse(auditsDto.AuditCheckTemplateResponse{Data: *template}, http.StatusOK), nil
+}
@@ -0,0 +1,85 @@
+package audits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/series"
+	"sitecrawler/newgo/models"
+)
+
+// defaultHistoryLimit caps the sessions History reads when the request does
+// not ask for fewer; sessions without a snapshot are evaluated live.
+const defaultHistoryLimit = 100
+
+// History returns the check's matched pages for the most recent finished
+// sessions of its SKU. Sessions with an audit results snapshot use the
+// stored count, so the series does not move when the check is edited; older
+// sessions are evaluated live with the current filter_config.
+func (s *service) History(ctx context.Context, req auditsDto.AuditCheckHistoryRequest) (*dto.Response[auditsDto.AuditCheckHistoryResponse], error) {
+	check, err := s.repo.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuditCheckNotFound) {
+			return dto.NewResponse[auditsDto.AuditCheckHistoryResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[auditsDto.AuditCheckHistoryResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	sessions, err := s.sessionRepo.ListDoneBySKU(ctx, check.SearchKeywordURLID, req.From, req.To)
+	if err != nil {
+		return dto.NewResponse[auditsDto.AuditCheckHistoryResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if len(sessions) > limit {
+		sessions = sessions[len(sessions)-limit:]
+	}
+
+	ids := make([]int64, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	results, err := s.resultRepo.ListByCheck(ctx, check.ID, ids)
+	if err != nil {
+		return dto.NewResponse[auditsDto.AuditCheckHistoryResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	evaluated, err := s.resultRepo.ListEvaluatedSessions(ctx, ids)
+	if err != nil {
+		return dto.NewResponse[auditsDto.AuditCheckHistoryResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	points := make([]models.HistoryPoint, 0, len(sessions))
+	for _, session := range sessions {
+		var count int
+		if res, ok := results[session.ID]; ok {
+			// A check that failed in the snapshot has no value.
+			if res.Error != "" {
+				continue
+			}
+			count = res.MatchedPages
+		} else if evaluated[session.ID] {
+			// The snapshot predates the check.
+			continue
+		} else {
+			counts, err := s.matchRepo.CountMatches(ctx, session.ID, []map[string]any{check.FilterConfig})
+			if err != nil {
+				return dto.NewResponse[auditsDto.AuditCheckHistoryResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+			}
+			count = counts[0]
+		}
+		points = append(points, models.HistoryPoint{SessionID: session.ID, EndedAt: *session.EndedAt, Value: float64(count), Sessions: 1})
+	}
+
+	return dto.NewSuccessResponse(auditsDto.AuditCheckHistoryResponse{Data: auditsDto.AuditCheckHistoryData{
+		CheckID: check.ID,
+		Name:    check.Name,
+		Points:  series.Downsample(points, req.Points),
+	}}, http.StatusOK), nil
+}
@@ -0,0 +1,67 @@
+package audits
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/catalog"
+	"sitecrawler/newgo/models"
+)
+
+func (s *service) InstallDefaults(ctx context.Context, req auditsDto.InstallDefaultsRequest) (*dto.Response[auditsDto.InstallDefaultsResponse], error) {
+	existing, err := s.repo.ListBySKU(ctx, req.SearchKeywordURLID)
+	if err != nil {
+		return dto.NewResponse[auditsDto.InstallDefaultsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	byKey := map[string]models.AuditCheck{}
+	for _, ac := range existing {
+		if ac.BuiltinKey != "" {
+			byKey[ac.BuiltinKey] = ac
+		}
+	}
+
+	out := auditsDto.InstallDefaultsData{
+		CatalogVersion: catalog.Version,
+		Installed:      []string{},
+		Upgraded:       []string{},
+		Skipped:        []string{},
+		Unchanged:      []string{},
+		Checks:         []models.AuditCheck{},
+	}
+	for _, c := range catalog.Checks() {
+		ac, ok := byKey[c.Key]
+		switch {
+		case !ok:
+			ac = models.AuditCheck{
+				SearchKeywordURLID: req.SearchKeywordURLID,
+				Name:               c.Name,
+				Category:           c.Category,
+				FilterConfig:       c.FilterConfig,
+				BuiltinKey:         c.Key,
+				BuiltinVersion:     c.Version,
+			}
+			if err := s.repo.Create(ctx, &ac); err != nil {
+				return dto.NewResponse[auditsDto.InstallDefaultsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+			}
+			out.Installed = append(out.Installed, c.Key)
+		case ac.BuiltinVersion >= c.Version:
+			out.Unchanged = append(out.Unchanged, c.Key)
+		case ac.Customized:
+			out.Skipped = append(out.Skipped, c.Key)
+		default:
+			ac.Name = c.Name
+			ac.Category = c.Category
+			ac.FilterConfig = c.FilterConfig
+			ac.BuiltinVersion = c.Version
+			if err := s.repo.Update(ctx, &ac); err != nil {
+				return dto.NewResponse[auditsDto.InstallDefaultsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+			}
+			out.Upgraded = append(out.Upgraded, c.Key)
+		}
+		out.Checks = append(out.Checks, ac)
+	}
+
+	return dto.NewSuccessResponse(auditsDto.InstallDefaultsResponse{Data: out}, http.StatusOK), nil
+}
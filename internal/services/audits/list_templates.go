@@ -0,0 +1,18 @@
+package audits
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+)
+
+func (s *service) ListTemplates(ctx context.Context) (*dto.Response[auditsDto.AuditCheckTemplatesResponse], error) {
+	templates, err := s.templates.List(ctx)
+	if err != nil {
+		return dto.NewResponse[auditsDto.AuditCheckTemplatesResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(auditsDto.AuditCheckTemplatesResponse{Data: templates}, http.StatusOK), nil
+}
@@ -0,0 +1,39 @@
+package audits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/repository"
+)
+
+const defaultPreviewLimit = 20
+
+// Preview evaluates an unsaved filter_config against a session through the
+// same page match repository that counts saved checks, so the preview agrees
+// with what the check reports once stored.
+func (s *service) Preview(ctx context.Context, req auditsDto.PreviewAuditCheckRequest) (*dto.Response[auditsDto.AuditCheckPreviewResponse], error) {
+	if _, err := s.sessionRepo.GetByID(ctx, req.Data.CrawlingSessionID); err != nil {
+		if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+			return dto.NewResponse[auditsDto.AuditCheckPreviewResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[auditsDto.AuditCheckPreviewResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	limit := req.Data.Limit
+	if limit <= 0 {
+		limit = defaultPreviewLimit
+	}
+	pages, total, err := s.matchRepo.ListMatches(ctx, req.Data.CrawlingSessionID, req.Data.FilterConfig, 1, limit)
+	if err != nil {
+		return dto.NewResponse[auditsDto.AuditCheckPreviewResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(auditsDto.AuditCheckPreviewResponse{Data: auditsDto.AuditCheckPreviewData{
+		PageCount: total,
+		Pages:     pages,
+	}}, http.StatusOK), nil
+}
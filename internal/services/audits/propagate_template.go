@@ -0,0 +1,71 @@
+package audits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// PropagateTemplate copies the current template definition onto applied
+// checks that are behind its version. Checks edited by a user are skipped.
+func (s *service) PropagateTemplate(ctx context.Context, req auditsDto.PropagateAuditCheckTemplateRequest) (*dto.Response[auditsDto.PropagateAuditCheckTemplateResponse], error) {
+	template, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[auditsDto.PropagateAuditCheckTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[auditsDto.PropagateAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	applied, err := s.repo.ListByTemplate(ctx, template.ID)
+	if err != nil {
+		return dto.NewResponse[auditsDto.PropagateAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	var only map[int64]bool
+	if len(req.Data.SearchKeywordURLIDs) > 0 {
+		only = make(map[int64]bool, len(req.Data.SearchKeywordURLIDs))
+		for _, id := range req.Data.SearchKeywordURLIDs {
+			only[id] = true
+		}
+	}
+
+	out := auditsDto.PropagateAuditCheckTemplateResult{
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Updated:    []int64{},
+		Skipped:    []int64{},
+		Unchanged:  []int64{},
+		Checks:     []models.AuditCheck{},
+	}
+	for _, ac := range applied {
+		if only != nil && !only[ac.SearchKeywordURLID] {
+			continue
+		}
+		switch {
+		case ac.TemplateVersion >= template.Version:
+			out.Unchanged = append(out.Unchanged, ac.ID)
+		case ac.Customized:
+			out.Skipped = append(out.Skipped, ac.ID)
+		default:
+			ac.Name = template.Name
+			ac.Category = template.Category
+			ac.Severity = template.Severity
+			ac.Weight = template.Weight
+			ac.FilterConfig = template.FilterConfig
+			ac.TemplateVersion = template.Version
+			if err := s.repo.Update(ctx, &ac); err != nil {
+				return dto.NewResponse[auditsDto.PropagateAuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+			}
+			out.Updated = append(out.Updated, ac.ID)
+		}
+		out.Checks = append(out.Checks, ac)
+	}
+
+	return dto.NewSuccessResponse(auditsDto.PropagateAuditCheckTemplateResponse{Data: out}, http.StatusOK), nil
+}
@@ -9,15 +9,37 @@ import (
 )
 
 type service struct {
-	repo repository.AuditCheckRepository
+	repo        repository.AuditCheckRepository
+	sessionRepo repository.CrawlingSessionRepository
+	matchRepo   repository.PageMatchRepository
+	resultRepo  repository.AuditResultRepository
+	templates   repository.AuditCheckTemplateRepository
 }
 
 // NewService creates a new audit check service.
-func NewService(repo repository.AuditCheckRepository) Service {
+func NewService(
+	repo repository.AuditCheckRepository,
+	sessionRepo repository.CrawlingSessionRepository,
+	matchRepo repository.PageMatchRepository,
+	resultRepo repository.AuditResultRepository,
+	templates repository.AuditCheckTemplateRepository,
+) Service {
 	if repo == nil {
 		panic("audit check repository required")
 	}
-	return &service{repo: repo}
+	if sessionRepo == nil {
+		panic("crawling session repository required")
+	}
+	if matchRepo == nil {
+		panic("page match repository required")
+	}
+	if resultRepo == nil {
+		panic("audit result repository required")
+	}
+	if templates == nil {
+		panic("audit check template repository required")
+	}
+	return &service{repo: repo, sessionRepo: sessionRepo, matchRepo: matchRepo, resultRepo: resultRepo, templates: templates}
 }
 
 // Service defines all audit check operations.
@@ -27,4 +49,14 @@ type Service interface {
 	Get(ctx context.Context, req auditsDto.GetAuditCheckRequest) (*dto.Response[auditsDto.AuditCheckResponse], error)
 	Update(ctx context.Context, req auditsDto.UpdateAuditCheckRequest) (*dto.Response[auditsDto.AuditCheckResponse], error)
 	Delete(ctx context.Context, req auditsDto.DeleteAuditCheckRequest) (*dto.Response[auditsDto.DeleteAuditCheckResponse], error)
+	InstallDefaults(ctx context.Context, req auditsDto.InstallDefaultsRequest) (*dto.Response[auditsDto.InstallDefaultsResponse], error)
+	History(ctx context.Context, req auditsDto.AuditCheckHistoryRequest) (*dto.Response[auditsDto.AuditCheckHistoryResponse], error)
+	Preview(ctx context.Context, req auditsDto.PreviewAuditCheckRequest) (*dto.Response[auditsDto.AuditCheckPreviewResponse], error)
+	ListTemplates(ctx context.Context) (*dto.Response[auditsDto.AuditCheckTemplatesResponse], error)
+	CreateTemplate(ctx context.Context, req auditsDto.CreateAuditCheckTemplateRequest) (*dto.Response[auditsDto.AuditCheckTemplateResponse], error)
+	GetTemplate(ctx context.Context, req auditsDto.GetAuditCheckTemplateRequest) (*dto.Response[auditsDto.AuditCheckTemplateResponse], error)
+	UpdateTemplate(ctx context.Context, req auditsDto.UpdateAuditCheckTemplateRequest) (*dto.Response[auditsDto.AuditCheckTemplateResponse], error)
+	DeleteTemplate(ctx context.Context, req auditsDto.DeleteAuditCheckTemplateRequest) (*dto.Response[auditsDto.DeleteAuditCheckTemplateResponse], error)
+	ApplyTemplate(ctx context.Context, req auditsDto.ApplyAuditCheckTemplateRequest) (*dto.Response[auditsDto.ApplyAuditCheckTemplateResponse], error)
+	PropagateTemplate(ctx context.Context, req auditsDto.PropagateAuditCheckTemplateRequest) (*dto.Response[auditsDto.PropagateAuditCheckTemplateResponse], error)
 }
@@ -22,6 +22,23 @@ func (s *service) Update(ctx context.Context, req auditsDto.UpdateAuditCheckRequ
 	if req.Data.Name != nil {
 		existing.Name = *req.Data.Name
 	}
+	if req.Data.Category != nil {
+		existing.Category = *req.Data.Category
+	}
+	if req.Data.Severity != nil {
+		existing.Severity = *req.Data.Severity
+	}
+	if req.Data.Weight != nil {
+		existing.Weight = *req.Data.Weight
+	}
+	if req.Data.FilterConfig != nil {
+		existing.FilterConfig = *req.Data.FilterConfig
+	}
+	// Edited built-in and template checks are left alone by catalogue
+	// upgrades and template propagation.
+	if existing.BuiltinKey != "" || existing.TemplateID != nil {
+		existing.Customized = true
+	}
 
 	if err := s.repo.Update(ctx, existing); err != nil {
 		return dto.NewResponse[auditsDto.AuditCheckResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
@@ -0,0 +1,51 @@
+package audits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sitecrawler/newgo/dto"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	"sitecrawler/newgo/internal/repository"
+)
+
+// UpdateTemplate bumps the template version whenever the definition changes.
+// Applied checks are only touched by PropagateTemplate.
+func (s *service) UpdateTemplate(ctx context.Context, req auditsDto.UpdateAuditCheckTemplateRequest) (*dto.Response[auditsDto.AuditCheckTemplateResponse], error) {
+	existing, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[auditsDto.AuditCheckTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[auditsDto.AuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	updated := *existing
+	if req.Data.Name != nil {
+		updated.Name = *req.Data.Name
+	}
+	if req.Data.Category != nil {
+		updated.Category = *req.Data.Category
+	}
+	if req.Data.Severity != nil {
+		updated.Severity = *req.Data.Severity
+	}
+	if req.Data.Weight != nil {
+		updated.Weight = *req.Data.Weight
+	}
+	if req.Data.FilterConfig != nil {
+		updated.FilterConfig = *req.Data.FilterConfig
+	}
+	if reflect.DeepEqual(updated, *existing) {
+		return dto.NewSuccessResponse(auditsDto.AuditCheckTemplateResponse{Data: *existing}, http.StatusOK), nil
+	}
+	updated.Version++
+
+	if err := s.templates.Update(ctx, &updated); err != nil {
+		return dto.NewResponse[auditsDto.AuditCheckTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(auditsDto.AuditCheckTemplateResponse{Data: updated}, http.StatusOK), nil
+}
@@ -0,0 +1,54 @@
+package bundles
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+	"time"
+
+	bundlesDto "sitecrawler/newgo/dto/bundles"
+)
+
+func (s *service) Export(ctx context.Context, req bundlesDto.ExportConfigRequest) (*dto.Response[bundlesDto.ExportConfigResponse], error) {
+	checks, err := s.auditRepo.ListBySKU(ctx, req.SearchKeywordURLID)
+	if err != nil {
+		return dto.NewResponse[bundlesDto.ExportConfigResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	views, err := s.viewRepo.ListBySKU(ctx, req.SearchKeywordURLID)
+	if err != nil {
+		return dto.NewResponse[bundlesDto.ExportConfigResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	bundle := bundlesDto.Bundle{
+		Version:            bundlesDto.BundleVersion,
+		ExportedAt:         time.Now().UTC(),
+		SearchKeywordURLID: req.SearchKeywordURLID,
+		AuditChecks:        make([]bundlesDto.BundleAuditCheck, 0, len(checks)),
+		Views:              make([]bundlesDto.BundleView, 0, len(views)),
+	}
+	for _, c := range checks {
+		bundle.AuditChecks = append(bundle.AuditChecks, bundlesDto.BundleAuditCheck{
+			Name:           c.Name,
+			Category:       c.Category,
+			Severity:       c.Severity,
+			Weight:         c.Weight,
+			FilterConfig:   c.FilterConfig,
+			BuiltinKey:     c.BuiltinKey,
+			BuiltinVersion: c.BuiltinVersion,
+			Customized:     c.Customized,
+		})
+	}
+	for _, v := range views {
+		bundle.Views = append(bundle.Views, bundlesDto.BundleView{
+			Name:                v.Name,
+			FilterConfig:        v.FilterConfig,
+			Columns:             v.Columns,
+			Sort:                v.SortField,
+			Direction:           v.SortDirection,
+			PageSize:            v.PageSize,
+			CompareWithPrevious: v.CompareWithPrevious,
+		})
+	}
+
+	return dto.NewSuccessResponse(bundlesDto.ExportConfigResponse{Data: bundle}, http.StatusOK), nil
+}
@@ -0,0 +1,336 @@
+package bundles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sitecrawler/newgo/dto"
+	"slices"
+	"strings"
+
+	bundlesDto "sitecrawler/newgo/dto/bundles"
+	"sitecrawler/newgo/internal/filterconfig"
+	"sitecrawler/newgo/internal/scoring"
+	"sitecrawler/newgo/internal/viewsettings"
+	"sitecrawler/newgo/models"
+)
+
+// importOp is one planned write; exactly one of check and view is set.
+type importOp struct {
+	change int
+	create bool
+	check  *models.AuditCheck
+	view   *models.View
+}
+
+// Import validates the whole bundle first and only then plans and applies
+// the writes, so an invalid filter_config anywhere leaves the SKU untouched.
+// Items are matched to existing rows by name within their kind.
+func (s *service) Import(ctx context.Context, req bundlesDto.ImportConfigRequest) (*dto.Response[bundlesDto.ImportConfigResponse], error) {
+	if problems := validateBundle(req.Bundle); len(problems) > 0 {
+		return dto.NewResponse[bundlesDto.ImportConfigResponse](false, "invalid bundle: "+strings.Join(problems, "; "), http.StatusBadRequest, nil), nil
+	}
+
+	checks, err := s.auditRepo.ListBySKU(ctx, req.SearchKeywordURLID)
+	if err != nil {
+		return dto.NewResponse[bundlesDto.ImportConfigResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	views, err := s.viewRepo.ListBySKU(ctx, req.SearchKeywordURLID)
+	if err != nil {
+		return dto.NewResponse[bundlesDto.ImportConfigResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	result := bundlesDto.ImportConfigData{Mode: req.Mode, DryRun: req.DryRun, Changes: []bundlesDto.ImportChange{}}
+	var ops []importOp
+
+	checksByName := make(map[string]models.AuditCheck, len(checks))
+	for _, c := range checks {
+		if _, ok := checksByName[c.Name]; !ok {
+			checksByName[c.Name] = c
+		}
+	}
+	checkNames := newNameSet(len(checksByName))
+	for name := range checksByName {
+		checkNames.add(name)
+	}
+	for _, item := range req.Bundle.AuditChecks {
+		incoming := models.AuditCheck{
+			SearchKeywordURLID: req.SearchKeywordURLID,
+			Name:               item.Name,
+			Category:           item.Category,
+			Severity:           item.Severity,
+			Weight:             item.Weight,
+			FilterConfig:       item.FilterConfig,
+			BuiltinKey:         item.BuiltinKey,
+			BuiltinVersion:     item.BuiltinVersion,
+			Customized:         item.Customized,
+		}
+		change := bundlesDto.ImportChange{Kind: bundlesDto.KindAuditCheck, Name: item.Name}
+
+		existing, conflict := checksByName[item.Name]
+		var fields []string
+		if conflict {
+			fields = auditCheckDiff(existing, incoming)
+		}
+		switch {
+		case !conflict:
+			change.Action = bundlesDto.ImportActionCreate
+			checkNames.add(item.Name)
+			ops = append(ops, importOp{change: len(result.Changes), create: true, check: &incoming})
+		case len(fields) == 0:
+			change.Action = bundlesDto.ImportActionUnchanged
+			change.ID = existing.ID
+		case req.Mode == bundlesDto.ImportModeOverwrite:
+			change.Action = bundlesDto.ImportActionUpdate
+			change.ID = existing.ID
+			change.Fields = fields
+			incoming.ID = existing.ID
+			incoming.CreatedAt = existing.CreatedAt
+			// Bundles do not carry template links, so keep the row's own.
+			incoming.TemplateID = existing.TemplateID
+			incoming.TemplateVersion = existing.TemplateVersion
+			incoming.Customized = incoming.Customized || existing.Customized
+			ops = append(ops, importOp{change: len(result.Changes), check: &incoming})
+		case req.Mode == bundlesDto.ImportModeRename:
+			change.Action = bundlesDto.ImportActionRename
+			change.NewName = checkNames.unique(item.Name)
+			incoming.Name = change.NewName
+			ops = append(ops, importOp{change: len(result.Changes), create: true, check: &incoming})
+		default:
+			change.Action = bundlesDto.ImportActionSkip
+			change.ID = existing.ID
+			change.Fields = fields
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	viewsByName := make(map[string]models.View, len(views))
+	for _, v := range views {
+		if _, ok := viewsByName[v.Name]; !ok {
+			viewsByName[v.Name] = v
+		}
+	}
+	viewNames := newNameSet(len(viewsByName))
+	for name := range viewsByName {
+		viewNames.add(name)
+	}
+	for _, item := range req.Bundle.Views {
+		incoming := models.View{
+			SearchKeywordURLID:  req.SearchKeywordURLID,
+			Name:                item.Name,
+			FilterConfig:        item.FilterConfig,
+			Columns:             item.Columns,
+			SortField:           item.Sort,
+			SortDirection:       strings.ToLower(item.Direction),
+			PageSize:            item.PageSize,
+			CompareWithPrevious: item.CompareWithPrevious,
+		}
+		change := bundlesDto.ImportChange{Kind: bundlesDto.KindView, Name: item.Name}
+
+		existing, conflict := viewsByName[item.Name]
+		var fields []string
+		if conflict {
+			fields = viewDiff(existing, incoming)
+		}
+		switch {
+		case !conflict:
+			change.Action = bundlesDto.ImportActionCreate
+			viewNames.add(item.Name)
+			ops = append(ops, importOp{change: len(result.Changes), create: true, view: &incoming})
+		case len(fields) == 0:
+			change.Action = bundlesDto.ImportActionUnchanged
+			change.ID = existing.ID
+		case req.Mode == bundlesDto.ImportModeOverwrite:
+			change.Action = bundlesDto.ImportActionUpdate
+			change.ID = existing.ID
+			change.Fields = fields
+			incoming.ID = existing.ID
+			incoming.CreatedAt = existing.CreatedAt
+			incoming.TemplateID = existing.TemplateID
+			incoming.TemplateVersion = existing.TemplateVersion
+			incoming.Customized = existing.Customized
+			ops = append(ops, importOp{change: len(result.Changes), view: &incoming})
+		case req.Mode == bundlesDto.ImportModeRename:
+			change.Action = bundlesDto.ImportActionRename
+			change.NewName = viewNames.unique(item.Name)
+			incoming.Name = change.NewName
+			ops = append(ops, importOp{change: len(result.Changes), create: true, view: &incoming})
+		default:
+			change.Action = bundlesDto.ImportActionSkip
+			change.ID = existing.ID
+			change.Fields = fields
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	for _, change := range result.Changes {
+		switch change.Action {
+		case bundlesDto.ImportActionCreate, bundlesDto.ImportActionRename:
+			result.Created++
+		case bundlesDto.ImportActionUpdate:
+			result.Updated++
+		default:
+			result.Skipped++
+		}
+	}
+
+	if req.DryRun {
+		return dto.NewSuccessResponse(bundlesDto.ImportConfigResponse{Data: result}, http.StatusOK), nil
+	}
+
+	for _, op := range ops {
+		var err error
+		switch {
+		case op.check != nil && op.create:
+			err = s.auditRepo.Create(ctx, op.check)
+			result.Changes[op.change].ID = op.check.ID
+		case op.check != nil:
+			err = s.auditRepo.Update(ctx, op.check)
+		case op.create:
+			err = s.viewRepo.Create(ctx, op.view)
+			result.Changes[op.change].ID = op.view.ID
+		default:
+			err = s.viewRepo.Update(ctx, op.view)
+		}
+		if err != nil {
+			return dto.NewResponse[bundlesDto.ImportConfigResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+	}
+
+	return dto.NewSuccessResponse(bundlesDto.ImportConfigResponse{Data: result}, http.StatusOK), nil
+}
+
+// validateBundle lists every problem of the bundle so a client can fix them
+// all in one go.
+func validateBundle(b bundlesDto.Bundle) []string {
+	var problems []string
+	if b.Version != bundlesDto.BundleVersion {
+		problems = append(problems, fmt.Sprintf("unsupported bundle version %d", b.Version))
+	}
+
+	seen := map[string]bool{}
+	for i, c := range b.AuditChecks {
+		at := fmt.Sprintf("audit_checks[%d]", i)
+		if strings.TrimSpace(c.Name) == "" {
+			problems = append(problems, at+": name is required")
+		} else if seen[c.Name] {
+			problems = append(problems, fmt.Sprintf("%s: duplicate name %q", at, c.Name))
+		}
+		seen[c.Name] = true
+		if err := scoring.ValidateCategory(c.Category); err != nil {
+			problems = append(problems, at+": "+err.Error())
+		}
+		if err := scoring.ValidateSeverity(c.Severity); err != nil {
+			problems = append(problems, at+": "+err.Error())
+		}
+		if err := scoring.ValidateWeight(c.Weight); err != nil {
+			problems = append(problems, at+": "+err.Error())
+		}
+		if err := filterconfig.Validate(c.FilterConfig); err != nil {
+			problems = append(problems, at+".filter_config: "+err.Error())
+		}
+	}
+
+	seen = map[string]bool{}
+	for i, v := range b.Views {
+		at := fmt.Sprintf("views[%d]", i)
+		if strings.TrimSpace(v.Name) == "" {
+			problems = append(problems, at+": name is required")
+		} else if seen[v.Name] {
+			problems = append(problems, fmt.Sprintf("%s: duplicate name %q", at, v.Name))
+		}
+		seen[v.Name] = true
+		if err := filterconfig.Validate(v.FilterConfig); err != nil {
+			problems = append(problems, at+".filter_config: "+err.Error())
+		}
+		if err := viewsettings.Validate(v.Columns, v.Sort, v.Direction, v.PageSize); err != nil {
+			problems = append(problems, at+": "+err.Error())
+		}
+	}
+	return problems
+}
+
+func auditCheckDiff(existing, incoming models.AuditCheck) []string {
+	var fields []string
+	if existing.Category != incoming.Category {
+		fields = append(fields, "category")
+	}
+	if existing.Severity != incoming.Severity {
+		fields = append(fields, "severity")
+	}
+	if existing.Weight != incoming.Weight {
+		fields = append(fields, "weight")
+	}
+	if !sameConfig(existing.FilterConfig, incoming.FilterConfig) {
+		fields = append(fields, "filter_config")
+	}
+	if existing.BuiltinKey != incoming.BuiltinKey || existing.BuiltinVersion != incoming.BuiltinVersion {
+		fields = append(fields, "builtin")
+	}
+	return fields
+}
+
+func viewDiff(existing, incoming models.View) []string {
+	var fields []string
+	if !sameConfig(existing.FilterConfig, incoming.FilterConfig) {
+		fields = append(fields, "filter_config")
+	}
+	if !slices.Equal(existing.Columns, incoming.Columns) {
+		fields = append(fields, "columns")
+	}
+	if existing.SortField != incoming.SortField || existing.SortDirection != incoming.SortDirection {
+		fields = append(fields, "sort")
+	}
+	if existing.PageSize != incoming.PageSize {
+		fields = append(fields, "page_size")
+	}
+	if existing.CompareWithPrevious != incoming.CompareWithPrevious {
+		fields = append(fields, "compare_with_previous")
+	}
+	return fields
+}
+
+// sameConfig compares two filter_configs by their JSON form, so an int
+// stored by the catalogue equals the float64 decoded from a bundle.
+func sameConfig(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(normalizeConfig(a), normalizeConfig(b))
+}
+
+func normalizeConfig(cfg map[string]any) any {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return cfg
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return cfg
+	}
+	return out
+}
+
+// nameSet hands out names that are not taken yet, suffixing " (2)", " (3)"
+// and so on.
+type nameSet map[string]bool
+
+func newNameSet(size int) nameSet {
+	return make(nameSet, size)
+}
+
+func (s nameSet) add(name string) {
+	s[name] = true
+}
+
+func (s nameSet) unique(name string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		if !s[candidate] {
+			s.add(candidate)
+			return candidate
+		}
+	}
+}
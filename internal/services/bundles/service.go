@@ -0,0 +1,31 @@
+package bundles
+
+import (
+	"context"
+	"sitecrawler/newgo/dto"
+
+	bundlesDto "sitecrawler/newgo/dto/bundles"
+	"sitecrawler/newgo/internal/repository"
+)
+
+type service struct {
+	auditRepo repository.AuditCheckRepository
+	viewRepo  repository.ViewRepository
+}
+
+// NewService creates a new config bundle service.
+func NewService(auditRepo repository.AuditCheckRepository, viewRepo repository.ViewRepository) Service {
+	if auditRepo == nil {
+		panic("audit check repository required")
+	}
+	if viewRepo == nil {
+		panic("view repository required")
+	}
+	return &service{auditRepo: auditRepo, viewRepo: viewRepo}
+}
+
+// Service exports and imports the audit checks and views of a SKU.
+type Service interface {
+	Export(ctx context.Context, req bundlesDto.ExportConfigRequest) (*dto.Response[bundlesDto.ExportConfigResponse], error)
+	Import(ctx context.Context, req bundlesDto.ImportConfigRequest) (*dto.Response[bundlesDto.ImportConfigResponse], error)
+}
@@ -0,0 +1,82 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+const defaultDiffPageLimit = 50
+
+// Diff compares the session's pages with those of another session of the
+// same SKU, by default the previous finished one.
+func (s *service) Diff(ctx context.Context, req sessionsDto.SessionDiffRequest) (*dto.Response[sessionsDto.SessionDiffResponse], error) {
+	session, err := s.sessionRepo.GetByID(ctx, req.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+			return dto.NewResponse[sessionsDto.SessionDiffResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[sessionsDto.SessionDiffResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	var against *models.CrawlingSession
+	if req.AgainstSessionID != nil {
+		against, err = s.sessionRepo.GetByID(ctx, *req.AgainstSessionID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+				return dto.NewResponse[sessionsDto.SessionDiffResponse](false, "comparison crawling session not found", http.StatusBadRequest, nil), nil
+			}
+			return dto.NewResponse[sessionsDto.SessionDiffResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+		}
+		if against.SearchKeywordURLID != session.SearchKeywordURLID {
+			return dto.NewResponse[sessionsDto.SessionDiffResponse](false, "comparison crawling session belongs to another search keyword url", http.StatusBadRequest, nil), nil
+		}
+	} else {
+		against, err = repository.PreviousDoneSession(ctx, s.sessionRepo, *session)
+		if err != nil {
+			return dto.NewResponse[sessionsDto.SessionDiffResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+		}
+		if against == nil {
+			return dto.NewResponse[sessionsDto.SessionDiffResponse](false, "no previous finished crawling session to compare against", http.StatusNotFound, nil), nil
+		}
+	}
+
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	limit := req.PageLimit
+	if limit <= 0 {
+		limit = defaultDiffPageLimit
+	}
+	diff, err := s.diffRepo.DiffSessions(ctx, repository.SessionDiffParams{
+		SessionID:        session.ID,
+		AgainstSessionID: against.ID,
+		Changes:          req.Changes,
+		Page:             page,
+		PageLimit:        limit,
+	})
+	if err != nil {
+		return dto.NewResponse[sessionsDto.SessionDiffResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	data := sessionsDto.SessionDiffData{CrawlingSessionID: session.ID, AgainstSessionID: against.ID}
+	data.Added = pageChangeList(diff[models.PageChangeAdded])
+	data.Removed = pageChangeList(diff[models.PageChangeRemoved])
+	data.Changed = pageChangeList(diff[models.PageChangeChanged])
+
+	return dto.NewSuccessResponse(sessionsDto.SessionDiffResponse{Data: data}, http.StatusOK), nil
+}
+
+// pageChangeList returns nil for change lists that were not requested.
+func pageChangeList(set *models.PageChangeSet) *sessionsDto.PageChangeList {
+	if set == nil {
+		return nil
+	}
+	return &sessionsDto.PageChangeList{Total: set.Total, Pages: set.Pages}
+}
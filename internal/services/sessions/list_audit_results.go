@@ -0,0 +1,170 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+const defaultAuditResultsPageLimit = 10
+
+func (s *service) ListAuditResults(ctx context.Context, req sessionsDto.ListAuditResultsRequest) (*dto.Response[sessionsDto.AuditResultsResponse], error) {
+	session, err := s.sessionRepo.GetByID(ctx, req.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+			return dto.NewResponse[sessionsDto.AuditResultsResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[sessionsDto.AuditResultsResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	if req.ComparisonSessionID != nil {
+		comparison, err := s.sessionRepo.GetByID(ctx, *req.ComparisonSessionID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+				return dto.NewResponse[sessionsDto.AuditResultsResponse](false, "comparison crawling session not found", http.StatusBadRequest, nil), nil
+			}
+			return dto.NewResponse[sessionsDto.AuditResultsResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+		}
+		if comparison.SearchKeywordURLID != session.SearchKeywordURLID {
+			return dto.NewResponse[sessionsDto.AuditResultsResponse](false, "comparison crawling session belongs to another search keyword url", http.StatusBadRequest, nil), nil
+		}
+	}
+
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	limit := req.PageLimit
+	if limit <= 0 {
+		limit = defaultAuditResultsPageLimit
+	}
+
+	results, configs, source, err := s.auditResultsBase(ctx, session)
+	if err != nil {
+		return dto.NewResponse[sessionsDto.AuditResultsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	var comparisonCounts map[int64]int
+	if req.ComparisonSessionID != nil {
+		comparisonCounts, err = s.comparisonAuditCounts(ctx, *req.ComparisonSessionID, results, configs)
+		if err != nil {
+			return dto.NewResponse[sessionsDto.AuditResultsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+	}
+
+	for i := range results {
+		if results[i].Error != "" {
+			continue
+		}
+		if comparisonCounts != nil {
+			if prev, ok := comparisonCounts[results[i].CheckID]; ok {
+				delta := results[i].MatchedPages - prev
+				results[i].ComparisonMatchedPages = &prev
+				results[i].Delta = &delta
+			}
+		}
+		if results[i].MatchedPages == 0 {
+			continue
+		}
+		pages, _, err := s.matchRepo.ListMatches(ctx, session.ID, configs[i], page, limit)
+		if err != nil {
+			return dto.NewResponse[sessionsDto.AuditResultsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+		results[i].Pages = pages
+	}
+
+	return dto.NewSuccessResponse(sessionsDto.AuditResultsResponse{Data: sessionsDto.AuditResultsData{
+		Page:                page,
+		PageLimit:           limit,
+		ComparisonSessionID: req.ComparisonSessionID,
+		Source:              source,
+		Checks:              results,
+	}}, http.StatusOK), nil
+}
+
+// auditResultsBase returns the session's results without page samples, with
+// the filter_config each was evaluated with. The snapshot taken when the
+// session finished is preferred; sessions without one are evaluated live
+// against the current checks.
+func (s *service) auditResultsBase(ctx context.Context, session *models.CrawlingSession) ([]models.AuditCheckResult, []map[string]any, string, error) {
+	snapshot, err := s.resultRepo.ListBySession(ctx, session.ID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if len(snapshot) > 0 {
+		results := make([]models.AuditCheckResult, len(snapshot))
+		configs := make([]map[string]any, len(snapshot))
+		for i, res := range snapshot {
+			results[i] = models.AuditCheckResult{
+				CheckID:      res.AuditCheckID,
+				Name:         res.Name,
+				Category:     res.Category,
+				Severity:     res.Severity,
+				MatchedPages: res.MatchedPages,
+				Pages:        []models.Page{},
+				Error:        res.Error,
+			}
+			configs[i] = res.FilterConfig
+		}
+		return results, configs, sessionsDto.AuditResultsSourceSnapshot, nil
+	}
+
+	checks, err := s.auditRepo.ListBySKU(ctx, session.SearchKeywordURLID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	results := make([]models.AuditCheckResult, len(checks))
+	configs := make([]map[string]any, len(checks))
+	for i, check := range checks {
+		results[i] = models.AuditCheckResult{
+			CheckID:  check.ID,
+			Name:     check.Name,
+			Category: check.Category,
+			Severity: check.Severity,
+			Pages:    []models.Page{},
+		}
+		configs[i] = check.FilterConfig
+	}
+	counts, errs := repository.CountMatchesEach(ctx, s.matchRepo, session.ID, configs)
+	for i := range results {
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+			continue
+		}
+		results[i].MatchedPages = counts[i]
+	}
+	return results, configs, sessionsDto.AuditResultsSourceLive, nil
+}
+
+// comparisonAuditCounts returns the comparison session's matched pages per
+// check id, from its snapshot when there is one and otherwise by evaluating
+// configs against it. Checks that failed or are missing from the snapshot
+// have no entry.
+func (s *service) comparisonAuditCounts(ctx context.Context, sessionID int64, results []models.AuditCheckResult, configs []map[string]any) (map[int64]int, error) {
+	snapshot, err := s.resultRepo.ListBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]int, len(results))
+	if len(snapshot) > 0 {
+		for _, res := range snapshot {
+			if res.Error == "" {
+				out[res.AuditCheckID] = res.MatchedPages
+			}
+		}
+		return out, nil
+	}
+
+	counts, errs := repository.CountMatchesEach(ctx, s.matchRepo, sessionID, configs)
+	for i, res := range results {
+		if errs[i] == nil {
+			out[res.CheckID] = counts[i]
+		}
+	}
+	return out, nil
+}
@@ -2,13 +2,21 @@ package sessions
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"sitecrawler/newgo/dto"
 
 	sessionsDto "sitecrawler/newgo/dto/sessions"
 	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/viewsettings"
+	"sitecrawler/newgo/models"
 )
 
+const defaultPageLimitPerCheck = 10
+
+// ListChecks lists the session's audit checks with their matched pages. With
+// a comparison session, each check also reports the pages that newly match,
+// no longer match and still match.
 func (s *service) ListChecks(ctx context.Context, req sessionsDto.ListCrawlingSessionChecksRequest) (*dto.Response[sessionsDto.CrawlingSessionChecksResponse], error) {
 	params := repository.ChecksWithPagesParams{
 		SessionID:           req.SessionID,
@@ -16,6 +24,48 @@ func (s *service) ListChecks(ctx context.Context, req sessionsDto.ListCrawlingSe
 		ViewFilters:         req.ViewFilters,
 		PageLimitPerCheck:   req.PageLimitPerCheck,
 	}
+	if params.PageLimitPerCheck <= 0 {
+		params.PageLimitPerCheck = defaultPageLimitPerCheck
+	}
+
+	var session *models.CrawlingSession
+	if req.ViewID != 0 {
+		var v *models.View
+		var status int
+		var err error
+		session, v, status, err = viewsettings.LoadForSession(ctx, s.sessionRepo, s.viewRepo, req.SessionID, req.ViewID)
+		if err != nil {
+			return dto.NewResponse[sessionsDto.CrawlingSessionChecksResponse](false, err.Error(), status, nil), nil
+		}
+		params.FilterConfig = viewsettings.FilterConfig(v)
+		params.ComparisonSessionID, err = viewsettings.Comparison(ctx, s.sessionRepo, v, session, params.ComparisonSessionID)
+		if err != nil {
+			return dto.NewResponse[sessionsDto.CrawlingSessionChecksResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+		}
+	}
+
+	if req.ComparisonSessionID != nil {
+		if session == nil {
+			var err error
+			session, err = s.sessionRepo.GetByID(ctx, req.SessionID)
+			if err != nil {
+				if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+					return dto.NewResponse[sessionsDto.CrawlingSessionChecksResponse](false, err.Error(), http.StatusNotFound, nil), nil
+				}
+				return dto.NewResponse[sessionsDto.CrawlingSessionChecksResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+			}
+		}
+		comparison, err := s.sessionRepo.GetByID(ctx, *req.ComparisonSessionID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+				return dto.NewResponse[sessionsDto.CrawlingSessionChecksResponse](false, "comparison crawling session not found", http.StatusBadRequest, nil), nil
+			}
+			return dto.NewResponse[sessionsDto.CrawlingSessionChecksResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+		}
+		if comparison.SearchKeywordURLID != session.SearchKeywordURLID {
+			return dto.NewResponse[sessionsDto.CrawlingSessionChecksResponse](false, "comparison crawling session belongs to another search keyword url", http.StatusBadRequest, nil), nil
+		}
+	}
 
 	checks, err := s.checkRepo.ChecksWithPages(ctx, params)
 	if err != nil {
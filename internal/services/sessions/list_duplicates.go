@@ -0,0 +1,127 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+	"sort"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/analysis"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+func (s *service) ListDuplicates(ctx context.Context, req sessionsDto.ListDuplicatesRequest) (*dto.Response[sessionsDto.DuplicatesResponse], error) {
+	if _, err := s.sessionRepo.GetByID(ctx, req.SessionID); err != nil {
+		if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+			return dto.NewResponse[sessionsDto.DuplicatesResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[sessionsDto.DuplicatesResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	snapshots, err := s.snapshotRepo.ListSnapshots(ctx, req.SessionID)
+	if err != nil {
+		return dto.NewResponse[sessionsDto.DuplicatesResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	var clusters []models.DuplicateCluster
+	switch req.Kind {
+	case sessionsDto.DuplicateKindTitle:
+		clusters = clusterByText(req.SessionID, snapshots, func(p models.PageSnapshot) string { return p.Title })
+	case sessionsDto.DuplicateKindDescription:
+		clusters = clusterByText(req.SessionID, snapshots, func(p models.PageSnapshot) string { return p.MetaDescription })
+	case sessionsDto.DuplicateKindContent:
+		clusters = clusterByContent(req.SessionID, snapshots)
+	default:
+		return dto.NewResponse[sessionsDto.DuplicatesResponse](false, "unsupported duplicate kind: "+req.Kind, http.StatusBadRequest, nil), nil
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Size > clusters[j].Size })
+	if clusters == nil {
+		clusters = []models.DuplicateCluster{}
+	}
+
+	return dto.NewSuccessResponse(sessionsDto.DuplicatesResponse{Data: sessionsDto.DuplicatesData{Kind: req.Kind, Clusters: clusters}}, http.StatusOK), nil
+}
+
+// clusterByText groups pages whose normalized text field is identical.
+func clusterByText(sessionID int64, snapshots []models.PageSnapshot, field func(models.PageSnapshot) string) []models.DuplicateCluster {
+	groups := map[string][]models.Page{}
+	var keys []string
+	for _, snap := range snapshots {
+		key := analysis.NormalizeText(field(snap))
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], snapshotPage(sessionID, snap))
+	}
+
+	var out []models.DuplicateCluster
+	for _, key := range keys {
+		if pages := groups[key]; len(pages) > 1 {
+			out = append(out, models.DuplicateCluster{Key: key, Size: len(pages), Pages: pages})
+		}
+	}
+	return out
+}
+
+// clusterByContent groups pages with identical content hashes into exact
+// clusters, sized as the duplicate_content_count filter counts them. Pages
+// without an exact duplicate are then grouped by recorded SimHash
+// fingerprints within analysis.NearDuplicateDistance of each other.
+func clusterByContent(sessionID int64, snapshots []models.PageSnapshot) []models.DuplicateCluster {
+	byHash := map[string][]int{}
+	var keys []string
+	for i, snap := range snapshots {
+		if snap.ContentHash == "" {
+			continue
+		}
+		if _, ok := byHash[snap.ContentHash]; !ok {
+			keys = append(keys, snap.ContentHash)
+		}
+		byHash[snap.ContentHash] = append(byHash[snap.ContentHash], i)
+	}
+
+	var out []models.DuplicateCluster
+	exact := map[int]bool{}
+	for _, key := range keys {
+		members := byHash[key]
+		if len(members) < 2 {
+			continue
+		}
+		for _, idx := range members {
+			exact[idx] = true
+		}
+		out = append(out, contentCluster(sessionID, snapshots, key, models.DuplicateMatchExact, members))
+	}
+
+	// A zero fingerprint was never recorded and is left out of clustering.
+	hashes := make([]uint64, len(snapshots))
+	for i, snap := range snapshots {
+		if !exact[i] {
+			hashes[i] = snap.SimHash
+		}
+	}
+	for _, members := range analysis.ClusterSimHashes(hashes, analysis.NearDuplicateDistance) {
+		key := snapshots[members[0]].ContentHash
+		out = append(out, contentCluster(sessionID, snapshots, key, models.DuplicateMatchNear, members))
+	}
+	return out
+}
+
+func contentCluster(sessionID int64, snapshots []models.PageSnapshot, key, match string, members []int) models.DuplicateCluster {
+	pages := make([]models.Page, 0, len(members))
+	for _, idx := range members {
+		pages = append(pages, snapshotPage(sessionID, snapshots[idx]))
+	}
+	return models.DuplicateCluster{Key: key, Match: match, Size: len(pages), Pages: pages}
+}
+
+func snapshotPage(sessionID int64, snap models.PageSnapshot) models.Page {
+	return models.Page{ID: snap.ID, CrawlingSessionID: sessionID, URL: snap.URL, ResponseCode: snap.ResponseCode}
+}
@@ -0,0 +1,35 @@
+package sessions
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/analysis"
+)
+
+func (s *service) ListHreflang(ctx context.Context, req sessionsDto.ListHreflangRequest) (*dto.Response[sessionsDto.HreflangResponse], error) {
+	snapshots, err := s.snapshotRepo.ListSnapshots(ctx, req.SessionID)
+	if err != nil {
+		return dto.NewResponse[sessionsDto.HreflangResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	links, err := s.snapshotRepo.ListHreflangLinks(ctx, req.SessionID)
+	if err != nil {
+		return dto.NewResponse[sessionsDto.HreflangResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	pages := make([]analysis.HreflangPage, 0, len(snapshots))
+	for _, snap := range snapshots {
+		pages = append(pages, analysis.HreflangPage{
+			ID:         snap.ID,
+			URL:        snap.URL,
+			StatusCode: snap.ResponseCode,
+			Canonical:  snap.Canonical,
+			Links:      links[snap.ID],
+		})
+	}
+
+	checks := analysis.GroupHreflangIssues(analysis.AuditHreflang(pages))
+	return dto.NewSuccessResponse(sessionsDto.HreflangResponse{Data: sessionsDto.HreflangData{Checks: checks}}, http.StatusOK), nil
+}
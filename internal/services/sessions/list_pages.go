@@ -7,6 +7,7 @@ import (
 
 	sessionsDto "sitecrawler/newgo/dto/sessions"
 	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/viewsettings"
 )
 
 func (s *service) ListPages(ctx context.Context, req sessionsDto.ListCrawlingSessionPagesRequest) (*dto.Response[sessionsDto.CrawlingSessionPagesResponse], error) {
@@ -14,14 +15,30 @@ func (s *service) ListPages(ctx context.Context, req sessionsDto.ListCrawlingSes
 		SessionID: req.SessionID,
 		Filters:   req.Filters,
 		Sort:      req.Sort,
+		Direction: req.Direction,
 		Page:      req.Page,
 		PageLimit: req.PageLimit,
 	}
 
+	var columns []string
+	if req.ViewID != 0 {
+		_, v, status, err := viewsettings.LoadForSession(ctx, s.sessionRepo, s.viewRepo, req.SessionID, req.ViewID)
+		if err != nil {
+			return dto.NewResponse[sessionsDto.CrawlingSessionPagesResponse](false, err.Error(), status, nil), nil
+		}
+		params.FilterConfig = viewsettings.FilterConfig(v)
+		params.Sort, params.Direction, params.PageLimit = viewsettings.Listing(v, params.Sort, params.Direction, params.PageLimit)
+		columns = v.Columns
+	}
+
 	pages, total, err := s.pageRepo.List(ctx, params)
 	if err != nil {
 		return dto.NewResponse[sessionsDto.CrawlingSessionPagesResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
 	}
 
-	return dto.NewSuccessResponse(sessionsDto.CrawlingSessionPagesResponse{Data: sessionsDto.CrawlingSessionPagesData{Pages: pages, PagesTotal: total}}, http.StatusOK), nil
+	return dto.NewSuccessResponse(sessionsDto.CrawlingSessionPagesResponse{Data: sessionsDto.CrawlingSessionPagesData{
+		Columns:    columns,
+		Pages:      pages,
+		PagesTotal: total,
+	}}, http.StatusOK), nil
 }
@@ -9,9 +9,15 @@ import (
 )
 
 type service struct {
-	sessionRepo repository.CrawlingSessionRepository
-	pageRepo    repository.CrawlingSessionPageRepository
-	checkRepo   repository.CrawlingSessionCheckRepository
+	sessionRepo  repository.CrawlingSessionRepository
+	pageRepo     repository.CrawlingSessionPageRepository
+	checkRepo    repository.CrawlingSessionCheckRepository
+	snapshotRepo repository.PageSnapshotRepository
+	diffRepo     repository.SessionDiffRepository
+	auditRepo    repository.AuditCheckRepository
+	matchRepo    repository.PageMatchRepository
+	resultRepo   repository.AuditResultRepository
+	viewRepo     repository.ViewRepository
 }
 
 // NewService creates a new crawling session service.
@@ -19,6 +25,12 @@ func NewService(
 	sessionRepo repository.CrawlingSessionRepository,
 	pageRepo repository.CrawlingSessionPageRepository,
 	checkRepo repository.CrawlingSessionCheckRepository,
+	snapshotRepo repository.PageSnapshotRepository,
+	diffRepo repository.SessionDiffRepository,
+	auditRepo repository.AuditCheckRepository,
+	matchRepo repository.PageMatchRepository,
+	resultRepo repository.AuditResultRepository,
+	viewRepo repository.ViewRepository,
 ) Service {
 	if sessionRepo == nil {
 		panic("crawling session repository required")
@@ -29,10 +41,34 @@ func NewService(
 	if checkRepo == nil {
 		panic("check repository required")
 	}
+	if snapshotRepo == nil {
+		panic("page snapshot repository required")
+	}
+	if diffRepo == nil {
+		panic("session diff repository required")
+	}
+	if auditRepo == nil {
+		panic("audit check repository required")
+	}
+	if matchRepo == nil {
+		panic("page match repository required")
+	}
+	if resultRepo == nil {
+		panic("audit result repository required")
+	}
+	if viewRepo == nil {
+		panic("view repository required")
+	}
 	return &service{
-		sessionRepo: sessionRepo,
-		pageRepo:    pageRepo,
-		checkRepo:   checkRepo,
+		sessionRepo:  sessionRepo,
+		pageRepo:     pageRepo,
+		checkRepo:    checkRepo,
+		snapshotRepo: snapshotRepo,
+		diffRepo:     diffRepo,
+		auditRepo:    auditRepo,
+		matchRepo:    matchRepo,
+		resultRepo:   resultRepo,
+		viewRepo:     viewRepo,
 	}
 }
 
@@ -42,4 +78,8 @@ type Service interface {
 	Get(ctx context.Context, req sessionsDto.GetCrawlingSessionRequest) (*dto.Response[sessionsDto.CrawlingSessionResponse], error)
 	ListPages(ctx context.Context, req sessionsDto.ListCrawlingSessionPagesRequest) (*dto.Response[sessionsDto.CrawlingSessionPagesResponse], error)
 	ListChecks(ctx context.Context, req sessionsDto.ListCrawlingSessionChecksRequest) (*dto.Response[sessionsDto.CrawlingSessionChecksResponse], error)
+	ListDuplicates(ctx context.Context, req sessionsDto.ListDuplicatesRequest) (*dto.Response[sessionsDto.DuplicatesResponse], error)
+	ListHreflang(ctx context.Context, req sessionsDto.ListHreflangRequest) (*dto.Response[sessionsDto.HreflangResponse], error)
+	ListAuditResults(ctx context.Context, req sessionsDto.ListAuditResultsRequest) (*dto.Response[sessionsDto.AuditResultsResponse], error)
+	Diff(ctx context.Context, req sessionsDto.SessionDiffRequest) (*dto.Response[sessionsDto.SessionDiffResponse], error)
 }
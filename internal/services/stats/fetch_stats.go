@@ -7,6 +7,7 @@ import (
 
 	statsDto "sitecrawler/newgo/dto/stats"
 	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/viewsettings"
 )
 
 func (s *service) Fetch(ctx context.Context, req statsDto.StatsRequest) (*dto.Response[statsDto.StatsResponse], error) {
@@ -17,6 +18,24 @@ func (s *service) Fetch(ctx context.Context, req statsDto.StatsRequest) (*dto.Re
 		Prefilters:          req.Prefilters,
 	}
 
+	groupBy, err := statsGroupBy(req)
+	if err != nil {
+		return dto.NewResponse[statsDto.StatsResponse](false, err.Error(), http.StatusBadRequest, nil), nil
+	}
+	params.GroupBy = groupBy
+
+	if req.ViewID != 0 {
+		session, v, status, err := viewsettings.LoadForSession(ctx, s.sessionRepo, s.viewRepo, req.CrawlingSessionID, req.ViewID)
+		if err != nil {
+			return dto.NewResponse[statsDto.StatsResponse](false, err.Error(), status, nil), nil
+		}
+		params.FilterConfig = viewsettings.FilterConfig(v)
+		params.ComparisonSessionID, err = viewsettings.Comparison(ctx, s.sessionRepo, v, session, params.ComparisonSessionID)
+		if err != nil {
+			return dto.NewResponse[statsDto.StatsResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+		}
+	}
+
 	data, err := s.statsRepo.Fetch(ctx, params)
 	if err != nil {
 		return dto.NewResponse[statsDto.StatsResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
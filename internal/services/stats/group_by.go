@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	statsDto "sitecrawler/newgo/dto/stats"
+	"sitecrawler/newgo/internal/repository"
+)
+
+const (
+	defaultGroupSegments = 1
+	maxGroupSegments     = 10
+	maxGroupPatternLen   = 200
+)
+
+// statsGroupBy validates the request's grouping. It returns nil when the
+// stats are not grouped.
+func statsGroupBy(req statsDto.StatsRequest) (*repository.StatsGroupBy, error) {
+	if req.GroupBy == "" {
+		if req.GroupSegments != 0 || req.GroupPattern != "" {
+			return nil, errors.New("group_segments and group_pattern require group_by")
+		}
+		return nil, nil
+	}
+
+	g := &repository.StatsGroupBy{Field: req.GroupBy}
+	switch req.GroupBy {
+	case repository.StatsGroupDirectory:
+		g.Segments = req.GroupSegments
+		if g.Segments == 0 {
+			g.Segments = defaultGroupSegments
+		}
+		if g.Segments < 1 || g.Segments > maxGroupSegments {
+			return nil, fmt.Errorf("group_segments must be between 1 and %d", maxGroupSegments)
+		}
+	case repository.StatsGroupRegex:
+		if req.GroupPattern == "" {
+			return nil, errors.New("group_pattern is required for group_by regex")
+		}
+		if len(req.GroupPattern) > maxGroupPatternLen {
+			return nil, fmt.Errorf("group_pattern must be at most %d characters", maxGroupPatternLen)
+		}
+		if _, err := regexp.Compile(req.GroupPattern); err != nil {
+			return nil, fmt.Errorf("invalid group_pattern: %w", err)
+		}
+		g.Pattern = req.GroupPattern
+	case repository.StatsGroupSubdomain, repository.StatsGroupDepth, repository.StatsGroupContentType, repository.StatsGroupStatusClass:
+	default:
+		return nil, fmt.Errorf("invalid group_by: %s", req.GroupBy)
+	}
+	if g.Field != repository.StatsGroupDirectory && req.GroupSegments != 0 {
+		return nil, errors.New("group_segments only applies to group_by directory")
+	}
+	if g.Field != repository.StatsGroupRegex && req.GroupPattern != "" {
+		return nil, errors.New("group_pattern only applies to group_by regex")
+	}
+	return g, nil
+}
@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	statsDto "sitecrawler/newgo/dto/stats"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/series"
+	"sitecrawler/newgo/models"
+)
+
+// defaultHistoryLimit caps the sessions History computes stats for when the
+// request does not ask for fewer; every session costs a full stats Fetch.
+const defaultHistoryLimit = 100
+
+// History returns a /api/stats metric for the most recent finished sessions
+// of a SKU. Sessions whose stats do not contain the metric as a number are
+// left out.
+func (s *service) History(ctx context.Context, req statsDto.MetricHistoryRequest) (*dto.Response[statsDto.MetricHistoryResponse], error) {
+	sessions, err := s.sessionRepo.ListDoneBySKU(ctx, req.SearchKeywordURLID, req.From, req.To)
+	if err != nil {
+		return dto.NewResponse[statsDto.MetricHistoryResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if len(sessions) > limit {
+		sessions = sessions[len(sessions)-limit:]
+	}
+
+	points := make([]models.HistoryPoint, 0, len(sessions))
+	for _, session := range sessions {
+		data, err := s.statsRepo.Fetch(ctx, repository.StatsQueryParams{SessionID: session.ID})
+		if err != nil {
+			return dto.NewResponse[statsDto.MetricHistoryResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+		value, ok := series.MetricValue(data, req.Metric)
+		if !ok {
+			continue
+		}
+		points = append(points, models.HistoryPoint{SessionID: session.ID, EndedAt: *session.EndedAt, Value: value, Sessions: 1})
+	}
+
+	return dto.NewSuccessResponse(statsDto.MetricHistoryResponse{Data: statsDto.MetricHistoryData{
+		Metric: req.Metric,
+		Points: series.Downsample(points, req.Points),
+	}}, http.StatusOK), nil
+}
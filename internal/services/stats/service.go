@@ -11,19 +11,34 @@ import (
 type service struct {
 	statsRepo       repository.StatsRepository
 	pageDetailsRepo repository.PageDetailsRepository
+	sessionRepo     repository.CrawlingSessionRepository
+	viewRepo        repository.ViewRepository
 }
 
 // NewService creates a new stats service.
-func NewService(statsRepo repository.StatsRepository, pageDetailsRepo repository.PageDetailsRepository) Service {
+func NewService(
+	statsRepo repository.StatsRepository,
+	pageDetailsRepo repository.PageDetailsRepository,
+	sessionRepo repository.CrawlingSessionRepository,
+	viewRepo repository.ViewRepository,
+) Service {
 	if statsRepo == nil {
 		panic("stats repository required")
 	}
 	if pageDetailsRepo == nil {
 		panic("page details repository required")
 	}
+	if sessionRepo == nil {
+		panic("crawling session repository required")
+	}
+	if viewRepo == nil {
+		panic("view repository required")
+	}
 	return &service{
 		statsRepo:       statsRepo,
 		pageDetailsRepo: pageDetailsRepo,
+		sessionRepo:     sessionRepo,
+		viewRepo:        viewRepo,
 	}
 }
 
@@ -31,4 +46,5 @@ func NewService(statsRepo repository.StatsRepository, pageDetailsRepo repository
 type Service interface {
 	Fetch(ctx context.Context, req statsDto.StatsRequest) (*dto.Response[statsDto.StatsResponse], error)
 	Details(ctx context.Context, req statsDto.PageDetailsRequest) (*dto.Response[statsDto.PageDetailsResponse], error)
+	History(ctx context.Context, req statsDto.MetricHistoryRequest) (*dto.Response[statsDto.MetricHistoryResponse], error)
 }
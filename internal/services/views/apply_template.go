@@ -0,0 +1,68 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// ApplyTemplate creates a linked copy of the template for every SKU that
+// does not have one yet.
+func (s *service) ApplyTemplate(ctx context.Context, req viewsDto.ApplyViewTemplateRequest) (*dto.Response[viewsDto.ApplyViewTemplateResponse], error) {
+	template, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[viewsDto.ApplyViewTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[viewsDto.ApplyViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	applied, err := s.viewRepo.ListByTemplate(ctx, template.ID)
+	if err != nil {
+		return dto.NewResponse[viewsDto.ApplyViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	bySKU := make(map[int64]models.View, len(applied))
+	for _, v := range applied {
+		bySKU[v.SearchKeywordURLID] = v
+	}
+
+	out := viewsDto.ApplyViewTemplateResult{
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Applied:    []int64{},
+		Existing:   []int64{},
+		Views:      []models.View{},
+	}
+	seen := make(map[int64]bool, len(req.Data.SearchKeywordURLIDs))
+	for _, skuID := range req.Data.SearchKeywordURLIDs {
+		if seen[skuID] {
+			continue
+		}
+		seen[skuID] = true
+		if v, ok := bySKU[skuID]; ok {
+			out.Existing = append(out.Existing, skuID)
+			out.Views = append(out.Views, v)
+			continue
+		}
+		templateID := template.ID
+		v := models.View{
+			SearchKeywordURLID: skuID,
+			Name:               template.Name,
+			FilterConfig:       template.FilterConfig,
+			TemplateID:         &templateID,
+			TemplateVersion:    template.Version,
+		}
+		if err := s.viewRepo.Create(ctx, &v); err != nil {
+			return dto.NewResponse[viewsDto.ApplyViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+		out.Applied = append(out.Applied, skuID)
+		out.Views = append(out.Views, v)
+	}
+
+	return dto.NewSuccessResponse(viewsDto.ApplyViewTemplateResponse{Data: out}, http.StatusOK), nil
+}
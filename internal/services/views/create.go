@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"sitecrawler/newgo/dto"
+	"strings"
 
 	viewsDto "sitecrawler/newgo/dto/views"
 	"sitecrawler/newgo/models"
@@ -11,9 +12,14 @@ import (
 
 func (s *service) Create(ctx context.Context, req viewsDto.CreateViewRequest) (*dto.Response[viewsDto.ViewResponse], error) {
 	view := &models.View{
-		SearchKeywordURLID: req.Data.SearchKeywordURLID,
-		Name:               req.Data.Name,
-		FilterConfig:       req.Data.FilterConfig,
+		SearchKeywordURLID:  req.Data.SearchKeywordURLID,
+		Name:                req.Data.Name,
+		FilterConfig:        req.Data.FilterConfig,
+		Columns:             req.Data.Columns,
+		SortField:           req.Data.Sort,
+		SortDirection:       strings.ToLower(req.Data.Direction),
+		PageSize:            req.Data.PageSize,
+		CompareWithPrevious: req.Data.CompareWithPrevious,
 	}
 
 	if err := s.viewRepo.Create(ctx, view); err != nil {
@@ -0,0 +1,24 @@
+package views
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/models"
+)
+
+func (s *service) CreateTemplate(ctx context.Context, req viewsDto.CreateViewTemplateRequest) (*dto.Response[viewsDto.ViewTemplateResponse], error) {
+	template := &models.ViewTemplate{
+		Name:         req.Data.Name,
+		FilterConfig: req.Data.FilterConfig,
+		Version:      1,
+	}
+
+	if err := s.templates.Create(ctx, template); err != nil {
+		return dto.NewResponse[viewsDto.ViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(viewsDto.ViewTemplateResponse{Data: *template}, http.StatusCreated), nil
+}
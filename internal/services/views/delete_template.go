@@ -0,0 +1,41 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+)
+
+// DeleteTemplate unlinks the applied views before removing the template;
+// the views themselves stay in place.
+func (s *service) DeleteTemplate(ctx context.Context, req viewsDto.DeleteViewTemplateRequest) (*dto.Response[viewsDto.DeleteViewTemplateResponse], error) {
+	if _, err := s.templates.Get(ctx, req.ID); err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[viewsDto.DeleteViewTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[viewsDto.DeleteViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	applied, err := s.viewRepo.ListByTemplate(ctx, req.ID)
+	if err != nil {
+		return dto.NewResponse[viewsDto.DeleteViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	for i := range applied {
+		applied[i].TemplateID = nil
+		applied[i].TemplateVersion = 0
+		if err := s.viewRepo.Update(ctx, &applied[i]); err != nil {
+			return dto.NewResponse[viewsDto.DeleteViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+		}
+	}
+
+	if err := s.templates.Delete(ctx, req.ID); err != nil {
+		return dto.NewResponse[viewsDto.DeleteViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	result := viewsDto.DeleteViewTemplateData{ID: req.ID, Unlinked: len(applied)}
+	return dto.NewSuccessResponse(viewsDto.DeleteViewTemplateResponse{Data: result}, http.StatusOK), nil
+}
@@ -0,0 +1,23 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+)
+
+func (s *service) GetTemplate(ctx context.Context, req viewsDto.GetViewTemplateRequest) (*dto.Response[viewsDto.ViewTemplateResponse], error) {
+	template, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[viewsDto.ViewTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[viewsDto.ViewTemplateResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	return dto.NewSuccessResponse(viewsDto.ViewTemplateResponse{Data: *template}, http.StatusOK), nil
+}
@@ -6,7 +6,6 @@ import (
 	"sitecrawler/newgo/dto"
 
 	viewsDto "sitecrawler/newgo/dto/views"
-	"sitecrawler/newgo/internal/repository"
 	"sitecrawler/newgo/models"
 )
 
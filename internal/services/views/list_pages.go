@@ -0,0 +1,45 @@
+package views
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/internal/viewsettings"
+	"sitecrawler/newgo/models"
+)
+
+// ListPages lists the session's pages matched by the view's filter_config,
+// narrowed further by the request's ad-hoc filters. The view's sort and page
+// size apply unless the request sets its own.
+func (s *service) ListPages(ctx context.Context, req viewsDto.ListViewPagesRequest) (*dto.Response[viewsDto.ViewPagesResponse], error) {
+	_, v, status, err := viewsettings.LoadForSession(ctx, s.sessionRepo, s.viewRepo, req.SessionID, req.ViewID)
+	if err != nil {
+		return dto.NewResponse[viewsDto.ViewPagesResponse](false, err.Error(), status, nil), nil
+	}
+
+	params := repository.PageListParams{
+		SessionID:    req.SessionID,
+		FilterConfig: viewsettings.FilterConfig(v),
+		Filters:      req.Filters,
+		Page:         req.Page,
+	}
+	params.Sort, params.Direction, params.PageLimit = viewsettings.Listing(v, req.Sort, req.Direction, req.PageLimit)
+
+	pages, total, err := s.pageRepo.List(ctx, params)
+	if err != nil {
+		return dto.NewResponse[viewsDto.ViewPagesResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+	if pages == nil {
+		pages = []models.Page{}
+	}
+
+	return dto.NewSuccessResponse(viewsDto.ViewPagesResponse{Data: viewsDto.ViewPagesData{
+		ViewID:     v.ID,
+		Columns:    v.Columns,
+		Pages:      pages,
+		PagesTotal: total,
+	}}, http.StatusOK), nil
+}
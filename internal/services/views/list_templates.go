@@ -0,0 +1,18 @@
+package views
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+)
+
+func (s *service) ListTemplates(ctx context.Context) (*dto.Response[viewsDto.ViewTemplatesResponse], error) {
+	templates, err := s.templates.List(ctx)
+	if err != nil {
+		return dto.NewResponse[viewsDto.ViewTemplatesResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(viewsDto.ViewTemplatesResponse{Data: templates}, http.StatusOK), nil
+}
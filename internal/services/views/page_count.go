@@ -6,36 +6,20 @@ import (
 	"sitecrawler/newgo/dto"
 
 	viewsDto "sitecrawler/newgo/dto/views"
-	"sitecrawler/newgo/internal/repository"
 )
 
+// PageCount counts the session's pages matched by the view's filter_config:
+// filter groups are ORed and the filters of a group ANDed, as for audit checks.
 func (s *service) PageCount(ctx context.Context, req viewsDto.ViewPageCountRequest) (*dto.Response[viewsDto.ViewPageCountResponse], error) {
 	v, err := s.viewRepo.Get(ctx, req.ViewID)
 	if err != nil {
 		return dto.NewResponse[viewsDto.ViewPageCountResponse](false, err.Error(), http.StatusNotFound, nil), nil
 	}
 
-	// Build filter from view's filter_config and use the page repo's List method
-	var filters []map[string]any
-	if fg, ok := v.FilterConfig["filter_groups"].([]any); ok {
-		for _, f := range fg {
-			if m, ok := f.(map[string]any); ok {
-				filters = append(filters, m)
-			}
-		}
-	}
-
-	params := repository.PageListParams{
-		SessionID: req.SessionID,
-		Filters:   filters,
-		PageLimit: 1,
-		Page:      1,
-	}
-
-	_, total, err := s.pageRepo.List(ctx, params)
+	counts, err := s.matchRepo.CountMatches(ctx, req.SessionID, []map[string]any{v.FilterConfig})
 	if err != nil {
 		return dto.NewResponse[viewsDto.ViewPageCountResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
 	}
 
-	return dto.NewSuccessResponse(viewsDto.ViewPageCountResponse{Data: viewsDto.ViewPageCountData{PageCount: total}}, http.StatusOK), nil
+	return dto.NewSuccessResponse(viewsDto.ViewPageCountResponse{Data: viewsDto.ViewPageCountData{PageCount: counts[0]}}, http.StatusOK), nil
 }
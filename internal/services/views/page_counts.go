@@ -0,0 +1,76 @@
+package views
+
+import (
+	"context"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/viewsettings"
+	"sitecrawler/newgo/models"
+)
+
+// PageCounts counts the view's matched pages in each requested session.
+func (s *service) PageCounts(ctx context.Context, req viewsDto.ViewPageCountsRequest) (*dto.Response[viewsDto.ViewPageCountsResponse], error) {
+	counts, status, err := s.countViewPages(ctx, []int64{req.ViewID}, req.SessionIDs)
+	if err != nil {
+		return dto.NewResponse[viewsDto.ViewPageCountsResponse](false, err.Error(), status, nil), nil
+	}
+	return dto.NewSuccessResponse(viewsDto.ViewPageCountsResponse{Data: counts[0]}, http.StatusOK), nil
+}
+
+// BatchPageCounts counts the matched pages of several views in each
+// requested session.
+func (s *service) BatchPageCounts(ctx context.Context, req viewsDto.BatchViewPageCountsRequest) (*dto.Response[viewsDto.BatchViewPageCountsResponse], error) {
+	counts, status, err := s.countViewPages(ctx, req.Data.ViewIDs, req.Data.CrawlingSessionIDs)
+	if err != nil {
+		return dto.NewResponse[viewsDto.BatchViewPageCountsResponse](false, err.Error(), status, nil), nil
+	}
+	return dto.NewSuccessResponse(viewsDto.BatchViewPageCountsResponse{Data: counts}, http.StatusOK), nil
+}
+
+// countViewPages evaluates every view's filter_config against every session
+// in one grouped query. A missing view or session answers 404, a view and
+// session of different SKUs 400.
+func (s *service) countViewPages(ctx context.Context, viewIDs, sessionIDs []int64) ([]viewsDto.ViewPageCounts, int, error) {
+	sessions := make([]*models.CrawlingSession, len(sessionIDs))
+	for i, id := range sessionIDs {
+		session, status, err := viewsettings.LoadSession(ctx, s.sessionRepo, id)
+		if err != nil {
+			return nil, status, err
+		}
+		sessions[i] = session
+	}
+
+	configs := make([]map[string]any, len(viewIDs))
+	for i, id := range viewIDs {
+		v, err := s.viewRepo.Get(ctx, id)
+		if err != nil {
+			return nil, viewsettings.LoadStatus(err), err
+		}
+		for _, session := range sessions {
+			if v.SearchKeywordURLID != session.SearchKeywordURLID {
+				return nil, http.StatusBadRequest, viewsettings.ErrOtherSKU
+			}
+		}
+		configs[i] = viewsettings.FilterConfig(v)
+	}
+
+	bySession, err := s.matchRepo.CountMatchesBySession(ctx, sessionIDs, configs)
+	if err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+
+	out := make([]viewsDto.ViewPageCounts, len(viewIDs))
+	for i, id := range viewIDs {
+		counts := make([]viewsDto.SessionPageCount, len(sessionIDs))
+		for j, sid := range sessionIDs {
+			counts[j] = viewsDto.SessionPageCount{CrawlingSessionID: sid}
+			if c, ok := bySession[sid]; ok {
+				counts[j].PageCount = c[i]
+			}
+		}
+		out[i] = viewsDto.ViewPageCounts{ViewID: id, Counts: counts}
+	}
+	return out, http.StatusOK, nil
+}
@@ -0,0 +1,38 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+)
+
+const defaultPreviewLimit = 20
+
+// Preview evaluates an unsaved filter_config against a session the same way
+// PageCount evaluates a saved view.
+func (s *service) Preview(ctx context.Context, req viewsDto.PreviewViewRequest) (*dto.Response[viewsDto.ViewPreviewResponse], error) {
+	if _, err := s.sessionRepo.GetByID(ctx, req.Data.CrawlingSessionID); err != nil {
+		if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+			return dto.NewResponse[viewsDto.ViewPreviewResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[viewsDto.ViewPreviewResponse](false, err.Error(), http.StatusInternalServerError, nil), nil
+	}
+
+	limit := req.Data.Limit
+	if limit <= 0 {
+		limit = defaultPreviewLimit
+	}
+	pages, total, err := s.matchRepo.ListMatches(ctx, req.Data.CrawlingSessionID, req.Data.FilterConfig, 1, limit)
+	if err != nil {
+		return dto.NewResponse[viewsDto.ViewPreviewResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(viewsDto.ViewPreviewResponse{Data: viewsDto.ViewPreviewData{
+		PageCount: total,
+		Pages:     pages,
+	}}, http.StatusOK), nil
+}
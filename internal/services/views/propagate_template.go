@@ -0,0 +1,68 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// PropagateTemplate copies the current template definition onto applied
+// views that are behind its version. Views edited by a user are skipped.
+func (s *service) PropagateTemplate(ctx context.Context, req viewsDto.PropagateViewTemplateRequest) (*dto.Response[viewsDto.PropagateViewTemplateResponse], error) {
+	template, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[viewsDto.PropagateViewTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[viewsDto.PropagateViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	applied, err := s.viewRepo.ListByTemplate(ctx, template.ID)
+	if err != nil {
+		return dto.NewResponse[viewsDto.PropagateViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	var only map[int64]bool
+	if len(req.Data.SearchKeywordURLIDs) > 0 {
+		only = make(map[int64]bool, len(req.Data.SearchKeywordURLIDs))
+		for _, id := range req.Data.SearchKeywordURLIDs {
+			only[id] = true
+		}
+	}
+
+	out := viewsDto.PropagateViewTemplateResult{
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Updated:    []int64{},
+		Skipped:    []int64{},
+		Unchanged:  []int64{},
+		Views:      []models.View{},
+	}
+	for _, v := range applied {
+		if only != nil && !only[v.SearchKeywordURLID] {
+			continue
+		}
+		switch {
+		case v.TemplateVersion >= template.Version:
+			out.Unchanged = append(out.Unchanged, v.ID)
+		case v.Customized:
+			out.Skipped = append(out.Skipped, v.ID)
+		default:
+			v.Name = template.Name
+			v.FilterConfig = template.FilterConfig
+			v.TemplateVersion = template.Version
+			if err := s.viewRepo.Update(ctx, &v); err != nil {
+				return dto.NewResponse[viewsDto.PropagateViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+			}
+			out.Updated = append(out.Updated, v.ID)
+		}
+		out.Views = append(out.Views, v)
+	}
+
+	return dto.NewSuccessResponse(viewsDto.PropagateViewTemplateResponse{Data: out}, http.StatusOK), nil
+}
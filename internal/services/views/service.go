@@ -9,21 +9,36 @@ import (
 )
 
 type service struct {
-	viewRepo repository.ViewRepository
-	pageRepo repository.CrawlingSessionPageRepository
+	viewRepo    repository.ViewRepository
+	sessionRepo repository.CrawlingSessionRepository
+	pageRepo    repository.CrawlingSessionPageRepository
+	matchRepo   repository.PageMatchRepository
+	templates   repository.ViewTemplateRepository
 }
 
 // NewService creates a new view service.
-func NewService(viewRepo repository.ViewRepository, pageRepo repository.CrawlingSessionPageRepository) Service {
+func NewService(viewRepo repository.ViewRepository, sessionRepo repository.CrawlingSessionRepository, pageRepo repository.CrawlingSessionPageRepository, matchRepo repository.PageMatchRepository, templates repository.ViewTemplateRepository) Service {
 	if viewRepo == nil {
 		panic("view repository required")
 	}
+	if sessionRepo == nil {
+		panic("crawling session repository required")
+	}
 	if pageRepo == nil {
 		panic("page repository required")
 	}
+	if matchRepo == nil {
+		panic("page match repository required")
+	}
+	if templates == nil {
+		panic("view template repository required")
+	}
 	return &service{
-		viewRepo: viewRepo,
-		pageRepo: pageRepo,
+		viewRepo:    viewRepo,
+		sessionRepo: sessionRepo,
+		pageRepo:    pageRepo,
+		matchRepo:   matchRepo,
+		templates:   templates,
 	}
 }
 
@@ -35,4 +50,15 @@ type Service interface {
 	Update(ctx context.Context, req viewsDto.UpdateViewRequest) (*dto.Response[viewsDto.ViewResponse], error)
 	Delete(ctx context.Context, req viewsDto.DeleteViewRequest) (*dto.Response[viewsDto.DeleteViewResponse], error)
 	PageCount(ctx context.Context, req viewsDto.ViewPageCountRequest) (*dto.Response[viewsDto.ViewPageCountResponse], error)
+	PageCounts(ctx context.Context, req viewsDto.ViewPageCountsRequest) (*dto.Response[viewsDto.ViewPageCountsResponse], error)
+	BatchPageCounts(ctx context.Context, req viewsDto.BatchViewPageCountsRequest) (*dto.Response[viewsDto.BatchViewPageCountsResponse], error)
+	ListPages(ctx context.Context, req viewsDto.ListViewPagesRequest) (*dto.Response[viewsDto.ViewPagesResponse], error)
+	Preview(ctx context.Context, req viewsDto.PreviewViewRequest) (*dto.Response[viewsDto.ViewPreviewResponse], error)
+	ListTemplates(ctx context.Context) (*dto.Response[viewsDto.ViewTemplatesResponse], error)
+	CreateTemplate(ctx context.Context, req viewsDto.CreateViewTemplateRequest) (*dto.Response[viewsDto.ViewTemplateResponse], error)
+	GetTemplate(ctx context.Context, req viewsDto.GetViewTemplateRequest) (*dto.Response[viewsDto.ViewTemplateResponse], error)
+	UpdateTemplate(ctx context.Context, req viewsDto.UpdateViewTemplateRequest) (*dto.Response[viewsDto.ViewTemplateResponse], error)
+	DeleteTemplate(ctx context.Context, req viewsDto.DeleteViewTemplateRequest) (*dto.Response[viewsDto.DeleteViewTemplateResponse], error)
+	ApplyTemplate(ctx context.Context, req viewsDto.ApplyViewTemplateRequest) (*dto.Response[viewsDto.ApplyViewTemplateResponse], error)
+	PropagateTemplate(ctx context.Context, req viewsDto.PropagateViewTemplateRequest) (*dto.Response[viewsDto.PropagateViewTemplateResponse], error)
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"sitecrawler/newgo/dto"
+	"strings"
 
 	viewsDto "sitecrawler/newgo/dto/views"
 	"sitecrawler/newgo/internal/repository"
@@ -25,6 +26,25 @@ func (s *service) Update(ctx context.Context, req viewsDto.UpdateViewRequest) (*
 	if req.Data.FilterConfig != nil {
 		existing.FilterConfig = *req.Data.FilterConfig
 	}
+	if req.Data.Columns != nil {
+		existing.Columns = *req.Data.Columns
+	}
+	if req.Data.Sort != nil {
+		existing.SortField = *req.Data.Sort
+	}
+	if req.Data.Direction != nil {
+		existing.SortDirection = strings.ToLower(*req.Data.Direction)
+	}
+	if req.Data.PageSize != nil {
+		existing.PageSize = *req.Data.PageSize
+	}
+	if req.Data.CompareWithPrevious != nil {
+		existing.CompareWithPrevious = *req.Data.CompareWithPrevious
+	}
+	// Edited template views are left alone by template propagation.
+	if existing.TemplateID != nil {
+		existing.Customized = true
+	}
 
 	if err := s.viewRepo.Update(ctx, existing); err != nil {
 		return dto.NewResponse[viewsDto.ViewResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
@@ -0,0 +1,42 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sitecrawler/newgo/dto"
+
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+)
+
+// UpdateTemplate bumps the template version whenever the definition changes.
+// Applied views are only touched by PropagateTemplate.
+func (s *service) UpdateTemplate(ctx context.Context, req viewsDto.UpdateViewTemplateRequest) (*dto.Response[viewsDto.ViewTemplateResponse], error) {
+	existing, err := s.templates.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return dto.NewResponse[viewsDto.ViewTemplateResponse](false, err.Error(), http.StatusNotFound, nil), nil
+		}
+		return dto.NewResponse[viewsDto.ViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	updated := *existing
+	if req.Data.Name != nil {
+		updated.Name = *req.Data.Name
+	}
+	if req.Data.FilterConfig != nil {
+		updated.FilterConfig = *req.Data.FilterConfig
+	}
+	if reflect.DeepEqual(updated, *existing) {
+		return dto.NewSuccessResponse(viewsDto.ViewTemplateResponse{Data: *existing}, http.StatusOK), nil
+	}
+	updated.Version++
+
+	if err := s.templates.Update(ctx, &updated); err != nil {
+		return dto.NewResponse[viewsDto.ViewTemplateResponse](false, err.Error(), http.StatusUnprocessableEntity, nil), nil
+	}
+
+	return dto.NewSuccessResponse(viewsDto.ViewTemplateResponse{Data: updated}, http.StatusOK), nil
+}
@@ -0,0 +1,140 @@
+// Package viewsettings applies the saved settings of a view to the page,
+// stats and checks requests that name it with view_id.
+package viewsettings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sitecrawler/newgo/internal/filterconfig"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+// MaxPageSize bounds the page size a view can save.
+const MaxPageSize = 500
+
+// ErrOtherSKU is returned when a view is used with a session of another SKU.
+var ErrOtherSKU = errors.New("view belongs to another search keyword url")
+
+// Validate reports the first problem in a view's saved listing settings.
+func Validate(columns []string, sort, direction string, pageSize int) error {
+	for _, col := range columns {
+		if !filterconfig.ValidColumn(col) {
+			return fmt.Errorf("invalid column: %s", col)
+		}
+	}
+	if sort != "" && !filterconfig.ValidColumn(sort) {
+		return fmt.Errorf("invalid sort column: %s", sort)
+	}
+	if err := ValidateDirection(direction); err != nil {
+		return err
+	}
+	if pageSize < 0 || pageSize > MaxPageSize {
+		return fmt.Errorf("page_size must be between 0 and %d", MaxPageSize)
+	}
+	return nil
+}
+
+// ValidateDirection accepts asc, desc or empty, in any case.
+func ValidateDirection(direction string) error {
+	switch strings.ToLower(direction) {
+	case "", "asc", "desc":
+		return nil
+	}
+	return errors.New("direction must be asc or desc")
+}
+
+// Load returns the view, checking that it can be applied to session.
+func Load(ctx context.Context, repo repository.ViewRepository, viewID int64, session *models.CrawlingSession) (*models.View, error) {
+	v, err := repo.Get(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if v.SearchKeywordURLID != session.SearchKeywordURLID {
+		return nil, ErrOtherSKU
+	}
+	return v, nil
+}
+
+// LoadForSession loads a session and the view a request applies to it. When
+// err is set, status is the one to answer with.
+func LoadForSession(ctx context.Context, sessionRepo repository.CrawlingSessionRepository, viewRepo repository.ViewRepository, sessionID, viewID int64) (*models.CrawlingSession, *models.View, int, error) {
+	session, status, err := LoadSession(ctx, sessionRepo, sessionID)
+	if err != nil {
+		return nil, nil, status, err
+	}
+	v, err := Load(ctx, viewRepo, viewID, session)
+	if err != nil {
+		return nil, nil, LoadStatus(err), err
+	}
+	return session, v, http.StatusOK, nil
+}
+
+// LoadSession loads the session a view is applied to. When err is set,
+// status is the one to answer with.
+func LoadSession(ctx context.Context, repo repository.CrawlingSessionRepository, sessionID int64) (*models.CrawlingSession, int, error) {
+	session, err := repo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCrawlingSessionNotFound) {
+			return nil, http.StatusNotFound, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	return session, http.StatusOK, nil
+}
+
+// LoadStatus is the status to answer a Load error with.
+func LoadStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrViewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrOtherSKU):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FilterConfig returns the view's filter_config for the repositories'
+// FilterConfig params. It is never nil: a view without filter groups matches
+// nothing rather than every page.
+func FilterConfig(v *models.View) map[string]any {
+	if v.FilterConfig == nil {
+		return map[string]any{}
+	}
+	return v.FilterConfig
+}
+
+// Listing fills the sort, direction and page size a request left empty with
+// the view's defaults. An explicit sort keeps the request's direction.
+func Listing(v *models.View, sort, direction string, pageLimit int) (string, string, int) {
+	if sort == "" {
+		sort = v.SortField
+		if direction == "" {
+			direction = v.SortDirection
+		}
+	}
+	if pageLimit <= 0 {
+		pageLimit = v.PageSize
+	}
+	return sort, direction, pageLimit
+}
+
+// Comparison returns the comparison session to use: the requested one when
+// given, otherwise the session's previous finished session when the view
+// compares with it by default.
+func Comparison(ctx context.Context, repo repository.CrawlingSessionRepository, v *models.View, session *models.CrawlingSession, requested *int64) (*int64, error) {
+	if requested != nil || !v.CompareWithPrevious {
+		return requested, nil
+	}
+	previous, err := repository.PreviousDoneSession(ctx, repo, *session)
+	if err != nil || previous == nil {
+		return nil, err
+	}
+	return &previous.ID, nil
+}
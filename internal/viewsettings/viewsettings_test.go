@@ -0,0 +1,118 @@
+package viewsettings
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+func TestListing(t *testing.T) {
+	v := &models.View{SortField: "depth", SortDirection: "desc", PageSize: 50}
+
+	tests := []struct {
+		name          string
+		sort, dir     string
+		limit         int
+		wantSort      string
+		wantDirection string
+		wantLimit     int
+	}{
+		{name: "view defaults", wantSort: "depth", wantDirection: "desc", wantLimit: 50},
+		{name: "explicit sort keeps request direction", sort: "url", wantSort: "url", wantLimit: 50},
+		{name: "explicit direction", dir: "asc", limit: 10, wantSort: "depth", wantDirection: "asc", wantLimit: 10},
+	}
+	for _, tt := range tests {
+		sort, dir, limit := Listing(v, tt.sort, tt.dir, tt.limit)
+		if sort != tt.wantSort || dir != tt.wantDirection || limit != tt.wantLimit {
+			t.Fatalf("%s: got %q %q %d", tt.name, sort, dir, limit)
+		}
+	}
+}
+
+func TestLoadAndFilterConfig(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryViewRepository()
+	_ = repo.Create(ctx, &models.View{SearchKeywordURLID: 1, Name: "All"})
+
+	v, err := Load(ctx, repo, 1, &models.CrawlingSession{SearchKeywordURLID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg := FilterConfig(v); cfg == nil || len(cfg) != 0 {
+		t.Fatalf("expected an empty non-nil config, got %#v", cfg)
+	}
+	if _, err := Load(ctx, repo, 1, &models.CrawlingSession{SearchKeywordURLID: 2}); !errors.Is(err, ErrOtherSKU) {
+		t.Fatalf("expected ErrOtherSKU, got %v", err)
+	}
+	if _, err := Load(ctx, repo, 9, &models.CrawlingSession{SearchKeywordURLID: 1}); !errors.Is(err, repository.ErrViewNotFound) {
+		t.Fatalf("expected ErrViewNotFound, got %v", err)
+	}
+}
+
+func TestLoadForSession(t *testing.T) {
+	ctx := context.Background()
+	views := repository.NewInMemoryViewRepository()
+	_ = views.Create(ctx, &models.View{SearchKeywordURLID: 1, Name: "All"})
+	sessions := repository.NewInMemoryCrawlingSessionRepository()
+	_ = sessions.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 1, URL: "https://example.com"})
+	_ = sessions.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 2, URL: "https://example.org"})
+
+	tests := []struct {
+		name      string
+		sessionID int64
+		viewID    int64
+		status    int
+	}{
+		{name: "ok", sessionID: 1, viewID: 1, status: http.StatusOK},
+		{name: "unknown session", sessionID: 9, viewID: 1, status: http.StatusNotFound},
+		{name: "unknown view", sessionID: 1, viewID: 9, status: http.StatusNotFound},
+		{name: "other sku", sessionID: 2, viewID: 1, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		session, v, status, err := LoadForSession(ctx, sessions, views, tt.sessionID, tt.viewID)
+		if status != tt.status {
+			t.Fatalf("%s: expected status %d got %d (%v)", tt.name, tt.status, status, err)
+		}
+		if (err == nil) != (tt.status == http.StatusOK) {
+			t.Fatalf("%s: unexpected error %v", tt.name, err)
+		}
+		if err == nil && (session.ID != tt.sessionID || v.ID != tt.viewID) {
+			t.Fatalf("%s: got session %d view %d", tt.name, session.ID, v.ID)
+		}
+	}
+}
+
+func TestComparison(t *testing.T) {
+	ctx := context.Background()
+	sessions := repository.NewInMemoryCrawlingSessionRepository()
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		ended := base.AddDate(0, 0, i)
+		_ = sessions.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 1, Status: "done", EndedAt: &ended})
+	}
+	current, _ := sessions.GetByID(ctx, 3)
+
+	compare := &models.View{CompareWithPrevious: true}
+	got, err := Comparison(ctx, sessions, compare, current, nil)
+	if err != nil || got == nil || *got != 2 {
+		t.Fatalf("expected previous session 2, got %v %v", got, err)
+	}
+
+	requested := int64(1)
+	if got, _ := Comparison(ctx, sessions, compare, current, &requested); got == nil || *got != 1 {
+		t.Fatalf("expected the requested session to win, got %v", got)
+	}
+	if got, _ := Comparison(ctx, sessions, &models.View{}, current, nil); got != nil {
+		t.Fatalf("expected no comparison, got %d", *got)
+	}
+
+	first, _ := sessions.GetByID(ctx, 1)
+	if got, _ := Comparison(ctx, sessions, compare, first, nil); got != nil {
+		t.Fatalf("expected no previous session, got %d", *got)
+	}
+}
@@ -9,13 +9,19 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"sitecrawler/newgo/controllers/alerts"
 	"sitecrawler/newgo/controllers/audits"
+	"sitecrawler/newgo/controllers/bundles"
 	"sitecrawler/newgo/controllers/health"
 	"sitecrawler/newgo/controllers/sessions"
 	"sitecrawler/newgo/controllers/stats"
 	"sitecrawler/newgo/controllers/views"
+	"sitecrawler/newgo/internal/alerting"
+	"sitecrawler/newgo/internal/postcrawl"
 	"sitecrawler/newgo/internal/repository"
+	alertsvc "sitecrawler/newgo/internal/services/alerts"
 	auditsvc "sitecrawler/newgo/internal/services/audits"
+	bundlesvc "sitecrawler/newgo/internal/services/bundles"
 	sessionsvc "sitecrawler/newgo/internal/services/sessions"
 	statssvc "sitecrawler/newgo/internal/services/stats"
 	viewsvc "sitecrawler/newgo/internal/services/views"
@@ -27,67 +33,167 @@ func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
 	// Initialize repositories
-	crawlingSessionRepo := repository.NewInMemoryCrawlingSessionRepository()
 	pageRepo := repository.NewNoopCrawlingSessionPageRepository()
 	checkRepo := repository.NewNoopCrawlingSessionCheckRepository()
+	snapshotRepo := repository.NewNoopPageSnapshotRepository()
+	diffRepo := repository.NewSnapshotSessionDiffRepository(snapshotRepo)
 	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	auditTemplateRepo := repository.NewInMemoryAuditCheckTemplateRepository()
+	matchRepo := repository.NewNoopPageMatchRepository()
+	auditResultRepo := repository.NewInMemoryAuditResultRepository()
+	alertRuleRepo := repository.NewInMemoryAlertRuleRepository()
+	alertRepo := repository.NewInMemoryAlertRepository()
 	viewRepo := repository.NewInMemoryViewRepository()
+	viewTemplateRepo := repository.NewInMemoryViewTemplateRepository()
 	statsRepo := repository.NewNoopStatsRepository()
+
+	// Email alerts are only sent when an SMTP server is configured.
+	notifiers := []alerting.Notifier{alerting.NewWebhookNotifier(nil)}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		notifiers = append(notifiers, alerting.NewSMTPNotifier(alerting.SMTPConfig{
+			Addr:     addr,
+			From:     getenv("SMTP_FROM", "alerts@localhost"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		}))
+	}
+
+	// Sessions marked done are evaluated by the post-crawl stages. Alerts read
+	// the audit results snapshot, so they run last.
+	sessionStore := repository.NewInMemoryCrawlingSessionRepository()
+	crawlingSessionRepo := postcrawl.NewSessionRepository(sessionStore, logger,
+		postcrawl.NewAuditResultsStage(auditRepo, matchRepo, auditResultRepo),
+		postcrawl.NewAlertsStage(alertRuleRepo, alertRepo, sessionStore, statsRepo, auditResultRepo, logger, notifiers...),
+	)
 	pageDetailsRepo := repository.NewNoopPageDetailsRepository()
 
 	// Health controller
 	healthCtrl := health.NewController(logger)
 
 	// Crawling session service and controllers
-	sessionSvc := sessionsvc.NewService(crawlingSessionRepo, pageRepo, checkRepo)
+	sessionSvc := sessionsvc.NewService(crawlingSessionRepo, pageRepo, checkRepo, snapshotRepo, diffRepo, auditRepo, matchRepo, auditResultRepo, viewRepo)
 	crawlingCreateCtrl := sessions.NewCreateController(sessionSvc, logger)
 	crawlingGetCtrl := sessions.NewGetController(sessionSvc, logger)
 	crawlingPagesCtrl := sessions.NewPagesController(sessionSvc, logger)
 	crawlingChecksCtrl := sessions.NewChecksController(sessionSvc, logger)
+	crawlingDuplicatesCtrl := sessions.NewDuplicatesController(sessionSvc, logger)
+	crawlingHreflangCtrl := sessions.NewHreflangController(sessionSvc, logger)
+	crawlingAuditResultsCtrl := sessions.NewAuditResultsController(sessionSvc, logger)
+	crawlingDiffCtrl := sessions.NewDiffController(sessionSvc, logger)
 
 	// Audit check service and controllers
-	auditSvc := auditsvc.NewService(auditRepo)
+	auditSvc := auditsvc.NewService(auditRepo, crawlingSessionRepo, matchRepo, auditResultRepo, auditTemplateRepo)
 	auditListCtrl := audits.NewListController(auditSvc, logger)
 	auditCreateCtrl := audits.NewCreateController(auditSvc, logger)
 	auditGetCtrl := audits.NewGetController(auditSvc, logger)
 	auditUpdateCtrl := audits.NewUpdateController(auditSvc, logger)
 	auditDeleteCtrl := audits.NewDeleteController(auditSvc, logger)
+	auditDefaultsCtrl := audits.NewInstallDefaultsController(auditSvc, logger)
+	auditHistoryCtrl := audits.NewHistoryController(auditSvc, logger)
+	auditPreviewCtrl := audits.NewPreviewController(auditSvc, logger)
+	auditTemplateListCtrl := audits.NewListTemplatesController(auditSvc, logger)
+	auditTemplateCreateCtrl := audits.NewCreateTemplateController(auditSvc, logger)
+	auditTemplateGetCtrl := audits.NewGetTemplateController(auditSvc, logger)
+	auditTemplateUpdateCtrl := audits.NewUpdateTemplateController(auditSvc, logger)
+	auditTemplateDeleteCtrl := audits.NewDeleteTemplateController(auditSvc, logger)
+	auditTemplateApplyCtrl := audits.NewApplyTemplateController(auditSvc, logger)
+	auditTemplatePropagateCtrl := audits.NewPropagateTemplateController(auditSvc, logger)
 
 	// View service and controllers
-	viewSvc := viewsvc.NewService(viewRepo, pageRepo)
+	viewSvc := viewsvc.NewService(viewRepo, crawlingSessionRepo, pageRepo, matchRepo, viewTemplateRepo)
 	viewListCtrl := views.NewListController(viewSvc, logger)
 	viewCreateCtrl := views.NewCreateController(viewSvc, logger)
 	viewGetCtrl := views.NewGetController(viewSvc, logger)
 	viewUpdateCtrl := views.NewUpdateController(viewSvc, logger)
 	viewDeleteCtrl := views.NewDeleteController(viewSvc, logger)
 	viewPageCountCtrl := views.NewPageCountController(viewSvc, logger)
+	viewPageCountsCtrl := views.NewPageCountsController(viewSvc, logger)
+	viewBatchPageCountsCtrl := views.NewBatchPageCountsController(viewSvc, logger)
+	viewPreviewCtrl := views.NewPreviewController(viewSvc, logger)
+	viewPagesCtrl := views.NewListPagesController(viewSvc, logger)
+	viewTemplateListCtrl := views.NewListTemplatesController(viewSvc, logger)
+	viewTemplateCreateCtrl := views.NewCreateTemplateController(viewSvc, logger)
+	viewTemplateGetCtrl := views.NewGetTemplateController(viewSvc, logger)
+	viewTemplateUpdateCtrl := views.NewUpdateTemplateController(viewSvc, logger)
+	viewTemplateDeleteCtrl := views.NewDeleteTemplateController(viewSvc, logger)
+	viewTemplateApplyCtrl := views.NewApplyTemplateController(viewSvc, logger)
+	viewTemplatePropagateCtrl := views.NewPropagateTemplateController(viewSvc, logger)
+
+	// Alert service and controllers
+	alertSvc := alertsvc.NewService(alertRuleRepo, alertRepo, auditRepo)
+	alertRuleListCtrl := alerts.NewListRulesController(alertSvc, logger)
+	alertRuleCreateCtrl := alerts.NewCreateRuleController(alertSvc, logger)
+	alertRuleGetCtrl := alerts.NewGetRuleController(alertSvc, logger)
+	alertRuleUpdateCtrl := alerts.NewUpdateRuleController(alertSvc, logger)
+	alertRuleDeleteCtrl := alerts.NewDeleteRuleController(alertSvc, logger)
+	alertListCtrl := alerts.NewListAlertsController(alertSvc, logger)
+
+	// Config bundle service and controllers
+	bundleSvc := bundlesvc.NewService(auditRepo, viewRepo)
+	configExportCtrl := bundles.NewExportController(bundleSvc, logger)
+	configImportCtrl := bundles.NewImportController(bundleSvc, logger)
 
 	// Stats service and controllers
-	statsSvc := statssvc.NewService(statsRepo, pageDetailsRepo)
+	statsSvc := statssvc.NewService(statsRepo, pageDetailsRepo, crawlingSessionRepo, viewRepo)
 	metricsCtrl := stats.NewMetricsController()
 	statsCtrl := stats.NewStatsController(statsSvc, logger)
+	statsHistoryCtrl := stats.NewHistoryController(statsSvc, logger)
 	pageDetailsCtrl := stats.NewPageDetailsController(statsSvc, logger)
 
 	routes.Register(app, routes.Dependencies{
-		Health:                healthCtrl,
-		Metrics:               metricsCtrl,
-		CrawlingSessionCreate: crawlingCreateCtrl,
-		CrawlingSessionGet:    crawlingGetCtrl,
-		CrawlingSessionPages:  crawlingPagesCtrl,
-		CrawlingSessionChecks: crawlingChecksCtrl,
-		PageDetails:           pageDetailsCtrl,
-		Stats:                 statsCtrl,
-		AuditCheckList:        auditListCtrl,
-		AuditCheckCreate:      auditCreateCtrl,
-		AuditCheckGet:         auditGetCtrl,
-		AuditCheckUpdate:      auditUpdateCtrl,
-		AuditCheckDelete:      auditDeleteCtrl,
-		ViewList:              viewListCtrl,
-		ViewCreate:            viewCreateCtrl,
-		ViewGet:               viewGetCtrl,
-		ViewUpdate:            viewUpdateCtrl,
-		ViewDelete:            viewDeleteCtrl,
-		ViewPageCount:         viewPageCountCtrl,
+		Health:                      healthCtrl,
+		Metrics:                     metricsCtrl,
+		CrawlingSessionCreate:       crawlingCreateCtrl,
+		CrawlingSessionGet:          crawlingGetCtrl,
+		CrawlingSessionPages:        crawlingPagesCtrl,
+		CrawlingSessionChecks:       crawlingChecksCtrl,
+		CrawlingSessionDuplicates:   crawlingDuplicatesCtrl,
+		CrawlingSessionHreflang:     crawlingHreflangCtrl,
+		CrawlingSessionAuditResults: crawlingAuditResultsCtrl,
+		CrawlingSessionDiff:         crawlingDiffCtrl,
+		PageDetails:                 pageDetailsCtrl,
+		Stats:                       statsCtrl,
+		StatsHistory:                statsHistoryCtrl,
+		AuditCheckList:              auditListCtrl,
+		AuditCheckCreate:            auditCreateCtrl,
+		AuditCheckGet:               auditGetCtrl,
+		AuditCheckUpdate:            auditUpdateCtrl,
+		AuditCheckDelete:            auditDeleteCtrl,
+		AuditCheckDefaults:          auditDefaultsCtrl,
+		AuditCheckHistory:           auditHistoryCtrl,
+		AuditCheckPreview:           auditPreviewCtrl,
+		AuditTemplateList:           auditTemplateListCtrl,
+		AuditTemplateCreate:         auditTemplateCreateCtrl,
+		AuditTemplateGet:            auditTemplateGetCtrl,
+		AuditTemplateUpdate:         auditTemplateUpdateCtrl,
+		AuditTemplateDelete:         auditTemplateDeleteCtrl,
+		AuditTemplateApply:          auditTemplateApplyCtrl,
+		AuditTemplatePropagate:      auditTemplatePropagateCtrl,
+		ViewList:                    viewListCtrl,
+		ViewCreate:                  viewCreateCtrl,
+		ViewGet:                     viewGetCtrl,
+		ViewUpdate:                  viewUpdateCtrl,
+		ViewDelete:                  viewDeleteCtrl,
+		ViewPageCount:               viewPageCountCtrl,
+		ViewPageCounts:              viewPageCountsCtrl,
+		ViewBatchPageCounts:         viewBatchPageCountsCtrl,
+		ViewPreview:                 viewPreviewCtrl,
+		ViewPages:                   viewPagesCtrl,
+		ViewTemplateList:            viewTemplateListCtrl,
+		ViewTemplateCreate:          viewTemplateCreateCtrl,
+		ViewTemplateGet:             viewTemplateGetCtrl,
+		ViewTemplateUpdate:          viewTemplateUpdateCtrl,
+		ViewTemplateDelete:          viewTemplateDeleteCtrl,
+		ViewTemplateApply:           viewTemplateApplyCtrl,
+		ViewTemplatePropagate:       viewTemplatePropagateCtrl,
+		AlertRuleList:               alertRuleListCtrl,
+		AlertRuleCreate:             alertRuleCreateCtrl,
+		AlertRuleGet:                alertRuleGetCtrl,
+		AlertRuleUpdate:             alertRuleUpdateCtrl,
+		AlertRuleDelete:             alertRuleDeleteCtrl,
+		AlertList:                   alertListCtrl,
+		ConfigExport:                configExportCtrl,
+		ConfigImport:                configImportCtrl,
 	})
 
 	addr := getenv("ADDR", ":8080")
@@ -38,16 +38,212 @@ type Health struct {
 }
 
 type Page struct {
-	ID                int64  `json:"id"`
-	CrawlingSessionID int64  `json:"crawling_session_id"`
-	URL               string `json:"url"`
-	ResponseCode      int    `json:"response_code"`
+	ID                 int64            `json:"id"`
+	CrawlingSessionID  int64            `json:"crawling_session_id"`
+	URL                string           `json:"url"`
+	ResponseCode       int              `json:"response_code"`
+	Indexability       string           `json:"indexability,omitempty"`
+	IndexabilityReason string           `json:"indexability_reason,omitempty"`
+	Performance        *PagePerformance `json:"performance,omitempty"`
 }
 
+// Indexability statuses stored in pages.indexability.
+const (
+	IndexabilityIndexable    = "indexable"
+	IndexabilityNonIndexable = "non_indexable"
+)
+
+// PageIndexability is the outcome of evaluating a page's indexability signals.
+type PageIndexability struct {
+	Status string `json:"indexability"`
+	Reason string `json:"indexability_reason"`
+}
+
+// PageContentFingerprint identifies a page body for duplicate detection.
+type PageContentFingerprint struct {
+	ContentHash string `json:"content_hash"`
+	SimHash     uint64 `json:"simhash"`
+}
+
+// PageSnapshot is the subset of page state used for cross-page analysis.
+type PageSnapshot struct {
+	ID              int64
+	URL             string
+	ResponseCode    int
+	Title           string
+	MetaDescription string
+	ContentHash     string
+	SimHash         uint64
+	Canonical       string
+	Indexability    string
+}
+
+// Hreflang link sources.
+const (
+	HreflangSourceHTML    = "html"
+	HreflangSourceHeader  = "header"
+	HreflangSourceSitemap = "sitemap"
+)
+
+// HreflangLink is one alternate-language annotation declared for a page.
+type HreflangLink struct {
+	Hreflang string `json:"hreflang"`
+	Href     string `json:"href"`
+	Source   string `json:"source"`
+}
+
+// HreflangIssue is a single hreflang problem found on a page.
+type HreflangIssue struct {
+	PageID   int64  `json:"page_id"`
+	URL      string `json:"url"`
+	Type     string `json:"type"`
+	Hreflang string `json:"hreflang,omitempty"`
+	Target   string `json:"target,omitempty"`
+}
+
+// HreflangCheck groups the issues of one built-in hreflang check.
+type HreflangCheck struct {
+	Key    string          `json:"key"`
+	Name   string          `json:"name"`
+	Count  int             `json:"count"`
+	Issues []HreflangIssue `json:"issues"`
+}
+
+// Structured data formats.
+const (
+	StructuredDataJSONLD    = "json-ld"
+	StructuredDataMicrodata = "microdata"
+)
+
+// StructuredDataItem is one top-level schema.org entity found on a page.
+// Missing lists required properties absent for Type; Error is set when the
+// block could not be parsed at all.
+type StructuredDataItem struct {
+	Format     string   `json:"format"`
+	Type       string   `json:"type"`
+	Properties []string `json:"properties"`
+	Missing    []string `json:"missing,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// PagePerformance holds the request timings and transfer figures recorded
+// when a page was fetched. Timings are in milliseconds; a phase that did not
+// happen (e.g. DNS on a reused connection) is 0.
+type PagePerformance struct {
+	DNSMs            int64  `json:"dns_ms"`
+	ConnectMs        int64  `json:"connect_ms"`
+	TLSMs            int64  `json:"tls_ms"`
+	TTFBMs           int64  `json:"ttfb_ms"`
+	ResponseTimeMs   int64  `json:"response_time_ms"`
+	TransferSize     int64  `json:"transfer_size"`
+	UncompressedSize int64  `json:"uncompressed_size"`
+	ContentEncoding  string `json:"content_encoding"`
+	ResourceCount    int    `json:"resource_count"`
+}
+
+// PageSecurity is the outcome of the security header and mixed-content
+// audit of a page. MixedContent holds the http:// resources an https page
+// loads.
+type PageSecurity struct {
+	Issues       []string `json:"issues"`
+	MixedContent []string `json:"mixed_content"`
+}
+
+// Duplicate content cluster matches.
+const (
+	DuplicateMatchExact = "exact"
+	DuplicateMatchNear  = "near"
+)
+
+// DuplicateCluster groups pages sharing a title, description or body.
+// Content clusters say whether their pages share a content hash or only
+// have close SimHash fingerprints.
+type DuplicateCluster struct {
+	Key   string `json:"key"`
+	Match string `json:"match,omitempty"`
+	Size  int    `json:"size"`
+	Pages []Page `json:"pages"`
+}
+
+// Page diff change types.
+const (
+	PageChangeAdded   = "added"
+	PageChangeRemoved = "removed"
+	PageChangeChanged = "changed"
+)
+
+// PageChange is a page that appeared, disappeared or changed between two
+// sessions, matched by normalized URL. PageID is the page in the current
+// session and PreviousPageID the one in the session compared against.
+type PageChange struct {
+	Change         string            `json:"change"`
+	URL            string            `json:"url"`
+	PageID         int64             `json:"page_id,omitempty"`
+	PreviousPageID int64             `json:"previous_page_id,omitempty"`
+	Fields         []PageFieldChange `json:"fields,omitempty"`
+}
+
+// PageChangeSet is one page of a change list and the list's total.
+type PageChangeSet struct {
+	Total int          `json:"total"`
+	Pages []PageChange `json:"pages"`
+}
+
+// PageFieldChange is one field of a changed page.
+type PageFieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// CheckWithPages lists the first pages of a session matched by an audit
+// check. Error is set, and the pages left empty, when the check's
+// filter_config cannot be evaluated.
 type CheckWithPages struct {
 	ID    int64  `json:"id"`
 	Name  string `json:"name"`
 	Pages []Page `json:"pages"`
+	// Comparison is set when a comparison session was requested.
+	Comparison *CheckPageDiff `json:"comparison,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Check page diff statuses, matching pages of two sessions by URL.
+const (
+	CheckPageNew        = "new"
+	CheckPageResolved   = "resolved"
+	CheckPagePersisting = "persisting"
+)
+
+// CheckPageDiff splits the pages matched by a check in either session into
+// those that newly match, no longer match and still match. Resolved pages
+// belong to the comparison session, the others to the current one.
+type CheckPageDiff struct {
+	ComparisonSessionID int64        `json:"comparison_session_id"`
+	New                 CheckPageSet `json:"new"`
+	Resolved            CheckPageSet `json:"resolved"`
+	Persisting          CheckPageSet `json:"persisting"`
+}
+
+// CheckPageSet is the first pages of a diff status and their total count.
+type CheckPageSet struct {
+	Total int    `json:"total"`
+	Pages []Page `json:"pages"`
+}
+
+// AuditCheckResult is the outcome of evaluating one audit check's
+// filter_config against a session. The comparison fields are set only when
+// a comparison session was requested.
+type AuditCheckResult struct {
+	CheckID                int64  `json:"check_id"`
+	Name                   string `json:"name"`
+	Category               string `json:"category"`
+	Severity               string `json:"severity,omitempty"`
+	MatchedPages           int    `json:"matched_pages"`
+	ComparisonMatchedPages *int   `json:"comparison_matched_pages,omitempty"`
+	Delta                  *int   `json:"delta,omitempty"`
+	Pages                  []Page `json:"pages"`
+	Error                  string `json:"error,omitempty"`
 }
 
 type PageImage struct {
@@ -61,9 +257,23 @@ type AuditCheck struct {
 	SearchKeywordURLID int64
 	Name               string
 	Category           string
-	FilterConfig       map[string]any
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	// Severity defaults to the category's severity when empty; Weight
+	// multiplies the check's share of site health and defaults to 1 when 0.
+	Severity     string
+	Weight       float64
+	FilterConfig map[string]any
+	// BuiltinKey and BuiltinVersion identify checks installed from the
+	// built-in catalogue. Customized is set once a user edits such a check,
+	// which excludes it from catalogue upgrades.
+	BuiltinKey     string
+	BuiltinVersion int
+	Customized     bool
+	// TemplateID and TemplateVersion link checks applied from an
+	// AuditCheckTemplate; Customized also holds them back from propagation.
+	TemplateID      *int64
+	TemplateVersion int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 type View struct {
@@ -71,6 +281,123 @@ type View struct {
 	SearchKeywordURLID int64
 	Name               string
 	FilterConfig       map[string]any
+	// Columns, SortField, SortDirection and PageSize are the listing defaults
+	// applied when a request names the view; an explicit request value wins.
+	Columns       []string
+	SortField     string
+	SortDirection string
+	PageSize      int
+	// CompareWithPrevious makes stats and checks compare against the SKU's
+	// previous finished session when no comparison session is given.
+	CompareWithPrevious bool
+	// TemplateID and TemplateVersion link views applied from a ViewTemplate.
+	// Customized is set once a user edits such a view, which excludes it
+	// from template propagation.
+	TemplateID      *int64
+	TemplateVersion int
+	Customized      bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// AuditCheckTemplate is an organization-wide audit check definition that
+// can be applied to many SKUs. Version is bumped on every change so that
+// applied copies can be brought up to date.
+type AuditCheckTemplate struct {
+	ID           int64
+	Name         string
+	Category     string
+	Severity     string
+	Weight       float64
+	FilterConfig map[string]any
+	Version      int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ViewTemplate is the organization-wide counterpart of View.
+type ViewTemplate struct {
+	ID           int64
+	Name         string
+	FilterConfig map[string]any
+	Version      int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// AuditResult is the persisted outcome of one audit check for a finished
+// session. Name, category, severity and filter_config are copied from the
+// check at evaluation time so later edits do not rewrite history.
+type AuditResult struct {
+	CrawlingSessionID int64          `json:"crawling_session_id"`
+	AuditCheckID      int64          `json:"audit_check_id"`
+	Name              string         `json:"name"`
+	Category          string         `json:"category"`
+	Severity          string         `json:"severity,omitempty"`
+	FilterConfig      map[string]any `json:"filter_config"`
+	MatchedPages      int            `json:"matched_pages"`
+	SamplePageIDs     []int64        `json:"sample_page_ids"`
+	Error             string         `json:"error,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
+
+// HistoryPoint is one value of a per-session history series. A downsampled
+// point stands for Sessions consecutive sessions: SessionID and EndedAt are
+// those of the last one and Value is their average.
+type HistoryPoint struct {
+	SessionID int64     `json:"crawling_session_id"`
+	EndedAt   time.Time `json:"ended_at"`
+	Value     float64   `json:"value"`
+	Sessions  int       `json:"sessions"`
+}
+
+// DepthLevel counts the pages found at one crawl depth, in total and per
+// response status class ("2xx" to "5xx").
+type DepthLevel struct {
+	Depth         int            `json:"depth"`
+	Pages         int            `json:"pages"`
+	StatusClasses map[string]int `json:"status_classes"`
+}
+
+// AlertRule fires when a value observed at the end of a session crosses
+// Threshold. The value is either an audit check's matched pages
+// (AuditCheckID) or a dotted /api/stats metric (Metric). In "change" mode the
+// value is the difference to the previous finished session, so "site_health
+// drops by 10" is Mode "change", Operator "lte", Threshold -10.
+type AlertRule struct {
+	ID                 int64
+	SearchKeywordURLID int64
+	Name               string
+	AuditCheckID       *int64
+	Metric             string
+	Mode               string
+	Operator           string
+	Threshold          float64
+	WebhookURL         string
+	Emails             []string
+	Enabled            bool
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 }
+
+// Alert records one triggered AlertRule and how it was delivered.
+type Alert struct {
+	ID                 int64
+	RuleID             int64
+	SearchKeywordURLID int64
+	CrawlingSessionID  int64
+	RuleName           string
+	Value              float64
+	PreviousValue      *float64
+	Threshold          float64
+	Message            string
+	Deliveries         []AlertDelivery
+	CreatedAt          time.Time
+}
+
+// AlertDelivery is the outcome of sending an alert through one notifier
+// channel; Error is empty on success.
+type AlertDelivery struct {
+	Channel string `json:"channel"`
+	Error   string `json:"error,omitempty"`
+}
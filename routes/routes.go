@@ -3,7 +3,9 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"sitecrawler/newgo/controllers/alerts"
 	"sitecrawler/newgo/controllers/audits"
+	"sitecrawler/newgo/controllers/bundles"
 	"sitecrawler/newgo/controllers/health"
 	"sitecrawler/newgo/controllers/sessions"
 	"sitecrawler/newgo/controllers/stats"
@@ -11,25 +13,59 @@ import (
 )
 
 type Dependencies struct {
-	Health                *health.Controller
-	Metrics               *stats.MetricsController
-	CrawlingSessionCreate *sessions.CreateController
-	CrawlingSessionGet    *sessions.GetController
-	CrawlingSessionPages  *sessions.PagesController
-	CrawlingSessionChecks *sessions.ChecksController
-	PageDetails           *stats.PageDetailsController
-	Stats                 *stats.StatsController
-	AuditCheckList        *audits.ListController
-	AuditCheckCreate      *audits.CreateController
-	AuditCheckGet         *audits.GetController
-	AuditCheckUpdate      *audits.UpdateController
-	AuditCheckDelete      *audits.DeleteController
-	ViewList              *views.ListController
-	ViewCreate            *views.CreateController
-	ViewGet               *views.GetController
-	ViewUpdate            *views.UpdateController
-	ViewDelete            *views.DeleteController
-	ViewPageCount         *views.PageCountController
+	Health                      *health.Controller
+	Metrics                     *stats.MetricsController
+	CrawlingSessionCreate       *sessions.CreateController
+	CrawlingSessionGet          *sessions.GetController
+	CrawlingSessionPages        *sessions.PagesController
+	CrawlingSessionChecks       *sessions.ChecksController
+	CrawlingSessionDuplicates   *sessions.DuplicatesController
+	CrawlingSessionHreflang     *sessions.HreflangController
+	CrawlingSessionAuditResults *sessions.AuditResultsController
+	CrawlingSessionDiff         *sessions.DiffController
+	PageDetails                 *stats.PageDetailsController
+	Stats                       *stats.StatsController
+	StatsHistory                *stats.HistoryController
+	AuditCheckList              *audits.ListController
+	AuditCheckCreate            *audits.CreateController
+	AuditCheckGet               *audits.GetController
+	AuditCheckUpdate            *audits.UpdateController
+	AuditCheckDelete            *audits.DeleteController
+	AuditCheckDefaults          *audits.InstallDefaultsController
+	AuditCheckHistory           *audits.HistoryController
+	AuditCheckPreview           *audits.PreviewController
+	AuditTemplateList           *audits.ListTemplatesController
+	AuditTemplateCreate         *audits.CreateTemplateController
+	AuditTemplateGet            *audits.GetTemplateController
+	AuditTemplateUpdate         *audits.UpdateTemplateController
+	AuditTemplateDelete         *audits.DeleteTemplateController
+	AuditTemplateApply          *audits.ApplyTemplateController
+	AuditTemplatePropagate      *audits.PropagateTemplateController
+	ViewList                    *views.ListController
+	ViewCreate                  *views.CreateController
+	ViewGet                     *views.GetController
+	ViewUpdate                  *views.UpdateController
+	ViewDelete                  *views.DeleteController
+	ViewPageCount               *views.PageCountController
+	ViewPageCounts              *views.PageCountsController
+	ViewBatchPageCounts         *views.BatchPageCountsController
+	ViewPreview                 *views.PreviewController
+	ViewPages                   *views.ListPagesController
+	ViewTemplateList            *views.ListTemplatesController
+	ViewTemplateCreate          *views.CreateTemplateController
+	ViewTemplateGet             *views.GetTemplateController
+	ViewTemplateUpdate          *views.UpdateTemplateController
+	ViewTemplateDelete          *views.DeleteTemplateController
+	ViewTemplateApply           *views.ApplyTemplateController
+	ViewTemplatePropagate       *views.PropagateTemplateController
+	AlertRuleList               *alerts.ListRulesController
+	AlertRuleCreate             *alerts.CreateRuleController
+	AlertRuleGet                *alerts.GetRuleController
+	AlertRuleUpdate             *alerts.UpdateRuleController
+	AlertRuleDelete             *alerts.DeleteRuleController
+	AlertList                   *alerts.ListAlertsController
+	ConfigExport                *bundles.ExportController
+	ConfigImport                *bundles.ImportController
 }
 
 func Register(app *fiber.App, deps Dependencies) {
@@ -56,12 +92,27 @@ func Register(app *fiber.App, deps Dependencies) {
 	if deps.CrawlingSessionChecks != nil {
 		app.Get("/api/crawling_sessions/:id/checks_with_pages", deps.CrawlingSessionChecks.List)
 	}
+	if deps.CrawlingSessionDuplicates != nil {
+		app.Get("/api/crawling_sessions/:id/duplicates", deps.CrawlingSessionDuplicates.List)
+	}
+	if deps.CrawlingSessionHreflang != nil {
+		app.Get("/api/crawling_sessions/:id/hreflang", deps.CrawlingSessionHreflang.List)
+	}
+	if deps.CrawlingSessionAuditResults != nil {
+		app.Get("/api/crawling_sessions/:id/audit_results", deps.CrawlingSessionAuditResults.List)
+	}
+	if deps.CrawlingSessionDiff != nil {
+		app.Get("/api/crawling_sessions/:id/diff", deps.CrawlingSessionDiff.Diff)
+	}
 	if deps.PageDetails != nil {
 		app.Get("/api/pages/:id/page_details", deps.PageDetails.Details)
 	}
 	if deps.Stats != nil {
 		app.Get("/api/stats", deps.Stats.Fetch)
 	}
+	if deps.StatsHistory != nil {
+		app.Get("/api/stats/history", deps.StatsHistory.History)
+	}
 
 	if deps.AuditCheckList != nil {
 		app.Get("/api/audit_checks", deps.AuditCheckList.List)
@@ -69,9 +120,18 @@ func Register(app *fiber.App, deps Dependencies) {
 	if deps.AuditCheckCreate != nil {
 		app.Post("/api/audit_checks", deps.AuditCheckCreate.Create)
 	}
+	if deps.AuditCheckDefaults != nil {
+		app.Post("/api/audit_checks/install_defaults", deps.AuditCheckDefaults.InstallDefaults)
+	}
+	if deps.AuditCheckPreview != nil {
+		app.Post("/api/audit_checks/preview", deps.AuditCheckPreview.Preview)
+	}
 	if deps.AuditCheckGet != nil {
 		app.Get("/api/audit_checks/:id", deps.AuditCheckGet.Get)
 	}
+	if deps.AuditCheckHistory != nil {
+		app.Get("/api/audit_checks/:id/history", deps.AuditCheckHistory.History)
+	}
 	if deps.AuditCheckUpdate != nil {
 		app.Put("/api/audit_checks/:id", deps.AuditCheckUpdate.Update)
 	}
@@ -79,6 +139,29 @@ func Register(app *fiber.App, deps Dependencies) {
 		app.Delete("/api/audit_checks/:id", deps.AuditCheckDelete.Delete)
 	}
 
+	// Audit check template routes
+	if deps.AuditTemplateList != nil {
+		app.Get("/api/audit_check_templates", deps.AuditTemplateList.List)
+	}
+	if deps.AuditTemplateCreate != nil {
+		app.Post("/api/audit_check_templates", deps.AuditTemplateCreate.Create)
+	}
+	if deps.AuditTemplateGet != nil {
+		app.Get("/api/audit_check_templates/:id", deps.AuditTemplateGet.Get)
+	}
+	if deps.AuditTemplateUpdate != nil {
+		app.Put("/api/audit_check_templates/:id", deps.AuditTemplateUpdate.Update)
+	}
+	if deps.AuditTemplateDelete != nil {
+		app.Delete("/api/audit_check_templates/:id", deps.AuditTemplateDelete.Delete)
+	}
+	if deps.AuditTemplateApply != nil {
+		app.Post("/api/audit_check_templates/:id/apply", deps.AuditTemplateApply.Apply)
+	}
+	if deps.AuditTemplatePropagate != nil {
+		app.Post("/api/audit_check_templates/:id/propagate", deps.AuditTemplatePropagate.Propagate)
+	}
+
 	// View routes
 	if deps.ViewList != nil {
 		app.Get("/api/views", deps.ViewList.List)
@@ -86,6 +169,12 @@ func Register(app *fiber.App, deps Dependencies) {
 	if deps.ViewCreate != nil {
 		app.Post("/api/views", deps.ViewCreate.Create)
 	}
+	if deps.ViewPreview != nil {
+		app.Post("/api/views/preview", deps.ViewPreview.Preview)
+	}
+	if deps.ViewBatchPageCounts != nil {
+		app.Post("/api/views/page_counts", deps.ViewBatchPageCounts.PageCounts)
+	}
 	if deps.ViewGet != nil {
 		app.Get("/api/views/:id", deps.ViewGet.Get)
 	}
@@ -98,4 +187,61 @@ func Register(app *fiber.App, deps Dependencies) {
 	if deps.ViewPageCount != nil {
 		app.Get("/api/views/:id/page_count", deps.ViewPageCount.PageCount)
 	}
+	if deps.ViewPageCounts != nil {
+		app.Get("/api/views/:id/page_counts", deps.ViewPageCounts.PageCounts)
+	}
+	if deps.ViewPages != nil {
+		app.Get("/api/views/:id/pages", deps.ViewPages.List)
+	}
+
+	// View template routes
+	if deps.ViewTemplateList != nil {
+		app.Get("/api/view_templates", deps.ViewTemplateList.List)
+	}
+	if deps.ViewTemplateCreate != nil {
+		app.Post("/api/view_templates", deps.ViewTemplateCreate.Create)
+	}
+	if deps.ViewTemplateGet != nil {
+		app.Get("/api/view_templates/:id", deps.ViewTemplateGet.Get)
+	}
+	if deps.ViewTemplateUpdate != nil {
+		app.Put("/api/view_templates/:id", deps.ViewTemplateUpdate.Update)
+	}
+	if deps.ViewTemplateDelete != nil {
+		app.Delete("/api/view_templates/:id", deps.ViewTemplateDelete.Delete)
+	}
+	if deps.ViewTemplateApply != nil {
+		app.Post("/api/view_templates/:id/apply", deps.ViewTemplateApply.Apply)
+	}
+	if deps.ViewTemplatePropagate != nil {
+		app.Post("/api/view_templates/:id/propagate", deps.ViewTemplatePropagate.Propagate)
+	}
+
+	// Alert routes
+	if deps.AlertRuleList != nil {
+		app.Get("/api/alert_rules", deps.AlertRuleList.List)
+	}
+	if deps.AlertRuleCreate != nil {
+		app.Post("/api/alert_rules", deps.AlertRuleCreate.Create)
+	}
+	if deps.AlertRuleGet != nil {
+		app.Get("/api/alert_rules/:id", deps.AlertRuleGet.Get)
+	}
+	if deps.AlertRuleUpdate != nil {
+		app.Put("/api/alert_rules/:id", deps.AlertRuleUpdate.Update)
+	}
+	if deps.AlertRuleDelete != nil {
+		app.Delete("/api/alert_rules/:id", deps.AlertRuleDelete.Delete)
+	}
+	if deps.AlertList != nil {
+		app.Get("/api/alerts", deps.AlertList.List)
+	}
+
+	// Config bundle routes
+	if deps.ConfigExport != nil {
+		app.Get("/api/search_keyword_urls/:sku/config/export", deps.ConfigExport.Export)
+	}
+	if deps.ConfigImport != nil {
+		app.Post("/api/search_keyword_urls/:sku/config/import", deps.ConfigImport.Import)
+	}
 }
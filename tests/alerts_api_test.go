@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"sitecrawler/newgo/controllers/alerts"
+	"sitecrawler/newgo/controllers/health"
+	alertsDto "sitecrawler/newgo/dto/alerts"
+	"sitecrawler/newgo/internal/alerting"
+	"sitecrawler/newgo/internal/postcrawl"
+	"sitecrawler/newgo/internal/repository"
+	alertsvc "sitecrawler/newgo/internal/services/alerts"
+	"sitecrawler/newgo/models"
+	"sitecrawler/newgo/routes"
+)
+
+func TestAlertRulesCRUD(t *testing.T) {
+	t.Parallel()
+
+	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	_ = auditRepo.Create(context.Background(), &models.AuditCheck{SearchKeywordURLID: 5, Name: "Broken links", Category: "error"})
+	app, _, _ := setupAlertsApp(auditRepo)
+
+	createBody := `{"data":{"search_keyword_url_id":5,"name":"broken","audit_check_id":1,"operator":"gt","threshold":0,"emails":["Ops Team <ops@example.com>"]}}`
+	resp := doAlertRequest(t, app, http.MethodPost, "/api/alert_rules", createBody)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d got %d", http.StatusCreated, resp.StatusCode)
+	}
+	var created alertsDto.AlertRuleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+	if created.Data.ID != 1 || created.Data.Mode != alerting.ModeValue || !created.Data.Enabled {
+		t.Fatalf("expected defaults to be applied, got %+v", created.Data)
+	}
+	if len(created.Data.Emails) != 1 || created.Data.Emails[0] != "ops@example.com" {
+		t.Fatalf("expected the bare address to be stored, got %v", created.Data.Emails)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodPut, "/api/alert_rules/1", `{"data":{"metric":"site_health","mode":"change","operator":"lte","threshold":-10,"enabled":false}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var updated alertsDto.AlertRuleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		t.Fatalf("decode update response: %v", err)
+	}
+	if updated.Data.AuditCheckID != nil || updated.Data.Metric != "site_health" || updated.Data.Enabled {
+		t.Fatalf("expected metric rule, got %+v", updated.Data)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodGet, "/api/alert_rules?search_keyword_url_id=5", "")
+	var listed alertsDto.AlertRulesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		t.Fatalf("decode list response: %v", err)
+	}
+	if len(listed.Data) != 1 {
+		t.Fatalf("expected 1 rule got %d", len(listed.Data))
+	}
+
+	if resp = doAlertRequest(t, app, http.MethodDelete, "/api/alert_rules/1", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	if resp = doAlertRequest(t, app, http.MethodGet, "/api/alert_rules/1", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestAlertRulesBadRequests(t *testing.T) {
+	t.Parallel()
+
+	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	_ = auditRepo.Create(context.Background(), &models.AuditCheck{SearchKeywordURLID: 9, Name: "Other SKU", Category: "error"})
+	app, _, _ := setupAlertsApp(auditRepo)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"list without sku", http.MethodGet, "/api/alert_rules", "", http.StatusBadRequest},
+		{"alerts without sku", http.MethodGet, "/api/alerts", "", http.StatusBadRequest},
+		{"alerts bad limit", http.MethodGet, "/api/alerts?search_keyword_url_id=1&limit=0", "", http.StatusBadRequest},
+		{"bad id", http.MethodGet, "/api/alert_rules/abc", "", http.StatusBadRequest},
+		{"missing sku", http.MethodPost, "/api/alert_rules", `{"data":{"name":"x","metric":"site_health","operator":"lt"}}`, http.StatusBadRequest},
+		{"no target", http.MethodPost, "/api/alert_rules", `{"data":{"search_keyword_url_id":1,"name":"x","operator":"lt"}}`, http.StatusBadRequest},
+		{"two targets", http.MethodPost, "/api/alert_rules", `{"data":{"search_keyword_url_id":1,"name":"x","metric":"site_health","audit_check_id":1,"operator":"lt"}}`, http.StatusBadRequest},
+		{"bad operator", http.MethodPost, "/api/alert_rules", `{"data":{"search_keyword_url_id":1,"name":"x","metric":"site_health","operator":"about"}}`, http.StatusBadRequest},
+		{"name with line break", http.MethodPost, "/api/alert_rules", `{"data":{"search_keyword_url_id":1,"name":"x\r\nBcc: evil@example.com","metric":"site_health","operator":"lt"}}`, http.StatusBadRequest},
+		{"bad webhook", http.MethodPost, "/api/alert_rules", `{"data":{"search_keyword_url_id":1,"name":"x","metric":"site_health","operator":"lt","webhook_url":"ftp://x"}}`, http.StatusBadRequest},
+		{"check of another sku", http.MethodPost, "/api/alert_rules", `{"data":{"search_keyword_url_id":1,"name":"x","audit_check_id":1,"operator":"gt"}}`, http.StatusBadRequest},
+		{"unknown check", http.MethodPost, "/api/alert_rules", `{"data":{"search_keyword_url_id":1,"name":"x","audit_check_id":42,"operator":"gt"}}`, http.StatusBadRequest},
+		{"update missing rule", http.MethodPut, "/api/alert_rules/42", `{"data":{"name":"y"}}`, http.StatusNotFound},
+		{"delete missing rule", http.MethodDelete, "/api/alert_rules/42", "", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		resp := doAlertRequest(t, app, tc.method, tc.path, tc.body)
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected status %d got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestAlertsFiredAtSessionCompletion(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		payloads []alerting.WebhookPayload
+	)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var p alerting.WebhookPayload
+		if err := json.Unmarshal(body, &p); err == nil {
+			mu.Lock()
+			payloads = append(payloads, p)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+
+	ctx := context.Background()
+	app, ruleRepo, alertRepo := setupAlertsApp(nil)
+	_ = ruleRepo.Create(ctx, &models.AlertRule{SearchKeywordURLID: 3, Name: "health drop", Metric: "site_health", Mode: alerting.ModeChange, Operator: alerting.OperatorLTE, Threshold: -10, WebhookURL: hook.URL, Enabled: true})
+	_ = ruleRepo.Create(ctx, &models.AlertRule{SearchKeywordURLID: 3, Name: "never", Metric: "site_health", Mode: alerting.ModeValue, Operator: alerting.OperatorLT, Threshold: 0, WebhookURL: hook.URL, Enabled: true})
+
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	_ = sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 3, URL: "https://example.com", Status: "processing"})
+	_ = sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 3, URL: "https://example.com", Status: "processing"})
+	stats := fakeStatsRepo{bySession: map[int64]map[string]any{
+		1: {"site_health": 90.0},
+		2: {"site_health": 75.0},
+	}}
+	wrapped := postcrawl.NewSessionRepository(sessionRepo, nil,
+		postcrawl.NewAlertsStage(ruleRepo, alertRepo, sessionRepo, stats, repository.NewInMemoryAuditResultRepository(), nil,
+			alerting.NewWebhookNotifier(hook.Client())),
+	)
+	for _, id := range []int64{1, 2} {
+		if err := wrapped.MarkDone(ctx, id, "finished"); err != nil {
+			t.Fatalf("mark done %d: %v", id, err)
+		}
+	}
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/alerts?search_keyword_url_id=3", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out alertsDto.AlertsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode alerts response: %v", err)
+	}
+	if len(out.Data) != 1 {
+		t.Fatalf("expected 1 alert got %+v", out.Data)
+	}
+	got := out.Data[0]
+	if got.RuleID != 1 || got.CrawlingSessionID != 2 || got.Value != 75 || got.PreviousValue == nil || *got.PreviousValue != 90 {
+		t.Fatalf("unexpected alert %+v", got)
+	}
+	if len(got.Deliveries) != 1 || got.Deliveries[0].Channel != "webhook" || got.Deliveries[0].Error != "" {
+		t.Fatalf("expected a successful webhook delivery, got %+v", got.Deliveries)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads) != 1 || payloads[0].Alert.CrawlingSessionID != 2 {
+		t.Fatalf("expected one webhook call for session 2, got %+v", payloads)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodGet, "/api/alerts?search_keyword_url_id=3&crawling_session_id=1", "")
+	out = alertsDto.AlertsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode alerts response: %v", err)
+	}
+	if len(out.Data) != 0 {
+		t.Fatalf("expected no alerts for the first session, got %+v", out.Data)
+	}
+}
+
+func doAlertRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("fiber request failed: %v", err)
+	}
+	return resp
+}
+
+// setupAlertsApp wires the alert routes over in-memory repositories and
+// returns them so tests can seed rules and run the post-crawl stage.
+func setupAlertsApp(auditRepo repository.AuditCheckRepository) (*fiber.App, *repository.InMemoryAlertRuleRepository, *repository.InMemoryAlertRepository) {
+	if auditRepo == nil {
+		auditRepo = repository.NewInMemoryAuditCheckRepository()
+	}
+	ruleRepo := repository.NewInMemoryAlertRuleRepository()
+	alertRepo := repository.NewInMemoryAlertRepository()
+
+	app := fiber.New()
+	alertService := alertsvc.NewService(ruleRepo, alertRepo, auditRepo)
+
+	routes.Register(app, routes.Dependencies{
+		Health:          health.NewController(nil),
+		AlertRuleList:   alerts.NewListRulesController(alertService, nil),
+		AlertRuleCreate: alerts.NewCreateRuleController(alertService, nil),
+		AlertRuleGet:    alerts.NewGetRuleController(alertService, nil),
+		AlertRuleUpdate: alerts.NewUpdateRuleController(alertService, nil),
+		AlertRuleDelete: alerts.NewDeleteRuleController(alertService, nil),
+		AlertList:       alerts.NewListAlertsController(alertService, nil),
+	})
+
+	return app, ruleRepo, alertRepo
+}
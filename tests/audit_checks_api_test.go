@@ -9,6 +9,7 @@ auditsDto "sitecrawler/newgo/dto/audits"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -25,7 +26,7 @@ func TestAuditChecksCRUD(t *testing.T) {
 
 	app := setupAuditApp(nil, nil)
 
-	createBody := `{"data":{"search_keyword_url_id":123,"name":"check-1","category":"cat-1","filter_config":{"k":"v"}}}`
+	createBody := `{"data":{"search_keyword_url_id":123,"name":"check-1","category":"warning","severity":"low","weight":2,"filter_config":{"k":"v"}}}`
 	createReq := httptest.NewRequest(http.MethodPost, "/api/audit_checks", strings.NewReader(createBody))
 	createReq.Header.Set("Content-Type", "application/json")
 	createResp, err := app.Test(createReq)
@@ -43,6 +44,9 @@ func TestAuditChecksCRUD(t *testing.T) {
 	if created.Data.ID == 0 {
 		t.Fatalf("expected created id")
 	}
+	if created.Data.Category != "warning" || created.Data.Severity != "low" || created.Data.Weight != 2 || created.Data.FilterConfig["k"] != "v" {
+		t.Fatalf("expected category and filter_config to be stored, got %+v", created.Data)
+	}
 
 	getReq := httptest.NewRequest(http.MethodGet, "/api/audit_checks/1", nil)
 	getResp, err := app.Test(getReq)
@@ -115,6 +119,99 @@ func TestAuditChecksCRUD(t *testing.T) {
 	}
 }
 
+func TestAuditChecksInstallDefaults(t *testing.T) {
+	t.Parallel()
+
+	app := setupAuditApp(nil, func(repo *repository.InMemoryAuditCheckRepository) {
+		ctx := context.Background()
+		_ = repo.Create(ctx, &models.AuditCheck{SearchKeywordURLID: 9, Name: "old title check", BuiltinKey: "missing_title"})
+		_ = repo.Create(ctx, &models.AuditCheck{SearchKeywordURLID: 9, Name: "my h1 check", BuiltinKey: "missing_h1", Customized: true})
+		_ = repo.Create(ctx, &models.AuditCheck{SearchKeywordURLID: 9, Name: "hand made"})
+	})
+
+	install := func() auditsDto.InstallDefaultsData {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/audit_checks/install_defaults?search_keyword_url_id=9", nil))
+		if err != nil {
+			t.Fatalf("install request failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+		}
+		var out auditsDto.InstallDefaultsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("decode install response: %v", err)
+		}
+		return out.Data
+	}
+
+	first := install()
+	if len(first.Installed) != len(first.Checks)-2 || len(first.Unchanged) != 0 {
+		t.Fatalf("expected all but the seeded checks installed, got %+v", first)
+	}
+	if len(first.Upgraded) != 1 || first.Upgraded[0] != "missing_title" {
+		t.Fatalf("expected missing_title upgraded, got %v", first.Upgraded)
+	}
+	if len(first.Skipped) != 1 || first.Skipped[0] != "missing_h1" {
+		t.Fatalf("expected customized missing_h1 skipped, got %v", first.Skipped)
+	}
+	for _, c := range first.Checks {
+		if c.BuiltinKey == "missing_h1" && c.Name != "my h1 check" {
+			t.Fatalf("customized check was overwritten: %+v", c)
+		}
+		if c.BuiltinKey == "missing_title" && (c.Name == "old title check" || c.FilterConfig == nil) {
+			t.Fatalf("outdated check was not upgraded: %+v", c)
+		}
+	}
+
+	second := install()
+	if len(second.Installed) != 0 || len(second.Upgraded) != 0 || len(second.Unchanged) != len(second.Checks)-1 {
+		t.Fatalf("expected a no-op reinstall, got %+v", second)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/audit_checks?search_keyword_url_id=9", nil))
+	if err != nil {
+		t.Fatalf("list request failed: %v", err)
+	}
+	var listed auditsDto.AuditChecksResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		t.Fatalf("decode list response: %v", err)
+	}
+	if len(listed.Data) != len(first.Checks)+1 {
+		t.Fatalf("expected catalogue plus the hand made check, got %d", len(listed.Data))
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/audit_checks/install_defaults", nil))
+	if err != nil {
+		t.Fatalf("install request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestAuditChecksUpdateMarksBuiltinCustomized(t *testing.T) {
+	t.Parallel()
+
+	app := setupAuditApp(nil, func(repo *repository.InMemoryAuditCheckRepository) {
+		_ = repo.Create(context.Background(), &models.AuditCheck{SearchKeywordURLID: 9, Name: "Missing H1", BuiltinKey: "missing_h1", BuiltinVersion: 1})
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/audit_checks/1", strings.NewReader(`{"data":{"category":"notice"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("update request failed: %v", err)
+	}
+	var updated auditsDto.AuditCheckResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		t.Fatalf("decode update response: %v", err)
+	}
+	if !updated.Data.Customized || updated.Data.Category != "notice" {
+		t.Fatalf("expected customized notice check, got %+v", updated.Data)
+	}
+}
+
 func TestAuditChecksBadRequests(t *testing.T) {
 	t.Parallel()
 
@@ -166,6 +263,30 @@ func TestAuditChecksBadRequests(t *testing.T) {
 	if resp.StatusCode != http.StatusBadRequest {
 		t.Fatalf("expected status %d got %d", http.StatusBadRequest, resp.StatusCode)
 	}
+
+	invalid := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPost, "/api/audit_checks", `{"data":{"search_keyword_url_id":1,"name":"x","category":"cat-1"}}`},
+		{http.MethodPost, "/api/audit_checks", `{"data":{"search_keyword_url_id":1,"name":"x","category":"error","severity":"urgent"}}`},
+		{http.MethodPost, "/api/audit_checks", `{"data":{"search_keyword_url_id":1,"name":"x","category":"error","weight":-1}}`},
+		{http.MethodPut, "/api/audit_checks/1", `{"data":{"category":"cat-1"}}`},
+		{http.MethodPost, "/api/audit_checks", `{"data":{"search_keyword_url_id":1,"name":"x","category":"error","filter_config":{"filter_groups":[{"filters":[{"name":"depth;","operator":"eq","value":1}]}]}}}`},
+		{http.MethodPut, "/api/audit_checks/1", `{"data":{"filter_config":{"filter_groups":[{"filters":[{"name":"depth","operator":"like","value":1}]}]}}}`},
+	}
+	for _, tc := range invalid {
+		req = httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = app.Test(req)
+		if err != nil {
+			t.Fatalf("fiber request failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s %s: expected status %d got %d", tc.method, tc.body, http.StatusBadRequest, resp.StatusCode)
+		}
+	}
 }
 
 func TestAuditChecksNotFound(t *testing.T) {
@@ -210,6 +331,76 @@ func TestAuditChecksRepositoryErrors(t *testing.T) {
 	}
 }
 
+func TestAuditCheckHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	check := &models.AuditCheck{SearchKeywordURLID: 5, Name: "Broken links", Category: "error", FilterConfig: map[string]any{"tag": "broken"}}
+	_ = auditRepo.Create(ctx, check)
+
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	for i, sku := range []int64{5, 5, 9, 5, 5} {
+		ended := base.AddDate(0, 0, i)
+		_ = sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: sku, Status: "done", EndedAt: &ended})
+	}
+
+	// Session 1 has a snapshot taken before the check was edited; sessions 2
+	// and 4 have none and are evaluated live. Session 5's snapshot predates
+	// the check, so it has no point.
+	resultRepo := repository.NewInMemoryAuditResultRepository()
+	_ = resultRepo.Save(ctx, 1, []models.AuditResult{{CrawlingSessionID: 1, AuditCheckID: check.ID, MatchedPages: 12}})
+	_ = resultRepo.Save(ctx, 5, []models.AuditResult{{CrawlingSessionID: 5, AuditCheckID: 99, MatchedPages: 30}})
+	matchRepo := fakeHistoryMatchRepo{2: 8, 4: 2}
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		wantSessions   []int64
+		wantValues     []float64
+	}{
+		{name: "chronological series", path: "/api/audit_checks/1/history", expectedStatus: http.StatusOK, wantSessions: []int64{1, 2, 4}, wantValues: []float64{12, 8, 2}},
+		{name: "date range", path: "/api/audit_checks/1/history?from=2026-03-02T00:00:00Z", expectedStatus: http.StatusOK, wantSessions: []int64{2, 4}, wantValues: []float64{8, 2}},
+		{name: "downsampled", path: "/api/audit_checks/1/history?points=1", expectedStatus: http.StatusOK, wantSessions: []int64{4}, wantValues: []float64{22.0 / 3}},
+		{name: "limit keeps the latest sessions", path: "/api/audit_checks/1/history?limit=3", expectedStatus: http.StatusOK, wantSessions: []int64{2, 4}, wantValues: []float64{8, 2}},
+		{name: "not found", path: "/api/audit_checks/99/history", expectedStatus: http.StatusNotFound},
+		{name: "limit above maximum", path: "/api/audit_checks/1/history?limit=101", expectedStatus: http.StatusBadRequest},
+		{name: "invalid to", path: "/api/audit_checks/1/history?to=later", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupAuditAppWith(auditRepo, sessionRepo, matchRepo, resultRepo)
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("fiber request failed: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("expected status %d got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var out auditsDto.AuditCheckHistoryResponse
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(out.Data.Points) != len(tt.wantSessions) {
+				t.Fatalf("expected %d points got %+v", len(tt.wantSessions), out.Data.Points)
+			}
+			for i, p := range out.Data.Points {
+				if p.SessionID != tt.wantSessions[i] || p.Value != tt.wantValues[i] {
+					t.Fatalf("unexpected point %d: %+v", i, p)
+				}
+			}
+		})
+	}
+}
+
 func setupAuditApp(factory func() repository.AuditCheckRepository, seed func(*repository.InMemoryAuditCheckRepository)) *fiber.App {
 	repo := repository.AuditCheckRepository(nil)
 	if factory != nil {
@@ -225,20 +416,52 @@ func setupAuditApp(factory func() repository.AuditCheckRepository, seed func(*re
 		}
 	}
 
+	return setupAuditAppWith(repo, nil, nil, nil)
+}
+
+// setupAuditAppWith wires the audit check routes over the given repositories;
+// nil ones default to empty in-memory or no-op implementations.
+func setupAuditAppWith(
+	repo repository.AuditCheckRepository,
+	sessionRepo repository.CrawlingSessionRepository,
+	matchRepo repository.PageMatchRepository,
+	resultRepo repository.AuditResultRepository,
+) *fiber.App {
+	if sessionRepo == nil {
+		sessionRepo = repository.NewInMemoryCrawlingSessionRepository()
+	}
+	if matchRepo == nil {
+		matchRepo = repository.NewNoopPageMatchRepository()
+	}
+	if resultRepo == nil {
+		resultRepo = repository.NewInMemoryAuditResultRepository()
+	}
+
 	app := fiber.New()
 
 	healthController := health.NewController(nil)
 
 	// Use unified service
-	auditService := auditsvc.NewService(repo)
+	auditService := auditsvc.NewService(repo, sessionRepo, matchRepo, resultRepo, repository.NewInMemoryAuditCheckTemplateRepository())
 
 	routes.Register(app, routes.Dependencies{
-		Health:           healthController,
-		AuditCheckList:   audits.NewListController(auditService, nil),
-		AuditCheckGet:    audits.NewGetController(auditService, nil),
-		AuditCheckCreate: audits.NewCreateController(auditService, nil),
-		AuditCheckUpdate: audits.NewUpdateController(auditService, nil),
-		AuditCheckDelete: audits.NewDeleteController(auditService, nil),
+		Health:             healthController,
+		AuditCheckList:     audits.NewListController(auditService, nil),
+		AuditCheckGet:      audits.NewGetController(auditService, nil),
+		AuditCheckCreate:   audits.NewCreateController(auditService, nil),
+		AuditCheckUpdate:   audits.NewUpdateController(auditService, nil),
+		AuditCheckDelete:   audits.NewDeleteController(auditService, nil),
+		AuditCheckDefaults: audits.NewInstallDefaultsController(auditService, nil),
+		AuditCheckHistory:  audits.NewHistoryController(auditService, nil),
+		AuditCheckPreview:  audits.NewPreviewController(auditService, nil),
+
+		AuditTemplateList:      audits.NewListTemplatesController(auditService, nil),
+		AuditTemplateCreate:    audits.NewCreateTemplateController(auditService, nil),
+		AuditTemplateGet:       audits.NewGetTemplateController(auditService, nil),
+		AuditTemplateUpdate:    audits.NewUpdateTemplateController(auditService, nil),
+		AuditTemplateDelete:    audits.NewDeleteTemplateController(auditService, nil),
+		AuditTemplateApply:     audits.NewApplyTemplateController(auditService, nil),
+		AuditTemplatePropagate: audits.NewPropagateTemplateController(auditService, nil),
 	})
 
 	return app
@@ -291,3 +514,32 @@ func (f failingAuditRepo) ListByIDsAndSKUs(ctx context.Context, ids, skuIDs []in
 	_ = skuIDs
 	return nil, nil
 }
+
+func (f failingAuditRepo) ListByTemplate(ctx context.Context, templateID int64) ([]models.AuditCheck, error) {
+	_ = ctx
+	_ = templateID
+	return nil, f.listErr
+}
+
+// fakeHistoryMatchRepo returns a fixed matched page count per session.
+type fakeHistoryMatchRepo map[int64]int
+
+func (f fakeHistoryMatchRepo) CountMatches(ctx context.Context, sessionID int64, configs []map[string]any) ([]int, error) {
+	out := make([]int, len(configs))
+	for i := range out {
+		out[i] = f[sessionID]
+	}
+	return out, nil
+}
+
+func (f fakeHistoryMatchRepo) CountMatchesBySession(ctx context.Context, sessionIDs []int64, configs []map[string]any) (map[int64][]int, error) {
+	out := make(map[int64][]int, len(sessionIDs))
+	for _, id := range sessionIDs {
+		out[id], _ = f.CountMatches(ctx, id, configs)
+	}
+	return out, nil
+}
+
+func (f fakeHistoryMatchRepo) ListMatches(ctx context.Context, sessionID int64, config map[string]any, page, limit int) ([]models.Page, int, error) {
+	return []models.Page{}, 0, nil
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+func TestChecksWithPagesComparison(t *testing.T) {
+	t.Parallel()
+
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	ctx := context.Background()
+	for _, sku := range []int64{1, 1, 2} {
+		if err := sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: sku}); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+	}
+
+	diff := repository.NewCheckPageDiff(1)
+	repository.AddCheckDiffPage(diff, models.CheckPageNew, models.Page{ID: 12, CrawlingSessionID: 2, URL: "https://example.com/b"}, 1)
+	repository.AddCheckDiffPage(diff, models.CheckPagePersisting, models.Page{ID: 11, CrawlingSessionID: 2, URL: "https://example.com/a"}, 1)
+	repository.AddCheckDiffPage(diff, models.CheckPageResolved, models.Page{ID: 3, CrawlingSessionID: 1, URL: "https://example.com/c"}, 1)
+	checkRepo := &recordingChecksRepo{checks: []models.CheckWithPages{{
+		ID:         7,
+		Name:       "broken pages",
+		Pages:      repository.CurrentCheckPages(diff, 10),
+		Comparison: diff,
+	}}}
+	app := setupCrawlingSessionApp(func() repository.CrawlingSessionRepository { return sessionRepo }, nil, nil, checkRepo, nil, nil, nil, nil)
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/2/checks_with_pages?comparison_crawling_session_id=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	if checkRepo.params.PageLimitPerCheck != 10 {
+		t.Fatalf("expected default page limit per check, got %d", checkRepo.params.PageLimitPerCheck)
+	}
+	var out sessionsDto.CrawlingSessionChecksResponse
+	decodeBody(t, resp, &out)
+	if len(out.Data.Checks) != 1 || out.Data.Checks[0].Comparison == nil {
+		t.Fatalf("expected a check with comparison, got %+v", out.Data.Checks)
+	}
+	check := out.Data.Checks[0]
+	if ids := pageIDs(check.Pages); !reflect.DeepEqual(ids, []int64{11, 12}) {
+		t.Fatalf("expected current pages ordered by id, got %v", ids)
+	}
+	c := check.Comparison
+	if c.ComparisonSessionID != 1 || c.New.Total != 1 || c.Resolved.Total != 1 || c.Persisting.Total != 1 {
+		t.Fatalf("unexpected comparison %+v", c)
+	}
+	if c.Resolved.Pages[0].CrawlingSessionID != 1 || c.New.Pages[0].URL != "https://example.com/b" {
+		t.Fatalf("unexpected comparison pages %+v", c)
+	}
+
+	for path, status := range map[string]int{
+		"/api/crawling_sessions/2/checks_with_pages?comparison_crawling_session_id=9": http.StatusBadRequest,
+		"/api/crawling_sessions/2/checks_with_pages?comparison_crawling_session_id=3": http.StatusBadRequest,
+		"/api/crawling_sessions/9/checks_with_pages?comparison_crawling_session_id=1": http.StatusNotFound,
+	} {
+		resp := doAlertRequest(t, app, http.MethodGet, path, "")
+		if resp.StatusCode != status {
+			t.Fatalf("%s: expected status %d got %d", path, status, resp.StatusCode)
+		}
+	}
+}
+
+func pageIDs(pages []models.Page) []int64 {
+	ids := make([]int64, len(pages))
+	for i, p := range pages {
+		ids[i] = p.ID
+	}
+	return ids
+}
@@ -0,0 +1,253 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"sitecrawler/newgo/controllers/bundles"
+	"sitecrawler/newgo/controllers/health"
+	bundlesDto "sitecrawler/newgo/dto/bundles"
+	"sitecrawler/newgo/internal/repository"
+	bundlesvc "sitecrawler/newgo/internal/services/bundles"
+	"sitecrawler/newgo/models"
+	"sitecrawler/newgo/routes"
+)
+
+var bundleFilter = map[string]any{"filter_groups": []any{map[string]any{"filters": []any{
+	map[string]any{"name": "response_code", "operator": "gte", "value": 400},
+}}}}
+
+func TestConfigBundleRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	viewRepo := repository.NewInMemoryViewRepository()
+	_ = auditRepo.Create(ctx, &models.AuditCheck{SearchKeywordURLID: 1, Name: "4xx", Category: "error", Severity: "high", FilterConfig: bundleFilter})
+	_ = auditRepo.Create(ctx, &models.AuditCheck{SearchKeywordURLID: 1, Name: "Missing title", Category: "warning", BuiltinKey: "missing_title", BuiltinVersion: 1})
+	_ = viewRepo.Create(ctx, &models.View{SearchKeywordURLID: 1, Name: "Errors", FilterConfig: bundleFilter})
+	app := setupBundleApp(auditRepo, viewRepo)
+
+	exported := exportBundle(t, app, 1)
+	if exported.Version != bundlesDto.BundleVersion || len(exported.AuditChecks) != 2 || len(exported.Views) != 1 {
+		t.Fatalf("unexpected bundle %+v", exported)
+	}
+	if exported.AuditChecks[1].BuiltinKey != "missing_title" {
+		t.Fatalf("expected builtin key to be exported, got %+v", exported.AuditChecks[1])
+	}
+
+	result := importBundle(t, app, 2, "", false, exported, http.StatusOK)
+	if result.Mode != bundlesDto.ImportModeSkip || result.Created != 3 || result.Updated != 0 || result.Skipped != 0 {
+		t.Fatalf("unexpected import result %+v", result)
+	}
+	for _, change := range result.Changes {
+		if change.Action != bundlesDto.ImportActionCreate || change.ID == 0 {
+			t.Fatalf("expected created rows, got %+v", change)
+		}
+	}
+
+	copied := exportBundle(t, app, 2)
+	if len(copied.AuditChecks) != 2 || copied.AuditChecks[0].Severity != "high" || copied.Views[0].Name != "Errors" {
+		t.Fatalf("unexpected copied bundle %+v", copied)
+	}
+
+	// Importing the same bundle again changes nothing.
+	result = importBundle(t, app, 2, bundlesDto.ImportModeOverwrite, false, exported, http.StatusOK)
+	if result.Created != 0 || result.Updated != 0 || result.Skipped != 3 {
+		t.Fatalf("expected an idempotent import, got %+v", result)
+	}
+	if result.Changes[0].Action != bundlesDto.ImportActionUnchanged {
+		t.Fatalf("expected unchanged, got %+v", result.Changes[0])
+	}
+}
+
+func TestConfigBundleConflictModes(t *testing.T) {
+	t.Parallel()
+
+	bundle := bundlesDto.Bundle{
+		Version:     bundlesDto.BundleVersion,
+		AuditChecks: []bundlesDto.BundleAuditCheck{{Name: "4xx", Category: "error", FilterConfig: bundleFilter}},
+		Views:       []bundlesDto.BundleView{{Name: "Errors", FilterConfig: bundleFilter}},
+	}
+
+	cases := []struct {
+		mode       string
+		action     string
+		newName    string
+		checks     int
+		storedCat  string
+		writesNone bool
+	}{
+		{bundlesDto.ImportModeSkip, bundlesDto.ImportActionSkip, "", 1, "notice", true},
+		{bundlesDto.ImportModeOverwrite, bundlesDto.ImportActionUpdate, "", 1, "error", false},
+		{bundlesDto.ImportModeRename, bundlesDto.ImportActionRename, "4xx (2)", 2, "notice", false},
+	}
+	for _, tc := range cases {
+		for _, dryRun := range []bool{true, false} {
+			ctx := context.Background()
+			auditRepo := repository.NewInMemoryAuditCheckRepository()
+			viewRepo := repository.NewInMemoryViewRepository()
+			_ = auditRepo.Create(ctx, &models.AuditCheck{SearchKeywordURLID: 7, Name: "4xx", Category: "notice"})
+			_ = viewRepo.Create(ctx, &models.View{SearchKeywordURLID: 7, Name: "Errors", FilterConfig: map[string]any{}})
+			app := setupBundleApp(auditRepo, viewRepo)
+
+			result := importBundle(t, app, 7, tc.mode, dryRun, bundle, http.StatusOK)
+			got := result.Changes[0]
+			if got.Action != tc.action || got.NewName != tc.newName {
+				t.Fatalf("%s dry_run=%v: unexpected change %+v", tc.mode, dryRun, got)
+			}
+			if tc.action != bundlesDto.ImportActionRename && strings.Join(got.Fields, ",") != "category,filter_config" {
+				t.Fatalf("%s: expected diff fields, got %v", tc.mode, got.Fields)
+			}
+			if result.Changes[1].Kind != bundlesDto.KindView || result.Changes[1].Action != tc.action {
+				t.Fatalf("%s: unexpected view change %+v", tc.mode, result.Changes[1])
+			}
+
+			checks, _ := auditRepo.ListBySKU(ctx, 7)
+			wantChecks, wantCat := tc.checks, tc.storedCat
+			if dryRun {
+				wantChecks, wantCat = 1, "notice"
+			}
+			if len(checks) != wantChecks || checks[0].Category != wantCat {
+				t.Fatalf("%s dry_run=%v: unexpected stored checks %+v", tc.mode, dryRun, checks)
+			}
+			if !dryRun && tc.newName != "" && checks[1].Name != tc.newName {
+				t.Fatalf("%s: expected renamed copy, got %+v", tc.mode, checks[1])
+			}
+		}
+	}
+}
+
+func TestConfigBundleOverwriteKeepsTemplateLinks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	viewRepo := repository.NewInMemoryViewRepository()
+	templateID := int64(11)
+	check := &models.AuditCheck{SearchKeywordURLID: 8, Name: "4xx", Category: "notice", TemplateID: &templateID, TemplateVersion: 3}
+	view := &models.View{SearchKeywordURLID: 8, Name: "Errors", FilterConfig: map[string]any{}, TemplateID: &templateID, TemplateVersion: 2, Customized: true}
+	_ = auditRepo.Create(ctx, check)
+	_ = viewRepo.Create(ctx, view)
+	app := setupBundleApp(auditRepo, viewRepo)
+
+	bundle := bundlesDto.Bundle{
+		Version:     bundlesDto.BundleVersion,
+		AuditChecks: []bundlesDto.BundleAuditCheck{{Name: "4xx", Category: "error", FilterConfig: bundleFilter}},
+		Views:       []bundlesDto.BundleView{{Name: "Errors", FilterConfig: bundleFilter}},
+	}
+	result := importBundle(t, app, 8, bundlesDto.ImportModeOverwrite, false, bundle, http.StatusOK)
+	if result.Updated != 2 {
+		t.Fatalf("expected 2 updates, got %+v", result)
+	}
+
+	storedCheck, _ := auditRepo.Get(ctx, check.ID)
+	if storedCheck.Category != "error" || storedCheck.TemplateID == nil || *storedCheck.TemplateID != templateID ||
+		storedCheck.TemplateVersion != 3 || storedCheck.Customized {
+		t.Fatalf("expected the check to stay linked to its template, got %+v", storedCheck)
+	}
+	storedView, _ := viewRepo.Get(ctx, view.ID)
+	if storedView.FilterConfig["filter_groups"] == nil || storedView.TemplateID == nil || *storedView.TemplateID != templateID ||
+		storedView.TemplateVersion != 2 || !storedView.Customized {
+		t.Fatalf("expected the view to stay linked to its template, got %+v", storedView)
+	}
+}
+
+func TestConfigBundleImportValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	viewRepo := repository.NewInMemoryViewRepository()
+	app := setupBundleApp(auditRepo, viewRepo)
+
+	invalid := []bundlesDto.Bundle{
+		{Version: 99},
+		{Version: 1, AuditChecks: []bundlesDto.BundleAuditCheck{
+			{Name: "fine", Category: "error", FilterConfig: bundleFilter},
+			{Name: "broken", Category: "error", FilterConfig: map[string]any{"filter_groups": []any{map[string]any{"filters": []any{
+				map[string]any{"name": "depth", "operator": "between", "value": 1},
+			}}}}},
+		}},
+		{Version: 1, AuditChecks: []bundlesDto.BundleAuditCheck{{Name: "x", Category: "cat-1"}}},
+		{Version: 1, Views: []bundlesDto.BundleView{{Name: "v"}, {Name: "v"}}},
+		{Version: 1, Views: []bundlesDto.BundleView{{Name: "v", FilterConfig: map[string]any{"filter_groups": "all"}}}},
+	}
+	for _, bundle := range invalid {
+		importBundle(t, app, 3, bundlesDto.ImportModeOverwrite, false, bundle, http.StatusBadRequest)
+	}
+
+	checks, _ := auditRepo.ListBySKU(ctx, 3)
+	views, _ := viewRepo.ListBySKU(ctx, 3)
+	if len(checks) != 0 || len(views) != 0 {
+		t.Fatalf("expected nothing written, got %d checks and %d views", len(checks), len(views))
+	}
+
+	badRequests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/api/search_keyword_urls/abc/config/export", ""},
+		{http.MethodPost, "/api/search_keyword_urls/abc/config/import", `{"bundle":{"version":1}}`},
+		{http.MethodPost, "/api/search_keyword_urls/3/config/import", `{"mode":"merge","bundle":{"version":1}}`},
+		{http.MethodPost, "/api/search_keyword_urls/3/config/import", `{"bundle":`},
+	}
+	for _, tc := range badRequests {
+		resp := doAlertRequest(t, app, tc.method, tc.path, tc.body)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s %s: expected status %d got %d", tc.method, tc.path, http.StatusBadRequest, resp.StatusCode)
+		}
+	}
+}
+
+func exportBundle(t *testing.T, app *fiber.App, skuID int64) bundlesDto.Bundle {
+	t.Helper()
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/search_keyword_urls/"+strconv.FormatInt(skuID, 10)+"/config/export", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out bundlesDto.ExportConfigResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode export response: %v", err)
+	}
+	return out.Data
+}
+
+func importBundle(t *testing.T, app *fiber.App, skuID int64, mode string, dryRun bool, bundle bundlesDto.Bundle, status int) bundlesDto.ImportConfigData {
+	t.Helper()
+	body, err := json.Marshal(bundlesDto.ImportConfigRequest{Mode: mode, DryRun: dryRun, Bundle: bundle})
+	if err != nil {
+		t.Fatalf("encode bundle: %v", err)
+	}
+	resp := doAlertRequest(t, app, http.MethodPost, "/api/search_keyword_urls/"+strconv.FormatInt(skuID, 10)+"/config/import", string(body))
+	if resp.StatusCode != status {
+		t.Fatalf("expected status %d got %d", status, resp.StatusCode)
+	}
+	var out bundlesDto.ImportConfigResponse
+	if status == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("decode import response: %v", err)
+		}
+	}
+	return out.Data
+}
+
+func setupBundleApp(auditRepo repository.AuditCheckRepository, viewRepo repository.ViewRepository) *fiber.App {
+	app := fiber.New()
+	bundleService := bundlesvc.NewService(auditRepo, viewRepo)
+
+	routes.Register(app, routes.Dependencies{
+		Health:       health.NewController(nil),
+		ConfigExport: bundles.NewExportController(bundleService, nil),
+		ConfigImport: bundles.NewImportController(bundleService, nil),
+	})
+
+	return app
+}
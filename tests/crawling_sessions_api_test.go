@@ -7,13 +7,17 @@ sessionsDto "sitecrawler/newgo/dto/sessions"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
 	"sitecrawler/newgo/controllers/health"
 	"sitecrawler/newgo/controllers/sessions"
+	"sitecrawler/newgo/internal/analysis"
+	"sitecrawler/newgo/internal/postcrawl"
 	"sitecrawler/newgo/internal/repository"
 	sessionsvc "sitecrawler/newgo/internal/services/sessions"
 	"sitecrawler/newgo/models"
@@ -78,7 +82,7 @@ func TestCreateCrawlingSession(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			app := setupCrawlingSessionApp(tt.repoFactory, tt.seed, nil, nil)
+			app := setupCrawlingSessionApp(tt.repoFactory, tt.seed, nil, nil, nil, nil, nil, nil)
 
 			req := httptest.NewRequest(http.MethodPost, "/api/crawling_sessions", strings.NewReader(tt.body))
 			req.Header.Set("Content-Type", "application/json")
@@ -172,7 +176,7 @@ func TestGetCrawlingSession(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			app := setupCrawlingSessionApp(tt.repoFactory, tt.seed, nil, nil)
+			app := setupCrawlingSessionApp(tt.repoFactory, tt.seed, nil, nil, nil, nil, nil, nil)
 			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
 			resp, err := app.Test(req)
 			if err != nil {
@@ -252,7 +256,7 @@ func TestListCrawlingSessionPages(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			app := setupCrawlingSessionApp(nil, nil, tt.pageRepo, nil)
+			app := setupCrawlingSessionApp(nil, nil, tt.pageRepo, nil, nil, nil, nil, nil)
 			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
 
 			resp, err := app.Test(req)
@@ -344,7 +348,7 @@ func TestListCrawlingSessionChecks(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			app := setupCrawlingSessionApp(nil, nil, nil, tt.checkRepo)
+			app := setupCrawlingSessionApp(nil, nil, nil, tt.checkRepo, nil, nil, nil, nil)
 			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
 
 			resp, err := app.Test(req)
@@ -363,6 +367,416 @@ func TestListCrawlingSessionChecks(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// LIST DUPLICATES TESTS
+// =============================================================================
+
+func TestListCrawlingSessionDuplicates(t *testing.T) {
+	t.Parallel()
+
+	body := "shared article body about crawling and indexing that appears on several pages of the site"
+	snapshots := fakeSnapshotRepo{
+		snapshots: []models.PageSnapshot{
+			{ID: 1, URL: "https://example.com/a", ResponseCode: 200, Title: "Home", MetaDescription: "Welcome", ContentHash: analysis.ContentHash(body), SimHash: analysis.SimHash(body)},
+			{ID: 2, URL: "https://example.com/b", ResponseCode: 200, Title: "home ", MetaDescription: "", ContentHash: analysis.ContentHash(body + "!"), SimHash: analysis.SimHash(body + "!")},
+			{ID: 3, URL: "https://example.com/c", ResponseCode: 200, Title: "Contact", MetaDescription: "Welcome", SimHash: analysis.SimHash("a different page entirely, nothing in common")},
+		},
+	}
+	// Pages 1 and 2 share a body and only page 2 has its SimHash recorded;
+	// pages 3 and 4 differ from each other by a word.
+	exactAndNear := fakeSnapshotRepo{
+		snapshots: []models.PageSnapshot{
+			{ID: 1, URL: "https://example.com/a", ResponseCode: 200, ContentHash: analysis.ContentHash(body)},
+			{ID: 2, URL: "https://example.com/b", ResponseCode: 200, ContentHash: analysis.ContentHash(body), SimHash: analysis.SimHash(body)},
+			{ID: 3, URL: "https://example.com/c", ResponseCode: 200, ContentHash: analysis.ContentHash(body + " today"), SimHash: analysis.SimHash(body + " today")},
+			{ID: 4, URL: "https://example.com/d", ResponseCode: 200, ContentHash: analysis.ContentHash(body + " again"), SimHash: analysis.SimHash(body + " again")},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		snapshotRepo   repository.PageSnapshotRepository
+		expectedStatus int
+		wantClusters   int
+		wantSize       int
+		wantMatch      string
+	}{
+		{name: "title", path: "/api/crawling_sessions/7/duplicates?kind=title", snapshotRepo: snapshots, expectedStatus: http.StatusOK, wantClusters: 1, wantSize: 2},
+		{name: "description", path: "/api/crawling_sessions/7/duplicates?kind=description", snapshotRepo: snapshots, expectedStatus: http.StatusOK, wantClusters: 1, wantSize: 2},
+		{name: "content", path: "/api/crawling_sessions/7/duplicates?kind=content", snapshotRepo: snapshots, expectedStatus: http.StatusOK, wantClusters: 1, wantSize: 2, wantMatch: models.DuplicateMatchExact},
+		{name: "content exact and near", path: "/api/crawling_sessions/7/duplicates?kind=content", snapshotRepo: exactAndNear, expectedStatus: http.StatusOK, wantClusters: 2, wantSize: 2, wantMatch: models.DuplicateMatchExact},
+		{name: "invalid kind", path: "/api/crawling_sessions/7/duplicates?kind=h1", snapshotRepo: snapshots, expectedStatus: http.StatusBadRequest},
+		{name: "invalid id", path: "/api/crawling_sessions/x/duplicates?kind=title", snapshotRepo: snapshots, expectedStatus: http.StatusBadRequest},
+		{name: "repo error", path: "/api/crawling_sessions/7/duplicates?kind=title", snapshotRepo: fakeSnapshotRepo{err: errors.New("boom")}, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "session not found", path: "/api/crawling_sessions/99/duplicates?kind=title", snapshotRepo: snapshots, expectedStatus: http.StatusNotFound},
+	}
+
+	seed := func(repo *repository.InMemoryCrawlingSessionRepository) {
+		for i := 0; i < 7; i++ {
+			_ = repo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: 1})
+		}
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupCrawlingSessionApp(nil, seed, nil, nil, tt.snapshotRepo, nil, nil, nil)
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("fiber request failed: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("expected status %d got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var out sessionsDto.DuplicatesResponse
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(out.Data.Clusters) != tt.wantClusters {
+				t.Fatalf("expected %d clusters got %d", tt.wantClusters, len(out.Data.Clusters))
+			}
+			if out.Data.Clusters[0].Size != tt.wantSize || len(out.Data.Clusters[0].Pages) != tt.wantSize {
+				t.Fatalf("expected cluster of %d got %+v", tt.wantSize, out.Data.Clusters[0])
+			}
+			if out.Data.Clusters[0].Match != tt.wantMatch {
+				t.Fatalf("expected %q match got %+v", tt.wantMatch, out.Data.Clusters[0])
+			}
+		})
+	}
+}
+
+// =============================================================================
+// LIST HREFLANG TESTS
+// =============================================================================
+
+func TestListCrawlingSessionHreflang(t *testing.T) {
+	t.Parallel()
+
+	hreflangs := fakeSnapshotRepo{
+		snapshots: []models.PageSnapshot{
+			{ID: 1, URL: "https://example.com/en/", ResponseCode: 200},
+			{ID: 2, URL: "https://example.com/de/", ResponseCode: 200},
+			{ID: 3, URL: "https://example.com/fr/", ResponseCode: 404},
+		},
+		links: map[int64][]models.HreflangLink{
+			1: {
+				{Hreflang: "en", Href: "https://example.com/en/", Source: models.HreflangSourceHTML},
+				{Hreflang: "de", Href: "https://example.com/de/", Source: models.HreflangSourceHTML},
+				{Hreflang: "fr", Href: "https://example.com/fr/", Source: models.HreflangSourceHTML},
+				{Hreflang: "x-default", Href: "https://example.com/en/", Source: models.HreflangSourceHTML},
+			},
+			2: {
+				{Hreflang: "de-EU", Href: "https://example.com/de/", Source: models.HreflangSourceSitemap},
+				{Hreflang: "x-default", Href: "https://example.com/", Source: models.HreflangSourceSitemap},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		snapshotRepo   repository.PageSnapshotRepository
+		expectedStatus int
+		wantCounts     map[string]int
+	}{
+		{
+			name:           "issues grouped by check",
+			path:           "/api/crawling_sessions/7/hreflang",
+			snapshotRepo:   hreflangs,
+			expectedStatus: http.StatusOK,
+			wantCounts: map[string]int{
+				"hreflang_missing_return_link":  1,
+				"hreflang_invalid_code":         1,
+				"hreflang_non_200_target":       1,
+				"hreflang_non_canonical_target": 0,
+				"hreflang_missing_x_default":    0,
+			},
+		},
+		{name: "invalid id", path: "/api/crawling_sessions/x/hreflang", snapshotRepo: hreflangs, expectedStatus: http.StatusBadRequest},
+		{name: "repo error", path: "/api/crawling_sessions/7/hreflang", snapshotRepo: fakeSnapshotRepo{err: errors.New("boom")}, expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupCrawlingSessionApp(nil, nil, nil, nil, tt.snapshotRepo, nil, nil, nil)
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("fiber request failed: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("expected status %d got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var out sessionsDto.HreflangResponse
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(out.Data.Checks) != len(tt.wantCounts) {
+				t.Fatalf("expected %d checks got %d", len(tt.wantCounts), len(out.Data.Checks))
+			}
+			for _, check := range out.Data.Checks {
+				if check.Count != tt.wantCounts[check.Key] || len(check.Issues) != check.Count {
+					t.Fatalf("unexpected %s: %+v", check.Key, check)
+				}
+			}
+		})
+	}
+}
+
+func TestListCrawlingSessionAuditResults(t *testing.T) {
+	t.Parallel()
+
+	seed := func(repo *repository.InMemoryCrawlingSessionRepository) {
+		for _, sku := range []int64{77, 77, 88} {
+			_ = repo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: sku, URL: "https://example.com", Status: "done"})
+		}
+	}
+	newAuditRepo := func() repository.AuditCheckRepository {
+		repo := repository.NewInMemoryAuditCheckRepository()
+		for _, ac := range []models.AuditCheck{
+			{SearchKeywordURLID: 77, Name: "Missing title", Category: "error", FilterConfig: map[string]any{"tag": "title"}},
+			{SearchKeywordURLID: 77, Name: "Broken filter", Category: "warning", FilterConfig: map[string]any{"tag": "broken"}},
+			{SearchKeywordURLID: 77, Name: "Thin content", Category: "notice", FilterConfig: map[string]any{"tag": "thin"}},
+			{SearchKeywordURLID: 88, Name: "Other SKU", Category: "error", FilterConfig: map[string]any{"tag": "title"}},
+		} {
+			ac := ac
+			_ = repo.Create(context.Background(), &ac)
+		}
+		return repo
+	}
+	matches := fakeMatchRepo{
+		counts: map[int64]map[string]int{
+			1: {"title": 3, "thin": 0},
+			2: {"title": 5, "thin": 2},
+		},
+		pages: []models.Page{{ID: 10, URL: "https://example.com/a"}},
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		assertBody     func(t *testing.T, out sessionsDto.AuditResultsResponse)
+	}{
+		{
+			name:           "counts, samples and per-check errors",
+			path:           "/api/crawling_sessions/1/audit_results",
+			expectedStatus: http.StatusOK,
+			assertBody: func(t *testing.T, out sessionsDto.AuditResultsResponse) {
+				if len(out.Data.Checks) != 3 {
+					t.Fatalf("expected 3 checks got %d", len(out.Data.Checks))
+				}
+				title, broken, thin := out.Data.Checks[0], out.Data.Checks[1], out.Data.Checks[2]
+				if title.MatchedPages != 3 || len(title.Pages) != 1 || title.Delta != nil {
+					t.Fatalf("unexpected title result %+v", title)
+				}
+				if broken.Error == "" || broken.MatchedPages != 0 {
+					t.Fatalf("expected broken filter error, got %+v", broken)
+				}
+				if thin.MatchedPages != 0 || len(thin.Pages) != 0 {
+					t.Fatalf("unexpected thin result %+v", thin)
+				}
+				if out.Data.Source != sessionsDto.AuditResultsSourceLive {
+					t.Fatalf("expected live source got %q", out.Data.Source)
+				}
+				if out.Data.Page != 1 || out.Data.PageLimit != 10 {
+					t.Fatalf("unexpected pagination %d/%d", out.Data.Page, out.Data.PageLimit)
+				}
+			},
+		},
+		{
+			name:           "deltas against comparison",
+			path:           "/api/crawling_sessions/1/audit_results?comparison_crawling_session_id=2&page=2&page_limit=5",
+			expectedStatus: http.StatusOK,
+			assertBody: func(t *testing.T, out sessionsDto.AuditResultsResponse) {
+				title, thin := out.Data.Checks[0], out.Data.Checks[2]
+				if title.ComparisonMatchedPages == nil || *title.ComparisonMatchedPages != 5 || *title.Delta != -2 {
+					t.Fatalf("unexpected title delta %+v", title)
+				}
+				if thin.Delta == nil || *thin.Delta != -2 {
+					t.Fatalf("unexpected thin delta %+v", thin)
+				}
+				if out.Data.Page != 2 || out.Data.PageLimit != 5 {
+					t.Fatalf("unexpected pagination %d/%d", out.Data.Page, out.Data.PageLimit)
+				}
+			},
+		},
+		{name: "invalid id", path: "/api/crawling_sessions/x/audit_results", expectedStatus: http.StatusBadRequest},
+		{name: "invalid comparison", path: "/api/crawling_sessions/1/audit_results?comparison_crawling_session_id=x", expectedStatus: http.StatusBadRequest},
+		{name: "invalid page limit", path: "/api/crawling_sessions/1/audit_results?page_limit=x", expectedStatus: http.StatusBadRequest},
+		{name: "page limit above maximum", path: "/api/crawling_sessions/1/audit_results?page_limit=101", expectedStatus: http.StatusBadRequest},
+		{name: "comparison from another sku", path: "/api/crawling_sessions/1/audit_results?comparison_crawling_session_id=3", expectedStatus: http.StatusBadRequest},
+		{name: "comparison not found", path: "/api/crawling_sessions/1/audit_results?comparison_crawling_session_id=9", expectedStatus: http.StatusBadRequest},
+		{name: "session not found", path: "/api/crawling_sessions/9/audit_results", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupCrawlingSessionApp(nil, seed, nil, nil, nil, newAuditRepo(), matches, nil)
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("fiber request failed: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("expected status %d got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.assertBody == nil {
+				return
+			}
+
+			var out sessionsDto.AuditResultsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			tt.assertBody(t, out)
+		})
+	}
+}
+
+func TestAuditResultsSnapshotAtCompletion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	_ = sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 77, URL: "https://example.com", Status: "processing"})
+	auditRepo := repository.NewInMemoryAuditCheckRepository()
+	check := &models.AuditCheck{SearchKeywordURLID: 77, Name: "Missing title", Category: "error", FilterConfig: map[string]any{"tag": "title"}}
+	_ = auditRepo.Create(ctx, check)
+	results := repository.NewInMemoryAuditResultRepository()
+	matches := fakeMatchRepo{
+		counts: map[int64]map[string]int{1: {"title": 3, "edited": 40}},
+		pages:  []models.Page{{ID: 10}, {ID: 11}},
+	}
+
+	wrapped := postcrawl.NewSessionRepository(sessionRepo, nil, postcrawl.NewAuditResultsStage(auditRepo, matches, results))
+	if err := wrapped.MarkDone(ctx, 1, "finished"); err != nil {
+		t.Fatalf("mark done: %v", err)
+	}
+	snapshot, _ := results.ListBySession(ctx, 1)
+	if len(snapshot) != 1 || snapshot[0].MatchedPages != 3 || !reflect.DeepEqual(snapshot[0].SamplePageIDs, []int64{10, 11}) {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	// Editing the check afterwards must not change the stored results.
+	check.Name = "Renamed"
+	check.FilterConfig = map[string]any{"tag": "edited"}
+	_ = auditRepo.Update(ctx, check)
+
+	app := setupCrawlingSessionApp(func() repository.CrawlingSessionRepository { return wrapped }, nil, nil, nil, nil, auditRepo, matches, results)
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/crawling_sessions/1/audit_results", nil))
+	if err != nil {
+		t.Fatalf("fiber request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200 got %d", resp.StatusCode)
+	}
+	var out sessionsDto.AuditResultsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if out.Data.Source != sessionsDto.AuditResultsSourceSnapshot {
+		t.Fatalf("expected snapshot source got %q", out.Data.Source)
+	}
+	if len(out.Data.Checks) != 1 || out.Data.Checks[0].Name != "Missing title" || out.Data.Checks[0].MatchedPages != 3 {
+		t.Fatalf("unexpected results %+v", out.Data.Checks)
+	}
+}
+
+// =============================================================================
+// SESSION DIFF TESTS
+// =============================================================================
+
+func TestCrawlingSessionDiff(t *testing.T) {
+	t.Parallel()
+
+	seed := func(repo *repository.InMemoryCrawlingSessionRepository) {
+		ctx := context.Background()
+		for _, sku := range []int64{1, 1, 1, 2} {
+			_ = repo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: sku})
+		}
+		_ = repo.MarkDone(ctx, 1, "finished")
+	}
+	snapshots := fakeSnapshotRepo{bySession: map[int64][]models.PageSnapshot{
+		1: {
+			{ID: 1, URL: "https://example.com/", ResponseCode: 200, Title: "Home"},
+			{ID: 2, URL: "https://example.com/old", ResponseCode: 200},
+			{ID: 3, URL: "https://example.com/a", ResponseCode: 200},
+		},
+		3: {
+			{ID: 11, URL: "https://example.com", ResponseCode: 200, Title: "Welcome"},
+			{ID: 12, URL: "https://example.com/a", ResponseCode: 200},
+			{ID: 13, URL: "https://example.com/b", ResponseCode: 200},
+			{ID: 14, URL: "https://example.com/c", ResponseCode: 200},
+		},
+	}}
+	app := setupCrawlingSessionApp(nil, seed, nil, nil, snapshots, nil, nil, nil)
+
+	// Session 3 is compared with session 1, the previous finished one.
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/3/diff?page_limit=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out sessionsDto.SessionDiffResponse
+	decodeBody(t, resp, &out)
+	data := out.Data
+	if data.CrawlingSessionID != 3 || data.AgainstSessionID != 1 {
+		t.Fatalf("unexpected sessions %+v", data)
+	}
+	if data.Added == nil || data.Added.Total != 2 || len(data.Added.Pages) != 1 || data.Added.Pages[0].URL != "https://example.com/b" {
+		t.Fatalf("unexpected added %+v", data.Added)
+	}
+	if data.Removed == nil || data.Removed.Total != 1 || data.Removed.Pages[0].PreviousPageID != 2 {
+		t.Fatalf("unexpected removed %+v", data.Removed)
+	}
+	if data.Changed == nil || data.Changed.Total != 1 || data.Changed.Pages[0].Fields[0].Field != "title" {
+		t.Fatalf("unexpected changed %+v", data.Changed)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/3/diff?against=1&change=added&page=2&page_limit=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	out = sessionsDto.SessionDiffResponse{}
+	decodeBody(t, resp, &out)
+	if out.Data.Removed != nil || out.Data.Changed != nil {
+		t.Fatalf("expected only added pages, got %+v", out.Data)
+	}
+	if out.Data.Added.Total != 2 || len(out.Data.Added.Pages) != 1 || out.Data.Added.Pages[0].URL != "https://example.com/c" {
+		t.Fatalf("unexpected second page %+v", out.Data.Added)
+	}
+
+	cases := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"invalid change", "/api/crawling_sessions/3/diff?change=moved", http.StatusBadRequest},
+		{"invalid against", "/api/crawling_sessions/3/diff?against=x", http.StatusBadRequest},
+		{"invalid page limit", "/api/crawling_sessions/3/diff?page_limit=x", http.StatusBadRequest},
+		{"page limit above maximum", "/api/crawling_sessions/3/diff?page_limit=201", http.StatusBadRequest},
+		{"against itself", "/api/crawling_sessions/3/diff?against=3", http.StatusBadRequest},
+		{"against other sku", "/api/crawling_sessions/3/diff?against=4", http.StatusBadRequest},
+		{"against unknown", "/api/crawling_sessions/3/diff?against=99", http.StatusBadRequest},
+		{"unknown session", "/api/crawling_sessions/99/diff", http.StatusNotFound},
+		{"no previous session", "/api/crawling_sessions/4/diff", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		if resp := doAlertRequest(t, app, http.MethodGet, tc.path, ""); resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected status %d got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+}
+
 // =============================================================================
 // HELPER: UNIFIED TEST APP SETUP
 // =============================================================================
@@ -372,6 +786,24 @@ func setupCrawlingSessionApp(
 	sessionSeed func(*repository.InMemoryCrawlingSessionRepository),
 	pageRepo repository.CrawlingSessionPageRepository,
 	checkRepo repository.CrawlingSessionCheckRepository,
+	snapshotRepo repository.PageSnapshotRepository,
+	auditRepo repository.AuditCheckRepository,
+	matchRepo repository.PageMatchRepository,
+	resultRepo repository.AuditResultRepository,
+) *fiber.App {
+	return setupCrawlingSessionAppWithViews(sessionRepoFactory, sessionSeed, pageRepo, checkRepo, snapshotRepo, auditRepo, matchRepo, resultRepo, nil)
+}
+
+func setupCrawlingSessionAppWithViews(
+	sessionRepoFactory func() repository.CrawlingSessionRepository,
+	sessionSeed func(*repository.InMemoryCrawlingSessionRepository),
+	pageRepo repository.CrawlingSessionPageRepository,
+	checkRepo repository.CrawlingSessionCheckRepository,
+	snapshotRepo repository.PageSnapshotRepository,
+	auditRepo repository.AuditCheckRepository,
+	matchRepo repository.PageMatchRepository,
+	resultRepo repository.AuditResultRepository,
+	viewRepo repository.ViewRepository,
 ) *fiber.App {
 	// Session repository
 	sessionRepo := repository.CrawlingSessionRepository(nil)
@@ -397,24 +829,53 @@ func setupCrawlingSessionApp(
 		checkRepo = repository.NewNoopCrawlingSessionCheckRepository()
 	}
 
+	// Snapshot repository
+	if snapshotRepo == nil {
+		snapshotRepo = repository.NewNoopPageSnapshotRepository()
+	}
+
+	// Audit check, page match and audit result repositories
+	if auditRepo == nil {
+		auditRepo = repository.NewInMemoryAuditCheckRepository()
+	}
+	if matchRepo == nil {
+		matchRepo = repository.NewNoopPageMatchRepository()
+	}
+	if resultRepo == nil {
+		resultRepo = repository.NewInMemoryAuditResultRepository()
+	}
+
+	// View repository
+	if viewRepo == nil {
+		viewRepo = repository.NewInMemoryViewRepository()
+	}
+
 	app := fiber.New()
 
 	// Health
 	healthController := health.NewController(nil)
 
 	// Crawling session service using unified service
-	sessionService := sessionsvc.NewService(sessionRepo, pageRepo, checkRepo)
+	sessionService := sessionsvc.NewService(sessionRepo, pageRepo, checkRepo, snapshotRepo, repository.NewSnapshotSessionDiffRepository(snapshotRepo), auditRepo, matchRepo, resultRepo, viewRepo)
 	crawlingCreateController := sessions.NewCreateController(sessionService, nil)
 	crawlingGetController := sessions.NewGetController(sessionService, nil)
 	pagesController := sessions.NewPagesController(sessionService, nil)
 	checksController := sessions.NewChecksController(sessionService, nil)
+	duplicatesController := sessions.NewDuplicatesController(sessionService, nil)
+	hreflangController := sessions.NewHreflangController(sessionService, nil)
+	auditResultsController := sessions.NewAuditResultsController(sessionService, nil)
+	diffController := sessions.NewDiffController(sessionService, nil)
 
 	routes.Register(app, routes.Dependencies{
-		Health:                healthController,
-		CrawlingSessionCreate: crawlingCreateController,
-		CrawlingSessionGet:    crawlingGetController,
-		CrawlingSessionPages:  pagesController,
-		CrawlingSessionChecks: checksController,
+		Health:                      healthController,
+		CrawlingSessionCreate:       crawlingCreateController,
+		CrawlingSessionGet:          crawlingGetController,
+		CrawlingSessionPages:        pagesController,
+		CrawlingSessionChecks:       checksController,
+		CrawlingSessionDuplicates:   duplicatesController,
+		CrawlingSessionHreflang:     hreflangController,
+		CrawlingSessionAuditResults: auditResultsController,
+		CrawlingSessionDiff:         diffController,
 	})
 
 	return app
@@ -460,6 +921,10 @@ func (f failingCrawlingRepo) MarkDone(ctx context.Context, id int64, reason stri
 	return nil
 }
 
+func (f failingCrawlingRepo) ListDoneBySKU(ctx context.Context, skuID int64, from, to *time.Time) ([]models.CrawlingSession, error) {
+	return nil, nil
+}
+
 func (f failingCrawlingRepo) UpdateSiteInfo(ctx context.Context, id int64, info repository.SiteInfo) error {
 	return nil
 }
@@ -492,3 +957,62 @@ func (f fakeChecksRepo) ChecksWithPages(ctx context.Context, params repository.C
 	}
 	return f.checks, nil
 }
+
+type fakeSnapshotRepo struct {
+	snapshots []models.PageSnapshot
+	bySession map[int64][]models.PageSnapshot
+	links     map[int64][]models.HreflangLink
+	err       error
+}
+
+func (f fakeSnapshotRepo) ListSnapshots(ctx context.Context, sessionID int64) ([]models.PageSnapshot, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.bySession != nil {
+		return f.bySession[sessionID], nil
+	}
+	return f.snapshots, nil
+}
+
+func (f fakeSnapshotRepo) ListHreflangLinks(ctx context.Context, sessionID int64) (map[int64][]models.HreflangLink, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.links, nil
+}
+
+// fakeMatchRepo counts configs by their "tag" entry; a "broken" tag fails like
+// an invalid filter_config would.
+type fakeMatchRepo struct {
+	counts map[int64]map[string]int
+	pages  []models.Page
+}
+
+func (f fakeMatchRepo) CountMatches(ctx context.Context, sessionID int64, configs []map[string]any) ([]int, error) {
+	out := make([]int, len(configs))
+	for i, cfg := range configs {
+		tag, _ := cfg["tag"].(string)
+		if tag == "broken" {
+			return nil, errors.New("invalid filter column: nope")
+		}
+		out[i] = f.counts[sessionID][tag]
+	}
+	return out, nil
+}
+
+func (f fakeMatchRepo) CountMatchesBySession(ctx context.Context, sessionIDs []int64, configs []map[string]any) (map[int64][]int, error) {
+	out := make(map[int64][]int, len(sessionIDs))
+	for _, id := range sessionIDs {
+		counts, err := f.CountMatches(ctx, id, configs)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = counts
+	}
+	return out, nil
+}
+
+func (f fakeMatchRepo) ListMatches(ctx context.Context, sessionID int64, config map[string]any, page, limit int) ([]models.Page, int, error) {
+	return f.pages, len(f.pages), nil
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/models"
+)
+
+func TestStatsIndexabilitySection(t *testing.T) {
+	t.Parallel()
+
+	indexability := map[string]any{
+		"indexable":     3,
+		"non_indexable": 2,
+		"reasons":       map[string]int{"noindex": 1, "canonicalized": 1},
+	}
+	app := setupStatsApp(fakeStatsRepo{result: map[string]any{"total": 5, "indexability": indexability}}, nil)
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/stats?crawling_session_id=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out struct {
+		Data struct {
+			Indexability struct {
+				Indexable    int            `json:"indexable"`
+				NonIndexable int            `json:"non_indexable"`
+				Reasons      map[string]int `json:"reasons"`
+			} `json:"indexability"`
+		} `json:"data"`
+	}
+	decodeBody(t, resp, &out)
+	got := out.Data.Indexability
+	if got.Indexable != 3 || got.NonIndexable != 2 ||
+		!reflect.DeepEqual(got.Reasons, map[string]int{"noindex": 1, "canonicalized": 1}) {
+		t.Fatalf("unexpected indexability section %+v", got)
+	}
+}
+
+func TestSessionPagesIndexabilityFields(t *testing.T) {
+	t.Parallel()
+
+	pageRepo := fakePageRepo{
+		pages: []models.Page{
+			{ID: 1, CrawlingSessionID: 10, URL: "https://example.com/", ResponseCode: 200, Indexability: models.IndexabilityIndexable},
+			{ID: 2, CrawlingSessionID: 10, URL: "https://example.com/private", ResponseCode: 200,
+				Indexability: models.IndexabilityNonIndexable, IndexabilityReason: "noindex"},
+			{ID: 3, CrawlingSessionID: 10, URL: "https://example.com/unevaluated", ResponseCode: 200},
+		},
+		total: 3,
+	}
+	app := setupCrawlingSessionApp(nil, nil, pageRepo, nil, nil, nil, nil, nil)
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/10/pages", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out struct {
+		Data struct {
+			Pages []map[string]any `json:"pages"`
+		} `json:"data"`
+	}
+	decodeBody(t, resp, &out)
+	if len(out.Data.Pages) != 3 {
+		t.Fatalf("expected 3 pages got %d", len(out.Data.Pages))
+	}
+
+	if p := out.Data.Pages[0]; p["indexability"] != "indexable" || p["indexability_reason"] != nil {
+		t.Fatalf("unexpected indexable page %v", p)
+	}
+	if p := out.Data.Pages[1]; p["indexability"] != "non_indexable" || p["indexability_reason"] != "noindex" {
+		t.Fatalf("unexpected non-indexable page %v", p)
+	}
+	// Pages crawled before indexability was evaluated carry neither field.
+	if p := out.Data.Pages[2]; p["indexability"] != nil || p["indexability_reason"] != nil {
+		t.Fatalf("expected no indexability fields got %v", p)
+	}
+}
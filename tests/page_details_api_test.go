@@ -140,7 +140,7 @@ func setupPageDetailsApp(repo repository.PageDetailsRepository) *fiber.App {
 	}
 
 	// Use unified stats service
-	service := statssvc.NewService(repository.NewNoopStatsRepository(), repo)
+	service := statssvc.NewService(repository.NewNoopStatsRepository(), repo, repository.NewInMemoryCrawlingSessionRepository(), repository.NewInMemoryViewRepository())
 	controller := stats.NewPageDetailsController(service, nil)
 
 	routes.Register(app, routes.Dependencies{
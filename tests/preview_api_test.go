@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+func TestAuditCheckPreview(t *testing.T) {
+	t.Parallel()
+
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	_ = sessionRepo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: 1})
+	matches := &fakePreviewMatchRepo{total: 42, pages: []models.Page{{ID: 7, URL: "https://example.com/a"}}}
+	app := setupAuditAppWith(repository.NewInMemoryAuditCheckRepository(), sessionRepo, matches, nil)
+
+	resp := doAlertRequest(t, app, http.MethodPost, "/api/audit_checks/preview",
+		`{"data":{"crawling_session_id":1,"limit":5,"filter_config":{"filter_groups":[{"filters":[{"name":"response_code","operator":"gte","value":500}]}]}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out auditsDto.AuditCheckPreviewResponse
+	decodeBody(t, resp, &out)
+	if out.Data.PageCount != 42 || len(out.Data.Pages) != 1 || out.Data.Pages[0].ID != 7 {
+		t.Fatalf("unexpected preview %+v", out.Data)
+	}
+	if matches.sessionID != 1 || matches.limit != 5 || matches.page != 1 {
+		t.Fatalf("unexpected match call: session %d page %d limit %d", matches.sessionID, matches.page, matches.limit)
+	}
+	if _, ok := matches.config["filter_groups"]; !ok {
+		t.Fatalf("expected the unsaved filter_config to be evaluated, got %+v", matches.config)
+	}
+
+	// Nothing is stored.
+	var listed auditsDto.AuditChecksResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodGet, "/api/audit_checks?search_keyword_url_id=1", ""), &listed)
+	if len(listed.Data) != 0 {
+		t.Fatalf("expected no stored checks, got %+v", listed.Data)
+	}
+
+	cases := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing session", `{"data":{"filter_config":{}}}`, http.StatusBadRequest},
+		{"limit too large", `{"data":{"crawling_session_id":1,"limit":1000}}`, http.StatusBadRequest},
+		{"bad operator", `{"data":{"crawling_session_id":1,"filter_config":{"filter_groups":[{"filters":[{"name":"depth","operator":"like","value":1}]}]}}}`, http.StatusBadRequest},
+		{"unknown session", `{"data":{"crawling_session_id":99}}`, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		if resp := doAlertRequest(t, app, http.MethodPost, "/api/audit_checks/preview", tc.body); resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected status %d got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestViewPreview(t *testing.T) {
+	t.Parallel()
+
+	matches := &fakePreviewMatchRepo{total: 3, pages: []models.Page{{ID: 1}, {ID: 2}, {ID: 3}}}
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	for i := 0; i < 4; i++ {
+		_ = sessionRepo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: 1})
+	}
+	app := setupViewAppWith(repository.NewInMemoryViewRepository(), sessionRepo, nil, matches)
+
+	var out viewsDto.ViewPreviewResponse
+	resp := doAlertRequest(t, app, http.MethodPost, "/api/views/preview",
+		`{"data":{"crawling_session_id":4,"filter_config":{"filter_groups":[{"response_code":301},{"response_code":302}]}}}`)
+	decodeBody(t, resp, &out)
+	if out.Data.PageCount != 3 || len(out.Data.Pages) != 3 {
+		t.Fatalf("unexpected preview %+v", out.Data)
+	}
+	if matches.sessionID != 4 || matches.limit != 20 {
+		t.Fatalf("expected the default limit for session 4, got session %d limit %d", matches.sessionID, matches.limit)
+	}
+
+	// The saved view counts through the same evaluation path.
+	doAlertRequest(t, app, http.MethodPost, "/api/views", `{"data":{"search_keyword_url_id":1,"name":"Redirects","filter_config":{"filter_groups":[{"response_code":301},{"response_code":302}]}}}`)
+	var count viewsDto.ViewPageCountResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodGet, "/api/views/1/page_count?view_id=1&crawling_session_id=4", ""), &count)
+	if count.Data.PageCount != 3 || !reflect.DeepEqual(matches.counted, matches.config) {
+		t.Fatalf("expected page_count to match the preview, got %d for %+v", count.Data.PageCount, matches.counted)
+	}
+
+	matches.err = errors.New("invalid filter column: nope")
+	if resp := doAlertRequest(t, app, http.MethodPost, "/api/views/preview", `{"data":{"crawling_session_id":4}}`); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+	if resp := doAlertRequest(t, app, http.MethodPost, "/api/views/preview", `{"data":{"crawling_session_id":4,"limit":-1}}`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+	if resp := doAlertRequest(t, app, http.MethodPost, "/api/views/preview", `{"data":{"crawling_session_id":99}}`); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+// fakePreviewMatchRepo records the last evaluated configs and answers with a
+// fixed result.
+type fakePreviewMatchRepo struct {
+	total int
+	pages []models.Page
+	err   error
+
+	sessionID   int64
+	page, limit int
+	config      map[string]any
+	counted     map[string]any
+}
+
+func (f *fakePreviewMatchRepo) CountMatches(ctx context.Context, sessionID int64, configs []map[string]any) ([]int, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.counted = configs[0]
+	out := make([]int, len(configs))
+	for i := range out {
+		out[i] = f.total
+	}
+	return out, nil
+}
+
+func (f *fakePreviewMatchRepo) CountMatchesBySession(ctx context.Context, sessionIDs []int64, configs []map[string]any) (map[int64][]int, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return repository.ZeroCountsBySession(sessionIDs, len(configs)), nil
+}
+
+func (f *fakePreviewMatchRepo) ListMatches(ctx context.Context, sessionID int64, config map[string]any, page, limit int) ([]models.Page, int, error) {
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	f.sessionID, f.page, f.limit, f.config = sessionID, page, limit, config
+	return f.pages, f.total, nil
+}
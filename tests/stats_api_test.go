@@ -8,6 +8,7 @@ statsDto "sitecrawler/newgo/dto/stats"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -15,6 +16,7 @@ statsDto "sitecrawler/newgo/dto/stats"
 	"sitecrawler/newgo/controllers/stats"
 	"sitecrawler/newgo/internal/repository"
 	statssvc "sitecrawler/newgo/internal/services/stats"
+	"sitecrawler/newgo/models"
 	"sitecrawler/newgo/routes"
 )
 
@@ -83,7 +85,7 @@ func TestStatsAPI(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			app := setupStatsApp(tt.repo)
+			app := setupStatsApp(tt.repo, nil)
 			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
 			resp, err := app.Test(req)
 			if err != nil {
@@ -99,7 +101,82 @@ func TestStatsAPI(t *testing.T) {
 	}
 }
 
-func setupStatsApp(repo repository.StatsRepository) *fiber.App {
+func TestStatsHistoryAPI(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newSessionRepo := func() repository.CrawlingSessionRepository {
+		repo := repository.NewInMemoryCrawlingSessionRepository()
+		for i, sku := range []int64{5, 5, 5, 9, 5} {
+			ended := base.AddDate(0, 0, i)
+			_ = repo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: sku, Status: "done", EndedAt: &ended})
+		}
+		_ = repo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: 5, Status: "processing"})
+		return repo
+	}
+	statsRepo := fakeStatsRepo{bySession: map[int64]map[string]any{
+		1: {"site_health": 60, "performance": map[string]any{"slow_pages": 4}},
+		2: {"site_health": 70},
+		3: {"site_health": 90, "performance": map[string]any{"slow_pages": 1}},
+		4: {"site_health": 10},
+		5: {"site_health": 100},
+	}}
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		wantSessions   []int64
+		wantValues     []float64
+	}{
+		{name: "chronological series", path: "/api/stats/history?search_keyword_url_id=5&metric=site_health", expectedStatus: http.StatusOK, wantSessions: []int64{1, 2, 3, 5}, wantValues: []float64{60, 70, 90, 100}},
+		{name: "nested metric skips sessions without it", path: "/api/stats/history?search_keyword_url_id=5&metric=performance.slow_pages", expectedStatus: http.StatusOK, wantSessions: []int64{1, 3}, wantValues: []float64{4, 1}},
+		{name: "date range", path: "/api/stats/history?search_keyword_url_id=5&metric=site_health&from=2026-03-02&to=2026-03-03", expectedStatus: http.StatusOK, wantSessions: []int64{2, 3}, wantValues: []float64{70, 90}},
+		{name: "downsampled", path: "/api/stats/history?search_keyword_url_id=5&metric=site_health&points=2", expectedStatus: http.StatusOK, wantSessions: []int64{2, 5}, wantValues: []float64{65, 95}},
+		{name: "limit keeps the latest sessions", path: "/api/stats/history?search_keyword_url_id=5&metric=site_health&limit=2", expectedStatus: http.StatusOK, wantSessions: []int64{3, 5}, wantValues: []float64{90, 100}},
+		{name: "missing metric", path: "/api/stats/history?search_keyword_url_id=5", expectedStatus: http.StatusBadRequest},
+		{name: "invalid sku", path: "/api/stats/history?metric=site_health", expectedStatus: http.StatusBadRequest},
+		{name: "invalid date", path: "/api/stats/history?search_keyword_url_id=5&metric=site_health&from=soon", expectedStatus: http.StatusBadRequest},
+		{name: "limit above maximum", path: "/api/stats/history?search_keyword_url_id=5&metric=site_health&limit=101", expectedStatus: http.StatusBadRequest},
+		{name: "invalid points", path: "/api/stats/history?search_keyword_url_id=5&metric=site_health&points=-1", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupStatsApp(statsRepo, newSessionRepo())
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("fiber request failed: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("expected status %d got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var out statsDto.MetricHistoryResponse
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(out.Data.Points) != len(tt.wantSessions) {
+				t.Fatalf("expected %d points got %+v", len(tt.wantSessions), out.Data.Points)
+			}
+			for i, p := range out.Data.Points {
+				if p.SessionID != tt.wantSessions[i] || p.Value != tt.wantValues[i] {
+					t.Fatalf("unexpected point %d: %+v", i, p)
+				}
+			}
+		})
+	}
+}
+
+func setupStatsApp(repo repository.StatsRepository, sessionRepo repository.CrawlingSessionRepository) *fiber.App {
+	return setupStatsAppWith(repo, sessionRepo, nil)
+}
+
+func setupStatsAppWith(repo repository.StatsRepository, sessionRepo repository.CrawlingSessionRepository, viewRepo repository.ViewRepository) *fiber.App {
 	app := fiber.New()
 
 	healthController := health.NewController(nil)
@@ -107,28 +184,40 @@ func setupStatsApp(repo repository.StatsRepository) *fiber.App {
 	if repo == nil {
 		repo = fakeStatsRepo{}
 	}
+	if sessionRepo == nil {
+		sessionRepo = repository.NewInMemoryCrawlingSessionRepository()
+	}
+	if viewRepo == nil {
+		viewRepo = repository.NewInMemoryViewRepository()
+	}
 
 	// Use unified stats service
-	statsService := statssvc.NewService(repo, repository.NewNoopPageDetailsRepository())
+	statsService := statssvc.NewService(repo, repository.NewNoopPageDetailsRepository(), sessionRepo, viewRepo)
 	statsController := stats.NewStatsController(statsService, nil)
+	historyController := stats.NewHistoryController(statsService, nil)
 
 	routes.Register(app, routes.Dependencies{
-		Health: healthController,
-		Stats:  statsController,
+		Health:       healthController,
+		Stats:        statsController,
+		StatsHistory: historyController,
 	})
 
 	return app
 }
 
 type fakeStatsRepo struct {
-	result map[string]any
-	err    error
+	result    map[string]any
+	bySession map[int64]map[string]any
+	err       error
 }
 
 func (f fakeStatsRepo) Fetch(ctx context.Context, params repository.StatsQueryParams) (map[string]any, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
+	if res, ok := f.bySession[params.SessionID]; ok {
+		return res, nil
+	}
 	if f.result == nil {
 		return map[string]any{}, nil
 	}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sitecrawler/newgo/internal/repository"
+)
+
+func TestStatsGroupBy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		want           *repository.StatsGroupBy
+	}{
+		{name: "ungrouped", query: "", expectedStatus: http.StatusOK},
+		{name: "directory defaults to one segment", query: "&group_by=directory", expectedStatus: http.StatusOK,
+			want: &repository.StatsGroupBy{Field: repository.StatsGroupDirectory, Segments: 1}},
+		{name: "directory segments", query: "&group_by=directory&group_segments=3", expectedStatus: http.StatusOK,
+			want: &repository.StatsGroupBy{Field: repository.StatsGroupDirectory, Segments: 3}},
+		{name: "status class", query: "&group_by=status_class", expectedStatus: http.StatusOK,
+			want: &repository.StatsGroupBy{Field: repository.StatsGroupStatusClass}},
+		{name: "regex", query: "&group_by=regex&group_pattern=%2Fproducts%2F(%5B%5E%2F%5D%2B)", expectedStatus: http.StatusOK,
+			want: &repository.StatsGroupBy{Field: repository.StatsGroupRegex, Pattern: "/products/([^/]+)"}},
+		{name: "unknown field", query: "&group_by=colour", expectedStatus: http.StatusBadRequest},
+		{name: "too many segments", query: "&group_by=directory&group_segments=11", expectedStatus: http.StatusBadRequest},
+		{name: "non numeric segments", query: "&group_by=directory&group_segments=two", expectedStatus: http.StatusBadRequest},
+		{name: "segments without directory", query: "&group_by=depth&group_segments=2", expectedStatus: http.StatusBadRequest},
+		{name: "regex without pattern", query: "&group_by=regex", expectedStatus: http.StatusBadRequest},
+		{name: "invalid pattern", query: "&group_by=regex&group_pattern=(", expectedStatus: http.StatusBadRequest},
+		{name: "pattern without group_by", query: "&group_pattern=x", expectedStatus: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		statsRepo := &recordingStatsRepo{}
+		app := setupStatsApp(statsRepo, nil)
+		resp := doAlertRequest(t, app, http.MethodGet, "/api/stats?crawling_session_id=1"+tc.query, "")
+		if resp.StatusCode != tc.expectedStatus {
+			t.Fatalf("%s: expected status %d got %d", tc.name, tc.expectedStatus, resp.StatusCode)
+		}
+		if tc.expectedStatus == http.StatusOK && !reflect.DeepEqual(statsRepo.params.GroupBy, tc.want) {
+			t.Fatalf("%s: unexpected group_by %+v", tc.name, statsRepo.params.GroupBy)
+		}
+	}
+}
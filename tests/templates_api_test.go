@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	auditsDto "sitecrawler/newgo/dto/audits"
+	viewsDto "sitecrawler/newgo/dto/views"
+)
+
+func TestAuditCheckTemplateLifecycle(t *testing.T) {
+	t.Parallel()
+
+	app := setupAuditApp(nil, nil)
+
+	resp := doAlertRequest(t, app, http.MethodPost, "/api/audit_check_templates",
+		`{"data":{"name":"5xx","category":"error","severity":"critical","filter_config":{"filter_groups":[{"response_code":500}]}}}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d got %d", http.StatusCreated, resp.StatusCode)
+	}
+	var template auditsDto.AuditCheckTemplateResponse
+	decodeBody(t, resp, &template)
+	if template.Data.ID != 1 || template.Data.Version != 1 {
+		t.Fatalf("unexpected template %+v", template.Data)
+	}
+
+	var applied auditsDto.ApplyAuditCheckTemplateResponse
+	resp = doAlertRequest(t, app, http.MethodPost, "/api/audit_check_templates/1/apply", `{"data":{"search_keyword_url_ids":[10,11,10]}}`)
+	decodeBody(t, resp, &applied)
+	if !reflect.DeepEqual(applied.Data.Applied, []int64{10, 11}) || len(applied.Data.Existing) != 0 {
+		t.Fatalf("unexpected apply result %+v", applied.Data)
+	}
+	check := applied.Data.Checks[0]
+	if check.TemplateID == nil || *check.TemplateID != 1 || check.TemplateVersion != 1 || check.Severity != "critical" {
+		t.Fatalf("expected a linked copy, got %+v", check)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodPost, "/api/audit_check_templates/1/apply", `{"data":{"search_keyword_url_ids":[11,12]}}`)
+	applied = auditsDto.ApplyAuditCheckTemplateResponse{}
+	decodeBody(t, resp, &applied)
+	if !reflect.DeepEqual(applied.Data.Applied, []int64{12}) || !reflect.DeepEqual(applied.Data.Existing, []int64{11}) {
+		t.Fatalf("unexpected second apply result %+v", applied.Data)
+	}
+
+	// SKU 11 edits its copy, which opts it out of propagation.
+	if resp = doAlertRequest(t, app, http.MethodPut, "/api/audit_checks/2", `{"data":{"weight":3}}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	// An update without changes keeps the version.
+	resp = doAlertRequest(t, app, http.MethodPut, "/api/audit_check_templates/1", `{"data":{"name":"5xx"}}`)
+	decodeBody(t, resp, &template)
+	if template.Data.Version != 1 {
+		t.Fatalf("expected version 1 got %d", template.Data.Version)
+	}
+	resp = doAlertRequest(t, app, http.MethodPut, "/api/audit_check_templates/1", `{"data":{"filter_config":{"filter_groups":[{"response_code":503}]}}}`)
+	decodeBody(t, resp, &template)
+	if template.Data.Version != 2 {
+		t.Fatalf("expected version 2 got %d", template.Data.Version)
+	}
+
+	// Nothing changes on the SKUs until the update is propagated.
+	var got auditsDto.AuditCheckResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodGet, "/api/audit_checks/1", ""), &got)
+	if got.Data.TemplateVersion != 1 {
+		t.Fatalf("expected the copy to stay at version 1, got %+v", got.Data)
+	}
+
+	var propagated auditsDto.PropagateAuditCheckTemplateResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodPost, "/api/audit_check_templates/1/propagate", `{"data":{"search_keyword_url_ids":[10,11]}}`), &propagated)
+	if !reflect.DeepEqual(propagated.Data.Updated, []int64{1}) || !reflect.DeepEqual(propagated.Data.Skipped, []int64{2}) {
+		t.Fatalf("unexpected propagation %+v", propagated.Data)
+	}
+	propagated = auditsDto.PropagateAuditCheckTemplateResponse{}
+	decodeBody(t, doAlertRequest(t, app, http.MethodPost, "/api/audit_check_templates/1/propagate", ""), &propagated)
+	if !reflect.DeepEqual(propagated.Data.Updated, []int64{3}) || !reflect.DeepEqual(propagated.Data.Unchanged, []int64{1}) {
+		t.Fatalf("unexpected propagation %+v", propagated.Data)
+	}
+	decodeBody(t, doAlertRequest(t, app, http.MethodGet, "/api/audit_checks/1", ""), &got)
+	if got.Data.TemplateVersion != 2 || !reflect.DeepEqual(got.Data.FilterConfig, map[string]any{"filter_groups": []any{map[string]any{"response_code": 503.0}}}) {
+		t.Fatalf("expected the copy to be updated, got %+v", got.Data)
+	}
+
+	var deleted auditsDto.DeleteAuditCheckTemplateResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodDelete, "/api/audit_check_templates/1", ""), &deleted)
+	if deleted.Data.Unlinked != 3 {
+		t.Fatalf("expected 3 unlinked checks got %d", deleted.Data.Unlinked)
+	}
+	decodeBody(t, doAlertRequest(t, app, http.MethodGet, "/api/audit_checks/3", ""), &got)
+	if got.Data.TemplateID != nil || got.Data.Name != "5xx" {
+		t.Fatalf("expected an unlinked check, got %+v", got.Data)
+	}
+	if resp = doAlertRequest(t, app, http.MethodGet, "/api/audit_check_templates/1", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestViewTemplateLifecycle(t *testing.T) {
+	t.Parallel()
+
+	app := setupViewApp(nil, nil)
+
+	resp := doAlertRequest(t, app, http.MethodPost, "/api/view_templates", `{"data":{"name":"Redirects","filter_config":{"filter_groups":[{"response_code":301}]}}}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d got %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	var applied viewsDto.ApplyViewTemplateResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodPost, "/api/view_templates/1/apply", `{"data":{"search_keyword_url_ids":[1,2]}}`), &applied)
+	if len(applied.Data.Views) != 2 || applied.Data.Views[1].SearchKeywordURLID != 2 {
+		t.Fatalf("unexpected apply result %+v", applied.Data)
+	}
+
+	if resp = doAlertRequest(t, app, http.MethodPut, "/api/views/1", `{"data":{"name":"My redirects"}}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	doAlertRequest(t, app, http.MethodPut, "/api/view_templates/1", `{"data":{"name":"Permanent redirects"}}`)
+
+	var propagated viewsDto.PropagateViewTemplateResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodPost, "/api/view_templates/1/propagate", ""), &propagated)
+	if propagated.Data.Version != 2 || !reflect.DeepEqual(propagated.Data.Updated, []int64{2}) || !reflect.DeepEqual(propagated.Data.Skipped, []int64{1}) {
+		t.Fatalf("unexpected propagation %+v", propagated.Data)
+	}
+
+	var listed viewsDto.ViewTemplatesResponse
+	decodeBody(t, doAlertRequest(t, app, http.MethodGet, "/api/view_templates", ""), &listed)
+	if len(listed.Data) != 1 || listed.Data[0].Name != "Permanent redirects" {
+		t.Fatalf("unexpected templates %+v", listed.Data)
+	}
+}
+
+func TestTemplateBadRequests(t *testing.T) {
+	t.Parallel()
+
+	auditApp := setupAuditApp(nil, nil)
+	viewApp := setupViewApp(nil, nil)
+	doAlertRequest(t, auditApp, http.MethodPost, "/api/audit_check_templates", `{"data":{"name":"x","category":"error"}}`)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		views  bool
+	}{
+		{"missing name", http.MethodPost, "/api/audit_check_templates", `{"data":{"category":"error"}}`, http.StatusBadRequest, false},
+		{"bad category", http.MethodPost, "/api/audit_check_templates", `{"data":{"name":"x","category":"cat-1"}}`, http.StatusBadRequest, false},
+		{"bad filter_config", http.MethodPost, "/api/audit_check_templates", `{"data":{"name":"x","category":"error","filter_config":{"filter_groups":[{"filters":[{"name":"depth","operator":"like","value":1}]}]}}}`, http.StatusBadRequest, false},
+		{"bad update weight", http.MethodPut, "/api/audit_check_templates/1", `{"data":{"weight":-1}}`, http.StatusBadRequest, false},
+		{"apply without skus", http.MethodPost, "/api/audit_check_templates/1/apply", `{"data":{}}`, http.StatusBadRequest, false},
+		{"apply bad sku", http.MethodPost, "/api/audit_check_templates/1/apply", `{"data":{"search_keyword_url_ids":[0]}}`, http.StatusBadRequest, false},
+		{"apply missing template", http.MethodPost, "/api/audit_check_templates/9/apply", `{"data":{"search_keyword_url_ids":[1]}}`, http.StatusNotFound, false},
+		{"propagate missing template", http.MethodPost, "/api/audit_check_templates/9/propagate", "", http.StatusNotFound, false},
+		{"bad id", http.MethodGet, "/api/audit_check_templates/abc", "", http.StatusBadRequest, false},
+		{"view bad filter_config", http.MethodPost, "/api/view_templates", `{"data":{"name":"x","filter_config":{"filter_groups":"all"}}}`, http.StatusBadRequest, true},
+		{"view empty name", http.MethodPut, "/api/view_templates/1", `{"data":{"name":" "}}`, http.StatusBadRequest, true},
+		{"view missing template", http.MethodDelete, "/api/view_templates/9", "", http.StatusNotFound, true},
+	}
+	for _, tc := range cases {
+		app := auditApp
+		if tc.views {
+			app = viewApp
+		}
+		resp := doAlertRequest(t, app, tc.method, tc.path, tc.body)
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected status %d got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+}
+
+func decodeBody(t *testing.T, resp *http.Response, out any) {
+	t.Helper()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	sessionsDto "sitecrawler/newgo/dto/sessions"
+	viewsDto "sitecrawler/newgo/dto/views"
+	"sitecrawler/newgo/internal/repository"
+	"sitecrawler/newgo/models"
+)
+
+func TestViewSettingsPersisted(t *testing.T) {
+	t.Parallel()
+
+	app := setupViewApp(nil, nil)
+
+	resp := doAlertRequest(t, app, http.MethodPost, "/api/views",
+		`{"data":{"search_keyword_url_id":1,"name":"errors","filter_config":{},"columns":["url","response_code"],"sort":"depth","direction":"DESC","page_size":50,"compare_with_previous":true}}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d got %d", http.StatusCreated, resp.StatusCode)
+	}
+	var created viewsDto.ViewResponse
+	decodeBody(t, resp, &created)
+	v := created.Data
+	if !reflect.DeepEqual(v.Columns, []string{"url", "response_code"}) || v.SortField != "depth" || v.SortDirection != "desc" || v.PageSize != 50 || !v.CompareWithPrevious {
+		t.Fatalf("unexpected settings %+v", v)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodPut, "/api/views/1", `{"data":{"page_size":20,"compare_with_previous":false}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var updated viewsDto.ViewResponse
+	decodeBody(t, resp, &updated)
+	v = updated.Data
+	if !reflect.DeepEqual(v.Columns, []string{"url", "response_code"}) || v.SortField != "depth" || v.PageSize != 20 || v.CompareWithPrevious {
+		t.Fatalf("unexpected settings after update %+v", v)
+	}
+}
+
+func TestViewSettingsValidation(t *testing.T) {
+	t.Parallel()
+
+	app := setupViewApp(nil, nil)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"bad column", http.MethodPost, "/api/views", `{"data":{"search_keyword_url_id":1,"name":"v","columns":["url;drop"]}}`},
+		{"bad sort", http.MethodPost, "/api/views", `{"data":{"search_keyword_url_id":1,"name":"v","sort":"1x"}}`},
+		{"bad direction", http.MethodPost, "/api/views", `{"data":{"search_keyword_url_id":1,"name":"v","direction":"up"}}`},
+		{"page size too large", http.MethodPost, "/api/views", `{"data":{"search_keyword_url_id":1,"name":"v","page_size":501}}`},
+		{"negative page size on update", http.MethodPut, "/api/views/1", `{"data":{"page_size":-1}}`},
+	}
+	for _, tc := range cases {
+		resp := doAlertRequest(t, app, tc.method, tc.path, tc.body)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d got %d", tc.name, http.StatusBadRequest, resp.StatusCode)
+		}
+	}
+}
+
+func TestSessionPagesWithView(t *testing.T) {
+	t.Parallel()
+
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	viewRepo := repository.NewInMemoryViewRepository()
+	ctx := context.Background()
+	if err := sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 1}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	cfg := map[string]any{"filter_groups": []any{map[string]any{"response_code": 404}}}
+	for _, v := range []models.View{
+		{SearchKeywordURLID: 1, Name: "errors", FilterConfig: cfg, Columns: []string{"url"}, SortField: "depth", SortDirection: "desc", PageSize: 25},
+		{SearchKeywordURLID: 2, Name: "other"},
+	} {
+		if err := viewRepo.Create(ctx, &v); err != nil {
+			t.Fatalf("create view: %v", err)
+		}
+	}
+	pageRepo := &recordingPageRepo{pages: []models.Page{{ID: 3}}, total: 1}
+	app := setupCrawlingSessionAppWithViews(func() repository.CrawlingSessionRepository { return sessionRepo }, nil, pageRepo, nil, nil, nil, nil, nil, viewRepo)
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/1/pages?view_id=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out sessionsDto.CrawlingSessionPagesResponse
+	decodeBody(t, resp, &out)
+	if !reflect.DeepEqual(out.Data.Columns, []string{"url"}) {
+		t.Fatalf("unexpected columns %v", out.Data.Columns)
+	}
+	want := repository.PageListParams{SessionID: 1, FilterConfig: cfg, Sort: "depth", Direction: "desc", PageLimit: 25}
+	if !reflect.DeepEqual(pageRepo.params, want) {
+		t.Fatalf("unexpected list params %+v", pageRepo.params)
+	}
+
+	// Explicit request values win over the view's defaults.
+	resp = doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/1/pages?view_id=1&sort=url&page_limit=5", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	if pageRepo.params.Sort != "url" || pageRepo.params.Direction != "" || pageRepo.params.PageLimit != 5 {
+		t.Fatalf("unexpected overridden params %+v", pageRepo.params)
+	}
+
+	for path, status := range map[string]int{
+		"/api/crawling_sessions/1/pages?view_id=abc": http.StatusBadRequest,
+		"/api/crawling_sessions/1/pages?view_id=2":   http.StatusBadRequest,
+		"/api/crawling_sessions/1/pages?view_id=99":  http.StatusNotFound,
+		"/api/crawling_sessions/9/pages?view_id=1":   http.StatusNotFound,
+	} {
+		resp := doAlertRequest(t, app, http.MethodGet, path, "")
+		if resp.StatusCode != status {
+			t.Fatalf("%s: expected status %d got %d", path, status, resp.StatusCode)
+		}
+	}
+}
+
+func TestSessionChecksWithViewComparesWithPrevious(t *testing.T) {
+	t.Parallel()
+
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	viewRepo := repository.NewInMemoryViewRepository()
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 1}); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+		if err := sessionRepo.MarkDone(ctx, int64(i+1), "finished"); err != nil {
+			t.Fatalf("mark done: %v", err)
+		}
+	}
+	if err := viewRepo.Create(ctx, &models.View{SearchKeywordURLID: 1, Name: "all", CompareWithPrevious: true}); err != nil {
+		t.Fatalf("create view: %v", err)
+	}
+	checkRepo := &recordingChecksRepo{}
+	app := setupCrawlingSessionAppWithViews(func() repository.CrawlingSessionRepository { return sessionRepo }, nil, nil, checkRepo, nil, nil, nil, nil, viewRepo)
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/2/checks_with_pages?view_id=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	if checkRepo.params.ComparisonSessionID == nil || *checkRepo.params.ComparisonSessionID != 1 {
+		t.Fatalf("expected comparison with session 1 got %v", checkRepo.params.ComparisonSessionID)
+	}
+	if checkRepo.params.FilterConfig == nil {
+		t.Fatalf("expected the view's filter config to apply")
+	}
+
+	// An explicit comparison session wins.
+	resp = doAlertRequest(t, app, http.MethodGet, "/api/crawling_sessions/2/checks_with_pages?view_id=1&comparison_crawling_session_id=2", "")
+	if resp.StatusCode != http.StatusOK || *checkRepo.params.ComparisonSessionID != 2 {
+		t.Fatalf("expected explicit comparison to win, got status %d params %+v", resp.StatusCode, checkRepo.params)
+	}
+}
+
+func TestStatsWithView(t *testing.T) {
+	t.Parallel()
+
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	viewRepo := repository.NewInMemoryViewRepository()
+	ctx := context.Background()
+	if err := sessionRepo.Create(ctx, &models.CrawlingSession{SearchKeywordURLID: 1}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	cfg := map[string]any{"filter_groups": []any{map[string]any{"depth": 1}}}
+	if err := viewRepo.Create(ctx, &models.View{SearchKeywordURLID: 1, Name: "shallow", FilterConfig: cfg}); err != nil {
+		t.Fatalf("create view: %v", err)
+	}
+	statsRepo := &recordingStatsRepo{}
+	app := setupStatsAppWith(statsRepo, sessionRepo, viewRepo)
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/stats?crawling_session_id=1&view_id=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	if !reflect.DeepEqual(statsRepo.params.FilterConfig, cfg) || statsRepo.params.ComparisonSessionID != nil {
+		t.Fatalf("unexpected stats params %+v", statsRepo.params)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodGet, "/api/stats?crawling_session_id=1&view_id=0", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+	resp = doAlertRequest(t, app, http.MethodGet, "/api/stats?crawling_session_id=1&view_id=2", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+// recordingChecksRepo records the last checks_with_pages params.
+type recordingChecksRepo struct {
+	params repository.ChecksWithPagesParams
+	checks []models.CheckWithPages
+}
+
+func (r *recordingChecksRepo) ChecksWithPages(ctx context.Context, params repository.ChecksWithPagesParams) ([]models.CheckWithPages, error) {
+	r.params = params
+	if r.checks == nil {
+		return []models.CheckWithPages{}, nil
+	}
+	return r.checks, nil
+}
+
+// recordingStatsRepo records the last stats params.
+type recordingStatsRepo struct {
+	params repository.StatsQueryParams
+}
+
+func (r *recordingStatsRepo) Fetch(ctx context.Context, params repository.StatsQueryParams) (map[string]any, error) {
+	r.params = params
+	return map[string]any{}, nil
+}
@@ -7,6 +7,7 @@ viewsDto "sitecrawler/newgo/dto/views"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -203,6 +204,20 @@ func TestViewsBadRequests(t *testing.T) {
 			body:     `{invalid}`,
 			wantCode: http.StatusBadRequest,
 		},
+		{
+			name:     "create with invalid filter_config",
+			method:   http.MethodPost,
+			url:      "/api/views",
+			body:     `{"data":{"name":"x","filter_config":{"filter_groups":[{"filters":[{"name":"depth;","operator":"eq","value":1}]}]}}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "update with invalid filter_config",
+			method:   http.MethodPut,
+			url:      "/api/views/1",
+			body:     `{"data":{"filter_config":{"filter_groups":"all"}}}`,
+			wantCode: http.StatusBadRequest,
+		},
 		{
 			name:     "page_count missing view_id",
 			method:   http.MethodGet,
@@ -508,7 +523,156 @@ func TestViewsPageCount(t *testing.T) {
 	}
 }
 
+func TestViewPageCounts(t *testing.T) {
+	t.Parallel()
+
+	viewRepo := repository.NewInMemoryViewRepository()
+	_ = viewRepo.Create(context.Background(), &models.View{SearchKeywordURLID: 1, Name: "Errors", FilterConfig: map[string]any{"tag": "errors"}})
+	_ = viewRepo.Create(context.Background(), &models.View{SearchKeywordURLID: 1, Name: "Redirects", FilterConfig: map[string]any{"tag": "redirects"}})
+	matchRepo := &countingMatchRepo{fakeMatchRepo: fakeMatchRepo{counts: map[int64]map[string]int{
+		1: {"errors": 4, "redirects": 2},
+		3: {"errors": 1},
+	}}}
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	for _, sku := range []int64{1, 1, 1, 2} {
+		_ = sessionRepo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: sku, URL: "https://example.com"})
+	}
+	app := setupViewAppWith(viewRepo, sessionRepo, nil, matchRepo)
+
+	resp := doAlertRequest(t, app, http.MethodGet, "/api/views/1/page_counts?crawling_session_ids=3,1,2", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out viewsDto.ViewPageCountsResponse
+	decodeBody(t, resp, &out)
+	want := viewsDto.ViewPageCounts{ViewID: 1, Counts: []viewsDto.SessionPageCount{
+		{CrawlingSessionID: 3, PageCount: 1},
+		{CrawlingSessionID: 1, PageCount: 4},
+		{CrawlingSessionID: 2, PageCount: 0},
+	}}
+	if !reflect.DeepEqual(out.Data, want) {
+		t.Fatalf("unexpected counts %+v", out.Data)
+	}
+
+	resp = doAlertRequest(t, app, http.MethodPost, "/api/views/page_counts", `{"data":{"view_ids":[2,1],"crawling_session_ids":[1,3]}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var batch viewsDto.BatchViewPageCountsResponse
+	decodeBody(t, resp, &batch)
+	wantBatch := []viewsDto.ViewPageCounts{
+		{ViewID: 2, Counts: []viewsDto.SessionPageCount{{CrawlingSessionID: 1, PageCount: 2}, {CrawlingSessionID: 3, PageCount: 0}}},
+		{ViewID: 1, Counts: []viewsDto.SessionPageCount{{CrawlingSessionID: 1, PageCount: 4}, {CrawlingSessionID: 3, PageCount: 1}}},
+	}
+	if !reflect.DeepEqual(batch.Data, wantBatch) {
+		t.Fatalf("unexpected batch counts %+v", batch.Data)
+	}
+	if matchRepo.grouped != 2 || matchRepo.single != 0 {
+		t.Fatalf("expected one grouped count per request, got %d grouped and %d single", matchRepo.grouped, matchRepo.single)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"missing sessions", http.MethodGet, "/api/views/1/page_counts", "", http.StatusBadRequest},
+		{"invalid session", http.MethodGet, "/api/views/1/page_counts?crawling_session_ids=1,x", "", http.StatusBadRequest},
+		{"zero session", http.MethodGet, "/api/views/1/page_counts?crawling_session_ids=0", "", http.StatusBadRequest},
+		{"unknown view", http.MethodGet, "/api/views/99/page_counts?crawling_session_ids=1", "", http.StatusNotFound},
+		{"batch without views", http.MethodPost, "/api/views/page_counts", `{"data":{"crawling_session_ids":[1]}}`, http.StatusBadRequest},
+		{"batch without sessions", http.MethodPost, "/api/views/page_counts", `{"data":{"view_ids":[1]}}`, http.StatusBadRequest},
+		{"batch unknown view", http.MethodPost, "/api/views/page_counts", `{"data":{"view_ids":[1,99],"crawling_session_ids":[1]}}`, http.StatusNotFound},
+		{"batch invalid json", http.MethodPost, "/api/views/page_counts", `{`, http.StatusBadRequest},
+		{"unknown session", http.MethodGet, "/api/views/1/page_counts?crawling_session_ids=1,99", "", http.StatusNotFound},
+		{"session of another sku", http.MethodGet, "/api/views/1/page_counts?crawling_session_ids=1,4", "", http.StatusBadRequest},
+		{"batch unknown session", http.MethodPost, "/api/views/page_counts", `{"data":{"view_ids":[1],"crawling_session_ids":[99]}}`, http.StatusNotFound},
+		{"batch session of another sku", http.MethodPost, "/api/views/page_counts", `{"data":{"view_ids":[2,1],"crawling_session_ids":[4]}}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		if resp := doAlertRequest(t, app, tc.method, tc.path, tc.body); resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected status %d got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+
+	_ = viewRepo.Create(context.Background(), &models.View{SearchKeywordURLID: 1, Name: "Broken", FilterConfig: map[string]any{"tag": "broken"}})
+	if resp := doAlertRequest(t, app, http.MethodGet, "/api/views/3/page_counts?crawling_session_ids=1", ""); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+}
+
 // setupViewApp creates a test fiber app with view routes
+func TestViewPages(t *testing.T) {
+	t.Parallel()
+
+	viewRepo := repository.NewInMemoryViewRepository()
+	_ = viewRepo.Create(context.Background(), &models.View{
+		SearchKeywordURLID: 1,
+		Name:               "Errors",
+		FilterConfig:       map[string]any{"filter_groups": []any{map[string]any{"response_code": 404}, map[string]any{"response_code": 500}}},
+	})
+	_ = viewRepo.Create(context.Background(), &models.View{SearchKeywordURLID: 1, Name: "Empty"})
+	pageRepo := &recordingPageRepo{pages: []models.Page{{ID: 3, URL: "https://example.com/missing", ResponseCode: 404}}, total: 11}
+	sessionRepo := repository.NewInMemoryCrawlingSessionRepository()
+	_ = sessionRepo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: 1, URL: "https://example.com"})
+	_ = sessionRepo.Create(context.Background(), &models.CrawlingSession{SearchKeywordURLID: 2, URL: "https://example.org"})
+	app := setupViewAppWith(viewRepo, sessionRepo, pageRepo, nil)
+
+	resp := doAlertRequest(t, app, http.MethodGet,
+		`/api/views/1/pages?crawling_session_id=1&sort=url&direction=DESC&page=2&page_limit=5&filters=[{"depth":1}]`, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, resp.StatusCode)
+	}
+	var out viewsDto.ViewPagesResponse
+	decodeBody(t, resp, &out)
+	if out.Data.ViewID != 1 || out.Data.PagesTotal != 11 || len(out.Data.Pages) != 1 {
+		t.Fatalf("unexpected pages %+v", out.Data)
+	}
+	want := repository.PageListParams{
+		SessionID:    1,
+		FilterConfig: map[string]any{"filter_groups": []any{map[string]any{"response_code": 404}, map[string]any{"response_code": 500}}},
+		Filters:      []map[string]any{{"depth": float64(1)}},
+		Sort:         "url",
+		Direction:    "desc",
+		Page:         2,
+		PageLimit:    5,
+	}
+	if !reflect.DeepEqual(pageRepo.params, want) {
+		t.Fatalf("unexpected list params %+v", pageRepo.params)
+	}
+
+	// A view without a filter_config matches nothing rather than everything.
+	doAlertRequest(t, app, http.MethodGet, "/api/views/2/pages?crawling_session_id=1", "")
+	if pageRepo.params.FilterConfig == nil {
+		t.Fatalf("expected an empty filter_config, got nil")
+	}
+
+	cases := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"missing session", "/api/views/1/pages", http.StatusBadRequest},
+		{"invalid filters", "/api/views/1/pages?crawling_session_id=1&filters=nope", http.StatusBadRequest},
+		{"invalid direction", "/api/views/1/pages?crawling_session_id=1&direction=up", http.StatusBadRequest},
+		{"unknown view", "/api/views/99/pages?crawling_session_id=1", http.StatusNotFound},
+		{"unknown session", "/api/views/1/pages?crawling_session_id=99", http.StatusNotFound},
+		{"session of another sku", "/api/views/1/pages?crawling_session_id=2", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		if resp := doAlertRequest(t, app, http.MethodGet, tc.path, ""); resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected status %d got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+
+	pageRepo.err = errors.New("invalid sort column: nope")
+	if resp := doAlertRequest(t, app, http.MethodGet, "/api/views/1/pages?crawling_session_id=1", ""); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+}
+
 func setupViewApp(factory func() repository.ViewRepository, seed func(*repository.InMemoryViewRepository)) *fiber.App {
 	viewRepo := repository.ViewRepository(nil)
 	if factory != nil {
@@ -524,14 +688,29 @@ func setupViewApp(factory func() repository.ViewRepository, seed func(*repositor
 		}
 	}
 
-	pageRepo := repository.NewNoopCrawlingSessionPageRepository()
+	return setupViewAppWith(viewRepo, nil, nil, nil)
+}
+
+// setupViewAppWith wires the view routes over the given repositories; a nil
+// session repository defaults to an empty in-memory one, nil page and match
+// repositories to the no-op implementations.
+func setupViewAppWith(viewRepo repository.ViewRepository, sessionRepo repository.CrawlingSessionRepository, pageRepo repository.CrawlingSessionPageRepository, matchRepo repository.PageMatchRepository) *fiber.App {
+	if sessionRepo == nil {
+		sessionRepo = repository.NewInMemoryCrawlingSessionRepository()
+	}
+	if pageRepo == nil {
+		pageRepo = repository.NewNoopCrawlingSessionPageRepository()
+	}
+	if matchRepo == nil {
+		matchRepo = repository.NewNoopPageMatchRepository()
+	}
 
 	app := fiber.New()
 
 	healthController := health.NewController(nil)
 
 	// Use unified views service
-	viewService := viewsvc.NewService(viewRepo, pageRepo)
+	viewService := viewsvc.NewService(viewRepo, sessionRepo, pageRepo, matchRepo, repository.NewInMemoryViewTemplateRepository())
 
 	routes.Register(app, routes.Dependencies{
 		Health:        healthController,
@@ -541,6 +720,19 @@ func setupViewApp(factory func() repository.ViewRepository, seed func(*repositor
 		ViewUpdate:    views.NewUpdateController(viewService, nil),
 		ViewDelete:    views.NewDeleteController(viewService, nil),
 		ViewPageCount: views.NewPageCountController(viewService, nil),
+		ViewPreview:   views.NewPreviewController(viewService, nil),
+		ViewPages:     views.NewListPagesController(viewService, nil),
+
+		ViewPageCounts:      views.NewPageCountsController(viewService, nil),
+		ViewBatchPageCounts: views.NewBatchPageCountsController(viewService, nil),
+
+		ViewTemplateList:      views.NewListTemplatesController(viewService, nil),
+		ViewTemplateCreate:    views.NewCreateTemplateController(viewService, nil),
+		ViewTemplateGet:       views.NewGetTemplateController(viewService, nil),
+		ViewTemplateUpdate:    views.NewUpdateTemplateController(viewService, nil),
+		ViewTemplateDelete:    views.NewDeleteTemplateController(viewService, nil),
+		ViewTemplateApply:     views.NewApplyTemplateController(viewService, nil),
+		ViewTemplatePropagate: views.NewPropagateTemplateController(viewService, nil),
 	})
 
 	return app
@@ -587,3 +779,43 @@ func (f failingViewRepo) ListBySKU(ctx context.Context, skuID int64) ([]models.V
 	_ = skuID
 	return nil, f.listErr
 }
+
+func (f failingViewRepo) ListByTemplate(ctx context.Context, templateID int64) ([]models.View, error) {
+	_ = ctx
+	_ = templateID
+	return nil, f.listErr
+}
+
+// countingMatchRepo counts how often single-session and grouped counts are
+// requested.
+type countingMatchRepo struct {
+	fakeMatchRepo
+	single, grouped int
+}
+
+func (r *countingMatchRepo) CountMatches(ctx context.Context, sessionID int64, configs []map[string]any) ([]int, error) {
+	r.single++
+	return r.fakeMatchRepo.CountMatches(ctx, sessionID, configs)
+}
+
+func (r *countingMatchRepo) CountMatchesBySession(ctx context.Context, sessionIDs []int64, configs []map[string]any) (map[int64][]int, error) {
+	r.grouped++
+	return r.fakeMatchRepo.CountMatchesBySession(ctx, sessionIDs, configs)
+}
+
+// recordingPageRepo records the last listing params and answers with a fixed
+// page of results.
+type recordingPageRepo struct {
+	pages  []models.Page
+	total  int
+	err    error
+	params repository.PageListParams
+}
+
+func (r *recordingPageRepo) List(ctx context.Context, params repository.PageListParams) ([]models.Page, int, error) {
+	r.params = params
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	return r.pages, r.total, nil
+}